@@ -7,6 +7,8 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/steve/llm-agents/internal/config"
 	"github.com/steve/llm-agents/internal/tls"
@@ -20,11 +22,33 @@ var (
 	clientName = flag.String("client-name", "mcp-client", "Common name for client certificate")
 	force      = flag.Bool("force", false, "Overwrite existing certificates")
 	verbose    = flag.Bool("verbose", false, "Enable verbose logging")
+	policyFile = flag.String("policy-file", "", "Path to a JSON signing policy (config.SigningPolicy) governing issued certificates")
+	profile    = flag.String("profile", "", "Named profile from --policy-file to issue a narrowly-scoped certificate instead of the default server/client pair")
+	clientRole = flag.String("client-role", "", "If set, encode spiffe://llm-agents/<client-role>/<client-name> into the client certificate's URI SAN, for internal/authz role-based authorization")
+	noColor    = flag.Bool("no-color", false, "Disable colored terminal output")
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "rotate-ca":
+			runRotateCA(os.Args[2:])
+			return
+		case "csr":
+			runCSR(os.Args[2:])
+			return
+		case "sign":
+			runSign(os.Args[2:])
+			return
+		}
+	}
+
 	flag.Parse()
 
+	if *noColor {
+		utils.SetColorEnabled(false)
+	}
+
 	// Initialize logging
 	logLevel := "INFO"
 	if *verbose {
@@ -46,7 +70,7 @@ func main() {
 
 	// Check if certificates already exist
 	if !*force && certificatesExist(tlsConfig) {
-		fmt.Println("Certificates already exist. Use --force to overwrite.")
+		fmt.Println(utils.Yellow("Certificates already exist. Use --force to overwrite."))
 		listExistingCertificates(tlsConfig)
 		return
 	}
@@ -54,26 +78,59 @@ func main() {
 	// Create certificate manager
 	certManager := tls.NewCertificateManager(tlsConfig)
 
+	// Load a signing policy if provided, enabling profile-based issuance
+	var signingPolicy *config.SigningPolicy
+	if *policyFile != "" {
+		var err error
+		signingPolicy, err = config.LoadSigningPolicy(*policyFile)
+		if err != nil {
+			log.Fatalf("Failed to load signing policy: %v", err)
+		}
+		certManager = certManager.WithSigningPolicy(signingPolicy)
+		utils.Info("%s Signing policy loaded: %s", utils.Green("✓"), *policyFile)
+	}
+
 	// Generate CA certificate
 	utils.Info("Generating Certificate Authority...")
 	if err := certManager.GenerateCA(); err != nil {
 		log.Fatalf("Failed to generate CA certificate: %v", err)
 	}
-	utils.Info("✓ CA certificate generated: %s", tlsConfig.CACert)
+	utils.Info("%s CA certificate generated: %s", utils.Green("✓"), tlsConfig.CACert)
+
+	// If a profile was requested, issue a narrowly-scoped certificate from
+	// it instead of the hardcoded server/client pair.
+	if *profile != "" {
+		if signingPolicy == nil {
+			log.Fatalf("--profile requires --policy-file")
+		}
+		if err := certManager.GenerateFromProfile(*profile, tls.CSRTemplate{
+			CommonName: *serverName,
+			DNSNames:   []string{"localhost", *serverName},
+		}); err != nil {
+			log.Fatalf("Failed to generate certificate from profile %q: %v", *profile, err)
+		}
+		fmt.Printf("\n%s Certificate issued from profile %q in %s\n", utils.Green("✓"), *profile, *certDir)
+		return
+	}
 
 	// Generate server certificate
 	utils.Info("Generating server certificate for: %s", *serverName)
 	if err := certManager.GenerateServerCert(*serverName); err != nil {
 		log.Fatalf("Failed to generate server certificate: %v", err)
 	}
-	utils.Info("✓ Server certificate generated: %s", tlsConfig.ServerCert)
+	utils.Info("%s Server certificate generated: %s", utils.Green("✓"), tlsConfig.ServerCert)
 
 	// Generate client certificate
 	utils.Info("Generating client certificate for: %s", *clientName)
-	if err := certManager.GenerateClientCert(*clientName); err != nil {
+	if *clientRole != "" {
+		if err := certManager.GenerateClientCertForIdentity(tls.ClientIdentity{Role: *clientRole, Name: *clientName}); err != nil {
+			log.Fatalf("Failed to generate client certificate: %v", err)
+		}
+		utils.Info("Client certificate encodes spiffe://llm-agents/%s/%s", *clientRole, *clientName)
+	} else if err := certManager.GenerateClientCert(*clientName); err != nil {
 		log.Fatalf("Failed to generate client certificate: %v", err)
 	}
-	utils.Info("✓ Client certificate generated: %s", tlsConfig.ClientCert)
+	utils.Info("%s Client certificate generated: %s", utils.Green("✓"), tlsConfig.ClientCert)
 
 	// Set proper permissions
 	if err := setPermissions(tlsConfig); err != nil {
@@ -81,7 +138,7 @@ func main() {
 	}
 
 	// Display certificate information
-	fmt.Println("\n🎉 Certificate generation completed successfully!")
+	fmt.Println(utils.Green("\n🎉 Certificate generation completed successfully!"))
 	fmt.Println("\nGenerated certificates:")
 	displayCertificateInfo(certManager, tlsConfig)
 
@@ -95,6 +152,178 @@ func main() {
 	fmt.Println("3. Test connections with cmd/test-tls utility")
 }
 
+// runRotateCA implements the "rotate-ca" subcommand: it generates a new CA,
+// cross-signs the existing leaf certificates against it, and writes a
+// combined bundle so peers still validating with the old root keep working
+// until they pick up the new one.
+func runRotateCA(args []string) {
+	fs := flag.NewFlagSet("rotate-ca", flag.ExitOnError)
+	certDir := fs.String("cert-dir", "./certs", "Directory containing the existing certificates")
+	overlap := fs.Duration("overlap", 24*time.Hour, "How long both the old and new CA remain trusted in the combined bundle")
+	verbose := fs.Bool("verbose", false, "Enable verbose logging")
+	noColor := fs.Bool("no-color", false, "Disable colored terminal output")
+
+	if err := fs.Parse(args); err != nil {
+		log.Fatalf("Failed to parse rotate-ca flags: %v", err)
+	}
+
+	if *noColor {
+		utils.SetColorEnabled(false)
+	}
+
+	logLevel := "INFO"
+	if *verbose {
+		logLevel = "DEBUG"
+	}
+	utils.InitLogger(logLevel, true)
+
+	tlsConfig := config.NewTLSConfig(*certDir, false)
+	certManager := tls.NewCertificateManager(tlsConfig)
+
+	utils.Info("Rotating CA in %s with a %s overlap window...", *certDir, *overlap)
+	if err := certManager.RotateCA(*overlap); err != nil {
+		log.Fatalf("Failed to rotate CA: %v", err)
+	}
+
+	fmt.Printf("\n%s CA rotation started.\n", utils.Green("✓"))
+	fmt.Printf("  Combined bundle (old + new CA): %s\n", tlsConfig.CACert)
+	fmt.Printf("  New CA certificate: %s\n", filepath.Join(*certDir, "ca-new.crt"))
+	fmt.Println("\nNext steps:")
+	fmt.Println("1. Deploy the combined bundle to all peers and let them reload it.")
+	fmt.Printf("2. After the %s overlap window, replace %s with %s and re-run without --force.\n", *overlap, tlsConfig.CACert, filepath.Join(*certDir, "ca-new.crt"))
+}
+
+// runCSR implements the "csr" subcommand: it generates a private key and a
+// PKCS#10 certificate signing request without touching the CA, so it can
+// run on a host that never holds ca.key.
+func runCSR(args []string) {
+	fs := flag.NewFlagSet("csr", flag.ExitOnError)
+	certDir := fs.String("cert-dir", "./certs", "Directory to write the key/CSR to when --key/--out aren't given")
+	name := fs.String("name", "mcp-server", "Common name for the certificate request")
+	sansFlag := fs.String("sans", "", "Comma-separated type:value SANs, e.g. dns:foo,ip:10.0.0.1")
+	keyOut := fs.String("key", "", "Path to write the generated private key (default: <cert-dir>/<name>.key)")
+	csrOut := fs.String("out", "", "Path to write the CSR (default: <cert-dir>/<name>.csr)")
+	verbose := fs.Bool("verbose", false, "Enable verbose logging")
+	noColor := fs.Bool("no-color", false, "Disable colored terminal output")
+
+	if err := fs.Parse(args); err != nil {
+		log.Fatalf("Failed to parse csr flags: %v", err)
+	}
+
+	if *noColor {
+		utils.SetColorEnabled(false)
+	}
+
+	logLevel := "INFO"
+	if *verbose {
+		logLevel = "DEBUG"
+	}
+	utils.InitLogger(logLevel, true)
+
+	sans, err := tls.ParseSANs(*sansFlag)
+	if err != nil {
+		log.Fatalf("Invalid --sans: %v", err)
+	}
+
+	if err := os.MkdirAll(*certDir, 0755); err != nil {
+		log.Fatalf("Failed to create certificate directory: %v", err)
+	}
+
+	tlsConfig := config.NewTLSConfig(*certDir, false)
+	certManager := tls.NewCertificateManager(tlsConfig)
+
+	csrPEM, keyPEM, err := certManager.GenerateCSR(*name, sans)
+	if err != nil {
+		log.Fatalf("Failed to generate CSR: %v", err)
+	}
+
+	keyPath := *keyOut
+	if keyPath == "" {
+		keyPath = filepath.Join(*certDir, *name+".key")
+	}
+	csrPath := *csrOut
+	if csrPath == "" {
+		csrPath = filepath.Join(*certDir, *name+".csr")
+	}
+
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		log.Fatalf("Failed to write private key: %v", err)
+	}
+	if err := os.WriteFile(csrPath, csrPEM, 0644); err != nil {
+		log.Fatalf("Failed to write CSR: %v", err)
+	}
+
+	fmt.Printf("\n%s CSR generated: %s\n", utils.Green("✓"), csrPath)
+	fmt.Printf("%s Private key generated: %s\n", utils.Green("✓"), keyPath)
+	fmt.Println("\nNext steps:")
+	fmt.Println("1. Transfer the CSR to a host holding ca.key; keep the private key here.")
+	fmt.Printf("2. Run: certgen sign --csr %s --profile <profile> --policy-file <policy.json> --out <cert>.crt\n", csrPath)
+}
+
+// runSign implements the "sign" subcommand: it validates a CSR against a
+// signing profile and issues a certificate from it. Meant to run on a host
+// that holds ca.key but never a leaf's private key, the other half of the
+// air-gapped CA workflow started by "csr".
+func runSign(args []string) {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	certDir := fs.String("cert-dir", "./certs", "Directory containing ca.crt/ca.key")
+	csrPath := fs.String("csr", "", "Path to the PKCS#10 CSR to sign")
+	profile := fs.String("profile", "", "Named profile from --policy-file governing the issued certificate")
+	policyFile := fs.String("policy-file", "", "Path to a JSON signing policy (config.SigningPolicy)")
+	out := fs.String("out", "", "Path to write the signed certificate (default: <csr base name>.crt)")
+	verbose := fs.Bool("verbose", false, "Enable verbose logging")
+	noColor := fs.Bool("no-color", false, "Disable colored terminal output")
+
+	if err := fs.Parse(args); err != nil {
+		log.Fatalf("Failed to parse sign flags: %v", err)
+	}
+
+	if *noColor {
+		utils.SetColorEnabled(false)
+	}
+
+	logLevel := "INFO"
+	if *verbose {
+		logLevel = "DEBUG"
+	}
+	utils.InitLogger(logLevel, true)
+
+	if *csrPath == "" {
+		log.Fatalf("--csr is required")
+	}
+	if *profile == "" || *policyFile == "" {
+		log.Fatalf("--profile and --policy-file are required")
+	}
+
+	csrPEM, err := os.ReadFile(*csrPath)
+	if err != nil {
+		log.Fatalf("Failed to read CSR: %v", err)
+	}
+
+	signingPolicy, err := config.LoadSigningPolicy(*policyFile)
+	if err != nil {
+		log.Fatalf("Failed to load signing policy: %v", err)
+	}
+
+	tlsConfig := config.NewTLSConfig(*certDir, false)
+	certManager := tls.NewCertificateManager(tlsConfig).WithSigningPolicy(signingPolicy)
+
+	certPEM, err := certManager.SignCSR(csrPEM, *profile)
+	if err != nil {
+		log.Fatalf("Failed to sign CSR: %v", err)
+	}
+
+	outPath := *out
+	if outPath == "" {
+		outPath = strings.TrimSuffix(*csrPath, filepath.Ext(*csrPath)) + ".crt"
+	}
+	if err := os.WriteFile(outPath, certPEM, 0644); err != nil {
+		log.Fatalf("Failed to write signed certificate: %v", err)
+	}
+
+	fmt.Printf("\n%s Certificate signed: %s\n", utils.Green("✓"), outPath)
+}
+
 // certificatesExist checks if certificates already exist
 func certificatesExist(cfg *config.TLSConfig) bool {
 	files := []string{cfg.CACert, cfg.ServerCert, cfg.ClientCert}
@@ -117,9 +346,9 @@ func listExistingCertificates(cfg *config.TLSConfig) {
 
 	for name, path := range files {
 		if _, err := os.Stat(path); err == nil {
-			fmt.Printf("  ✓ %s: %s\n", name, path)
+			fmt.Printf("  %s %s: %s\n", utils.Green("✓"), name, path)
 		} else {
-			fmt.Printf("  ✗ %s: %s (missing)\n", name, path)
+			fmt.Printf("  %s %s: %s (missing)\n", utils.Red("✗"), name, path)
 		}
 	}
 }
@@ -0,0 +1,137 @@
+// Certs MCP Server using official MCP Go SDK with StreamableHTTPHandler.
+// Exposes PKI introspection (certificate validation and expiry) as an MCP
+// tool so an agent can check the deployment's certificate state directly
+// instead of shelling out to openssl.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/httpx"
+	mcpserver "github.com/steve/llm-agents/internal/mcp/server"
+	mcptls "github.com/steve/llm-agents/internal/tls"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+type ValidateCertificateArgs struct {
+	CertPath   string `json:"cert_path" jsonschema:"path to the PEM certificate to validate"`
+	CACertPath string `json:"ca_cert_path" jsonschema:"path to the PEM CA certificate (or bundle) to verify against"`
+	ServerName string `json:"server_name,omitempty" jsonschema:"if set, also checked against the certificate's DNS SANs"`
+}
+
+func main() {
+	useTLS := flag.Bool("tls", false, "Enable TLS support")
+	verbose := flag.Bool("verbose", false, "Enable verbose logging")
+	flag.Parse()
+
+	logLevel := "INFO"
+	if *verbose {
+		logLevel = "DEBUG"
+	}
+	utils.InitLogger(logLevel, true)
+
+	httpPort := 8084
+	if portStr := os.Getenv("CERTS_MCP_PORT"); portStr != "" {
+		if p, err := strconv.Atoi(portStr); err == nil {
+			httpPort = p
+		}
+	}
+
+	tlsPort := 8446
+	if portStr := os.Getenv("CERTS_MCP_TLS_PORT"); portStr != "" {
+		if p, err := strconv.Atoi(portStr); err == nil {
+			tlsPort = p
+		}
+	}
+
+	ts := mcpserver.NewToolServer("certs-mcp", "v1.0.0")
+
+	mcpserver.RegisterTool(ts, "validateCertificate", "Validate a certificate against a CA and report its expiry and PKI details",
+		func(ctx context.Context, req *mcp.CallToolRequest, args ValidateCertificateArgs) (*mcp.CallToolResult, mcptls.CertificateValidationResponse, error) {
+			utils.Info("Handling validateCertificate request for %s (caller=%s)", args.CertPath, httpx.ClientIdentityFromContext(ctx))
+
+			result, err := mcptls.ValidateCertificate(mcptls.CertificateValidationRequest{
+				CertPath:   args.CertPath,
+				CACertPath: args.CACertPath,
+				ServerName: args.ServerName,
+			})
+			if err != nil {
+				utils.Warn("Failed to validate certificate %s: %v", args.CertPath, err)
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: "failed to validate certificate: " + err.Error()},
+					},
+				}, mcptls.CertificateValidationResponse{}, nil
+			}
+
+			utils.Info("Returning certificate validation result: %+v", result)
+
+			status := "valid"
+			if !result.Valid {
+				status = "invalid"
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: "Certificate " + args.CertPath + " is " + status + ", expires " + result.ExpiresAt.Format("2006-01-02")},
+				},
+			}, *result, nil
+		})
+
+	var allowedClientCNs []string
+	if raw := os.Getenv("TLS_ALLOWED_CLIENT_CNS"); raw != "" {
+		for _, cn := range strings.Split(raw, ",") {
+			if cn = strings.TrimSpace(cn); cn != "" {
+				allowedClientCNs = append(allowedClientCNs, cn)
+			}
+		}
+	}
+
+	ts.HTTPPort = httpPort
+	ts.TLSPort = tlsPort
+	ts.AllowedClientCNs = allowedClientCNs
+	ts.DiagAddr = os.Getenv("DIAG_ADDR")
+	ts.UpgradeMode = os.Getenv("TLS_UPGRADE_MODE") == "true"
+
+	if *useTLS {
+		tlsEnabled := os.Getenv("TLS_ENABLED") == "true"
+		if !tlsEnabled {
+			log.Fatal("TLS flag provided but TLS_ENABLED environment variable not set")
+		}
+
+		certDir := os.Getenv("TLS_CERT_DIR")
+		if certDir == "" {
+			certDir = "./certs"
+		}
+
+		demoMode := os.Getenv("TLS_DEMO_MODE") == "true"
+		ts.TLSConfig = config.NewTLSConfig(certDir, demoMode)
+		ts.TLSConfig.ClientAuthMode = os.Getenv("TLS_CLIENT_AUTH")
+		ts.TLSConfig.ClientCACert = os.Getenv("TLS_CLIENT_CA")
+
+		// Re-validate the server's own cert/CA pair on a schedule and log a
+		// warning well before they expire, independent of the tool above
+		// (which only runs on demand against whatever paths a caller asks
+		// about).
+		mcptls.StartExpiryMonitor(context.Background(), ts.TLSConfig, mcptls.DefaultExpiryCheckInterval)
+
+		utils.Info("Certs MCP Server configured with TLS support")
+		utils.Info("HTTP port: %d, HTTPS port: %d", httpPort, tlsPort)
+		utils.Info("TLS demo mode: %v", demoMode)
+		utils.Info("Certificate directory: %s", certDir)
+	} else {
+		utils.Info("Certs MCP Server configured for HTTP only")
+		utils.Info("HTTP port: %d", httpPort)
+	}
+
+	if err := ts.Run(context.Background()); err != nil {
+		log.Fatal("Server error:", err)
+	}
+}
@@ -7,44 +7,39 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/steve/llm-agents/internal/authz"
 	"github.com/steve/llm-agents/internal/config"
-	mcptls "github.com/steve/llm-agents/internal/tls"
+	"github.com/steve/llm-agents/internal/httpx"
+	"github.com/steve/llm-agents/internal/mcp/datetime"
+	mcpserver "github.com/steve/llm-agents/internal/mcp/server"
+	"github.com/steve/llm-agents/internal/mcperrors"
 	"github.com/steve/llm-agents/internal/utils"
 )
 
 type DateTimeArgs struct {
-	City string `json:"city" jsonschema:"the city to get datetime for"`
+	City     string `json:"city" jsonschema:"the city to get datetime for"`
+	Timezone string `json:"timezone,omitempty" jsonschema:"IANA timezone name to use directly, bypassing city lookup (e.g. Europe/Berlin)"`
 }
 
 type DateTimeResult struct {
-	LocalTime   string `json:"local_time"`
-	Timezone    string `json:"timezone"`
-	UTCOffset   string `json:"utc_offset"`
-	City        string `json:"city"`
-	Timestamp   string `json:"timestamp"`
+	LocalTime             string `json:"local_time"`
+	Timezone              string `json:"timezone"`
+	UTCOffset             string `json:"utc_offset"`
+	City                  string `json:"city"`
+	Timestamp             string `json:"timestamp"`
+	IsDST                 bool   `json:"is_dst"`
+	DSTOffsetSeconds      int    `json:"dst_offset_seconds"`
+	StandardOffsetSeconds int    `json:"standard_offset_seconds"`
 }
 
-// responseCapture wraps http.ResponseWriter to capture response data
-type responseCapture struct {
-	http.ResponseWriter
-	statusCode int
-	body       []byte
-}
-
-func (rc *responseCapture) WriteHeader(statusCode int) {
-	rc.statusCode = statusCode
-	rc.ResponseWriter.WriteHeader(statusCode)
-}
-
-func (rc *responseCapture) Write(b []byte) (int, error) {
-	rc.body = append(rc.body, b...)
-	return rc.ResponseWriter.Write(b)
+type ListSupportedCitiesResult struct {
+	Cities []string `json:"cities"`
 }
 
 func main() {
@@ -75,114 +70,179 @@ func main() {
 		}
 	}
 
-	// Create MCP server using official SDK
-	server := mcp.NewServer(&mcp.Implementation{
-		Name:    "datetime-mcp",
-		Version: "v1.0.0",
-	}, nil)
+	// Build the pluggable timezone resolver backend. DATETIME_RESOLVER
+	// selects "embedded" (default, bundled city table), "http" (delegate to
+	// DATETIME_GEOCODE_URL), or "openmeteo" (worldwide Open-Meteo geocoding,
+	// falling back to the embedded table if the API call fails, caching
+	// resolutions to disk at DATETIME_GEOCODE_CACHE_PATH if set);
+	// DATETIME_TZ_OVERRIDES_FILE, if set, layers operator corrections ahead
+	// of whichever backend is chosen.
+	resolver, err := datetime.NewResolverByName(
+		os.Getenv("DATETIME_RESOLVER"),
+		os.Getenv("DATETIME_GEOCODE_URL"),
+		os.Getenv("DATETIME_TZ_OVERRIDES_FILE"),
+		os.Getenv("DATETIME_GEOCODE_CACHE_PATH"),
+	)
+	if err != nil {
+		log.Fatal("Failed to construct timezone resolver:", err)
+	}
+
+	ts := mcpserver.NewToolServer("datetime-mcp", "v1.0.0")
 
 	// Add datetime tool using the official SDK's generic AddTool function
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "getDateTime",
-		Description: "Get current date and time information for a city",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, args DateTimeArgs) (*mcp.CallToolResult, DateTimeResult, error) {
-		utils.Info("Handling getDateTime request for city: %s", args.City)
-
-		// Get current time in UTC
-		now := time.Now()
-
-		// For simplicity, using fixed timezone mappings
-		// In real implementation, you'd use proper timezone database
-		var loc *time.Location
-		var err error
-
-		switch args.City {
-		case "New York", "NYC":
-			loc, err = time.LoadLocation("America/New_York")
-		case "Los Angeles", "LA":
-			loc, err = time.LoadLocation("America/Los_Angeles")
-		case "Chicago":
-			loc, err = time.LoadLocation("America/Chicago")
-		case "Denver":
-			loc, err = time.LoadLocation("America/Denver")
-		case "London":
-			loc, err = time.LoadLocation("Europe/London")
-		case "Tokyo":
-			loc, err = time.LoadLocation("Asia/Tokyo")
-		default:
-			// Default to Eastern Time
-			loc, err = time.LoadLocation("America/New_York")
-		}
+	mcpserver.RegisterTool(ts, "getDateTime", "Get current date and time information for a city",
+		func(ctx context.Context, req *mcp.CallToolRequest, args DateTimeArgs) (*mcp.CallToolResult, DateTimeResult, error) {
+			utils.Info("Handling getDateTime request for city: %s, timezone: %s (caller=%s)", args.City, args.Timezone, httpx.ClientIdentityFromContext(ctx))
+
+			var resolvedName, tzName string
+			if args.Timezone != "" {
+				// A caller-supplied timezone bypasses city resolution
+				// entirely - it's its own source of truth, so the
+				// resolver/geocoding chain never runs.
+				if _, err := time.LoadLocation(args.Timezone); err != nil {
+					utils.Warn("Invalid timezone override %q: %v", args.Timezone, err)
+					tzErr := mcperrors.New(mcperrors.ErrInvalidParams,
+						fmt.Sprintf("invalid timezone %q: %v", args.Timezone, err))
+					errText, marshalErr := json.Marshal(tzErr)
+					if marshalErr != nil {
+						errText = []byte(tzErr.Error())
+					}
+					return &mcp.CallToolResult{
+						IsError: true,
+						Content: []mcp.Content{
+							&mcp.TextContent{Text: string(errText)},
+						},
+					}, DateTimeResult{}, nil
+				}
+				tzName = args.Timezone
+			} else {
+				var err error
+				resolvedName, tzName, err = resolver.Resolve(ctx, args.City)
+				if err != nil {
+					utils.Warn("Failed to resolve timezone for city %s: %v", args.City, err)
+					// Not found is permanent - the client's retry logic (see
+					// mcperrors, internal/agents/client) shouldn't retry it.
+					cityErr := mcperrors.New(mcperrors.ErrCityNotFound,
+						fmt.Sprintf("could not resolve timezone for city %q: %v", args.City, err))
+					errText, marshalErr := json.Marshal(cityErr)
+					if marshalErr != nil {
+						errText = []byte(cityErr.Error())
+					}
+					return &mcp.CallToolResult{
+						IsError: true,
+						Content: []mcp.Content{
+							&mcp.TextContent{Text: string(errText)},
+						},
+					}, DateTimeResult{}, nil
+				}
+			}
 
-		if err != nil {
-			loc = time.UTC
-		}
+			loc, err := time.LoadLocation(tzName)
+			if err != nil {
+				loc = time.UTC
+			}
 
-		localTime := now.In(loc)
-		_, offset := localTime.Zone()
-		offsetHours := offset / 3600
-		offsetSign := "+"
-		if offsetHours < 0 {
-			offsetSign = "-"
-			offsetHours = -offsetHours
-		}
+			now := time.Now()
+			localTime := now.In(loc)
+			_, offset := localTime.Zone()
+			offsetHours := offset / 3600
+			offsetSign := "+"
+			if offsetHours < 0 {
+				offsetSign = "-"
+				offsetHours = -offsetHours
+			}
 
-		result := DateTimeResult{
-			LocalTime:   localTime.Format("2006-01-02 15:04:05"),
-			Timezone:    loc.String(),
-			UTCOffset:   fmt.Sprintf("%s%02d:00", offsetSign, offsetHours),
-			City:        args.City,
-			Timestamp:   now.Format(time.RFC3339),
-		}
+			isDST, dstOffsetSeconds, standardOffsetSeconds := datetime.DaylightSavingsOffset(loc, now)
+
+			result := DateTimeResult{
+				LocalTime:             localTime.Format("2006-01-02 15:04:05"),
+				Timezone:              loc.String(),
+				UTCOffset:             fmt.Sprintf("%s%02d:00", offsetSign, offsetHours),
+				City:                  resolvedName,
+				Timestamp:             now.Format(time.RFC3339),
+				IsDST:                 isDST,
+				DSTOffsetSeconds:      dstOffsetSeconds,
+				StandardOffsetSeconds: standardOffsetSeconds,
+			}
 
-		utils.Info("Returning datetime data: %+v", result)
+			utils.Info("Returning datetime data: %+v", result)
 
-		callToolResult := &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Time in %s: %s (%s, UTC%s)",
-						result.City, result.LocalTime, result.Timezone, result.UTCOffset),
+			displayName := result.City
+			if displayName == "" {
+				displayName = result.Timezone
+			}
+
+			callToolResult := &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{
+						Text: fmt.Sprintf("Time in %s: %s (%s, UTC%s)",
+							displayName, result.LocalTime, result.Timezone, result.UTCOffset),
+					},
 				},
-			},
-		}
+			}
 
-		// Log the complete response structure for debugging
-		if resultJSON, err := json.MarshalIndent(map[string]interface{}{
-			"callToolResult": callToolResult,
-			"structuredData": result,
-		}, "", "  "); err == nil {
-			utils.Debug("Complete tool response payload:\n%s", string(resultJSON))
-		}
+			// Log the complete response structure for debugging
+			if resultJSON, err := json.MarshalIndent(map[string]interface{}{
+				"callToolResult": callToolResult,
+				"structuredData": result,
+			}, "", "  "); err == nil {
+				utils.Debug("Complete tool response payload:\n%s", string(resultJSON))
+			}
 
-		return callToolResult, result, nil
-	})
-
-	// Create StreamableHTTPHandler using official SDK
-	handler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
-		return server
-	}, &mcp.StreamableHTTPOptions{JSONResponse: true})
-
-	// Wrap handler to log responses
-	loggingHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Create a response writer wrapper to capture the response
-		responseWriter := &responseCapture{
-			ResponseWriter: w,
-			statusCode:     200,
-			body:           []byte{},
-		}
+			return callToolResult, result, nil
+		})
+
+	// Add listSupportedCities tool, if the configured resolver can enumerate
+	// its coverage (EmbeddedResolver, OverridesResolver, and ChainResolver
+	// all do; a bare HTTPResolver doesn't, since its backend is opaque).
+	if lister, ok := resolver.(datetime.CityLister); ok {
+		mcpserver.RegisterTool(ts, "listSupportedCities", "List the cities this server can resolve timezones for",
+			func(ctx context.Context, req *mcp.CallToolRequest, args struct{}) (*mcp.CallToolResult, ListSupportedCitiesResult, error) {
+				cities := lister.SupportedCities()
+				result := ListSupportedCitiesResult{Cities: cities}
+
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("%d supported cities", len(cities))},
+					},
+				}, result, nil
+			})
+	}
 
-		handler.ServeHTTP(responseWriter, r)
+	// allowedClientCNs, if set, is the list of client certificate Subject
+	// CNs permitted to call this server over mTLS; every other caller gets
+	// a 403 from httpx.ClientCertIdentity below. Empty (the default)
+	// authorizes any caller whose certificate chains to our configured CA.
+	var allowedClientCNs []string
+	if raw := os.Getenv("TLS_ALLOWED_CLIENT_CNS"); raw != "" {
+		for _, cn := range strings.Split(raw, ",") {
+			if cn = strings.TrimSpace(cn); cn != "" {
+				allowedClientCNs = append(allowedClientCNs, cn)
+			}
+		}
+	}
 
-		// Log the complete HTTP response for debugging
-		utils.Debug("HTTP Response Status: %d", responseWriter.statusCode)
-		utils.Debug("HTTP Response Body:\n%s", string(responseWriter.body))
-	})
+	// AUTHZ_POLICY_FILE, if set, restricts each tool call to the SPIFFE
+	// roles a YAML policy lists for it (see internal/authz), on top of
+	// whatever TLS_ALLOWED_CLIENT_CNS already enforces. Reloads on SIGHUP.
+	if policyFile := os.Getenv("AUTHZ_POLICY_FILE"); policyFile != "" {
+		monitor, err := authz.NewMonitor(policyFile)
+		if err != nil {
+			log.Fatal("Failed to load authz policy:", err)
+		}
+		ts.AuthZ = monitor
+		utils.Info("DateTime MCP Server enforcing authz policy: %s", policyFile)
+	}
 
-	// Setup HTTP routes
-	mux := http.NewServeMux()
-	mux.Handle("/mcp", loggingHandler)
+	ts.HTTPPort = httpPort
+	ts.TLSPort = tlsPort
+	ts.AllowedClientCNs = allowedClientCNs
+	ts.DiagAddr = os.Getenv("DIAG_ADDR")
 
-	var tlsConfig *config.TLSConfig
+	// upgradeMode consolidates the plaintext and TLS listeners onto the
+	// single HTTP port via internal/tls/upgrade, so deployments behind
+	// port-restricted networks don't need to open a second port for TLS_PORT.
+	ts.UpgradeMode = os.Getenv("TLS_UPGRADE_MODE") == "true"
 
 	if *useTLS {
 		// TLS mode - configure TLS
@@ -198,7 +258,9 @@ func main() {
 		}
 
 		demoMode := os.Getenv("TLS_DEMO_MODE") == "true"
-		tlsConfig = config.NewTLSConfig(certDir, demoMode)
+		ts.TLSConfig = config.NewTLSConfig(certDir, demoMode)
+		ts.TLSConfig.ClientAuthMode = os.Getenv("TLS_CLIENT_AUTH")
+		ts.TLSConfig.ClientCACert = os.Getenv("TLS_CLIENT_CA")
 
 		utils.Info("DateTime MCP Server configured with TLS support")
 		utils.Info("HTTP port: %d, HTTPS port: %d", httpPort, tlsPort)
@@ -209,41 +271,7 @@ func main() {
 		utils.Info("HTTP port: %d", httpPort)
 	}
 
-	// Start HTTP server
-	go func() {
-		addr := fmt.Sprintf(":%d", httpPort)
-		utils.Info("Starting DateTime MCP Server (HTTP) on %s", addr)
-		if err := http.ListenAndServe(addr, mux); err != nil {
-			log.Fatal("Failed to start HTTP server:", err)
-		}
-	}()
-
-	// Start HTTPS server if TLS is enabled
-	if *useTLS && tlsConfig != nil {
-		go func() {
-			addr := fmt.Sprintf(":%d", tlsPort)
-			utils.Info("Starting DateTime MCP Server (HTTPS) on %s", addr)
-
-			tlsLoader := mcptls.NewTLSLoader(tlsConfig)
-			serverTLSConfig, err := tlsLoader.LoadServerTLSConfig()
-			if err != nil {
-				log.Fatal("Failed to load TLS config:", err)
-			}
-
-			server := &http.Server{
-				Addr:      addr,
-				Handler:   mux,
-				TLSConfig: serverTLSConfig,
-			}
-
-			if err := server.ListenAndServeTLS("", ""); err != nil {
-				log.Fatal("Failed to start HTTPS server:", err)
-			}
-		}()
+	if err := ts.Run(context.Background()); err != nil {
+		log.Fatal("Server error:", err)
 	}
-
-	utils.Info("DateTime MCP Server started with official SDK StreamableHTTPHandler")
-
-	// Keep the main goroutine alive
-	select {}
-}
\ No newline at end of file
+}
@@ -7,19 +7,22 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/steve/llm-agents/internal/config"
-	mcptls "github.com/steve/llm-agents/internal/tls"
+	"github.com/steve/llm-agents/internal/httpx"
+	mcpserver "github.com/steve/llm-agents/internal/mcp/server"
 	"github.com/steve/llm-agents/internal/utils"
 )
 
 type EchoArgs struct {
-	Text string `json:"text" jsonschema:"the text to echo back"`
+	Text      string `json:"text" jsonschema:"the text to echo back"`
+	Stream    bool   `json:"stream,omitempty" jsonschema:"deliver the text back in chunks via progress notifications instead of one response"`
+	ChunkSize int    `json:"chunkSize,omitempty" jsonschema:"characters per chunk when stream is true (default 200)"`
 }
 
 type EchoResult struct {
@@ -28,6 +31,29 @@ type EchoResult struct {
 	Timestamp    string `json:"timestamp"`
 }
 
+// defaultEchoChunkSize is used when a streaming request omits chunkSize.
+const defaultEchoChunkSize = 200
+
+// echoChunks splits text into chunks of at most chunkSize runes, preserving
+// order, so a streaming request can be delivered as a sequence of progress
+// notifications instead of a single response.
+func echoChunks(text string, chunkSize int) []string {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return nil
+	}
+
+	chunks := make([]string, 0, (len(runes)+chunkSize-1)/chunkSize)
+	for start := 0; start < len(runes); start += chunkSize {
+		end := start + chunkSize
+		if end > len(runes) {
+			end = len(runes)
+		}
+		chunks = append(chunks, string(runes[start:end]))
+	}
+	return chunks
+}
+
 func main() {
 	// Parse command line flags
 	useTLS := flag.Bool("tls", false, "Enable TLS support")
@@ -56,56 +82,68 @@ func main() {
 		}
 	}
 
-	// Create MCP server using official SDK
-	server := mcp.NewServer(&mcp.Implementation{
-		Name:    "echo-mcp",
-		Version: "v1.0.0",
-	}, nil)
+	ts := mcpserver.NewToolServer("echo-mcp", "v1.0.0")
 
 	// Add echo tool using the official SDK's generic AddTool function
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "echo",
-		Description: "Echo back the provided text",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, args EchoArgs) (*mcp.CallToolResult, EchoResult, error) {
-		utils.Info("Handling echo request for text: %s", args.Text)
-
-		result := EchoResult{
-			OriginalText: args.Text,
-			EchoText:     args.Text,
-			Timestamp:    time.Now().Format(time.RFC3339),
-		}
+	mcpserver.RegisterTool(ts, "echo", "Echo back the provided text",
+		func(ctx context.Context, req *mcp.CallToolRequest, args EchoArgs) (*mcp.CallToolResult, EchoResult, error) {
+			utils.Info("Handling echo request for text: %s (stream=%v, caller=%s)", args.Text, args.Stream, httpx.ClientIdentityFromContext(ctx))
+
+			if args.Stream {
+				if err := streamEcho(ctx, req, args); err != nil {
+					return nil, EchoResult{}, err
+				}
+			}
 
-		utils.Info("Returning echo data: %+v", result)
+			result := EchoResult{
+				OriginalText: args.Text,
+				EchoText:     args.Text,
+				Timestamp:    time.Now().Format(time.RFC3339),
+			}
 
-		callToolResult := &mcp.CallToolResult{
-			Content: []mcp.Content{
-				&mcp.TextContent{
-					Text: fmt.Sprintf("Echo: %s", result.EchoText),
-				},
-			},
-		}
+			utils.Info("Returning echo data: %+v", result)
 
-		// Log the complete response structure for debugging
-		if resultJSON, err := json.MarshalIndent(map[string]interface{}{
-			"callToolResult": callToolResult,
-			"structuredData": result,
-		}, "", "  "); err == nil {
-			utils.Debug("Complete tool response payload:\n%s", string(resultJSON))
-		}
+			callToolResult := &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{
+						Text: fmt.Sprintf("Echo: %s", result.EchoText),
+					},
+				},
+			}
 
-		return callToolResult, result, nil
-	})
+			// Log the complete response structure for debugging
+			if resultJSON, err := json.MarshalIndent(map[string]interface{}{
+				"callToolResult": callToolResult,
+				"structuredData": result,
+			}, "", "  "); err == nil {
+				utils.Debug("Complete tool response payload:\n%s", string(resultJSON))
+			}
 
-	// Create StreamableHTTPHandler using official SDK
-	handler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
-		return server
-	}, &mcp.StreamableHTTPOptions{JSONResponse: true})
+			return callToolResult, result, nil
+		})
+
+	// allowedClientCNs, if set, is the list of client certificate Subject
+	// CNs permitted to call this server over mTLS; every other caller gets
+	// a 403 from httpx.ClientCertIdentity below. Empty (the default)
+	// authorizes any caller whose certificate chains to our configured CA.
+	var allowedClientCNs []string
+	if raw := os.Getenv("TLS_ALLOWED_CLIENT_CNS"); raw != "" {
+		for _, cn := range strings.Split(raw, ",") {
+			if cn = strings.TrimSpace(cn); cn != "" {
+				allowedClientCNs = append(allowedClientCNs, cn)
+			}
+		}
+	}
 
-	// Setup HTTP routes
-	mux := http.NewServeMux()
-	mux.Handle("/mcp", handler)
+	ts.HTTPPort = httpPort
+	ts.TLSPort = tlsPort
+	ts.AllowedClientCNs = allowedClientCNs
+	ts.DiagAddr = os.Getenv("DIAG_ADDR")
 
-	var tlsConfig *config.TLSConfig
+	// upgradeMode consolidates the plaintext and TLS listeners onto the
+	// single HTTP port via internal/tls/upgrade, so deployments behind
+	// port-restricted networks don't need to open a second port for TLS_PORT.
+	ts.UpgradeMode = os.Getenv("TLS_UPGRADE_MODE") == "true"
 
 	if *useTLS {
 		// TLS mode - configure TLS
@@ -121,7 +159,9 @@ func main() {
 		}
 
 		demoMode := os.Getenv("TLS_DEMO_MODE") == "true"
-		tlsConfig = config.NewTLSConfig(certDir, demoMode)
+		ts.TLSConfig = config.NewTLSConfig(certDir, demoMode)
+		ts.TLSConfig.ClientAuthMode = os.Getenv("TLS_CLIENT_AUTH")
+		ts.TLSConfig.ClientCACert = os.Getenv("TLS_CLIENT_CA")
 
 		utils.Info("Echo MCP Server configured with TLS support")
 		utils.Info("HTTP port: %d, HTTPS port: %d", httpPort, tlsPort)
@@ -132,41 +172,45 @@ func main() {
 		utils.Info("HTTP port: %d", httpPort)
 	}
 
-	// Start HTTP server
-	go func() {
-		addr := fmt.Sprintf(":%d", httpPort)
-		utils.Info("Starting Echo MCP Server (HTTP) on %s", addr)
-		if err := http.ListenAndServe(addr, mux); err != nil {
-			log.Fatal("Failed to start HTTP server:", err)
-		}
-	}()
-
-	// Start HTTPS server if TLS is enabled
-	if *useTLS && tlsConfig != nil {
-		go func() {
-			addr := fmt.Sprintf(":%d", tlsPort)
-			utils.Info("Starting Echo MCP Server (HTTPS) on %s", addr)
-
-			tlsLoader := mcptls.NewTLSLoader(tlsConfig)
-			serverTLSConfig, err := tlsLoader.LoadServerTLSConfig()
-			if err != nil {
-				log.Fatal("Failed to load TLS config:", err)
-			}
+	if err := ts.Run(context.Background()); err != nil {
+		log.Fatal("Server error:", err)
+	}
+}
 
-			server := &http.Server{
-				Addr:      addr,
-				Handler:   mux,
-				TLSConfig: serverTLSConfig,
-			}
+// streamEcho delivers args.Text back to the caller as a sequence of MCP
+// progress notifications, one per chunk, instead of a single response.
+// It honors ctx.Done() between chunks so a client that cancels mid-stream
+// (or the request timing out) stops the echo early rather than running to
+// completion regardless.
+func streamEcho(ctx context.Context, req *mcp.CallToolRequest, args EchoArgs) error {
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return fmt.Errorf("stream requested but no progressToken was provided")
+	}
 
-			if err := server.ListenAndServeTLS("", ""); err != nil {
-				log.Fatal("Failed to start HTTPS server:", err)
-			}
-		}()
+	chunkSize := args.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultEchoChunkSize
 	}
 
-	utils.Info("Echo MCP Server started with official SDK StreamableHTTPHandler")
+	chunks := echoChunks(args.Text, chunkSize)
+	for i, chunk := range chunks {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("echo stream cancelled after %d/%d chunks: %w", i, len(chunks), ctx.Err())
+		default:
+		}
 
-	// Keep the main goroutine alive
-	select {}
-}
\ No newline at end of file
+		err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(i + 1),
+			Total:         float64(len(chunks)),
+			Message:       chunk,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to send progress notification for chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+// Forecast MCP Server using official MCP Go SDK with StreamableHTTPHandler
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/steve/llm-agents/internal/authz"
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/httpx"
+	mcpserver "github.com/steve/llm-agents/internal/mcp/server"
+	"github.com/steve/llm-agents/internal/mcp/weather"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+type ForecastArgs struct {
+	City string `json:"city" jsonschema:"the city to get a forecast for"`
+	Days int    `json:"days,omitempty" jsonschema:"number of days to forecast (default 5)"`
+}
+
+type ForecastDayResult struct {
+	Date                string  `json:"date"`
+	TemperatureMin      float64 `json:"temperature_min"`
+	TemperatureMax      float64 `json:"temperature_max"`
+	Unit                string  `json:"unit"`
+	PrecipitationChance float64 `json:"precipitation_chance"`
+	Description         string  `json:"description"`
+}
+
+type ForecastResult struct {
+	City string              `json:"city"`
+	Days []ForecastDayResult `json:"days"`
+}
+
+func main() {
+	// Parse command line flags
+	useTLS := flag.Bool("tls", false, "Enable TLS support")
+	providerFlag := flag.String("provider", "", "Forecast data provider to use: mock, open-meteo (overrides FORECAST_PROVIDER)")
+	verbose := flag.Bool("verbose", false, "Enable verbose logging")
+	flag.Parse()
+
+	// Initialize logging
+	logLevel := "INFO"
+	if *verbose {
+		logLevel = "DEBUG"
+	}
+	utils.InitLogger(logLevel, true)
+
+	// Get ports from environment or use defaults
+	httpPort := 8085
+	if portStr := os.Getenv("FORECAST_MCP_PORT"); portStr != "" {
+		if p, err := strconv.Atoi(portStr); err == nil {
+			httpPort = p
+		}
+	}
+
+	tlsPort := 8447
+	if portStr := os.Getenv("FORECAST_MCP_TLS_PORT"); portStr != "" {
+		if p, err := strconv.Atoi(portStr); err == nil {
+			tlsPort = p
+		}
+	}
+
+	// Select the forecast data provider: the --provider flag takes
+	// precedence over FORECAST_PROVIDER, which defaults to the random mock
+	// so the server still runs without any network access.
+	providerName := *providerFlag
+	if providerName == "" {
+		providerName = os.Getenv("FORECAST_PROVIDER")
+	}
+	forecastProvider, err := weather.NewForecastProviderByName(providerName, os.Getenv("FORECAST_UNITS"), config.MCPClientConfig{
+		Timeout:       10 * time.Second,
+		RetryAttempts: 3,
+	})
+	if err != nil {
+		log.Fatal("Failed to configure forecast provider:", err)
+	}
+	utils.Info("Forecast MCP Server using provider: %s (units: %s)", providerName, os.Getenv("FORECAST_UNITS"))
+
+	ts := mcpserver.NewToolServer("forecast-mcp", "v1.0.0")
+
+	mcpserver.RegisterTool(ts, "getForecast", "Get a multi-day weather forecast for a city",
+		func(ctx context.Context, req *mcp.CallToolRequest, args ForecastArgs) (*mcp.CallToolResult, ForecastResult, error) {
+			utils.Info("Handling getForecast request for city: %s, days: %d (caller=%s)", args.City, args.Days, httpx.ClientIdentityFromContext(ctx))
+
+			obs, err := forecastProvider.GetForecast(ctx, args.City, args.Days)
+			if err != nil {
+				utils.Warn("Failed to fetch forecast for city %s: %v", args.City, err)
+				return &mcp.CallToolResult{
+					IsError: true,
+					Content: []mcp.Content{
+						&mcp.TextContent{Text: fmt.Sprintf("failed to fetch forecast for %q: %v", args.City, err)},
+					},
+				}, ForecastResult{}, nil
+			}
+
+			result := ForecastResult{City: obs.City, Days: make([]ForecastDayResult, 0, len(obs.Days))}
+			for _, d := range obs.Days {
+				result.Days = append(result.Days, ForecastDayResult{
+					Date:                d.Date,
+					TemperatureMin:      d.TemperatureMin,
+					TemperatureMax:      d.TemperatureMax,
+					Unit:                d.Unit,
+					PrecipitationChance: d.PrecipitationChance,
+					Description:         d.Description,
+				})
+			}
+
+			utils.Info("Returning %d-day forecast for %s", len(result.Days), result.City)
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("%d-day forecast for %s", len(result.Days), result.City)},
+				},
+			}, result, nil
+		})
+
+	// allowedClientCNs, if set, is the list of client certificate Subject
+	// CNs permitted to call this server over mTLS; every other caller gets
+	// a 403 from httpx.ClientCertIdentity below. Empty (the default)
+	// authorizes any caller whose certificate chains to our configured CA.
+	var allowedClientCNs []string
+	if raw := os.Getenv("TLS_ALLOWED_CLIENT_CNS"); raw != "" {
+		for _, cn := range strings.Split(raw, ",") {
+			if cn = strings.TrimSpace(cn); cn != "" {
+				allowedClientCNs = append(allowedClientCNs, cn)
+			}
+		}
+	}
+
+	// AUTHZ_POLICY_FILE, if set, restricts each tool call to the SPIFFE
+	// roles a YAML policy lists for it (see internal/authz), on top of
+	// whatever TLS_ALLOWED_CLIENT_CNS already enforces. Reloads on SIGHUP.
+	if policyFile := os.Getenv("AUTHZ_POLICY_FILE"); policyFile != "" {
+		monitor, err := authz.NewMonitor(policyFile)
+		if err != nil {
+			log.Fatal("Failed to load authz policy:", err)
+		}
+		ts.AuthZ = monitor
+		utils.Info("Forecast MCP Server enforcing authz policy: %s", policyFile)
+	}
+
+	ts.HTTPPort = httpPort
+	ts.TLSPort = tlsPort
+	ts.AllowedClientCNs = allowedClientCNs
+	ts.DiagAddr = os.Getenv("DIAG_ADDR")
+	ts.UpgradeMode = os.Getenv("TLS_UPGRADE_MODE") == "true"
+
+	if *useTLS {
+		tlsEnabled := os.Getenv("TLS_ENABLED") == "true"
+		if !tlsEnabled {
+			log.Fatal("TLS flag provided but TLS_ENABLED environment variable not set")
+		}
+
+		certDir := os.Getenv("TLS_CERT_DIR")
+		if certDir == "" {
+			certDir = "./certs"
+		}
+
+		demoMode := os.Getenv("TLS_DEMO_MODE") == "true"
+		ts.TLSConfig = config.NewTLSConfig(certDir, demoMode)
+		ts.TLSConfig.ClientAuthMode = os.Getenv("TLS_CLIENT_AUTH")
+		ts.TLSConfig.ClientCACert = os.Getenv("TLS_CLIENT_CA")
+
+		utils.Info("Forecast MCP Server configured with TLS support")
+		utils.Info("HTTP port: %d, HTTPS port: %d", httpPort, tlsPort)
+		utils.Info("TLS demo mode: %v", demoMode)
+		utils.Info("Certificate directory: %s", certDir)
+	} else {
+		utils.Info("Forecast MCP Server configured for HTTP only")
+		utils.Info("HTTP port: %d", httpPort)
+	}
+
+	if err := ts.Run(context.Background()); err != nil {
+		log.Fatal("Server error:", err)
+	}
+}
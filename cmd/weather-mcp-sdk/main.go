@@ -14,9 +14,24 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/steve/llm-agents/internal/config"
 	"github.com/steve/llm-agents/internal/mcp/transport"
+	mcptls "github.com/steve/llm-agents/internal/tls"
 	"github.com/steve/llm-agents/internal/utils"
 )
 
+// parseMTLSState maps the WEATHER_MCP_MTLS env var ("enabled",
+// "verify_if_given", "disabled" or unset) to a config.MTLSState, defaulting
+// to MTLSStateDisabled (server-auth-only TLS) for backwards compatibility.
+func parseMTLSState(value string) config.MTLSState {
+	switch value {
+	case "enabled":
+		return config.MTLSStateEnabled
+	case "verify_if_given":
+		return config.MTLSStateVerifyIfGiven
+	default:
+		return config.MTLSStateDisabled
+	}
+}
+
 func main() {
 	// Parse command line flags
 	useTLS := flag.Bool("tls", false, "Enable TLS support")
@@ -45,6 +60,7 @@ func main() {
 	}
 
 	var tlsConfig *config.TLSConfig
+	var certReloader *mcptls.CertReloader
 	selectedPort := *port
 
 	if *useTLS {
@@ -61,10 +77,20 @@ func main() {
 
 		demoMode := os.Getenv("TLS_DEMO_MODE") == "true"
 		tlsConfig = config.NewTLSConfig(certDir, demoMode)
+		tlsConfig.MTLSState = parseMTLSState(os.Getenv("WEATHER_MCP_MTLS"))
 		selectedPort = *tlsPort
 
 		utils.Info("Weather MCP Server (SDK) configured with TLS support")
-		utils.Info("TLS port: %d, demo mode: %v, cert dir: %s", selectedPort, demoMode, certDir)
+		utils.Info("TLS port: %d, demo mode: %v, mTLS: %s, cert dir: %s", selectedPort, demoMode, tlsConfig.MTLSState, certDir)
+
+		if os.Getenv("TLS_CERT_RELOAD") == "true" {
+			var err error
+			certReloader, err = mcptls.NewCertReloader(tlsConfig, true)
+			if err != nil {
+				log.Fatalf("Failed to start certificate reloader: %v", err)
+			}
+			utils.Info("Weather MCP Server (SDK) watching certificates for hot reload (SIGHUP or file change)")
+		}
 	} else {
 		utils.Info("Weather MCP Server (SDK) configured for HTTP only on port %d", selectedPort)
 	}
@@ -119,16 +145,25 @@ func main() {
 		}, result, nil
 	})
 
-	// Create custom HTTP/SSE transport
-	mcpTransport := transport.NewServerTransport(selectedPort, tlsConfig)
+	// Create custom HTTP/SSE transport, backed by the CertReloader if hot
+	// reload was requested so rotating certs on disk doesn't need a restart
+	var mcpTransport *transport.HTTPSSETransport
+	if certReloader != nil {
+		mcpTransport = transport.NewServerTransportWithReloader(selectedPort, tlsConfig, certReloader)
+	} else {
+		mcpTransport = transport.NewServerTransport(selectedPort, tlsConfig)
+	}
 
 	// Run server with custom transport
 	utils.Info("Starting Weather MCP Server (SDK) with HTTP/SSE streaming transport...")
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	if certReloader != nil {
+		defer certReloader.Close()
+	}
 
 	if err := server.Run(ctx, mcpTransport); err != nil {
 		log.Fatalf("Failed to start weather MCP server: %v", err)
 	}
-}
\ No newline at end of file
+}
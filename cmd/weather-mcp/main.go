@@ -7,18 +7,35 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"math/rand"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/diag"
+	"github.com/steve/llm-agents/internal/httpx"
+	"github.com/steve/llm-agents/internal/mcp/weather"
+	"github.com/steve/llm-agents/internal/metrics"
+	mcpserver "github.com/steve/llm-agents/internal/server"
 	mcptls "github.com/steve/llm-agents/internal/tls"
+	tlsbootstrap "github.com/steve/llm-agents/internal/tls/bootstrap"
+	"github.com/steve/llm-agents/internal/tls/upgrade"
 	"github.com/steve/llm-agents/internal/utils"
 )
 
+// weatherCacheTTL bounds how long a provider's observation for a city is
+// reused before the next MCP call re-fetches it.
+const weatherCacheTTL = 5 * time.Minute
+
+// readinessMaxAge bounds how long /readyz on the diagnostics listener
+// keeps reporting ready after the weather provider's last successful
+// response before it starts failing.
+const readinessMaxAge = 60 * time.Second
+
 type WeatherArgs struct {
 	City string `json:"city" jsonschema:"the city to get weather for"`
 }
@@ -31,26 +48,54 @@ type WeatherResult struct {
 	Timestamp   string  `json:"timestamp"`
 }
 
-// responseCapture wraps http.ResponseWriter to capture response data
-type responseCapture struct {
-	http.ResponseWriter
-	statusCode int
-	body       []byte
+// weatherBatchWorkers bounds how many cities getTemperatureBatch fetches
+// concurrently, so a large city list can't open unbounded concurrent
+// requests against the configured weather provider.
+const weatherBatchWorkers = 5
+
+type WeatherBatchArgs struct {
+	Cities []string `json:"cities" jsonschema:"the cities to get weather for"`
 }
 
-func (rc *responseCapture) WriteHeader(statusCode int) {
-	rc.statusCode = statusCode
-	rc.ResponseWriter.WriteHeader(statusCode)
+// WeatherBatchEntry reports one city's result within a getTemperatureBatch
+// response: either the populated weather fields, or Error set when that
+// city's lookup failed, so one bad city doesn't fail the whole batch.
+type WeatherBatchEntry struct {
+	Temperature float64 `json:"temperature,omitempty"`
+	Unit        string  `json:"unit,omitempty"`
+	Description string  `json:"description,omitempty"`
+	City        string  `json:"city"`
+	Timestamp   string  `json:"timestamp,omitempty"`
+	Error       string  `json:"error,omitempty"`
 }
 
-func (rc *responseCapture) Write(b []byte) (int, error) {
-	rc.body = append(rc.body, b...)
-	return rc.ResponseWriter.Write(b)
+type WeatherBatchResult struct {
+	Results []WeatherBatchEntry `json:"results"`
+}
+
+// schemeMiddleware tags each request's context with the scheme it arrived
+// on, so the getTemperature tool handler can label its call-latency metric
+// as HTTP or HTTPS even though both listeners share the same mux.
+func schemeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme := metrics.SchemeHTTP
+		if r.TLS != nil {
+			scheme = metrics.SchemeHTTPS
+		}
+		next.ServeHTTP(w, r.WithContext(metrics.ContextWithScheme(r.Context(), scheme)))
+	})
 }
 
 func main() {
+	// Sampled before any TLS/cert/provider setup runs, so RecordStartupRSSDelta
+	// at the end of main reflects the memory cost of server startup.
+	baselineRSS, _ := metrics.ReadProcessRSSBytes()
+
 	// Parse command line flags
 	useTLS := flag.Bool("tls", false, "Enable TLS support")
+	bootstrapToken := flag.String("bootstrap-token", "", "One-time token to enroll TLS identity from the bootstrap CA, as an alternative to TLS_CERT_DIR")
+	providerFlag := flag.String("provider", "", "Weather data provider to use: mock, openweathermap, open-meteo, nws (overrides WEATHER_PROVIDER)")
+	metricsEnabled := flag.Bool("metrics", false, "Expose Prometheus metrics at /metrics")
 	verbose := flag.Bool("verbose", false, "Enable verbose logging")
 	flag.Parse()
 
@@ -76,6 +121,27 @@ func main() {
 		}
 	}
 
+	// Select the weather data provider: the --provider flag takes
+	// precedence over WEATHER_PROVIDER, which defaults to the random mock
+	// so the server still runs without any API key configured.
+	providerName := *providerFlag
+	if providerName == "" {
+		providerName = os.Getenv("WEATHER_PROVIDER")
+	}
+	weatherProvider, err := weather.NewProviderByName(providerName, os.Getenv("WEATHER_API_KEY"), os.Getenv("WEATHER_UNITS"), config.MCPClientConfig{
+		Timeout:       10 * time.Second,
+		RetryAttempts: 3,
+	}, weatherCacheTTL, os.Getenv("WEATHER_GEOCODE_CACHE_PATH"))
+	if err != nil {
+		log.Fatal("Failed to configure weather provider:", err)
+	}
+	utils.Info("Weather MCP Server using provider: %s (units: %s)", providerName, os.Getenv("WEATHER_UNITS"))
+
+	// readiness backs /readyz on the diagnostics listener: it starts ready
+	// and fails once the weather provider hasn't responded successfully in
+	// readinessMaxAge.
+	readiness := diag.NewReadiness(readinessMaxAge)
+
 	// Create MCP server using official SDK
 	server := mcp.NewServer(&mcp.Implementation{
 		Name:    "weather-mcp",
@@ -89,17 +155,21 @@ func main() {
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args WeatherArgs) (*mcp.CallToolResult, WeatherResult, error) {
 		utils.Info("Handling getTemperature request for city: %s", args.City)
 
-		// Simulate weather data (in real implementation, call actual weather API)
-		temperature := 20.0 + rand.Float64()*25.0 // 20-45°C
-		conditions := []string{"Sunny", "Partly cloudy", "Cloudy", "Light rain", "Clear"}
-		description := conditions[rand.Intn(len(conditions))]
+		callStart := time.Now()
+		observation, err := weatherProvider.Get(ctx, args.City)
+		metrics.ObserveToolCallDuration("getTemperature", metrics.SchemeFromContext(ctx), time.Since(callStart))
+		if err != nil {
+			utils.Error("Failed to get weather data for %s: %v", args.City, err)
+			return nil, WeatherResult{}, fmt.Errorf("failed to get weather data for %s: %w", args.City, err)
+		}
+		readiness.MarkSuccess()
 
 		result := WeatherResult{
-			Temperature: temperature,
-			Unit:        "°C",
-			Description: description,
+			Temperature: observation.Temperature,
+			Unit:        observation.Unit,
+			Description: observation.Description,
 			City:        args.City,
-			Timestamp:   time.Now().Format(time.RFC3339),
+			Timestamp:   observation.Timestamp.Format(time.RFC3339),
 		}
 
 		utils.Info("Returning weather data: %+v", result)
@@ -124,34 +194,80 @@ func main() {
 		return callToolResult, result, nil
 	})
 
-	// Create StreamableHTTPHandler using official SDK
-	handler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
-		return server
-	}, &mcp.StreamableHTTPOptions{JSONResponse: true})
-
-	// Wrap handler to log responses
-	loggingHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Create a response writer wrapper to capture the response
-		responseWriter := &responseCapture{
-			ResponseWriter: w,
-			statusCode:     200,
-			body:           []byte{},
+	// Add a batch weather tool so a caller with a list of cities can issue
+	// one MCP round-trip instead of N, fetched through a bounded worker
+	// pool so a large city list can't fan out unbounded provider calls.
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "getTemperatureBatch",
+		Description: "Get current temperature and weather conditions for many cities in one call",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args WeatherBatchArgs) (*mcp.CallToolResult, WeatherBatchResult, error) {
+		utils.Info("Handling getTemperatureBatch request for %d cities", len(args.Cities))
+
+		entries := make([]WeatherBatchEntry, len(args.Cities))
+		sem := make(chan struct{}, weatherBatchWorkers)
+		var wg sync.WaitGroup
+
+		for i, city := range args.Cities {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, city string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				callStart := time.Now()
+				observation, err := weatherProvider.Get(ctx, city)
+				metrics.ObserveToolCallDuration("getTemperatureBatch", metrics.SchemeFromContext(ctx), time.Since(callStart))
+				if err != nil {
+					utils.Error("Failed to get weather data for %s: %v", city, err)
+					entries[i] = WeatherBatchEntry{City: city, Error: err.Error()}
+					return
+				}
+				entries[i] = WeatherBatchEntry{
+					Temperature: observation.Temperature,
+					Unit:        observation.Unit,
+					Description: observation.Description,
+					City:        city,
+					Timestamp:   observation.Timestamp.Format(time.RFC3339),
+				}
+			}(i, city)
 		}
+		wg.Wait()
 
-		handler.ServeHTTP(responseWriter, r)
+		for _, e := range entries {
+			if e.Error == "" {
+				readiness.MarkSuccess()
+				break
+			}
+		}
+		result := WeatherBatchResult{Results: entries}
+		utils.Info("Returning weather batch data for %d cities", len(entries))
 
-		// Log the complete HTTP response for debugging
-		utils.Debug("HTTP Response Status: %d", responseWriter.statusCode)
-		utils.Debug("HTTP Response Body:\n%s", string(responseWriter.body))
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{
+					Text: fmt.Sprintf("Fetched weather for %d cities", len(entries)),
+				},
+			},
+		}, result, nil
 	})
 
+	// Create StreamableHTTPHandler using official SDK
+	handler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
+		return server
+	}, &mcp.StreamableHTTPOptions{JSONResponse: true})
+
 	// Setup HTTP routes
 	mux := http.NewServeMux()
-	mux.Handle("/mcp", loggingHandler)
+	mux.Handle("/mcp", httpx.RequestID(httpx.LoggingHandler(schemeMiddleware(handler), nil)))
+	if *metricsEnabled {
+		mux.Handle("/metrics", metrics.Handler())
+		utils.Info("Prometheus metrics exposed at /metrics")
+	}
 
 	var tlsConfig *config.TLSConfig
+	usingBootstrap := *useTLS && *bootstrapToken != ""
 
-	if *useTLS {
+	if *useTLS && !usingBootstrap {
 		// TLS mode - configure TLS
 		tlsEnabled := os.Getenv("TLS_ENABLED") == "true"
 		if !tlsEnabled {
@@ -166,51 +282,123 @@ func main() {
 
 		demoMode := os.Getenv("TLS_DEMO_MODE") == "true"
 		tlsConfig = config.NewTLSConfig(certDir, demoMode)
+		tlsConfig.ClientAuthMode = os.Getenv("TLS_CLIENT_AUTH")
+		tlsConfig.ClientCACert = os.Getenv("TLS_CLIENT_CA")
+
+		if err := metrics.SetCertExpiryFromFile("server", tlsConfig.ServerCert); err != nil {
+			utils.Warn("Failed to record server certificate expiry metric: %v", err)
+		}
 
 		utils.Info("Weather MCP Server configured with TLS support")
 		utils.Info("HTTP port: %d, HTTPS port: %d", httpPort, tlsPort)
 		utils.Info("TLS demo mode: %v", demoMode)
 		utils.Info("Certificate directory: %s", certDir)
+	} else if usingBootstrap {
+		utils.Info("Weather MCP Server configured with TLS support via bootstrap-token enrollment")
+		utils.Info("HTTP port: %d, HTTPS port: %d", httpPort, tlsPort)
 	} else {
 		utils.Info("Weather MCP Server configured for HTTP only")
 		utils.Info("HTTP port: %d", httpPort)
 	}
 
-	// Start HTTP server
-	go func() {
-		addr := fmt.Sprintf(":%d", httpPort)
-		utils.Info("Starting Weather MCP Server (HTTP) on %s", addr)
-		if err := http.ListenAndServe(addr, mux); err != nil {
-			log.Fatal("Failed to start HTTP server:", err)
+	httpServer := &http.Server{
+		Addr:        fmt.Sprintf(":%d", httpPort),
+		Handler:     mux,
+		BaseContext: httpx.BaseContext(nil),
+	}
+	mcpserver.ApplyDefaultTimeouts(httpServer)
+
+	// upgradeMode consolidates the plaintext and TLS listeners onto the
+	// single HTTP port via internal/tls/upgrade, so deployments behind
+	// port-restricted networks don't need to open a second port for TLS_PORT.
+	// It only applies to the certs-on-disk TLS path; bootstrap enrollment
+	// still gets its own HTTPS listener since BootstrapServer dials that
+	// port directly.
+	upgradeMode := os.Getenv("TLS_UPGRADE_MODE") == "true"
+
+	// Build the HTTPS server, either from certs on disk or from a
+	// bootstrap-token enrollment against the CA's /sign endpoint, before
+	// handing both servers to mcpserver.Run together.
+	var httpsServer *http.Server
+	var httpListener net.Listener
+	if usingBootstrap {
+		httpsServer = &http.Server{
+			Addr:        fmt.Sprintf(":%d", tlsPort),
+			Handler:     mux,
+			BaseContext: httpx.BaseContext(nil),
+		}
+		mcpserver.ApplyDefaultTimeouts(httpsServer)
+
+		caURL := os.Getenv("TLS_BOOTSTRAP_CA_URL")
+		if caURL == "" {
+			log.Fatal("bootstrap-token provided but TLS_BOOTSTRAP_CA_URL environment variable not set")
+		}
+
+		if _, err := tlsbootstrap.BootstrapServer(context.Background(), *bootstrapToken, caURL, httpsServer); err != nil {
+			log.Fatal("Failed to bootstrap TLS identity:", err)
+		}
+	} else if *useTLS && tlsConfig != nil {
+		tlsLoader := mcptls.NewTLSLoader(tlsConfig)
+		renewer, err := mcptls.StartRotation(context.Background(), tlsLoader, tlsConfig, "weather-mcp", mcptls.DefaultRotationCheckInterval)
+		if err != nil {
+			log.Fatal("Failed to start certificate rotation:", err)
 		}
-	}()
 
-	// Start HTTPS server if TLS is enabled
-	if *useTLS && tlsConfig != nil {
-		go func() {
-			addr := fmt.Sprintf(":%d", tlsPort)
-			utils.Info("Starting Weather MCP Server (HTTPS) on %s", addr)
+		// RenewalMonitor rides alongside StartRotation's own CertRenewer,
+		// adding the expiry_seconds/renewals_total metrics and day-threshold
+		// warnings an operator dashboard watches, plus an on-demand Renew
+		// the cert CLI subcommand can call without starting a second
+		// scheduled renewal loop against the same files.
+		renewalMonitor := mcptls.NewRenewalMonitor(mcptls.NewCertificateManager(tlsConfig))
+		renewalMonitor.Track(tlsConfig.ServerCert, renewer)
+		renewalMonitor.Start(context.Background(), mcptls.DefaultRotationCheckInterval)
+
+		serverTLSConfig, err := tlsLoader.LoadServerTLSConfig()
+		if err != nil {
+			log.Fatal("Failed to load TLS config:", err)
+		}
 
-			tlsLoader := mcptls.NewTLSLoader(tlsConfig)
-			serverTLSConfig, err := tlsLoader.LoadServerTLSConfig()
+		if upgradeMode {
+			ln, err := net.Listen("tcp", httpServer.Addr)
 			if err != nil {
-				log.Fatal("Failed to load TLS config:", err)
+				log.Fatal("Failed to listen for TLS upgrade mode:", err)
 			}
-
-			server := &http.Server{
-				Addr:      addr,
-				Handler:   mux,
-				TLSConfig: serverTLSConfig,
+			httpListener = upgrade.Listen(ln, serverTLSConfig)
+			httpServer.TLSConfig = serverTLSConfig
+			utils.Info("Weather MCP Server: TLS upgrade mode enabled, serving HTTP and TLS on port %d", httpPort)
+		} else {
+			httpsServer = &http.Server{
+				Addr:        fmt.Sprintf(":%d", tlsPort),
+				Handler:     mux,
+				TLSConfig:   serverTLSConfig,
+				BaseContext: httpx.BaseContext(nil),
 			}
+			mcpserver.ApplyDefaultTimeouts(httpsServer)
+		}
+	}
 
-			if err := server.ListenAndServeTLS("", ""); err != nil {
-				log.Fatal("Failed to start HTTPS server:", err)
-			}
-		}()
+	// A DIAG_ADDR listener keeps /healthz, /readyz, /metrics, and
+	// /debug/pprof/* off the MCP traffic port entirely, so operators can
+	// scrape or profile the server without exposing pprof to MCP clients.
+	var diagServer *http.Server
+	if diagAddr := os.Getenv("DIAG_ADDR"); diagAddr != "" {
+		diagServer = &http.Server{
+			Addr:        diagAddr,
+			Handler:     diag.NewMux(readiness.Check),
+			BaseContext: httpx.BaseContext(nil),
+		}
+		mcpserver.ApplyDefaultTimeouts(diagServer)
+		utils.Info("Weather MCP Server diagnostics listening on %s", diagAddr)
 	}
 
+	metrics.RecordStartupRSSDelta(baselineRSS)
 	utils.Info("Weather MCP Server started with official SDK StreamableHTTPHandler")
 
-	// Keep the main goroutine alive
-	select {}
-}
\ No newline at end of file
+	if err := mcpserver.Run(context.Background(),
+		mcpserver.NamedServer{Name: "HTTP", Server: httpServer, Listener: httpListener},
+		mcpserver.NamedServer{Name: "HTTPS", Server: httpsServer},
+		mcpserver.NamedServer{Name: "Diag", Server: diagServer},
+	); err != nil {
+		log.Fatal("Server error:", err)
+	}
+}
@@ -4,23 +4,51 @@ package client
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/steve/llm-agents/internal/config"
 	"github.com/steve/llm-agents/internal/mcp/transport"
 	"github.com/steve/llm-agents/internal/models"
+	mcptls "github.com/steve/llm-agents/internal/tls"
 	"github.com/steve/llm-agents/internal/utils"
 )
 
 // MCPClient represents an MCP client using official SDK with custom transport
 type MCPClient struct {
-	client    *mcp.Client
-	session   *mcp.ClientSession
-	transport *transport.HTTPSSETransport
-	serverURL string
-	useTLS    bool
-	tlsConfig *config.TLSConfig
+	client       *mcp.Client
+	session      *mcp.ClientSession
+	transport    *transport.HTTPSSETransport
+	serverURL    string
+	useTLS       bool
+	tlsConfig    *config.TLSConfig
+	certReloader *mcptls.CertReloader
+
+	retryPolicy       RetryPolicy
+	healthCheckStopCh chan struct{}
+	toolSchemas       map[string]*mcp.Tool
+	mu                sync.Mutex
+}
+
+// singleAttemptRetryPolicy preserves the historical all-or-nothing
+// behavior for clients created without an explicit RetryPolicy.
+var singleAttemptRetryPolicy = RetryPolicy{
+	MaxAttempts:     1,
+	InitialInterval: 0,
+	MaxInterval:     0,
+	Multiplier:      1,
+	Jitter:          0,
+	Timeout:         time.Hour,
+}
+
+// effectiveRetryPolicy returns the client's configured policy, or a
+// single-attempt policy if none was set.
+func (c *MCPClient) effectiveRetryPolicy() RetryPolicy {
+	if c.retryPolicy.MaxAttempts > 0 {
+		return c.retryPolicy
+	}
+	return singleAttemptRetryPolicy
 }
 
 // NewMCPClient creates a new MCP client using official SDK
@@ -62,220 +90,228 @@ func NewTLSMCPClient(serverURL string, timeout time.Duration, tlsConfig *config.
 		Version: "v1.0.0",
 	}, nil)
 
-	// Create custom HTTP/SSE transport with TLS
-	mcpTransport := transport.NewClientTransport(serverURL, tlsConfig)
+	// Start a CertReloader so rotated CA/client certificates on disk are
+	// picked up without restarting this process, then build the transport's
+	// TLS configuration from it rather than a static snapshot.
+	reloader, err := mcptls.NewCertReloader(tlsConfig, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start certificate reloader: %w", err)
+	}
+
+	mcpTransport := transport.NewClientTransportWithReloader(serverURL, tlsConfig, reloader)
 
 	mcpClient := &MCPClient{
-		client:    client,
-		transport: mcpTransport,
-		serverURL: serverURL,
-		useTLS:    true,
-		tlsConfig: tlsConfig,
+		client:       client,
+		transport:    mcpTransport,
+		serverURL:    serverURL,
+		useTLS:       true,
+		tlsConfig:    tlsConfig,
+		certReloader: reloader,
 	}
 
-	utils.Info("TLS MCP client created for %s with mTLS enabled", serverURL)
+	utils.Info("TLS MCP client created for %s with hot-reloadable mTLS enabled", serverURL)
 	return mcpClient, nil
 }
 
-// Initialize initializes the MCP client connection
-func (c *MCPClient) Initialize(ctx context.Context) error {
-	utils.Info("Initializing MCP client connection to %s", c.serverURL)
-
-	// Connect to server using custom transport
-	session, err := c.client.Connect(ctx, c.transport, nil)
+// NewMCPClientWithRetry creates a new non-TLS MCP client that transparently
+// retries transient failures in Initialize/ensureConnected and the
+// CallWeather/CallDateTime/CallEcho/TestConnection methods according to
+// policy, and runs a background health check that reconnects a dead
+// session before the next tool call needs it.
+func NewMCPClientWithRetry(serverURL string, timeout time.Duration, policy RetryPolicy) (*MCPClient, error) {
+	mcpClient, err := NewMCPClient(serverURL, timeout)
 	if err != nil {
-		return fmt.Errorf("failed to connect to MCP server: %w", err)
+		return nil, err
 	}
 
-	c.session = session
-	utils.Info("MCP client connected successfully")
-	return nil
-}
+	mcpClient.retryPolicy = policy
+	mcpClient.startHealthCheck(30 * time.Second)
 
-// ensureConnected ensures the client is connected
-func (c *MCPClient) ensureConnected(ctx context.Context) error {
-	if c.session == nil {
-		return c.Initialize(ctx)
-	}
-	return nil
+	return mcpClient, nil
 }
 
-// CallWeather makes a call to the weather MCP server using official SDK
-func (c *MCPClient) CallWeather(ctx context.Context, city string) (*models.TemperatureData, error) {
-	if err := c.ensureConnected(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ensure connection: %w", err)
-	}
-
-	utils.Info("Calling weather MCP server: getTemperature for city %s", city)
-
-	// Call tool using official SDK with correct parameter structure
-	toolParams := &mcp.CallToolParams{
-		Name: "getTemperature",
-		Arguments: map[string]any{
-			"city": city,
-		},
-	}
-
-	toolResult, err := c.session.CallTool(ctx, toolParams)
-	if err != nil {
-		return nil, fmt.Errorf("weather call failed: %w", err)
-	}
-
-	utils.Debug("Weather MCP response: %+v", toolResult)
+// startHealthCheck launches a goroutine that periodically calls ListTools
+// on the current session; a failing health check tears the session down so
+// the next tool call transparently reconnects via ensureConnected.
+func (c *MCPClient) startHealthCheck(interval time.Duration) {
+	c.mu.Lock()
+	if c.healthCheckStopCh != nil {
+		c.mu.Unlock()
+		return
+	}
+	c.healthCheckStopCh = make(chan struct{})
+	stopCh := c.healthCheckStopCh
+	c.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				c.mu.Lock()
+				session := c.session
+				c.mu.Unlock()
+				if session == nil {
+					continue
+				}
+
+				ctx, cancel := context.WithTimeout(context.Background(), interval/2)
+				_, err := session.ListTools(ctx, &mcp.ListToolsParams{})
+				cancel()
+
+				if err != nil {
+					utils.Warn("MCP health check failed for %s, session will reconnect on next call: %v", c.serverURL, err)
+					c.mu.Lock()
+					if c.session == session {
+						c.session = nil
+					}
+					c.mu.Unlock()
+				}
+			}
+		}
+	}()
+}
 
-	// Extract temperature data from result
-	if len(toolResult.Content) == 0 {
-		return nil, fmt.Errorf("no content in weather response")
-	}
+// Initialize initializes the MCP client connection
+func (c *MCPClient) Initialize(ctx context.Context) error {
+	utils.Info("Initializing MCP client connection to %s", c.serverURL)
 
-	// Parse the text content to extract temperature info
-	textContent := ""
-	for _, content := range toolResult.Content {
-		if tc, ok := content.(*mcp.TextContent); ok {
-			textContent = tc.Text
-			break
+	return withRetry(ctx, c.effectiveRetryPolicy(), "MCP Initialize", func(ctx context.Context) error {
+		// Connect to server using custom transport
+		session, err := c.client.Connect(ctx, c.transport, nil)
+		if err != nil {
+			return fmt.Errorf("failed to connect to MCP server: %w", err)
 		}
-	}
-
-	if textContent == "" {
-		return nil, fmt.Errorf("no text content in weather response")
-	}
 
-	// Parse the response text: "Weather in {city}: {temp}°C, {description}"
-	// Example: "Weather in Boston: 23.5°C, Sunny"
-	temperature, description, err := parseWeatherResponse(textContent)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse weather response: %w", err)
-	}
+		c.mu.Lock()
+		c.session = session
+		c.mu.Unlock()
 
-	return &models.TemperatureData{
-		City:        city,
-		Temperature: temperature,
-		Unit:        "°C",
-		Description: description,
-		Timestamp:   time.Now(),
-		Source:      "weather-mcp-streaming",
-	}, nil
+		utils.Info("MCP client connected successfully")
+		return nil
+	})
 }
 
-// CallDateTime makes a call to the datetime MCP server using official SDK
-func (c *MCPClient) CallDateTime(ctx context.Context, city string) (*models.DateTimeData, error) {
-	if err := c.ensureConnected(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ensure connection: %w", err)
-	}
-
-	utils.Info("Calling datetime MCP server: getDateTime for city %s", city)
+// ensureConnected ensures the client is connected, transparently
+// reconnecting (with retry) if the health check or a prior call tore the
+// session down.
+func (c *MCPClient) ensureConnected(ctx context.Context) error {
+	c.mu.Lock()
+	connected := c.session != nil
+	c.mu.Unlock()
 
-	// Call tool using official SDK
-	toolParams := &mcp.CallToolParams{
-		Name: "getDateTime",
-		Arguments: map[string]any{
-			"city": city,
-		},
+	if connected {
+		return nil
 	}
+	return c.Initialize(ctx)
+}
 
-	toolResult, err := c.session.CallTool(ctx, toolParams)
-	if err != nil {
-		return nil, fmt.Errorf("datetime call failed: %w", err)
-	}
+// callTool ensures a connection, invokes the named tool, and retries the
+// whole ensure-connect-call sequence on transient failure. A failed call
+// tears down the session so the next attempt (or the next caller) forces a
+// reconnect instead of retrying against a known-bad session.
+func (c *MCPClient) callTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	var result *mcp.CallToolResult
 
-	utils.Debug("DateTime MCP response: %+v", toolResult)
+	err := withRetry(ctx, c.effectiveRetryPolicy(), fmt.Sprintf("MCP call %s", name), func(ctx context.Context) error {
+		if err := c.ensureConnected(ctx); err != nil {
+			return fmt.Errorf("failed to ensure connection: %w", err)
+		}
 
-	// Extract datetime data from result
-	if len(toolResult.Content) == 0 {
-		return nil, fmt.Errorf("no content in datetime response")
-	}
+		c.mu.Lock()
+		session := c.session
+		c.mu.Unlock()
 
-	// Parse the text content to extract datetime info
-	textContent := ""
-	for _, content := range toolResult.Content {
-		if tc, ok := content.(*mcp.TextContent); ok {
-			textContent = tc.Text
-			break
+		toolResult, err := session.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			c.mu.Lock()
+			if c.session == session {
+				c.session = nil
+			}
+			c.mu.Unlock()
+			return fmt.Errorf("%s call failed: %w", name, err)
 		}
-	}
 
-	if textContent == "" {
-		return nil, fmt.Errorf("no text content in datetime response")
-	}
+		result = toolResult
+		return nil
+	})
+
+	return result, err
+}
 
-	// Parse the response text
-	localTimeStr, timezone, utcOffset, err := parseDateTimeResponse(textContent)
+// CallWeather makes a call to the weather MCP server using official SDK,
+// retrying with backoff when the server reports a retryable mcperrors.Error
+// (e.g. ErrUpstreamTimeout, ErrRateLimited) and returning any other error -
+// including a non-retryable one like ErrCityNotFound - immediately.
+func (c *MCPClient) CallWeather(ctx context.Context, city string) (*models.TemperatureData, error) {
+	utils.Info("Calling weather MCP server: getTemperature for city %s", city)
+
+	data, err := retryWithRetryHint(ctx, c.effectiveRetryPolicy(), "CallWeather", func(ctx context.Context) (*models.TemperatureData, error) {
+		return WeatherTool(ctx, c, city)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse datetime response: %w", err)
+		return nil, err
 	}
 
-	// Parse the local time string
-	localTime, err := time.Parse("2006-01-02 15:04:05", localTimeStr)
+	utils.Debug("Weather MCP response: %+v", data)
+	return data, nil
+}
+
+// CallDateTime makes a call to the datetime MCP server using official SDK,
+// retrying with backoff when the server reports a retryable mcperrors.Error
+// (e.g. ErrUpstreamTimeout, ErrRateLimited) and returning any other error -
+// including a non-retryable one like ErrCityNotFound - immediately. A
+// non-empty timezone bypasses city resolution on the server entirely.
+func (c *MCPClient) CallDateTime(ctx context.Context, city, timezone string) (*models.DateTimeData, error) {
+	utils.Info("Calling datetime MCP server: getDateTime for city %s, timezone %s", city, timezone)
+
+	data, err := retryWithRetryHint(ctx, c.effectiveRetryPolicy(), "CallDateTime", func(ctx context.Context) (*models.DateTimeData, error) {
+		return DateTimeTool(ctx, c, city, timezone)
+	})
 	if err != nil {
-		// If parsing fails, use current time
-		localTime = time.Now()
-		utils.Warn("Failed to parse datetime '%s', using current time: %v", localTimeStr, err)
+		return nil, err
 	}
 
-	return &models.DateTimeData{
-		City:      city,
-		DateTime:  localTime,
-		Timezone:  timezone,
-		UTCOffset: utcOffset,
-		Timestamp: time.Now(),
-	}, nil
+	utils.Debug("DateTime MCP response: %+v", data)
+	return data, nil
 }
 
 // CallEcho makes a call to the echo MCP server using official SDK
 func (c *MCPClient) CallEcho(ctx context.Context, text string) (*models.EchoData, error) {
-	if err := c.ensureConnected(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ensure connection: %w", err)
-	}
-
 	utils.Info("Calling echo MCP server: echo for text %s", text)
 
-	// Call tool using official SDK
-	toolParams := &mcp.CallToolParams{
-		Name: "echo",
-		Arguments: map[string]any{
-			"text": text,
-		},
-	}
-
-	toolResult, err := c.session.CallTool(ctx, toolParams)
+	data, err := EchoTool(ctx, c, text)
 	if err != nil {
-		return nil, fmt.Errorf("echo call failed: %w", err)
-	}
-
-	utils.Debug("Echo MCP response: %+v", toolResult)
-
-	// Extract echo data from result
-	if len(toolResult.Content) == 0 {
-		return nil, fmt.Errorf("no content in echo response")
-	}
-
-	// Parse the text content
-	textContent := ""
-	for _, content := range toolResult.Content {
-		if tc, ok := content.(*mcp.TextContent); ok {
-			textContent = tc.Text
-			break
-		}
-	}
-
-	if textContent == "" {
-		return nil, fmt.Errorf("no text content in echo response")
+		return nil, err
 	}
 
-	return &models.EchoData{
-		OriginalText: text,
-		EchoText:     textContent,
-		Timestamp:    time.Now(),
-	}, nil
+	utils.Debug("Echo MCP response: %+v", data)
+	return data, nil
 }
 
 // Close closes the MCP client connection
 func (c *MCPClient) Close() error {
-	if c.session != nil {
+	c.mu.Lock()
+	if c.healthCheckStopCh != nil {
+		close(c.healthCheckStopCh)
+		c.healthCheckStopCh = nil
+	}
+	session := c.session
+	c.session = nil
+	c.mu.Unlock()
+
+	if session != nil {
 		utils.Debug("Closing MCP client session")
-		c.session.Close()
-		c.session = nil
+		session.Close()
+	}
+	if c.certReloader != nil {
+		if err := c.certReloader.Close(); err != nil {
+			utils.Warn("Failed to stop certificate reloader: %v", err)
+		}
 	}
 	utils.Debug("MCP client closed")
 	return nil
@@ -293,18 +329,28 @@ func (c *MCPClient) GetServerURL() string {
 
 // TestConnection tests the connection to the MCP server
 func (c *MCPClient) TestConnection(ctx context.Context) error {
-	if err := c.ensureConnected(ctx); err != nil {
-		return fmt.Errorf("connection test failed: %w", err)
-	}
+	return withRetry(ctx, c.effectiveRetryPolicy(), "MCP TestConnection", func(ctx context.Context) error {
+		if err := c.ensureConnected(ctx); err != nil {
+			return fmt.Errorf("connection test failed: %w", err)
+		}
 
-	// Test connection by listing tools
-	toolsResult, err := c.session.ListTools(ctx, &mcp.ListToolsParams{})
-	if err != nil {
-		return fmt.Errorf("connection test failed: %w", err)
-	}
+		c.mu.Lock()
+		session := c.session
+		c.mu.Unlock()
 
-	utils.Debug("Connection test successful, found %d tools", len(toolsResult.Tools))
-	return nil
+		toolsResult, err := session.ListTools(ctx, &mcp.ListToolsParams{})
+		if err != nil {
+			c.mu.Lock()
+			if c.session == session {
+				c.session = nil
+			}
+			c.mu.Unlock()
+			return fmt.Errorf("connection test failed: %w", err)
+		}
+
+		utils.Debug("Connection test successful, found %d tools", len(toolsResult.Tools))
+		return nil
+	})
 }
 
 // ListTools lists available tools from the MCP server
@@ -313,124 +359,14 @@ func (c *MCPClient) ListTools(ctx context.Context) ([]*mcp.Tool, error) {
 		return nil, fmt.Errorf("failed to ensure connection: %w", err)
 	}
 
-	toolsResult, err := c.session.ListTools(ctx, &mcp.ListToolsParams{})
+	c.mu.Lock()
+	session := c.session
+	c.mu.Unlock()
+
+	toolsResult, err := session.ListTools(ctx, &mcp.ListToolsParams{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tools: %w", err)
 	}
 
 	return toolsResult.Tools, nil
 }
-
-// parseWeatherResponse parses weather text response
-// Expected format: "Weather in {city}: {temp}°C, {description}"
-func parseWeatherResponse(text string) (float64, string, error) {
-	// Find the temperature value
-	// Look for pattern: {number}°C
-	tempStart := -1
-	tempEnd := -1
-
-	for i := 0; i < len(text)-2; i++ {
-		if text[i:i+2] == "°C" {
-			tempEnd = i
-			// Find start of number (walk backwards)
-			j := i - 1
-			for j >= 0 && (text[j] >= '0' && text[j] <= '9' || text[j] == '.') {
-				j--
-			}
-			tempStart = j + 1
-			break
-		}
-	}
-
-	if tempStart == -1 || tempEnd == -1 {
-		return 0, "", fmt.Errorf("temperature not found in response: %s", text)
-	}
-
-	tempStr := text[tempStart:tempEnd]
-	temperature := 0.0
-	if _, err := fmt.Sscanf(tempStr, "%f", &temperature); err != nil {
-		return 0, "", fmt.Errorf("failed to parse temperature '%s': %w", tempStr, err)
-	}
-
-	// Extract description (everything after "°C, ")
-	description := ""
-	descStart := tempEnd + 4 // Skip "°C, "
-	if descStart < len(text) {
-		description = text[descStart:]
-	}
-
-	return temperature, description, nil
-}
-
-// parseDateTimeResponse parses datetime text response
-// Expected format: "Time in {city}: {time} ({timezone}, UTC{offset})"
-func parseDateTimeResponse(text string) (string, string, string, error) {
-	// Simple parsing for now - extract components from known format
-	// Example: "Time in New York: 2025-10-02 14:30:00 (America/New_York, UTC-05:00)"
-
-	// Find the colon after city
-	colonIdx := -1
-	for i := 0; i < len(text); i++ {
-		if text[i] == ':' {
-			colonIdx = i
-			break
-		}
-	}
-
-	if colonIdx == -1 {
-		return "", "", "", fmt.Errorf("invalid datetime response format: %s", text)
-	}
-
-	// Extract everything after the colon
-	remainder := text[colonIdx+2:] // Skip ": "
-
-	// Find the opening parenthesis
-	parenIdx := -1
-	for i := 0; i < len(remainder); i++ {
-		if remainder[i] == '(' {
-			parenIdx = i
-			break
-		}
-	}
-
-	if parenIdx == -1 {
-		// No timezone info, just return the time
-		return remainder, "Unknown", "+00:00", nil
-	}
-
-	localTime := remainder[:parenIdx-1] // Remove space before paren
-
-	// Extract timezone and offset from parentheses
-	tzInfo := remainder[parenIdx+1 : len(remainder)-1] // Remove ( and )
-
-	// Split by comma
-	parts := []string{}
-	current := ""
-	for _, ch := range tzInfo {
-		if ch == ',' {
-			parts = append(parts, current)
-			current = ""
-		} else if ch != ' ' || len(current) > 0 {
-			current += string(ch)
-		}
-	}
-	if current != "" {
-		parts = append(parts, current)
-	}
-
-	timezone := "Unknown"
-	utcOffset := "+00:00"
-
-	if len(parts) >= 1 {
-		timezone = parts[0]
-	}
-	if len(parts) >= 2 {
-		utcOffset = parts[1]
-		// Remove "UTC" prefix if present
-		if len(utcOffset) > 3 && utcOffset[:3] == "UTC" {
-			utcOffset = utcOffset[3:]
-		}
-	}
-
-	return localTime, timezone, utcOffset, nil
-}
\ No newline at end of file
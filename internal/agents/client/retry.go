@@ -0,0 +1,152 @@
+// Package client provides MCP client functionality using official MCP Go SDK
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/steve/llm-agents/internal/mcperrors"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// RetryPolicy configures retry-with-backoff behavior for MCPClient calls:
+// a maximum number of attempts, an exponential backoff with jitter between
+// attempts, and a total time budget across all attempts.
+type RetryPolicy struct {
+	MaxAttempts     int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	Jitter          float64
+	Timeout         time.Duration
+}
+
+// DefaultRetryPolicy returns a conservative policy suitable for MCP calls
+// against briefly-unavailable servers or dropped SSE streams.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     5,
+		InitialInterval: 200 * time.Millisecond,
+		MaxInterval:     5 * time.Second,
+		Multiplier:      2.0,
+		Jitter:          0.2,
+		Timeout:         30 * time.Second,
+	}
+}
+
+// withRetry runs operation, retrying on error using the exponential
+// backoff-with-jitter schedule in p, until it succeeds, the attempt budget
+// is exhausted, the total timeout elapses, or ctx is done.
+func withRetry(ctx context.Context, p RetryPolicy, description string, operation func(ctx context.Context) error) error {
+	deadline := time.Now().Add(p.Timeout)
+	interval := p.InitialInterval
+
+	var lastErr error
+	for attempt := 1; attempt <= p.MaxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+
+		lastErr = operation(ctx)
+		if lastErr == nil {
+			if attempt > 1 {
+				utils.Info("%s succeeded on attempt %d/%d", description, attempt, p.MaxAttempts)
+			}
+			return nil
+		}
+
+		utils.Warn("%s failed (attempt %d/%d): %v", description, attempt, p.MaxAttempts, lastErr)
+
+		if attempt == p.MaxAttempts || time.Now().Add(interval).After(deadline) {
+			break
+		}
+
+		sleep := jittered(interval, p.Jitter)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sleep):
+		}
+
+		interval = time.Duration(float64(interval) * p.Multiplier)
+		if interval > p.MaxInterval {
+			interval = p.MaxInterval
+		}
+	}
+
+	return lastErr
+}
+
+// retryWithRetryHint runs op, retrying it with p's exponential backoff and
+// jitter only when it fails with a *mcperrors.Error whose Data carries a
+// retryable RetryHint (e.g. ErrUpstreamTimeout, ErrRateLimited); any other
+// error - including a non-retryable *mcperrors.Error such as
+// ErrCityNotFound, or a connection-level error (callTool already retried
+// that according to p) - is returned to the caller immediately instead of
+// being retried or wrapped further.
+func retryWithRetryHint[T any](ctx context.Context, p RetryPolicy, description string, op func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+
+	interval := p.InitialInterval
+	if interval <= 0 {
+		interval = 200 * time.Millisecond
+	}
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+
+		result, err := op(ctx)
+		if err == nil {
+			return result, nil
+		}
+
+		var mcpErr *mcperrors.Error
+		if !errors.As(err, &mcpErr) {
+			return zero, err
+		}
+		hint, ok := mcpErr.RetryHint()
+		if !ok || !hint.Retryable || attempt == maxAttempts {
+			return zero, err
+		}
+
+		sleep := jittered(interval, p.Jitter)
+		if hinted := time.Duration(hint.RetryAfterMs) * time.Millisecond; hinted > sleep {
+			sleep = hinted
+		}
+		utils.Warn("%s: retrying after %s on retryable error (attempt %d/%d): %v", description, sleep, attempt, maxAttempts, err)
+
+		select {
+		case <-ctx.Done():
+			return zero, ctx.Err()
+		case <-time.After(sleep):
+		}
+
+		interval = time.Duration(float64(interval) * p.Multiplier)
+		if p.MaxInterval > 0 && interval > p.MaxInterval {
+			interval = p.MaxInterval
+		}
+	}
+
+	return zero, nil
+}
+
+// jittered returns d adjusted by +/- a random fraction up to jitter (e.g.
+// jitter=0.2 returns a value within 20% of d in either direction).
+func jittered(d time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return d
+	}
+	delta := (rand.Float64()*2 - 1) * jitter
+	return time.Duration(float64(d) * (1 + delta))
+}
@@ -0,0 +1,453 @@
+// Package client provides MCP client functionality using official MCP Go SDK
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/steve/llm-agents/internal/mcperrors"
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// ParserFunc converts a tool's raw text content into a value. CallTool uses
+// a registered ParserFunc as a fallback for tools that return plain text
+// instead of structured content.
+type ParserFunc func(text string) (any, error)
+
+var (
+	parserRegistryMu sync.Mutex
+	parserRegistry   = map[string]ParserFunc{}
+)
+
+// RegisterParser registers a fallback text parser for toolName. CallTool
+// consults it when a tool's response carries no structured content, so
+// callers wiring up a new MCP server don't have to modify this package.
+func RegisterParser(toolName string, parser ParserFunc) {
+	parserRegistryMu.Lock()
+	defer parserRegistryMu.Unlock()
+	parserRegistry[toolName] = parser
+}
+
+func lookupParser(toolName string) (ParserFunc, bool) {
+	parserRegistryMu.Lock()
+	defer parserRegistryMu.Unlock()
+	p, ok := parserRegistry[toolName]
+	return p, ok
+}
+
+func init() {
+	RegisterParser("getTemperature", func(text string) (any, error) {
+		temperature, description, err := parseWeatherResponse(text)
+		if err != nil {
+			return nil, err
+		}
+		return &models.TemperatureData{
+			Temperature: temperature,
+			Unit:        "°C",
+			Description: description,
+			Timestamp:   time.Now(),
+			Source:      "weather-mcp-streaming",
+		}, nil
+	})
+
+	RegisterParser("getDateTime", func(text string) (any, error) {
+		localTimeStr, timezone, utcOffset, err := parseDateTimeResponse(text)
+		if err != nil {
+			return nil, err
+		}
+
+		localTime, err := time.Parse("2006-01-02 15:04:05", localTimeStr)
+		if err != nil {
+			localTime = time.Now()
+			utils.Warn("Failed to parse datetime '%s', using current time: %v", localTimeStr, err)
+		}
+
+		return &models.DateTimeData{
+			DateTime:  localTime,
+			Timezone:  timezone,
+			UTCOffset: utcOffset,
+			Timestamp: time.Now(),
+		}, nil
+	})
+
+	RegisterParser("echo", func(text string) (any, error) {
+		return &models.EchoData{
+			EchoText:  text,
+			Timestamp: time.Now(),
+		}, nil
+	})
+}
+
+// CallTool invokes the named tool on c, validates args against the tool's
+// JSON Schema first, and decodes the response into T. Structured content
+// (mcp.CallToolResult.StructuredContent) is preferred; tools that only
+// return text fall back to the ParserFunc registered for name via
+// RegisterParser.
+//
+// Go does not allow type parameters on methods, so this is a free function
+// taking the client rather than an (*MCPClient) method.
+func CallTool[T any](ctx context.Context, c *MCPClient, name string, args map[string]any) (T, error) {
+	var zero T
+
+	tool, err := c.toolSchema(ctx, name)
+	if err != nil {
+		return zero, fmt.Errorf("failed to resolve tool %s: %w", name, err)
+	}
+	if err := validateArgsAgainstSchema(tool, args); err != nil {
+		return zero, fmt.Errorf("invalid arguments for tool %s: %w", name, err)
+	}
+
+	toolResult, err := c.callTool(ctx, name, args)
+	if err != nil {
+		return zero, err
+	}
+
+	if toolResult.IsError {
+		return zero, toolError(toolResult, name)
+	}
+
+	if toolResult.StructuredContent != nil {
+		raw, err := json.Marshal(toolResult.StructuredContent)
+		if err != nil {
+			return zero, fmt.Errorf("failed to marshal structured content from tool %s: %w", name, err)
+		}
+		var result T
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return zero, fmt.Errorf("failed to decode structured content from tool %s: %w", name, err)
+		}
+		return result, nil
+	}
+
+	text, err := firstTextContent(toolResult)
+	if err != nil {
+		return zero, fmt.Errorf("tool %s: %w", name, err)
+	}
+
+	parser, ok := lookupParser(name)
+	if !ok {
+		return zero, fmt.Errorf("tool %s returned unstructured content and no parser is registered for it via RegisterParser", name)
+	}
+
+	parsed, err := parser(text)
+	if err != nil {
+		return zero, fmt.Errorf("failed to parse response from tool %s: %w", name, err)
+	}
+
+	result, ok := parsed.(T)
+	if !ok {
+		return zero, fmt.Errorf("parser for tool %s produced %T, want %T", name, parsed, zero)
+	}
+	return result, nil
+}
+
+// WeatherTool calls the getTemperature tool via the generic CallTool API.
+func WeatherTool(ctx context.Context, c *MCPClient, city string) (*models.TemperatureData, error) {
+	data, err := CallTool[*models.TemperatureData](ctx, c, "getTemperature", map[string]any{"city": city})
+	if err != nil {
+		return nil, err
+	}
+	data.City = city
+	return data, nil
+}
+
+// DateTimeTool calls the getDateTime tool via the generic CallTool API. A
+// non-empty timezone bypasses city resolution on the server entirely, so
+// city may be empty when timezone is set.
+func DateTimeTool(ctx context.Context, c *MCPClient, city, timezone string) (*models.DateTimeData, error) {
+	args := map[string]any{"city": city}
+	if timezone != "" {
+		args["timezone"] = timezone
+	}
+
+	data, err := CallTool[*models.DateTimeData](ctx, c, "getDateTime", args)
+	if err != nil {
+		return nil, err
+	}
+	data.City = city
+	return data, nil
+}
+
+// EchoTool calls the echo tool via the generic CallTool API.
+func EchoTool(ctx context.Context, c *MCPClient, text string) (*models.EchoData, error) {
+	data, err := CallTool[*models.EchoData](ctx, c, "echo", map[string]any{"text": text})
+	if err != nil {
+		return nil, err
+	}
+	data.OriginalText = text
+	return data, nil
+}
+
+// toolError builds the error for an IsError tool result: a server that
+// speaks the shared mcperrors vocabulary encodes its *mcperrors.Error as
+// the JSON text of the result's first content block, so a caller can tell
+// a retryable failure (e.g. ErrUpstreamTimeout, ErrRateLimited) from a
+// permanent one (e.g. ErrCityNotFound) with errors.As instead of matching
+// the message text. A server that hasn't been updated to do so just gets
+// its plain text wrapped in a non-retryable error, same as before this
+// vocabulary existed.
+func toolError(result *mcp.CallToolResult, name string) error {
+	text, err := firstTextContent(result)
+	if err != nil {
+		return fmt.Errorf("tool %s failed", name)
+	}
+
+	var mcpErr mcperrors.Error
+	if err := json.Unmarshal([]byte(text), &mcpErr); err == nil && mcpErr.Message != "" {
+		return &mcpErr
+	}
+
+	return fmt.Errorf("tool %s failed: %s", name, text)
+}
+
+// firstTextContent returns the text of the first mcp.TextContent block in
+// result, for tools that haven't been updated to return structured content.
+func firstTextContent(result *mcp.CallToolResult) (string, error) {
+	if len(result.Content) == 0 {
+		return "", fmt.Errorf("no content in response")
+	}
+	for _, content := range result.Content {
+		if tc, ok := content.(*mcp.TextContent); ok {
+			return tc.Text, nil
+		}
+	}
+	return "", fmt.Errorf("no text content in response")
+}
+
+// toolSchema returns the tool named name from the server's ListTools
+// response, caching results on c so repeated calls don't re-list.
+func (c *MCPClient) toolSchema(ctx context.Context, name string) (*mcp.Tool, error) {
+	c.mu.Lock()
+	if c.toolSchemas != nil {
+		if t, ok := c.toolSchemas[name]; ok {
+			c.mu.Unlock()
+			return t, nil
+		}
+	}
+	c.mu.Unlock()
+
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.toolSchemas == nil {
+		c.toolSchemas = make(map[string]*mcp.Tool, len(tools))
+	}
+	for _, t := range tools {
+		c.toolSchemas[t.Name] = t
+	}
+	tool, ok := c.toolSchemas[name]
+	c.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("tool %q not found on MCP server %s", name, c.serverURL)
+	}
+	return tool, nil
+}
+
+// schemaProperty is the subset of a JSON Schema property this package
+// validates against: its declared type.
+type schemaProperty struct {
+	Type string `json:"type,omitempty"`
+}
+
+// toolInputSchema is the subset of a JSON Schema object this package reads
+// off a tool's InputSchema to validate call arguments before sending them.
+type toolInputSchema struct {
+	Required   []string                  `json:"required,omitempty"`
+	Properties map[string]schemaProperty `json:"properties,omitempty"`
+}
+
+// validateArgsAgainstSchema checks that args satisfies tool's InputSchema:
+// every required property present, and every recognized property's value
+// matching its declared JSON Schema type.
+func validateArgsAgainstSchema(tool *mcp.Tool, args map[string]any) error {
+	if tool.InputSchema == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(tool.InputSchema)
+	if err != nil {
+		return fmt.Errorf("failed to inspect schema for tool %s: %w", tool.Name, err)
+	}
+
+	var schema toolInputSchema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return fmt.Errorf("failed to parse schema for tool %s: %w", tool.Name, err)
+	}
+
+	for _, req := range schema.Required {
+		if _, ok := args[req]; !ok {
+			return fmt.Errorf("missing required argument %q", req)
+		}
+	}
+
+	for argName, argVal := range args {
+		prop, ok := schema.Properties[argName]
+		if !ok || prop.Type == "" {
+			continue
+		}
+		if !jsonTypeMatches(prop.Type, argVal) {
+			return fmt.Errorf("argument %q: expected type %s, got %T", argName, prop.Type, argVal)
+		}
+	}
+
+	return nil
+}
+
+// jsonTypeMatches reports whether v is a valid Go representation of the
+// given JSON Schema primitive type.
+func jsonTypeMatches(schemaType string, v any) bool {
+	switch schemaType {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		switch v.(type) {
+		case float32, float64, int, int32, int64:
+			return true
+		default:
+			return false
+		}
+	case "integer":
+		switch n := v.(type) {
+		case int, int32, int64:
+			return true
+		case float64:
+			return n == float64(int64(n))
+		default:
+			return false
+		}
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	default:
+		return true
+	}
+}
+
+// parseWeatherResponse parses weather text response
+// Expected format: "Weather in {city}: {temp}°C, {description}"
+func parseWeatherResponse(text string) (float64, string, error) {
+	// Find the temperature value
+	// Look for pattern: {number}°C
+	tempStart := -1
+	tempEnd := -1
+
+	for i := 0; i+len("°C") <= len(text); i++ {
+		if text[i:i+len("°C")] == "°C" {
+			tempEnd = i
+			// Find start of number (walk backwards)
+			j := i - 1
+			for j >= 0 && (text[j] >= '0' && text[j] <= '9' || text[j] == '.') {
+				j--
+			}
+			tempStart = j + 1
+			break
+		}
+	}
+
+	if tempStart == -1 || tempEnd == -1 {
+		return 0, "", fmt.Errorf("temperature not found in response: %s", text)
+	}
+
+	tempStr := text[tempStart:tempEnd]
+	temperature := 0.0
+	if _, err := fmt.Sscanf(tempStr, "%f", &temperature); err != nil {
+		return 0, "", fmt.Errorf("failed to parse temperature '%s': %w", tempStr, err)
+	}
+
+	// Extract description (everything after "°C, ")
+	description := ""
+	descStart := tempEnd + len("°C, ") // Skip "°C, "
+	if descStart < len(text) {
+		description = text[descStart:]
+	}
+
+	return temperature, description, nil
+}
+
+// parseDateTimeResponse parses datetime text response
+// Expected format: "Time in {city}: {time} ({timezone}, UTC{offset})"
+func parseDateTimeResponse(text string) (string, string, string, error) {
+	// Simple parsing for now - extract components from known format
+	// Example: "Time in New York: 2025-10-02 14:30:00 (America/New_York, UTC-05:00)"
+
+	// Find the colon after city
+	colonIdx := -1
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' {
+			colonIdx = i
+			break
+		}
+	}
+
+	if colonIdx == -1 {
+		return "", "", "", fmt.Errorf("invalid datetime response format: %s", text)
+	}
+
+	// Extract everything after the colon
+	remainder := text[colonIdx+2:] // Skip ": "
+
+	// Find the opening parenthesis
+	parenIdx := -1
+	for i := 0; i < len(remainder); i++ {
+		if remainder[i] == '(' {
+			parenIdx = i
+			break
+		}
+	}
+
+	if parenIdx == -1 {
+		// No timezone info, just return the time
+		return remainder, "Unknown", "+00:00", nil
+	}
+
+	localTime := remainder[:parenIdx-1] // Remove space before paren
+
+	// Extract timezone and offset from parentheses
+	tzInfo := remainder[parenIdx+1 : len(remainder)-1] // Remove ( and )
+
+	// Split by comma
+	parts := []string{}
+	current := ""
+	for _, ch := range tzInfo {
+		if ch == ',' {
+			parts = append(parts, current)
+			current = ""
+		} else if ch != ' ' || len(current) > 0 {
+			current += string(ch)
+		}
+	}
+	if current != "" {
+		parts = append(parts, current)
+	}
+
+	timezone := "Unknown"
+	utcOffset := "+00:00"
+
+	if len(parts) >= 1 {
+		timezone = parts[0]
+	}
+	if len(parts) >= 2 {
+		utcOffset = parts[1]
+		// Remove "UTC" prefix if present
+		if len(utcOffset) > 3 && utcOffset[:3] == "UTC" {
+			utcOffset = utcOffset[3:]
+		}
+	}
+
+	return localTime, timezone, utcOffset, nil
+}
@@ -3,78 +3,286 @@ package coordinator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/steve/llm-agents/internal/agents/datetime"
 	"github.com/steve/llm-agents/internal/agents/echo"
+	"github.com/steve/llm-agents/internal/agents/forecast"
 	"github.com/steve/llm-agents/internal/agents/temperature"
+	"github.com/steve/llm-agents/internal/cache"
+	"github.com/steve/llm-agents/internal/httpx"
+	"github.com/steve/llm-agents/internal/llm"
 	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/observability"
+	"github.com/steve/llm-agents/internal/resilience"
+	mcptls "github.com/steve/llm-agents/internal/tls"
 	"github.com/steve/llm-agents/internal/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Coordinator implements the main coordinator agent
 type Coordinator struct {
-	llmClient        *LLMClient
-	temperatureAgent *temperature.Agent
-	datetimeAgent    *datetime.Agent
-	echoAgent        *echo.Agent
-	requestCounter   int64
-	mu               sync.RWMutex
+	// planner generates the orchestration plan for a query - defaults to
+	// an llm.OpenRouterPlanner, overridable via WithPlanner (e.g. to
+	// select a different provider, or a fake Planner in tests).
+	planner llm.Planner
+	// agents is the registry executeTask and Describe dispatch through -
+	// see registry.go. NewCoordinator/NewCoordinatorTLS pre-populate it
+	// with the four built-in agent types; RegisterAgent adds or replaces
+	// entries, including types the coordinator package doesn't define.
+	agents         map[models.AgentType]SubAgent
+	responseCache  *cache.ResponseCache
+	tracker        *cache.HitTracker
+	requestCounter int64
+	mu             sync.RWMutex
+
+	// certRenewer/certMonitor/clientCertPath/certEvents/cancelCertRotation
+	// are only set on a Coordinator built via NewCoordinatorTLS - see
+	// tls.go. They manage the client certificate every sub-agent's
+	// MCPClient presents to its MCP server.
+	certRenewer        *mcptls.CertRenewer
+	certMonitor        *mcptls.RenewalMonitor
+	clientCertPath     string
+	certEvents         chan Event
+	cancelCertRotation context.CancelFunc
+
+	// agentExecutors applies a per-AgentType retry/backoff policy and
+	// circuit breaker around executeTask's dispatch, on top of (and
+	// independent from) each sub-agent's own internal resilience.Executor
+	// around its MCP client call - see resilience.go. metrics records what
+	// that wrapping observed.
+	agentExecutors map[models.AgentType]*resilience.Executor
+	metrics        *CoordinatorMetrics
+
+	// maxParallelism bounds how many AgentTasks executeDAG dispatches to
+	// their sub-agents at once; zero (the default) leaves it unbounded, one
+	// goroutine per ready task, the same as executeParallel. Set via
+	// WithMaxParallelism.
+	maxParallelism int
+
+	// tracer/meter/queryCounter default to OpenTelemetry's no-op
+	// implementations and are only backed by a real exporter once built
+	// with WithTracerProvider/WithMeterProvider - see tracing.go.
+	tracer       trace.Tracer
+	meter        metric.Meter
+	queryCounter metric.Int64Counter
 }
 
-// NewCoordinator creates a new coordinator agent
-func NewCoordinator(openRouterAPIKey, weatherServerURL, datetimeServerURL, echoServerURL string, timeout time.Duration) *Coordinator {
-	return &Coordinator{
-		llmClient:        NewLLMClient(openRouterAPIKey),
-		temperatureAgent: temperature.NewAgent(weatherServerURL, timeout),
-		datetimeAgent:    datetime.NewAgent(datetimeServerURL, timeout),
-		echoAgent:        echo.NewAgent(echoServerURL, timeout),
-		requestCounter:   0,
+// NewCoordinator creates a new coordinator agent. policy governs the
+// retry/backoff, rate limiting, and circuit breaking the temperature,
+// echo, and forecast sub-agents apply to their MCP calls. cacheTTL bounds
+// how long a warmed response stays in the response cache; hitStorePath, if
+// set, persists the (city, intent) hit tracker a Prefetcher reads to
+// disk (see internal/cache and Coordinator.Warm). opts can override the
+// coordinator-level resilience policy executeTask applies per AgentType
+// (see WithAgentPolicy); every AgentType defaults to
+// resilience.DefaultPolicy.
+func NewCoordinator(openRouterAPIKey, weatherServerURL, datetimeServerURL, echoServerURL, forecastServerURL string, timeout time.Duration, policy resilience.Policy, cacheTTL time.Duration, hitStorePath string, opts ...CoordinatorOption) *Coordinator {
+	c := &Coordinator{
+		planner: llm.NewOpenRouterPlanner(llm.Config{APIKey: openRouterAPIKey}),
+		agents: map[models.AgentType]SubAgent{
+			models.AgentTypeTemperature: temperature.NewAgent(weatherServerURL, timeout, policy),
+			models.AgentTypeDateTime:    datetime.NewAgent(datetimeServerURL, timeout),
+			models.AgentTypeEcho:        echo.NewAgent(echoServerURL, timeout, policy),
+			models.AgentTypeForecast:    forecast.NewAgent(forecastServerURL, timeout, policy),
+		},
+		responseCache:  cache.NewResponseCache(cacheTTL),
+		tracker:        cache.NewHitTracker(hitStorePath),
+		requestCounter: 0,
+		agentExecutors: newDefaultAgentExecutors(),
+		metrics:        newCoordinatorMetrics(),
+		tracer:         observability.Tracer(nil),
+		meter:          observability.Meter(nil),
 	}
+	c.queryCounter = newQueryCounter(c.meter)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Metrics returns the attempt/retry/breaker counters executeTask has
+// recorded per AgentType since this Coordinator was created.
+func (c *Coordinator) Metrics() *CoordinatorMetrics {
+	return c.metrics
 }
 
 // ProcessQuery processes a user query and coordinates sub-agents
 func (c *Coordinator) ProcessQuery(ctx context.Context, query models.Query) (*models.QueryResponse, error) {
+	return c.ProcessQueryWithContext(ctx, query, nil)
+}
+
+// ProcessQueryWithContext is ProcessQuery, plus a rolling conversation
+// history (oldest first) the orchestration plan is generated against, so a
+// follow-up query like "and the weather there?" resolves against a city or
+// intent a prior turn already established. A nil or empty history behaves
+// exactly like ProcessQuery.
+//
+// Before running the orchestration plan, it checks the response cache for
+// the query's (city, intent) key; on a hit it returns the cached response
+// immediately, with "served from prefetch cache" appended to
+// OrchestrationLog, instead of paying MCP + LLM latency again. Every
+// cacheable query's access is also recorded in the hit tracker, so a
+// Prefetcher knows which (city, intent) tuples are hot enough to keep warm.
+func (c *Coordinator) ProcessQueryWithContext(ctx context.Context, query models.Query, history []models.ConversationTurn) (result *models.QueryResponse, err error) {
 	utils.Info("Coordinator processing query: %s (city: %s)", query.Text, query.City)
 
-	// Generate orchestration plan using LLM
-	plan, err := c.llmClient.GenerateOrchestrationPlan(ctx, query)
+	ctx, span := c.startSpan(ctx, "coordinator.process_query", query.ID, "")
+	defer func() { endSpan(span, err) }()
+
+	observability.IncQueryCount()
+	c.queryCounter.Add(ctx, 1)
+
+	intent := classifyIntent(query.Text)
+	key := cache.NewQueryKey(query.City, intent)
+	cacheable := isCacheableIntent(intent) && query.City != ""
+
+	if cacheable {
+		c.tracker.Record(key)
+
+		if cached, ok := c.responseCache.Get(key); ok {
+			utils.Info("Serving query %s from prefetch cache (city: %s)", query.ID, query.City)
+			response := *cached
+			response.QueryID = query.ID
+			response.OrchestrationLog = append(append([]string{}, cached.OrchestrationLog...), "served from prefetch cache")
+			return &response, nil
+		}
+	}
+
+	queryResponse, err := c.processQueryUncached(ctx, query, history)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate orchestration plan: %w", err)
+		return nil, err
 	}
 
-	utils.Info("Orchestration plan: %s strategy with %d tasks", plan.Strategy, len(plan.Tasks))
-	utils.Debug("Plan reasoning: %s", plan.Reasoning)
+	if cacheable {
+		c.responseCache.Set(key, queryResponse)
+	}
+
+	return queryResponse, nil
+}
 
-	// Execute the orchestration plan
-	responses, err := c.executePlan(ctx, plan)
+// processQueryUncached runs the full orchestration pipeline for query - LLM
+// plan generation, execution, and response assembly - skipping the
+// response cache check ProcessQueryWithContext does, so Warm can refresh a
+// hot cache entry even while the last one is still within its TTL. It's
+// implemented on top of ProcessQueryStream, draining the stream down to its
+// terminal EventQueryCompleted instead of duplicating the orchestration
+// pipeline.
+func (c *Coordinator) processQueryUncached(ctx context.Context, query models.Query, history []models.ConversationTurn) (*models.QueryResponse, error) {
+	events := c.processQueryStreamWithHistory(ctx, query, history)
+
+	for event := range events {
+		if event.Type != models.EventQueryCompleted {
+			continue
+		}
+		if event.Error != "" {
+			return nil, errors.New(event.Error)
+		}
+		utils.Info("Query processed successfully, invoked agents: %v", event.Result.InvokedAgents)
+		return event.Result, nil
+	}
+
+	return nil, fmt.Errorf("query stream closed without a completion event")
+}
+
+// HotKeys returns the top n hottest (city, intent) keys from the hit
+// tracker, for a Prefetcher's periodic cache-warming pass.
+func (c *Coordinator) HotKeys(n int) []cache.QueryKey {
+	return c.tracker.TopK(n)
+}
+
+// Warm re-executes the query for key's (city, intent) tuple, bypassing the
+// response cache check ProcessQuery does, and stores the fresh result back
+// into the cache. It's a Prefetcher's hook for keeping a hot query's
+// TemperatureData/DateTimeData/ForecastData response warm ahead of the
+// next real request.
+func (c *Coordinator) Warm(ctx context.Context, key cache.QueryKey) error {
+	query := models.Query{
+		ID:        "prefetch-" + key.String(),
+		Text:      intentQueryText(key.Intent),
+		City:      key.City,
+		Timestamp: time.Now(),
+	}
+
+	response, err := c.processQueryUncached(ctx, query, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute orchestration plan: %w", err)
+		return err
 	}
 
-	// Build the final response
-	queryResponse := c.buildQueryResponse(query, plan, responses)
+	c.responseCache.Set(key, response)
+	return nil
+}
 
-	utils.Info("Query processed successfully, invoked agents: %v", queryResponse.InvokedAgents)
-	return queryResponse, nil
+// executePlan executes the orchestration plan. emit, if non-nil, receives
+// a TaskStarted/TaskCompleted/TaskFailed QueryEvent around every task
+// dispatch, for ProcessQueryStream; callers that only want the final
+// responses (e.g. processQueryUncached, by way of the stream itself) pass
+// nil.
+func (c *Coordinator) executePlan(ctx context.Context, plan *models.OrchestrationPlan, emit func(models.QueryEvent)) ([]*models.AgentResponse, error) {
+	return c.executePlanWithPrestarted(ctx, plan, emit, nil)
 }
 
-// executePlan executes the orchestration plan
-func (c *Coordinator) executePlan(ctx context.Context, plan *models.OrchestrationPlan) ([]*models.AgentResponse, error) {
+// prestartedResult is the outcome of a task runStreamingPlan already
+// dispatched before the rest of the orchestration plan finished streaming
+// in from the LLM - see StreamingPlanner.
+type prestartedResult struct {
+	response *models.AgentResponse
+	err      error
+}
+
+// executePlanWithPrestarted is executePlan, but for every task_id present
+// in prestarted, executeParallel/executeDAG use that already-available
+// result instead of dispatching the task again - see
+// Coordinator.runStreamingPlan, which only ever prestarts a task with no
+// DependsOn, so executeSequential (whose ordering prestarting would
+// violate) never receives a non-empty prestarted map.
+func (c *Coordinator) executePlanWithPrestarted(ctx context.Context, plan *models.OrchestrationPlan, emit func(models.QueryEvent), prestarted map[string]*prestartedResult) (responses []*models.AgentResponse, err error) {
+	observability.ObservePlanStrategy(string(plan.Strategy))
+
+	ctx, span := c.startSpan(ctx, "coordinator.execute_plan", plan.QueryID, "")
+	span.SetAttributes(attribute.String("strategy", string(plan.Strategy)), attribute.Int("task_count", len(plan.Tasks)))
+	defer func() { endSpan(span, err) }()
+
 	switch plan.Strategy {
 	case models.ExecutionParallel:
-		return c.executeParallel(ctx, plan.Tasks)
+		return c.executeParallel(ctx, plan.Tasks, emit, prestarted)
 	case models.ExecutionSequential:
-		return c.executeSequential(ctx, plan.Tasks)
+		return c.executeSequential(ctx, plan.Tasks, emit)
+	case models.ExecutionDAG:
+		return c.executeDAG(ctx, plan.Tasks, emit, prestarted)
 	default:
 		return nil, fmt.Errorf("unsupported execution strategy: %s", plan.Strategy)
 	}
 }
 
-// executeParallel executes tasks in parallel
-func (c *Coordinator) executeParallel(ctx context.Context, tasks []models.AgentTask) ([]*models.AgentResponse, error) {
+// emitTaskEvent sends a task-scoped QueryEvent through emit, a no-op if
+// emit is nil (the non-streaming execution path).
+func emitTaskEvent(emit func(models.QueryEvent), eventType models.QueryEventType, task models.AgentTask, response *models.AgentResponse, err error) {
+	if emit == nil {
+		return
+	}
+	event := models.QueryEvent{Type: eventType, Task: &task, Response: response}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	emit(event)
+}
+
+// executeParallel executes tasks in parallel. A task whose task_id is a
+// key in prestarted (possibly nil/empty) already has a result - from
+// runStreamingPlan dispatching it eagerly while the rest of the plan was
+// still streaming in - so it's folded into the results without being
+// dispatched or emitted again.
+func (c *Coordinator) executeParallel(ctx context.Context, tasks []models.AgentTask, emit func(models.QueryEvent), prestarted map[string]*prestartedResult) ([]*models.AgentResponse, error) {
 	utils.Debug("Executing %d tasks in parallel", len(tasks))
 
 	type result struct {
@@ -92,7 +300,18 @@ func (c *Coordinator) executeParallel(ctx context.Context, tasks []models.AgentT
 		go func(taskIndex int, t models.AgentTask) {
 			defer wg.Done()
 
+			if pre, ok := prestarted[t.TaskID]; ok {
+				resultChan <- result{response: pre.response, err: pre.err, index: taskIndex}
+				return
+			}
+
+			emitTaskEvent(emit, models.EventTaskStarted, t, nil, nil)
 			response, err := c.executeTask(ctx, t)
+			if err != nil {
+				emitTaskEvent(emit, models.EventTaskFailed, t, response, err)
+			} else {
+				emitTaskEvent(emit, models.EventTaskCompleted, t, response, nil)
+			}
 			resultChan <- result{
 				response: response,
 				err:      err,
@@ -127,24 +346,305 @@ func (c *Coordinator) executeParallel(ctx context.Context, tasks []models.AgentT
 }
 
 // executeSequential executes tasks sequentially
-func (c *Coordinator) executeSequential(ctx context.Context, tasks []models.AgentTask) ([]*models.AgentResponse, error) {
+func (c *Coordinator) executeSequential(ctx context.Context, tasks []models.AgentTask, emit func(models.QueryEvent)) ([]*models.AgentResponse, error) {
 	utils.Debug("Executing %d tasks sequentially", len(tasks))
 
 	responses := make([]*models.AgentResponse, len(tasks))
 
 	for i, task := range tasks {
+		emitTaskEvent(emit, models.EventTaskStarted, task, nil, nil)
 		response, err := c.executeTask(ctx, task)
 		if err != nil {
+			emitTaskEvent(emit, models.EventTaskFailed, task, response, err)
 			return responses, fmt.Errorf("task %d failed: %w", i, err)
 		}
+		emitTaskEvent(emit, models.EventTaskCompleted, task, response, nil)
 		responses[i] = response
 	}
 
 	return responses, nil
 }
 
+// executeDAG executes tasks as a dependency graph instead of a flat list:
+// each task waits for the task IDs in its DependsOn to finish, resolves its
+// InputBindings against their responses, and only then dispatches. Branches
+// with no shared dependencies run concurrently, bounded by c.maxParallelism
+// in-flight executeTask calls at a time (see WithMaxParallelism) -
+// unbounded when left zero. A task whose DependsOn includes one that
+// failed is never dispatched: it's marked with a synthesized "skipped"
+// AgentResponse instead, so a sibling branch sharing no dependency with the
+// failure still runs to completion rather than being cancelled along with
+// it. The first error from either a failed task or a skip is returned
+// alongside however many responses did complete. A task whose task_id is a
+// key in prestarted (possibly nil/empty) already has a result -
+// runStreamingPlan only ever prestarts a task with no DependsOn, so it's
+// always safe to fold straight into responses without waiting on anything
+// or dispatching it again.
+func (c *Coordinator) executeDAG(ctx context.Context, tasks []models.AgentTask, emit func(models.QueryEvent), prestarted map[string]*prestartedResult) ([]*models.AgentResponse, error) {
+	utils.Debug("Executing %d tasks as a DAG", len(tasks))
+
+	byID := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		if task.TaskID == "" {
+			return nil, fmt.Errorf("dag task %d is missing a task_id", i)
+		}
+		if _, dup := byID[task.TaskID]; dup {
+			return nil, fmt.Errorf("duplicate task_id %q in dag plan", task.TaskID)
+		}
+		byID[task.TaskID] = i
+	}
+	for _, task := range tasks {
+		for _, dep := range task.DependsOn {
+			if _, ok := byID[dep]; !ok {
+				return nil, fmt.Errorf("task %q depends on unknown task_id %q", task.TaskID, dep)
+			}
+		}
+	}
+	if cycleAt := detectDAGCycle(tasks, byID); cycleAt != "" {
+		return nil, fmt.Errorf("dag plan has a dependency cycle at task %q", cycleAt)
+	}
+
+	responses := make([]*models.AgentResponse, len(tasks))
+	done := make([]chan struct{}, len(tasks))
+	for i := range tasks {
+		done[i] = make(chan struct{})
+	}
+
+	var sem chan struct{}
+	if c.maxParallelism > 0 {
+		sem = make(chan struct{}, c.maxParallelism)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	recordErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	for i, task := range tasks {
+		wg.Add(1)
+		go func(i int, task models.AgentTask) {
+			defer wg.Done()
+			defer close(done[i])
+
+			for _, dep := range task.DependsOn {
+				select {
+				case <-done[byID[dep]]:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			if pre, ok := prestarted[task.TaskID]; ok {
+				responses[i] = pre.response
+				if pre.err != nil {
+					utils.Error("DAG task %q failed: %v", task.TaskID, pre.err)
+					recordErr(pre.err)
+				}
+				return
+			}
+
+			if failedDep, ok := firstFailedDependency(task, responses, byID); ok {
+				err := fmt.Errorf("task %q: skipped because dependency %q did not complete successfully", task.TaskID, failedDep)
+				response := &models.AgentResponse{
+					TaskID:  task.TaskID,
+					Success: false,
+					Error:   err.Error(),
+				}
+				responses[i] = response
+				emitTaskEvent(emit, models.EventTaskFailed, task, response, err)
+				recordErr(err)
+				return
+			}
+
+			resolved, err := resolveInputBindings(task, responses, byID)
+			if err != nil {
+				emitTaskEvent(emit, models.EventTaskFailed, task, nil, err)
+				recordErr(err)
+				return
+			}
+
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			emitTaskEvent(emit, models.EventTaskStarted, resolved, nil, nil)
+			response, err := c.executeTask(ctx, resolved)
+			responses[i] = response
+			if err != nil {
+				utils.Error("DAG task %q failed: %v", task.TaskID, err)
+				emitTaskEvent(emit, models.EventTaskFailed, resolved, response, err)
+				recordErr(err)
+			} else {
+				emitTaskEvent(emit, models.EventTaskCompleted, resolved, response, nil)
+			}
+		}(i, task)
+	}
+
+	wg.Wait()
+
+	return responses, firstErr
+}
+
+// firstFailedDependency reports the task_id of the first entry in
+// task.DependsOn whose response is missing or unsuccessful, so its caller
+// can skip task instead of dispatching it against incomplete inputs.
+func firstFailedDependency(task models.AgentTask, responses []*models.AgentResponse, byID map[string]int) (string, bool) {
+	for _, dep := range task.DependsOn {
+		response := responses[byID[dep]]
+		if response == nil || !response.Success {
+			return dep, true
+		}
+	}
+	return "", false
+}
+
+// taskBindingPattern matches an InputBindings expression of the form
+// "$tasks.<task_id>.output.<field>".
+var taskBindingPattern = regexp.MustCompile(`^\$tasks\.([^.]+)\.output\.([A-Za-z0-9_]+)$`)
+
+// resolveInputBindings returns a copy of task with every InputBindings
+// entry resolved against the already-completed responses of the tasks it
+// DependsOn, overriding whatever value the planner set on that field
+// directly. responses and byID index the full task list by position and
+// task_id respectively; only entries for tasks already guarded by
+// DependsOn are read.
+func resolveInputBindings(task models.AgentTask, responses []*models.AgentResponse, byID map[string]int) (models.AgentTask, error) {
+	if len(task.InputBindings) == 0 {
+		return task, nil
+	}
+
+	resolved := task
+	for field, expr := range task.InputBindings {
+		depTaskID, outputField, ok := taskBindingMatch(expr)
+		if !ok {
+			return task, fmt.Errorf("task %q: invalid input binding %q for field %q", task.TaskID, expr, field)
+		}
+		depIndex, ok := byID[depTaskID]
+		if !ok {
+			return task, fmt.Errorf("task %q: input binding references unknown task_id %q", task.TaskID, depTaskID)
+		}
+		depResponse := responses[depIndex]
+		if depResponse == nil || !depResponse.Success {
+			return task, fmt.Errorf("task %q: input binding depends on task %q which did not succeed", task.TaskID, depTaskID)
+		}
+
+		value, err := outputFieldValue(depResponse.Data, outputField)
+		if err != nil {
+			return task, fmt.Errorf("task %q: %w", task.TaskID, err)
+		}
+
+		switch field {
+		case "city":
+			resolved.City = value
+		case "timezone":
+			resolved.Timezone = value
+		case "echo_text":
+			resolved.EchoText = value
+		case "days":
+			days, err := strconv.Atoi(value)
+			if err != nil {
+				return task, fmt.Errorf("task %q: input binding for \"days\" produced non-numeric value %q", task.TaskID, value)
+			}
+			resolved.Days = days
+		default:
+			return task, fmt.Errorf("task %q: unsupported input binding field %q", task.TaskID, field)
+		}
+	}
+
+	return resolved, nil
+}
+
+// taskBindingMatch parses expr as "$tasks.<task_id>.output.<field>".
+func taskBindingMatch(expr string) (taskID, field string, ok bool) {
+	m := taskBindingPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// outputFieldValue reads field off data (one of the *Data structs an
+// AgentResponse carries, e.g. *models.TemperatureData) by matching its json
+// tag, and stringifies the result so it can be assigned to any of
+// AgentTask's string fields.
+func outputFieldValue(data interface{}, field string) (string, error) {
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", fmt.Errorf("output has no data")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("output is not a struct")
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name == field {
+			return fmt.Sprintf("%v", v.Field(i).Interface()), nil
+		}
+	}
+	return "", fmt.Errorf("output field %q not found", field)
+}
+
+// detectDAGCycle reports the task_id of a task participating in a
+// dependency cycle, or "" if tasks form a valid DAG.
+func detectDAGCycle(tasks []models.AgentTask, byID map[string]int) string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(tasks))
+
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		state[i] = visiting
+		for _, dep := range tasks[i].DependsOn {
+			di := byID[dep]
+			switch state[di] {
+			case visiting:
+				return true
+			case unvisited:
+				if visit(di) {
+					return true
+				}
+			}
+		}
+		state[i] = visited
+		return false
+	}
+
+	for i := range tasks {
+		if state[i] == unvisited && visit(i) {
+			return tasks[i].TaskID
+		}
+	}
+	return ""
+}
+
 // executeTask executes a single agent task
-func (c *Coordinator) executeTask(ctx context.Context, task models.AgentTask) (*models.AgentResponse, error) {
+func (c *Coordinator) executeTask(ctx context.Context, task models.AgentTask) (response *models.AgentResponse, err error) {
 	// Generate unique IDs for this task
 	c.mu.Lock()
 	c.requestCounter++
@@ -158,23 +658,88 @@ func (c *Coordinator) executeTask(ctx context.Context, task models.AgentTask) (*
 		TaskID:    taskID,
 		AgentType: task.AgentType,
 		City:      task.City,
+		Timezone:  task.Timezone,
 		EchoText:  task.EchoText,
+		Days:      task.Days,
 		Timeout:   15 * time.Second, // Default timeout for agent operations
 	}
 
 	utils.Debug("Executing task: %s agent for %s", task.AgentType, getTaskDescription(task))
 
-	// Route to appropriate agent
-	switch task.AgentType {
-	case models.AgentTypeTemperature:
-		return c.temperatureAgent.ProcessRequest(ctx, request)
-	case models.AgentTypeDateTime:
-		return c.datetimeAgent.ProcessRequest(ctx, request)
-	case models.AgentTypeEcho:
-		return c.echoAgent.ProcessRequest(ctx, request)
-	default:
-		return nil, fmt.Errorf("unsupported agent type: %s", task.AgentType)
+	ctx, span := c.startSpan(ctx, "coordinator.execute_task", requestID, taskID)
+	span.SetAttributes(attribute.String("agent_type", string(task.AgentType)))
+	ctx = httpx.WithCallerIdentity(ctx, coordinatorSPIFFEID)
+	start := time.Now()
+	defer func() {
+		observability.ObserveAgentCallDuration(string(task.AgentType), time.Since(start).Seconds())
+		outcome := "failure"
+		if response != nil && response.Success {
+			outcome = "success"
+		}
+		observability.ObserveTaskOutcome(string(task.AgentType), outcome)
+		endSpan(span, err)
+	}()
+
+	executor, ok := c.agentExecutors[task.AgentType]
+	if !ok {
+		return c.dispatchTask(ctx, task.AgentType, request)
+	}
+
+	attempts := 0
+	stateBefore := executor.State()
+
+	doErr := executor.Do(ctx, isTaskRetryable, func() error {
+		attempts++
+		c.metrics.recordAttempt(task.AgentType)
+		if attempts > 1 {
+			c.metrics.recordRetry(task.AgentType)
+		}
+
+		resp, dispatchErr := c.dispatchTask(ctx, task.AgentType, request)
+		if dispatchErr != nil {
+			return dispatchErr
+		}
+		response = resp
+		if !resp.Success {
+			return errors.New(resp.Error)
+		}
+		return nil
+	})
+
+	if stateBefore != resilience.BreakerOpen && executor.State() == resilience.BreakerOpen {
+		c.metrics.recordBreakerOpen(task.AgentType)
+	}
+
+	if doErr == nil {
+		return response, nil
+	}
+	if errors.Is(doErr, resilience.ErrBreakerOpen) {
+		return &models.AgentResponse{
+			RequestID: request.RequestID,
+			TaskID:    request.TaskID,
+			Success:   false,
+			Error:     "circuit open",
+		}, nil
+	}
+	if response != nil {
+		// The sub-agent already returned a Success:false response with its
+		// own Error set (see ProcessRequest across internal/agents/*); that
+		// response is more informative than doErr's wrapped retry-count
+		// message, so it wins once retries are exhausted.
+		return response, nil
 	}
+	return nil, doErr
+}
+
+// dispatchTask routes request to the sub-agent registered for agentType.
+func (c *Coordinator) dispatchTask(ctx context.Context, agentType models.AgentType, request models.AgentRequest) (*models.AgentResponse, error) {
+	c.mu.RLock()
+	agent, ok := c.agents[agentType]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported agent type: %s", agentType)
+	}
+	return agent.ProcessRequest(ctx, request)
 }
 
 // buildQueryResponse builds the final query response
@@ -236,6 +801,10 @@ func (c *Coordinator) buildQueryResponse(query models.Query, plan *models.Orches
 				if echoData, ok := agentResponse.Data.(*models.EchoData); ok {
 					response.Echo = echoData
 				}
+			case models.AgentTypeForecast:
+				if forecastData, ok := agentResponse.Data.(*models.ForecastData); ok {
+					response.Forecast = forecastData
+				}
 			}
 		}
 	}
@@ -257,9 +826,14 @@ func getTaskDescription(task models.AgentTask) string {
 	case models.AgentTypeTemperature:
 		return fmt.Sprintf("city: %s", task.City)
 	case models.AgentTypeDateTime:
+		if task.Timezone != "" {
+			return fmt.Sprintf("timezone: %s", task.Timezone)
+		}
 		return fmt.Sprintf("city: %s", task.City)
 	case models.AgentTypeEcho:
 		return fmt.Sprintf("text: %s", task.EchoText)
+	case models.AgentTypeForecast:
+		return fmt.Sprintf("city: %s, days: %d", task.City, task.Days)
 	default:
 		return "unknown"
 	}
@@ -267,42 +841,47 @@ func getTaskDescription(task models.AgentTask) string {
 
 // Validate validates the coordinator configuration
 func (c *Coordinator) Validate() error {
-	if c.llmClient == nil {
-		return fmt.Errorf("LLM client is not initialized")
+	if c.planner == nil {
+		return fmt.Errorf("LLM planner is not initialized")
 	}
-	if c.temperatureAgent == nil {
-		return fmt.Errorf("temperature agent is not initialized")
+	for _, agentType := range []models.AgentType{
+		models.AgentTypeTemperature,
+		models.AgentTypeDateTime,
+		models.AgentTypeEcho,
+		models.AgentTypeForecast,
+	} {
+		if _, ok := c.agents[agentType]; !ok {
+			return fmt.Errorf("%s agent is not registered", agentType)
+		}
 	}
-	if c.datetimeAgent == nil {
-		return fmt.Errorf("datetime agent is not initialized")
+	if c.responseCache == nil {
+		return fmt.Errorf("response cache is not initialized")
 	}
-	if c.echoAgent == nil {
-		return fmt.Errorf("echo agent is not initialized")
+	if c.tracker == nil {
+		return fmt.Errorf("hit tracker is not initialized")
 	}
 
-	// Validate sub-agents
-	if err := c.temperatureAgent.Validate(); err != nil {
-		return fmt.Errorf("temperature agent validation failed: %w", err)
-	}
-	if err := c.datetimeAgent.Validate(); err != nil {
-		return fmt.Errorf("datetime agent validation failed: %w", err)
-	}
-	if err := c.echoAgent.Validate(); err != nil {
-		return fmt.Errorf("echo agent validation failed: %w", err)
+	// Validate every registered sub-agent
+	for agentType, agent := range c.agents {
+		if err := agent.Validate(); err != nil {
+			return fmt.Errorf("%s agent validation failed: %w", agentType, err)
+		}
 	}
 
 	return nil
 }
 
-// Close closes the coordinator and all sub-agents
+// Close closes the coordinator and all registered sub-agents, and flushes
+// any hit-tracker records Record has batched but not yet persisted (see
+// cache.HitTracker.Close).
 func (c *Coordinator) Close() {
-	if c.temperatureAgent != nil {
-		c.temperatureAgent.Close()
+	for _, agent := range c.agents {
+		agent.Close()
 	}
-	if c.datetimeAgent != nil {
-		c.datetimeAgent.Close()
+	if c.tracker != nil {
+		c.tracker.Close()
 	}
-	if c.echoAgent != nil {
-		c.echoAgent.Close()
+	if c.cancelCertRotation != nil {
+		c.cancelCertRotation()
 	}
 }
@@ -0,0 +1,149 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/observability"
+	"github.com/steve/llm-agents/internal/resilience"
+)
+
+// noRetryPolicy gives up after one attempt, so a simulated sub-agent
+// failure in these tests resolves immediately instead of paying
+// resilience.DefaultPolicy's backoff delays between retries.
+var noRetryPolicy = resilience.Policy{Backoff: resilience.BackoffPolicy{MaxAttempts: 1}}
+
+// countingSubAgent counts how many ProcessRequest calls overlap at once
+// (for asserting maxParallelism) and, if failEcho names the request's
+// EchoText, returns a failed AgentResponse instead of a successful one.
+type countingSubAgent struct {
+	mu          sync.Mutex
+	inFlight    int32
+	maxInFlight int32
+	failEcho    map[string]bool
+}
+
+func (f *countingSubAgent) ProcessRequest(ctx context.Context, request models.AgentRequest) (*models.AgentResponse, error) {
+	cur := atomic.AddInt32(&f.inFlight, 1)
+	defer atomic.AddInt32(&f.inFlight, -1)
+
+	f.mu.Lock()
+	if cur > f.maxInFlight {
+		f.maxInFlight = cur
+	}
+	f.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	if f.failEcho[request.EchoText] {
+		return &models.AgentResponse{RequestID: request.RequestID, TaskID: request.TaskID, Success: false, Error: "simulated failure"}, nil
+	}
+	return &models.AgentResponse{
+		RequestID: request.RequestID,
+		TaskID:    request.TaskID,
+		Success:   true,
+		Data:      &models.EchoData{OriginalText: request.EchoText, EchoText: request.EchoText},
+	}, nil
+}
+
+func (f *countingSubAgent) Validate() error { return nil }
+
+func (f *countingSubAgent) Close() {}
+
+func (f *countingSubAgent) Describe() models.AgentDescriptor {
+	return models.AgentDescriptor{Type: models.AgentTypeEcho, Name: "Echo Agent"}
+}
+
+func newDAGTestCoordinator(agent *countingSubAgent) *Coordinator {
+	return &Coordinator{
+		agents:         map[models.AgentType]SubAgent{models.AgentTypeEcho: agent},
+		agentExecutors: map[models.AgentType]*resilience.Executor{models.AgentTypeEcho: resilience.NewExecutor(noRetryPolicy)},
+		metrics:        newCoordinatorMetrics(),
+		tracer:         observability.Tracer(nil),
+	}
+}
+
+// TestExecuteDAGDiamondRunsLeafAfterBothBranches verifies a diamond
+// dependency (d depends on b and c, which both depend on a) fully
+// completes all four tasks.
+func TestExecuteDAGDiamondRunsLeafAfterBothBranches(t *testing.T) {
+	agent := &countingSubAgent{}
+	c := newDAGTestCoordinator(agent)
+
+	tasks := []models.AgentTask{
+		{TaskID: "a", AgentType: models.AgentTypeEcho, EchoText: "a"},
+		{TaskID: "b", AgentType: models.AgentTypeEcho, EchoText: "b", DependsOn: []string{"a"}},
+		{TaskID: "c", AgentType: models.AgentTypeEcho, EchoText: "c", DependsOn: []string{"a"}},
+		{TaskID: "d", AgentType: models.AgentTypeEcho, EchoText: "d", DependsOn: []string{"b", "c"}},
+	}
+
+	responses, err := c.executeDAG(context.Background(), tasks, nil, nil)
+	if err != nil {
+		t.Fatalf("executeDAG() error = %v, want nil", err)
+	}
+	if len(responses) != 4 {
+		t.Fatalf("got %d responses, want 4", len(responses))
+	}
+	for i, response := range responses {
+		if response == nil || !response.Success {
+			t.Errorf("responses[%d] = %+v, want a successful response", i, response)
+		}
+	}
+}
+
+// TestExecuteDAGSkipsDownstreamOfFailedDependency verifies a task
+// depending on a failed task is never dispatched, and instead gets a
+// synthesized failure response, while a sibling branch that doesn't share
+// the failure still completes.
+func TestExecuteDAGSkipsDownstreamOfFailedDependency(t *testing.T) {
+	agent := &countingSubAgent{failEcho: map[string]bool{"a": true}}
+	c := newDAGTestCoordinator(agent)
+
+	tasks := []models.AgentTask{
+		{TaskID: "a", AgentType: models.AgentTypeEcho, EchoText: "a"},
+		{TaskID: "b", AgentType: models.AgentTypeEcho, EchoText: "b", DependsOn: []string{"a"}},
+		{TaskID: "c", AgentType: models.AgentTypeEcho, EchoText: "c"},
+	}
+
+	responses, err := c.executeDAG(context.Background(), tasks, nil, nil)
+	if err == nil {
+		t.Fatal("executeDAG() error = nil, want the skip error for task \"b\"")
+	}
+
+	if responses[0] == nil || responses[0].Success {
+		t.Errorf("responses[0] (a) = %+v, want a failure", responses[0])
+	}
+	if responses[1] == nil || responses[1].Success {
+		t.Errorf("responses[1] (b) = %+v, want a synthesized skip failure", responses[1])
+	}
+	if responses[2] == nil || !responses[2].Success {
+		t.Errorf("responses[2] (c) = %+v, want success - it doesn't depend on the failed task", responses[2])
+	}
+}
+
+// TestExecuteDAGMaxParallelismBoundsConcurrency verifies WithMaxParallelism
+// caps how many independent tasks run their executeTask call at once.
+func TestExecuteDAGMaxParallelismBoundsConcurrency(t *testing.T) {
+	agent := &countingSubAgent{}
+	c := newDAGTestCoordinator(agent)
+	c.maxParallelism = 2
+
+	var tasks []models.AgentTask
+	for i := 0; i < 6; i++ {
+		id := fmt.Sprintf("t%d", i)
+		tasks = append(tasks, models.AgentTask{TaskID: id, AgentType: models.AgentTypeEcho, EchoText: id})
+	}
+
+	if _, err := c.executeDAG(context.Background(), tasks, nil, nil); err != nil {
+		t.Fatalf("executeDAG() error = %v, want nil", err)
+	}
+
+	if agent.maxInFlight > 2 {
+		t.Errorf("observed %d concurrent ProcessRequest calls, want at most 2 (maxParallelism)", agent.maxInFlight)
+	}
+}
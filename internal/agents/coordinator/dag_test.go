@@ -0,0 +1,120 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steve/llm-agents/internal/models"
+)
+
+// TestDetectDAGCycleDiamond verifies a diamond dependency (d depends on b
+// and c, which both depend on a) is a valid DAG, not a false-positive cycle.
+func TestDetectDAGCycleDiamond(t *testing.T) {
+	tasks := []models.AgentTask{
+		{TaskID: "a"},
+		{TaskID: "b", DependsOn: []string{"a"}},
+		{TaskID: "c", DependsOn: []string{"a"}},
+		{TaskID: "d", DependsOn: []string{"b", "c"}},
+	}
+	byID := map[string]int{"a": 0, "b": 1, "c": 2, "d": 3}
+
+	if cycleAt := detectDAGCycle(tasks, byID); cycleAt != "" {
+		t.Fatalf("expected no cycle in diamond dag, got cycle at %q", cycleAt)
+	}
+}
+
+// TestDetectDAGCycleFanOut verifies a fan-out (b and c both depend only on
+// a) is a valid DAG.
+func TestDetectDAGCycleFanOut(t *testing.T) {
+	tasks := []models.AgentTask{
+		{TaskID: "a"},
+		{TaskID: "b", DependsOn: []string{"a"}},
+		{TaskID: "c", DependsOn: []string{"a"}},
+	}
+	byID := map[string]int{"a": 0, "b": 1, "c": 2}
+
+	if cycleAt := detectDAGCycle(tasks, byID); cycleAt != "" {
+		t.Fatalf("expected no cycle in fan-out dag, got cycle at %q", cycleAt)
+	}
+}
+
+// TestDetectDAGCycleDetected verifies a direct a->b->a cycle is reported.
+func TestDetectDAGCycleDetected(t *testing.T) {
+	tasks := []models.AgentTask{
+		{TaskID: "a", DependsOn: []string{"b"}},
+		{TaskID: "b", DependsOn: []string{"a"}},
+	}
+	byID := map[string]int{"a": 0, "b": 1}
+
+	if cycleAt := detectDAGCycle(tasks, byID); cycleAt == "" {
+		t.Fatal("expected a cycle to be detected")
+	}
+}
+
+// TestResolveInputBindings covers a task that binds two fields off two
+// different upstream tasks' outputs, as a diamond join would.
+func TestResolveInputBindings(t *testing.T) {
+	byID := map[string]int{"t1": 0, "t2": 1, "t3": 2}
+	responses := []*models.AgentResponse{
+		{TaskID: "t1", Success: true, Data: &models.DateTimeData{City: "Seattle", Timezone: "America/Los_Angeles"}},
+		{TaskID: "t2", Success: true, Data: &models.ForecastData{Days: []models.ForecastDay{{Date: "2026-07-29"}}}},
+		nil,
+	}
+	task := models.AgentTask{
+		TaskID:    "t3",
+		DependsOn: []string{"t1", "t2"},
+		InputBindings: map[string]string{
+			"city":     "$tasks.t1.output.city",
+			"timezone": "$tasks.t1.output.timezone",
+		},
+	}
+
+	resolved, err := resolveInputBindings(task, responses, byID)
+	if err != nil {
+		t.Fatalf("resolveInputBindings returned error: %v", err)
+	}
+	if resolved.City != "Seattle" {
+		t.Errorf("expected resolved city %q, got %q", "Seattle", resolved.City)
+	}
+	if resolved.Timezone != "America/Los_Angeles" {
+		t.Errorf("expected resolved timezone %q, got %q", "America/Los_Angeles", resolved.Timezone)
+	}
+}
+
+// TestResolveInputBindingsUnresolvedDependency verifies a binding onto a
+// dependency that failed (or hasn't run) surfaces an error instead of
+// silently leaving the field empty.
+func TestResolveInputBindingsUnresolvedDependency(t *testing.T) {
+	byID := map[string]int{"t1": 0, "t2": 1}
+	responses := []*models.AgentResponse{
+		{TaskID: "t1", Success: false, Error: "upstream failure"},
+		nil,
+	}
+	task := models.AgentTask{
+		TaskID:        "t2",
+		DependsOn:     []string{"t1"},
+		InputBindings: map[string]string{"city": "$tasks.t1.output.city"},
+	}
+
+	if _, err := resolveInputBindings(task, responses, byID); err == nil {
+		t.Fatal("expected an error when binding to a failed dependency")
+	}
+}
+
+// TestOutputFieldValue verifies field lookup matches on json tag name, not
+// Go field name, across the *Data shapes InputBindings commonly targets.
+func TestOutputFieldValue(t *testing.T) {
+	data := &models.TemperatureData{City: "Austin", Temperature: 101.2, Timestamp: time.Now()}
+
+	city, err := outputFieldValue(data, "city")
+	if err != nil {
+		t.Fatalf("outputFieldValue(city) returned error: %v", err)
+	}
+	if city != "Austin" {
+		t.Errorf("expected city %q, got %q", "Austin", city)
+	}
+
+	if _, err := outputFieldValue(data, "nonexistent_field"); err == nil {
+		t.Fatal("expected an error for a field that doesn't exist")
+	}
+}
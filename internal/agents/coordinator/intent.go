@@ -0,0 +1,53 @@
+package coordinator
+
+import "strings"
+
+// Intent labels the response cache and hit tracker key queries by. These
+// are a coarse, local classification - independent of the LLM's own
+// orchestration routing in internal/llm - so a cache lookup never requires an
+// LLM round trip.
+const (
+	intentTemperature = "temperature"
+	intentDateTime    = "datetime"
+	intentForecast    = "forecast"
+	intentEcho        = "echo"
+)
+
+// classifyIntent assigns one of the intent constants to queryText via
+// keyword matching, mirroring isEchoQuery's heuristic in cli: an echo
+// request is checked first, then forecast/datetime keywords, defaulting to
+// temperature for anything else.
+func classifyIntent(queryText string) string {
+	lower := strings.ToLower(queryText)
+	switch {
+	case strings.Contains(lower, "echo") || strings.Contains(lower, "repeat"):
+		return intentEcho
+	case strings.Contains(lower, "forecast") || strings.Contains(lower, "tomorrow") || strings.Contains(lower, "weekend"):
+		return intentForecast
+	case strings.Contains(lower, "time") || strings.Contains(lower, "date"):
+		return intentDateTime
+	default:
+		return intentTemperature
+	}
+}
+
+// isCacheableIntent reports whether an intent's response is safe to cache
+// and prefetch: an echo response depends on free text the query carries,
+// not just a city, so it's excluded.
+func isCacheableIntent(intent string) bool {
+	return intent != intentEcho
+}
+
+// intentQueryText synthesizes a representative query string for intent, so
+// a Prefetcher can re-run a hot query when it only has a cache.QueryKey's
+// (city, intent) and not the original free text.
+func intentQueryText(intent string) string {
+	switch intent {
+	case intentDateTime:
+		return "What time is it?"
+	case intentForecast:
+		return "5-day forecast"
+	default:
+		return "What's the temperature?"
+	}
+}
@@ -0,0 +1,69 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steve/llm-agents/internal/models"
+)
+
+// fakePlanner is a minimal llm.Planner for exercising the coordinator
+// without a real LLM provider.
+type fakePlanner struct {
+	plan *models.OrchestrationPlan
+	err  error
+}
+
+func (f *fakePlanner) GenerateOrchestrationPlan(ctx context.Context, query models.Query, descriptors []models.AgentDescriptor) (*models.OrchestrationPlan, error) {
+	return f.GenerateOrchestrationPlanWithHistory(ctx, query, nil, descriptors)
+}
+
+func (f *fakePlanner) GenerateOrchestrationPlanWithHistory(ctx context.Context, query models.Query, history []models.ConversationTurn, descriptors []models.AgentDescriptor) (*models.OrchestrationPlan, error) {
+	return f.plan, f.err
+}
+
+// TestWithPlannerOverridesDefault verifies WithPlanner replaces whatever
+// Planner NewCoordinator/NewCoordinatorTLS would otherwise default to,
+// so the coordinator can be unit-tested against a fake instead of a real
+// LLM provider.
+func TestWithPlannerOverridesDefault(t *testing.T) {
+	c := &Coordinator{}
+	planner := &fakePlanner{}
+	WithPlanner(planner)(c)
+
+	if c.planner != planner {
+		t.Fatalf("WithPlanner did not set c.planner to the given Planner")
+	}
+}
+
+// TestValidateRequiresPlanner verifies Validate reports a nil planner
+// before checking anything else, mirroring the other "not initialized"
+// checks it performs for the response cache and hit tracker.
+func TestValidateRequiresPlanner(t *testing.T) {
+	c := &Coordinator{}
+	if err := c.Validate(); err == nil {
+		t.Fatal("Validate() with no planner = nil, want an error")
+	}
+}
+
+// TestProcessQueryStreamReportsPlannerError verifies a Planner error
+// surfaces as a terminal EventQueryCompleted carrying the failure,
+// instead of the stream silently stalling.
+func TestProcessQueryStreamReportsPlannerError(t *testing.T) {
+	c := &Coordinator{planner: &fakePlanner{err: errors.New("LLM provider unavailable")}}
+
+	events := c.processQueryStreamWithHistory(context.Background(), models.Query{ID: "q1", Text: "echo hi"}, nil)
+
+	var last models.QueryEvent
+	for event := range events {
+		last = event
+	}
+
+	if last.Type != models.EventQueryCompleted {
+		t.Fatalf("final event type = %v, want %v", last.Type, models.EventQueryCompleted)
+	}
+	if last.Error == "" {
+		t.Error("final event Error is empty, want the planner failure reflected")
+	}
+}
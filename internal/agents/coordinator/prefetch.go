@@ -0,0 +1,82 @@
+package coordinator
+
+import (
+	"context"
+	"time"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// defaultPrefetchTop and defaultPrefetchInterval are Prefetcher's defaults
+// when a caller passes a non-positive top or interval.
+const (
+	defaultPrefetchTop      = 10
+	defaultPrefetchInterval = 15 * time.Minute
+
+	// prefetchTaskTimeout bounds how long Prefetcher waits for a single
+	// hot query to warm before moving on to the next one.
+	prefetchTaskTimeout = 15 * time.Second
+)
+
+// Prefetcher periodically re-executes a Coordinator's top-K hottest
+// (city, intent) queries, following the peak-request prefetch pattern from
+// wttr.in's initPeakHandling, so a hot query's response sits warm in the
+// coordinator's response cache and ProcessQuery can return it immediately
+// instead of paying MCP + LLM latency on every request.
+type Prefetcher struct {
+	coordinator *Coordinator
+	top         int
+	interval    time.Duration
+}
+
+// NewPrefetcher creates a Prefetcher that keeps the top hottest queries
+// from coordinator's hit tracker warm every interval, defaulting to
+// defaultPrefetchTop/defaultPrefetchInterval for a non-positive top or
+// interval.
+func NewPrefetcher(coordinator *Coordinator, top int, interval time.Duration) *Prefetcher {
+	if top <= 0 {
+		top = defaultPrefetchTop
+	}
+	if interval <= 0 {
+		interval = defaultPrefetchInterval
+	}
+	return &Prefetcher{coordinator: coordinator, top: top, interval: interval}
+}
+
+// Start runs the background prefetch loop in its own goroutine: it warms
+// the top hottest queries immediately, then again every p.interval, until
+// ctx is cancelled.
+func (p *Prefetcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		p.runOnce(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.runOnce(ctx)
+			}
+		}
+	}()
+}
+
+// runOnce warms every one of the current top-K hot keys in turn.
+func (p *Prefetcher) runOnce(ctx context.Context) {
+	keys := p.coordinator.HotKeys(p.top)
+	if len(keys) == 0 {
+		return
+	}
+	utils.Info("Prefetcher: warming %d hot quer(ies)", len(keys))
+
+	for _, key := range keys {
+		warmCtx, cancel := context.WithTimeout(ctx, prefetchTaskTimeout)
+		err := p.coordinator.Warm(warmCtx, key)
+		cancel()
+		if err != nil {
+			utils.Warn("Prefetcher: failed to warm %s: %v", key.String(), err)
+		}
+	}
+}
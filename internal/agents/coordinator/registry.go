@@ -0,0 +1,60 @@
+package coordinator
+
+import (
+	"context"
+
+	"github.com/steve/llm-agents/internal/models"
+)
+
+// SubAgent is the interface every agent type Coordinator dispatches tasks
+// to must implement. A new MCP-backed agent can be wired in with
+// RegisterAgent instead of adding a hard-coded field and switch case to
+// Coordinator.
+type SubAgent interface {
+	ProcessRequest(ctx context.Context, request models.AgentRequest) (*models.AgentResponse, error)
+	Validate() error
+	Close()
+	Describe() models.AgentDescriptor
+}
+
+// RegisterAgent adds agent to the registry under agentType, replacing
+// whatever was previously registered for it. executeTask and Describe
+// read from this registry rather than switching on a hard-coded set of
+// types, so a caller can plug in a new agent type without a coordinator
+// code change.
+func (c *Coordinator) RegisterAgent(agentType models.AgentType, agent SubAgent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.agents[agentType] = agent
+	if _, ok := c.agentExecutors[agentType]; !ok {
+		c.agentExecutors[agentType] = defaultAgentExecutor()
+	}
+}
+
+// agentDescriptors returns the Describe() of every registered agent, in a
+// stable order, for the LLM planner prompt.
+func (c *Coordinator) agentDescriptors() []models.AgentDescriptor {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	descriptors := make([]models.AgentDescriptor, 0, len(c.agents))
+	for _, agentType := range []models.AgentType{
+		models.AgentTypeTemperature,
+		models.AgentTypeDateTime,
+		models.AgentTypeEcho,
+		models.AgentTypeForecast,
+	} {
+		if agent, ok := c.agents[agentType]; ok {
+			descriptors = append(descriptors, agent.Describe())
+		}
+	}
+	for agentType, agent := range c.agents {
+		switch agentType {
+		case models.AgentTypeTemperature, models.AgentTypeDateTime, models.AgentTypeEcho, models.AgentTypeForecast:
+			continue
+		default:
+			descriptors = append(descriptors, agent.Describe())
+		}
+	}
+	return descriptors
+}
@@ -0,0 +1,87 @@
+package coordinator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steve/llm-agents/internal/models"
+)
+
+// fakeSubAgent is a minimal SubAgent for exercising the registry without a
+// real MCP client.
+type fakeSubAgent struct {
+	descriptor models.AgentDescriptor
+}
+
+func (f *fakeSubAgent) ProcessRequest(ctx context.Context, request models.AgentRequest) (*models.AgentResponse, error) {
+	return &models.AgentResponse{RequestID: request.RequestID, TaskID: request.TaskID, Success: true}, nil
+}
+
+func (f *fakeSubAgent) Validate() error { return nil }
+
+func (f *fakeSubAgent) Close() {}
+
+func (f *fakeSubAgent) Describe() models.AgentDescriptor { return f.descriptor }
+
+// TestRegisterAgentAddsToDescriptors verifies RegisterAgent makes a new
+// agent type dispatchable and visible to the LLM planner via
+// agentDescriptors, without touching any of the four built-in types.
+func TestRegisterAgentAddsToDescriptors(t *testing.T) {
+	c := &Coordinator{
+		agents:         map[models.AgentType]SubAgent{},
+		agentExecutors: newDefaultAgentExecutors(),
+	}
+
+	const customType models.AgentType = "news"
+	descriptor := models.AgentDescriptor{
+		Type:        customType,
+		Name:        "News Agent",
+		Purpose:     "Retrieves headlines for a city",
+		InputFields: []string{"city"},
+	}
+	c.RegisterAgent(customType, &fakeSubAgent{descriptor: descriptor})
+
+	agent, ok := c.agents[customType]
+	if !ok {
+		t.Fatalf("RegisterAgent did not add agent type %q to the registry", customType)
+	}
+	if got := agent.Describe(); got.Name != descriptor.Name || got.Purpose != descriptor.Purpose {
+		t.Errorf("Describe() = %+v, want %+v", got, descriptor)
+	}
+
+	if _, ok := c.agentExecutors[customType]; !ok {
+		t.Errorf("RegisterAgent did not assign a default executor for %q", customType)
+	}
+
+	descriptors := c.agentDescriptors()
+	found := false
+	for _, d := range descriptors {
+		if d.Type == customType && d.Name == descriptor.Name {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("agentDescriptors() = %+v, want it to include %+v", descriptors, descriptor)
+	}
+}
+
+// TestAgentDescriptorsOrdersBuiltinsFirst verifies the four built-in agent
+// types always come first, in a fixed order, ahead of any custom
+// registrations, so the planner prompt's numbered list stays stable.
+func TestAgentDescriptorsOrdersBuiltinsFirst(t *testing.T) {
+	c := &Coordinator{
+		agents: map[models.AgentType]SubAgent{
+			models.AgentTypeForecast: &fakeSubAgent{descriptor: models.AgentDescriptor{Type: models.AgentTypeForecast, Name: "Forecast Agent"}},
+			models.AgentTypeEcho:     &fakeSubAgent{descriptor: models.AgentDescriptor{Type: models.AgentTypeEcho, Name: "Echo Agent"}},
+		},
+		agentExecutors: newDefaultAgentExecutors(),
+	}
+
+	descriptors := c.agentDescriptors()
+	if len(descriptors) != 2 {
+		t.Fatalf("agentDescriptors() returned %d entries, want 2", len(descriptors))
+	}
+	if descriptors[0].Type != models.AgentTypeEcho || descriptors[1].Type != models.AgentTypeForecast {
+		t.Errorf("agentDescriptors() = %+v, want Echo before Forecast", descriptors)
+	}
+}
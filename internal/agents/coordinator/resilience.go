@@ -0,0 +1,143 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/steve/llm-agents/internal/llm"
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/resilience"
+)
+
+// CoordinatorOption configures optional Coordinator behavior at
+// construction time.
+type CoordinatorOption func(*Coordinator)
+
+// WithAgentPolicy overrides the retry/rate-limit/circuit-breaker policy
+// executeTask applies to every task routed to agentType, in place of
+// resilience.DefaultPolicy.
+func WithAgentPolicy(agentType models.AgentType, policy resilience.Policy) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.agentExecutors[agentType] = resilience.NewExecutor(policy)
+	}
+}
+
+// WithPlanner overrides the Planner NewCoordinator/NewCoordinatorTLS
+// default to (an llm.OpenRouterPlanner), in place of planner - e.g. to
+// select a different LLM provider via llm.New, or a fake Planner in
+// tests.
+func WithPlanner(planner llm.Planner) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.planner = planner
+	}
+}
+
+// WithMaxParallelism bounds executeDAG to at most n concurrent in-flight
+// AgentTask dispatches, queuing the rest until a slot frees up; n <= 0
+// leaves it unbounded, the default.
+func WithMaxParallelism(n int) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.maxParallelism = n
+	}
+}
+
+// defaultAgentExecutor returns a resilience.Executor running
+// resilience.DefaultPolicy, for an AgentType that hasn't been given an
+// explicit WithAgentPolicy.
+func defaultAgentExecutor() *resilience.Executor {
+	return resilience.NewExecutor(resilience.DefaultPolicy())
+}
+
+// newDefaultAgentExecutors returns one resilience.Executor per known
+// AgentType, each running resilience.DefaultPolicy until overridden by a
+// WithAgentPolicy option.
+func newDefaultAgentExecutors() map[models.AgentType]*resilience.Executor {
+	executors := make(map[models.AgentType]*resilience.Executor, 4)
+	for _, agentType := range []models.AgentType{
+		models.AgentTypeTemperature,
+		models.AgentTypeDateTime,
+		models.AgentTypeEcho,
+		models.AgentTypeForecast,
+	} {
+		executors[agentType] = defaultAgentExecutor()
+	}
+	return executors
+}
+
+// AgentMetrics is a point-in-time copy of the attempt/retry/breaker
+// counters CoordinatorMetrics records for one AgentType.
+type AgentMetrics struct {
+	Attempts     int
+	Retries      int
+	BreakerOpens int
+}
+
+// CoordinatorMetrics records, per AgentType, how many times executeTask
+// dispatched a sub-agent call, how many of those were retries of a prior
+// attempt, and how many times that AgentType's circuit breaker tripped to
+// open. It's read through Snapshot rather than its fields directly, which
+// are guarded by mu.
+type CoordinatorMetrics struct {
+	mu       sync.Mutex
+	counters map[models.AgentType]*AgentMetrics
+}
+
+func newCoordinatorMetrics() *CoordinatorMetrics {
+	return &CoordinatorMetrics{counters: make(map[models.AgentType]*AgentMetrics)}
+}
+
+func (m *CoordinatorMetrics) entry(agentType models.AgentType) *AgentMetrics {
+	c, ok := m.counters[agentType]
+	if !ok {
+		c = &AgentMetrics{}
+		m.counters[agentType] = c
+	}
+	return c
+}
+
+func (m *CoordinatorMetrics) recordAttempt(agentType models.AgentType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entry(agentType).Attempts++
+}
+
+func (m *CoordinatorMetrics) recordRetry(agentType models.AgentType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entry(agentType).Retries++
+}
+
+func (m *CoordinatorMetrics) recordBreakerOpen(agentType models.AgentType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entry(agentType).BreakerOpens++
+}
+
+// Snapshot returns a copy of the counters recorded for agentType, or a
+// zero AgentMetrics if no task has ever been routed to it.
+func (m *CoordinatorMetrics) Snapshot(agentType models.AgentType) AgentMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, ok := m.counters[agentType]; ok {
+		return *c
+	}
+	return AgentMetrics{}
+}
+
+// isTaskRetryable reports whether a coordinator-level retry of err is
+// worth attempting: a context cancellation/deadline or an already-open
+// breaker won't be fixed by retrying, so only the sub-agent's own
+// (already internally-retried) failure qualifies.
+func isTaskRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, resilience.ErrBreakerOpen) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return true
+}
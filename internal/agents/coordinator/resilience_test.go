@@ -0,0 +1,56 @@
+package coordinator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/resilience"
+)
+
+// TestIsTaskRetryableExcludesTerminalErrors verifies context cancellation
+// and an already-open breaker are treated as non-retryable, while any
+// other error (e.g. a sub-agent's Success:false Error) is retried.
+func TestIsTaskRetryableExcludesTerminalErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"breaker open", resilience.ErrBreakerOpen, false},
+		{"context canceled", context.Canceled, false},
+		{"context deadline exceeded", context.DeadlineExceeded, false},
+		{"transient failure", errors.New("weather service timeout"), true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isTaskRetryable(tc.err); got != tc.want {
+				t.Errorf("isTaskRetryable(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestCoordinatorMetricsSnapshot verifies counters accumulate per
+// AgentType and an AgentType with no recorded activity reads as zero.
+func TestCoordinatorMetricsSnapshot(t *testing.T) {
+	m := newCoordinatorMetrics()
+
+	m.recordAttempt(models.AgentTypeTemperature)
+	m.recordAttempt(models.AgentTypeTemperature)
+	m.recordRetry(models.AgentTypeTemperature)
+	m.recordBreakerOpen(models.AgentTypeTemperature)
+
+	got := m.Snapshot(models.AgentTypeTemperature)
+	want := AgentMetrics{Attempts: 2, Retries: 1, BreakerOpens: 1}
+	if got != want {
+		t.Errorf("Snapshot(temperature) = %+v, want %+v", got, want)
+	}
+
+	if got := m.Snapshot(models.AgentTypeEcho); got != (AgentMetrics{}) {
+		t.Errorf("Snapshot(echo) = %+v, want zero value", got)
+	}
+}
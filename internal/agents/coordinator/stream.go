@@ -0,0 +1,243 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/steve/llm-agents/internal/llm"
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// streamBufferSize bounds how many QueryEvents a streamSender holds before
+// backpressure kicks in.
+const streamBufferSize = 32
+
+// streamSender delivers QueryEvents to a consumer channel without ever
+// blocking the coordinator goroutine producing them. Once the buffer is
+// full, send drops the oldest buffered event to make room for the new one
+// rather than stalling orchestration on a slow consumer, and counts every
+// drop so a caller can detect and alert on it.
+type streamSender struct {
+	ch      chan models.QueryEvent
+	mu      sync.Mutex
+	dropped int64
+}
+
+func newStreamSender() *streamSender {
+	return &streamSender{ch: make(chan models.QueryEvent, streamBufferSize)}
+}
+
+// send delivers event, dropping the oldest buffered event first if the
+// buffer is full.
+func (s *streamSender) send(event models.QueryEvent) {
+	select {
+	case s.ch <- event:
+		return
+	default:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.ch:
+		dropped := atomic.AddInt64(&s.dropped, 1)
+		utils.Error("coordinator: stream consumer too slow, dropped oldest event (dropped so far: %d)", dropped)
+	default:
+	}
+	select {
+	case s.ch <- event:
+	default:
+		// Another send raced us and refilled the buffer; drop this event
+		// too rather than blocking.
+		atomic.AddInt64(&s.dropped, 1)
+	}
+}
+
+// ProcessQueryStream is ProcessQuery, but emits a QueryEvent for every
+// milestone of the orchestration pipeline - EventPlanGenerated,
+// EventTaskStarted/Completed/Failed for each task, and a terminal
+// EventQueryCompleted - instead of only returning a fully-assembled
+// QueryResponse once everything has finished. The returned channel is
+// closed once EventQueryCompleted has been sent. A slow consumer causes
+// older buffered events to be dropped (see streamSender) rather than
+// blocking execution; ProcessQuery itself is implemented on top of this
+// stream, so a synchronous caller never observes drops.
+func (c *Coordinator) ProcessQueryStream(ctx context.Context, query models.Query) (<-chan models.QueryEvent, error) {
+	return c.processQueryStreamWithHistory(ctx, query, nil), nil
+}
+
+// processQueryStreamWithHistory is ProcessQueryStream, plus a rolling
+// conversation history woven into orchestration plan generation - see
+// ProcessQueryWithContext. When c.planner implements llm.StreamingPlanner
+// (currently just OpenRouterPlanner), it's driven through runStreamingPlan
+// instead of runBlockingPlan, so a task the LLM has already fully
+// specified can dispatch before the rest of the plan finishes streaming
+// in - see runStreamingPlan.
+func (c *Coordinator) processQueryStreamWithHistory(ctx context.Context, query models.Query, history []models.ConversationTurn) <-chan models.QueryEvent {
+	sender := newStreamSender()
+
+	if streamingPlanner, ok := c.planner.(llm.StreamingPlanner); ok {
+		go c.runStreamingPlan(ctx, streamingPlanner, query, history, sender)
+		return sender.ch
+	}
+
+	go c.runBlockingPlan(ctx, query, history, sender)
+	return sender.ch
+}
+
+// runBlockingPlan drives query's orchestration the way every Planner
+// supports: wait for the whole GenerateOrchestrationPlanWithHistory call
+// to return, emit EventPlanGenerated, then execute the plan.
+func (c *Coordinator) runBlockingPlan(ctx context.Context, query models.Query, history []models.ConversationTurn, sender *streamSender) {
+	defer close(sender.ch)
+
+	plan, err := c.planner.GenerateOrchestrationPlanWithHistory(ctx, query, history, c.agentDescriptors())
+	if err != nil {
+		sender.send(models.QueryEvent{
+			Type:    models.EventQueryCompleted,
+			QueryID: query.ID,
+			Error:   fmt.Sprintf("failed to generate orchestration plan: %v", err),
+		})
+		return
+	}
+	utils.Info("Orchestration plan: %s strategy with %d tasks", plan.Strategy, len(plan.Tasks))
+	utils.Debug("Plan reasoning: %s", plan.Reasoning)
+	sender.send(models.QueryEvent{Type: models.EventPlanGenerated, QueryID: query.ID, Plan: plan})
+
+	emit := func(event models.QueryEvent) {
+		event.QueryID = query.ID
+		sender.send(event)
+	}
+
+	responses, err := c.executePlan(ctx, plan, emit)
+	if err != nil {
+		sender.send(models.QueryEvent{
+			Type:    models.EventQueryCompleted,
+			QueryID: query.ID,
+			Error:   fmt.Sprintf("failed to execute orchestration plan: %v", err),
+		})
+		return
+	}
+
+	result := c.buildQueryResponse(query, plan, responses)
+	sender.send(models.QueryEvent{Type: models.EventQueryCompleted, QueryID: query.ID, Result: result})
+}
+
+// runStreamingPlan drives query's orchestration through streamingPlanner's
+// incremental PlanEvent stream: every PlanEventTaskAdded dispatches that
+// task immediately, concurrently with the rest of the plan still
+// streaming in from the LLM, instead of waiting for the terminal
+// PlanEventDone the way runBlockingPlan waits for
+// GenerateOrchestrationPlanWithHistory to return. A task is only ever
+// offered this early - see OpenRouterPlanner.StreamOrchestrationPlan - once
+// it's confirmed safe: no DependsOn, and a strategy (parallel or dag)
+// whose normal execution would run it concurrently with its siblings
+// anyway, so dispatching it sooner changes nothing but latency. Once
+// PlanEventDone's full plan arrives, executePlanWithPrestarted folds those
+// already-running (or already-finished) tasks in instead of dispatching
+// them again.
+func (c *Coordinator) runStreamingPlan(ctx context.Context, streamingPlanner llm.StreamingPlanner, query models.Query, history []models.ConversationTurn, sender *streamSender) {
+	defer close(sender.ch)
+
+	emit := func(event models.QueryEvent) {
+		event.QueryID = query.ID
+		sender.send(event)
+	}
+
+	planEvents, err := streamingPlanner.StreamOrchestrationPlan(ctx, query, history, c.agentDescriptors())
+	if err != nil {
+		sender.send(models.QueryEvent{
+			Type:    models.EventQueryCompleted,
+			QueryID: query.ID,
+			Error:   fmt.Sprintf("failed to stream orchestration plan: %v", err),
+		})
+		return
+	}
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		prestarted = make(map[string]*prestartedResult)
+	)
+	dispatch := func(task models.AgentTask) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			emitTaskEvent(emit, models.EventTaskStarted, task, nil, nil)
+			response, taskErr := c.executeTask(ctx, task)
+			if taskErr != nil {
+				emitTaskEvent(emit, models.EventTaskFailed, task, response, taskErr)
+			} else {
+				emitTaskEvent(emit, models.EventTaskCompleted, task, response, nil)
+			}
+
+			mu.Lock()
+			prestarted[task.TaskID] = &prestartedResult{response: response, err: taskErr}
+			mu.Unlock()
+		}()
+	}
+
+	var (
+		plan    *models.OrchestrationPlan
+		planErr string
+	)
+	for event := range planEvents {
+		switch event.Type {
+		case models.PlanEventTaskAdded:
+			if event.Task != nil {
+				dispatch(*event.Task)
+			}
+		case models.PlanEventDone:
+			plan = event.Plan
+			planErr = event.Error
+		}
+	}
+
+	if planErr != "" {
+		wg.Wait()
+		sender.send(models.QueryEvent{
+			Type:    models.EventQueryCompleted,
+			QueryID: query.ID,
+			Error:   fmt.Sprintf("failed to generate orchestration plan: %s", planErr),
+		})
+		return
+	}
+	if plan == nil {
+		wg.Wait()
+		sender.send(models.QueryEvent{
+			Type:    models.EventQueryCompleted,
+			QueryID: query.ID,
+			Error:   "orchestration plan stream closed without a terminal event",
+		})
+		return
+	}
+
+	utils.Info("Orchestration plan: %s strategy with %d tasks", plan.Strategy, len(plan.Tasks))
+	utils.Debug("Plan reasoning: %s", plan.Reasoning)
+	sender.send(models.QueryEvent{Type: models.EventPlanGenerated, QueryID: query.ID, Plan: plan})
+
+	wg.Wait()
+	mu.Lock()
+	prestartedSnapshot := make(map[string]*prestartedResult, len(prestarted))
+	for taskID, result := range prestarted {
+		prestartedSnapshot[taskID] = result
+	}
+	mu.Unlock()
+
+	responses, err := c.executePlanWithPrestarted(ctx, plan, emit, prestartedSnapshot)
+	if err != nil {
+		sender.send(models.QueryEvent{
+			Type:    models.EventQueryCompleted,
+			QueryID: query.ID,
+			Error:   fmt.Sprintf("failed to execute orchestration plan: %v", err),
+		})
+		return
+	}
+
+	result := c.buildQueryResponse(query, plan, responses)
+	sender.send(models.QueryEvent{Type: models.EventQueryCompleted, QueryID: query.ID, Result: result})
+}
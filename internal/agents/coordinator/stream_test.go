@@ -0,0 +1,36 @@
+package coordinator
+
+import (
+	"testing"
+
+	"github.com/steve/llm-agents/internal/models"
+)
+
+// TestStreamSenderDropsOldestWhenFull verifies a slow consumer causes the
+// oldest buffered event to be dropped (not the newest), and that every
+// drop is counted.
+func TestStreamSenderDropsOldestWhenFull(t *testing.T) {
+	s := newStreamSender()
+
+	for i := 0; i < streamBufferSize+5; i++ {
+		s.send(models.QueryEvent{Type: models.EventTaskStarted, QueryID: string(rune('a' + i%26))})
+	}
+
+	if got := s.dropped; got != 5 {
+		t.Fatalf("dropped = %d, want 5", got)
+	}
+
+	close(s.ch)
+	var last models.QueryEvent
+	count := 0
+	for event := range s.ch {
+		last = event
+		count++
+	}
+	if count != streamBufferSize {
+		t.Errorf("buffered events = %d, want %d", count, streamBufferSize)
+	}
+	if want := string(rune('a' + (streamBufferSize+4)%26)); last.QueryID != want {
+		t.Errorf("last buffered event QueryID = %q, want %q (the most recent send)", last.QueryID, want)
+	}
+}
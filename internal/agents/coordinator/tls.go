@@ -0,0 +1,135 @@
+package coordinator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steve/llm-agents/internal/agents/datetime"
+	"github.com/steve/llm-agents/internal/agents/echo"
+	"github.com/steve/llm-agents/internal/agents/forecast"
+	"github.com/steve/llm-agents/internal/agents/temperature"
+	"github.com/steve/llm-agents/internal/cache"
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/llm"
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/observability"
+	"github.com/steve/llm-agents/internal/resilience"
+	mcptls "github.com/steve/llm-agents/internal/tls"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// certRenewBeforeExpiry renews the coordinator's client certificate once
+// fewer than this many days remain until its expiry.
+const certRenewBeforeExpiry = 30 * 24 * time.Hour
+
+// coordinatorSPIFFEID is the coordinator's own identity, declared on every
+// outgoing sub-agent request via httpx.WithCallerIdentity so a server's
+// ClientCertIdentity middleware can cross-check it against the mTLS
+// certificate the coordinator actually presented (see executeTask).
+const coordinatorSPIFFEID = "spiffe://llm-agents/agent/coordinator"
+
+// EventType labels a value sent on Coordinator's Events channel.
+type EventType string
+
+// CertRotation is sent whenever the coordinator's background CertRenewer
+// regenerates its client certificate.
+const CertRotation EventType = "cert_rotation"
+
+// Event is an observable occurrence an operator can watch for on
+// Coordinator.Events(), e.g. a CertRotation after the background renewer
+// regenerates the coordinator's client certificate.
+type Event struct {
+	Type     EventType
+	Time     time.Time
+	CertPath string
+}
+
+// NewCoordinatorTLS is NewCoordinator, but every sub-agent's MCP client
+// presents tlsConfig.ClientCert/ClientKey over mTLS (or an ephemeral
+// self-signed equivalent in tlsConfig.DemoMode) instead of talking
+// plaintext HTTP, and a background CertRenewer regenerates that client
+// certificate once less than certRenewBeforeExpiry remains until it
+// expires. The datetime sub-agent's MCPClient runs its own CertReloader
+// watching ClientCert/ClientKey (see client.NewTLSMCPClient), so it picks
+// up the renewed file without a restart; the temperature, echo, and
+// forecast sub-agents' mcp/client clients load the client certificate once
+// when they are built and do not reload it. ReloadTLS forces that renewal
+// on demand, and Events reports every rotation.
+func NewCoordinatorTLS(openRouterAPIKey, weatherServerURL, datetimeServerURL, echoServerURL, forecastServerURL string, timeout time.Duration, policy resilience.Policy, cacheTTL time.Duration, hitStorePath string, tlsConfig *config.TLSConfig, opts ...CoordinatorOption) *Coordinator {
+	c := &Coordinator{
+		planner: llm.NewOpenRouterPlanner(llm.Config{APIKey: openRouterAPIKey}),
+		agents: map[models.AgentType]SubAgent{
+			models.AgentTypeTemperature: temperature.NewTLSAgent(weatherServerURL, timeout, tlsConfig, policy),
+			models.AgentTypeDateTime:    datetime.NewTLSAgent(datetimeServerURL, timeout, tlsConfig),
+			models.AgentTypeEcho:        echo.NewTLSAgent(echoServerURL, timeout, tlsConfig, policy),
+			models.AgentTypeForecast:    forecast.NewTLSAgent(forecastServerURL, timeout, tlsConfig, policy),
+		},
+		responseCache:  cache.NewResponseCache(cacheTTL),
+		tracker:        cache.NewHitTracker(hitStorePath),
+		agentExecutors: newDefaultAgentExecutors(),
+		metrics:        newCoordinatorMetrics(),
+		tracer:         observability.Tracer(nil),
+		meter:          observability.Meter(nil),
+	}
+	c.queryCounter = newQueryCounter(c.meter)
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancelCertRotation = cancel
+	c.certEvents = make(chan Event, 8)
+	c.clientCertPath = tlsConfig.ClientCert
+
+	cm := mcptls.NewCertificateManager(tlsConfig)
+	renewer := mcptls.NewCertRenewer(cm, "coordinator", tlsConfig.ClientCert, tlsConfig.ClientKey, false, nil)
+	monitor := mcptls.NewRenewalMonitor(cm)
+	monitor.Track(tlsConfig.ClientCert, renewer)
+
+	c.certRenewer = renewer
+	c.certMonitor = monitor
+
+	renewer.StartAutoRenew(ctx, mcptls.DefaultRotationCheckInterval, certRenewBeforeExpiry)
+	go c.forwardCertEvents(ctx, renewer.Notify())
+
+	return c
+}
+
+// forwardCertEvents relays every renewal notification from renewerNotify
+// as a CertRotation Event on c.certEvents, until ctx is cancelled (by
+// Coordinator.Close).
+func (c *Coordinator) forwardCertEvents(ctx context.Context, renewerNotify <-chan struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-renewerNotify:
+			utils.Info("Coordinator: rotated client certificate %s", c.clientCertPath)
+			select {
+			case c.certEvents <- Event{Type: CertRotation, Time: time.Now(), CertPath: c.clientCertPath}:
+			default:
+			}
+		}
+	}
+}
+
+// ReloadTLS forces an immediate renewal of the coordinator's client
+// certificate, regardless of its remaining validity, instead of waiting
+// for the background CertRenewer's next scheduled check. Returns an error
+// if this Coordinator was built with NewCoordinator instead of
+// NewCoordinatorTLS, since there's no certificate to renew.
+func (c *Coordinator) ReloadTLS() error {
+	if c.certMonitor == nil {
+		return fmt.Errorf("coordinator: TLS is not enabled")
+	}
+	return c.certMonitor.Renew(c.clientCertPath)
+}
+
+// Events returns the channel Coordinator posts rotation events to. Safe to
+// call on a Coordinator built with NewCoordinator: it still returns a
+// (never-firing) channel, since c.certEvents is nil in that case and a nil
+// channel is a valid receive target that just blocks forever.
+func (c *Coordinator) Events() <-chan Event {
+	return c.certEvents
+}
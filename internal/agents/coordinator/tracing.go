@@ -0,0 +1,56 @@
+package coordinator
+
+import (
+	"context"
+
+	"github.com/steve/llm-agents/internal/observability"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTracerProvider wires provider's Tracer into every span Coordinator
+// starts, in place of OpenTelemetry's no-op default.
+func WithTracerProvider(provider trace.TracerProvider) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.tracer = observability.Tracer(provider)
+	}
+}
+
+// WithMeterProvider wires provider's Meter into Coordinator, in place of
+// OpenTelemetry's no-op default, and (re)creates the instruments derived
+// from it.
+func WithMeterProvider(provider metric.MeterProvider) CoordinatorOption {
+	return func(c *Coordinator) {
+		c.meter = observability.Meter(provider)
+		c.queryCounter = newQueryCounter(c.meter)
+	}
+}
+
+// newQueryCounter creates the "coordinator.queries" instrument on meter.
+// Int64Counter only errors on a malformed instrument name, which this
+// package's own constant name never is, so the error is safely discarded.
+func newQueryCounter(meter metric.Meter) metric.Int64Counter {
+	counter, _ := meter.Int64Counter(
+		"coordinator.queries",
+		metric.WithDescription("Total queries processed by the coordinator."),
+	)
+	return counter
+}
+
+// startSpan starts a child span of ctx named name, tagged with
+// observability.RequestAttributes(requestID, taskID) so it can be
+// correlated with the orchestration log entry for the same IDs.
+func (c *Coordinator) startSpan(ctx context.Context, name, requestID, taskID string) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, name, trace.WithAttributes(observability.RequestAttributes(requestID, taskID)...))
+}
+
+// endSpan records err on span (if non-nil) before ending it, so a failed
+// task or plan is visible in trace search without inspecting logs.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
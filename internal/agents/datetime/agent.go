@@ -3,11 +3,13 @@ package datetime
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/steve/llm-agents/internal/agents/client"
 	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/mcperrors"
 	"github.com/steve/llm-agents/internal/models"
 	"github.com/steve/llm-agents/internal/utils"
 )
@@ -52,8 +54,8 @@ func (a *Agent) ProcessRequest(ctx context.Context, request models.AgentRequest)
 			models.AgentTypeDateTime, request.AgentType)
 	}
 
-	if request.City == "" {
-		return nil, fmt.Errorf("city parameter is required for datetime requests")
+	if request.City == "" && request.Timezone == "" {
+		return nil, fmt.Errorf("city or timezone parameter is required for datetime requests")
 	}
 
 	// Create context with timeout
@@ -65,14 +67,25 @@ func (a *Agent) ProcessRequest(ctx context.Context, request models.AgentRequest)
 	}
 
 	// Call datetime MCP server
-	dateTimeData, err := a.mcpClient.CallDateTime(reqCtx, request.City)
+	dateTimeData, err := a.mcpClient.CallDateTime(reqCtx, request.City, request.Timezone)
 	if err != nil {
 		utils.Error("DateTime agent failed to get datetime data for %s: %v", request.City, err)
+
+		// A *mcperrors.Error already carries a message specific enough to
+		// surface as-is (e.g. "mcp error -32001: city \"Atlantis\" not
+		// found"); anything else - a connection or decoding failure - still
+		// gets the generic wrap for context.
+		errMsg := fmt.Sprintf("Failed to retrieve datetime data: %v", err)
+		var mcpErr *mcperrors.Error
+		if errors.As(err, &mcpErr) {
+			errMsg = mcpErr.Error()
+		}
+
 		return &models.AgentResponse{
 			RequestID: request.RequestID,
 			TaskID:    request.TaskID,
 			Success:   false,
-			Error:     fmt.Sprintf("Failed to retrieve datetime data: %v", err),
+			Error:     errMsg,
 		}, nil
 	}
 
@@ -104,3 +117,14 @@ func (a *Agent) Validate() error {
 	}
 	return nil
 }
+
+// Describe reports this agent's identity and input fields to the
+// coordinator's LLM planner prompt.
+func (a *Agent) Describe() models.AgentDescriptor {
+	return models.AgentDescriptor{
+		Type:        models.AgentTypeDateTime,
+		Name:        "DateTime Agent",
+		Purpose:     "Retrieves current date and time information for US cities with timezone handling",
+		InputFields: []string{"city", "timezone"},
+	}
+}
@@ -3,24 +3,66 @@ package echo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/steve/llm-agents/internal/config"
 	"github.com/steve/llm-agents/internal/mcp/client"
 	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/resilience"
 	"github.com/steve/llm-agents/internal/utils"
 )
 
 // Agent implements the echo sub-agent
 type Agent struct {
 	mcpClient *client.Client
+	executor  *resilience.Executor
 }
 
 // NewAgent creates a new echo agent
-func NewAgent(echoServerURL string, timeout time.Duration) *Agent {
+func NewAgent(echoServerURL string, timeout time.Duration, policy resilience.Policy) *Agent {
+	mcpClient, err := client.NewClient(echoServerURL, timeout)
+	if err != nil {
+		utils.Error("Failed to create MCP client: %v", err)
+		return nil
+	}
+	return &Agent{
+		mcpClient: mcpClient,
+		executor:  resilience.NewExecutor(policy),
+	}
+}
+
+// echoServerSPIFFEID is the peer identity NewTLSAgent pins its MCP client
+// to: the echo-mcp server is the only thing this agent ever dials, so an
+// mTLS chain alone (trusting anything our CA signed) is wider than this
+// agent actually needs.
+const echoServerSPIFFEID = "spiffe://llm-agents/server/echo"
+
+// NewTLSAgent creates a new echo agent with TLS support
+func NewTLSAgent(echoServerURL string, timeout time.Duration, tlsConfig *config.TLSConfig, policy resilience.Policy) *Agent {
+	mcpClient, err := client.NewTLSClientWithPeerPin(echoServerURL, timeout, tlsConfig, echoServerSPIFFEID)
+	if err != nil {
+		utils.Error("Failed to create TLS MCP client: %v", err)
+		return nil
+	}
 	return &Agent{
-		mcpClient: client.NewClient(echoServerURL, timeout),
+		mcpClient: mcpClient,
+		executor:  resilience.NewExecutor(policy),
+	}
+}
+
+// isRetryable reports whether err is worth retrying: an open circuit on
+// the underlying MCP client or a context cancellation/deadline means
+// retrying would not help, so only other transport/server errors qualify.
+func isRetryable(err error) bool {
+	if errors.Is(err, client.ErrCircuitOpen) {
+		return false
 	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return true
 }
 
 // ProcessRequest processes an echo request
@@ -45,15 +87,28 @@ func (a *Agent) ProcessRequest(ctx context.Context, request models.AgentRequest)
 		defer cancel()
 	}
 
-	// Call echo MCP server
-	echoData, err := a.mcpClient.CallEcho(reqCtx, request.EchoText)
+	// Call echo MCP server, retrying transient failures with backoff
+	// while a tripped circuit breaker fails fast.
+	var echoData *models.EchoData
+	err := a.executor.Do(reqCtx, isRetryable, func() error {
+		data, callErr := a.mcpClient.CallEcho(reqCtx, request.EchoText)
+		if callErr != nil {
+			return callErr
+		}
+		echoData = data
+		return nil
+	})
 	if err != nil {
 		utils.Error("Echo agent failed to process text: %v", err)
+		errMsg := fmt.Sprintf("Failed to process echo request: %v", err)
+		if errors.Is(err, resilience.ErrBreakerOpen) {
+			errMsg = fmt.Sprintf("Echo service unavailable (circuit breaker open): %v", err)
+		}
 		return &models.AgentResponse{
 			RequestID: request.RequestID,
 			TaskID:    request.TaskID,
 			Success:   false,
-			Error:     fmt.Sprintf("Failed to process echo request: %v", err),
+			Error:     errMsg,
 		}, nil
 	}
 
@@ -85,3 +140,14 @@ func (a *Agent) Validate() error {
 	}
 	return nil
 }
+
+// Describe reports this agent's identity and input fields to the
+// coordinator's LLM planner prompt.
+func (a *Agent) Describe() models.AgentDescriptor {
+	return models.AgentDescriptor{
+		Type:        models.AgentTypeEcho,
+		Name:        "Echo Agent",
+		Purpose:     "Simple text echo functionality (only use when explicitly requested to echo text)",
+		InputFields: []string{"echo_text"},
+	}
+}
@@ -0,0 +1,152 @@
+// Package forecast provides the forecast sub-agent implementation
+package forecast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/mcp/client"
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/resilience"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// Agent implements the forecast sub-agent
+type Agent struct {
+	mcpClient *client.Client
+	executor  *resilience.Executor
+}
+
+// NewAgent creates a new forecast agent
+func NewAgent(forecastServerURL string, timeout time.Duration, policy resilience.Policy) *Agent {
+	mcpClient, err := client.NewClient(forecastServerURL, timeout)
+	if err != nil {
+		utils.Error("Failed to create MCP client: %v", err)
+		return nil
+	}
+	return &Agent{
+		mcpClient: mcpClient,
+		executor:  resilience.NewExecutor(policy),
+	}
+}
+
+// forecastServerSPIFFEID is the peer identity NewTLSAgent pins its MCP
+// client to: the forecast-mcp server is the only thing this agent ever
+// dials, so an mTLS chain alone (trusting anything our CA signed) is wider
+// than this agent actually needs.
+const forecastServerSPIFFEID = "spiffe://llm-agents/server/forecast"
+
+// NewTLSAgent creates a new forecast agent with TLS support
+func NewTLSAgent(forecastServerURL string, timeout time.Duration, tlsConfig *config.TLSConfig, policy resilience.Policy) *Agent {
+	mcpClient, err := client.NewTLSClientWithPeerPin(forecastServerURL, timeout, tlsConfig, forecastServerSPIFFEID)
+	if err != nil {
+		utils.Error("Failed to create TLS MCP client: %v", err)
+		return nil
+	}
+	return &Agent{
+		mcpClient: mcpClient,
+		executor:  resilience.NewExecutor(policy),
+	}
+}
+
+// isRetryable reports whether err is worth retrying: an open circuit on
+// the underlying MCP client or a context cancellation/deadline means
+// retrying would not help, so only other transport/server errors qualify.
+func isRetryable(err error) bool {
+	if errors.Is(err, client.ErrCircuitOpen) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return true
+}
+
+// ProcessRequest processes a forecast request
+func (a *Agent) ProcessRequest(ctx context.Context, request models.AgentRequest) (*models.AgentResponse, error) {
+	utils.Debug("Forecast agent processing request: %+v", request)
+
+	// Validate request
+	if request.AgentType != models.AgentTypeForecast {
+		return nil, fmt.Errorf("invalid agent type: expected %s, got %s",
+			models.AgentTypeForecast, request.AgentType)
+	}
+
+	if request.City == "" {
+		return nil, fmt.Errorf("city parameter is required for forecast requests")
+	}
+
+	// Create context with timeout
+	reqCtx := ctx
+	if request.Timeout > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, request.Timeout)
+		defer cancel()
+	}
+
+	// Call forecast MCP server, retrying transient failures with backoff
+	// while a tripped circuit breaker fails fast.
+	var forecastData *models.ForecastData
+	err := a.executor.Do(reqCtx, isRetryable, func() error {
+		data, callErr := a.mcpClient.CallForecast(reqCtx, request.City, request.Days)
+		if callErr != nil {
+			return callErr
+		}
+		forecastData = data
+		return nil
+	})
+	if err != nil {
+		utils.Error("Forecast agent failed to get forecast data for %s: %v", request.City, err)
+		errMsg := fmt.Sprintf("Failed to retrieve forecast data: %v", err)
+		if errors.Is(err, resilience.ErrBreakerOpen) {
+			errMsg = fmt.Sprintf("Forecast service unavailable (circuit breaker open): %v", err)
+		}
+		return &models.AgentResponse{
+			RequestID: request.RequestID,
+			TaskID:    request.TaskID,
+			Success:   false,
+			Error:     errMsg,
+		}, nil
+	}
+
+	utils.Info("Forecast agent retrieved %d-day forecast for %s", len(forecastData.Days), forecastData.City)
+
+	// Create successful response
+	response := &models.AgentResponse{
+		RequestID: request.RequestID,
+		TaskID:    request.TaskID,
+		Success:   true,
+		Data:      forecastData,
+	}
+
+	return response, nil
+}
+
+// Close closes the agent and cleans up resources
+func (a *Agent) Close() {
+	if a.mcpClient != nil {
+		a.mcpClient.Close()
+	}
+}
+
+// Validate validates the agent configuration
+func (a *Agent) Validate() error {
+	if a.mcpClient == nil {
+		return fmt.Errorf("MCP client is not initialized")
+	}
+	return nil
+}
+
+// Describe reports this agent's identity and input fields to the
+// coordinator's LLM planner prompt.
+func (a *Agent) Describe() models.AgentDescriptor {
+	return models.AgentDescriptor{
+		Type:        models.AgentTypeForecast,
+		Name:        "Forecast Agent",
+		Purpose:     "Retrieves a multi-day forecast (temperature min/max, precipitation chance, conditions) for future days - use for any query about tomorrow, a specific future day, or a range of upcoming days",
+		InputFields: []string{"city", "days"},
+	}
+}
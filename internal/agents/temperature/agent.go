@@ -3,34 +3,68 @@ package temperature
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/steve/llm-agents/internal/config"
 	"github.com/steve/llm-agents/internal/mcp/client"
 	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/resilience"
 	"github.com/steve/llm-agents/internal/utils"
 )
 
 // Agent implements the temperature sub-agent
 type Agent struct {
 	mcpClient *client.Client
+	executor  *resilience.Executor
 }
 
 // NewAgent creates a new temperature agent
-func NewAgent(weatherServerURL string, timeout time.Duration) *Agent {
+func NewAgent(weatherServerURL string, timeout time.Duration, policy resilience.Policy) *Agent {
+	mcpClient, err := client.NewClient(weatherServerURL, timeout)
+	if err != nil {
+		utils.Error("Failed to create MCP client: %v", err)
+		return nil
+	}
 	return &Agent{
-		mcpClient: client.NewClient(weatherServerURL, timeout),
+		mcpClient: mcpClient,
+		executor:  resilience.NewExecutor(policy),
 	}
 }
 
+// weatherServerSPIFFEID is the peer identity NewTLSAgent pins its MCP
+// client to: the weather-mcp server is the only thing this agent ever
+// dials, so an mTLS chain alone (trusting anything our CA signed) is wider
+// than this agent actually needs.
+const weatherServerSPIFFEID = "spiffe://llm-agents/server/weather"
+
 // NewTLSAgent creates a new temperature agent with TLS support
-func NewTLSAgent(weatherServerURL string, timeout time.Duration, tlsConfig *config.TLSConfig) *Agent {
+func NewTLSAgent(weatherServerURL string, timeout time.Duration, tlsConfig *config.TLSConfig, policy resilience.Policy) *Agent {
+	mcpClient, err := client.NewTLSClientWithPeerPin(weatherServerURL, timeout, tlsConfig, weatherServerSPIFFEID)
+	if err != nil {
+		utils.Error("Failed to create TLS MCP client: %v", err)
+		return nil
+	}
 	return &Agent{
-		mcpClient: client.NewTLSClient(weatherServerURL, timeout, tlsConfig),
+		mcpClient: mcpClient,
+		executor:  resilience.NewExecutor(policy),
 	}
 }
 
+// isRetryable reports whether err is worth retrying: an open circuit on
+// the underlying MCP client or a context cancellation/deadline means
+// retrying would not help, so only other transport/server errors qualify.
+func isRetryable(err error) bool {
+	if errors.Is(err, client.ErrCircuitOpen) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return true
+}
+
 // ProcessRequest processes a temperature request
 func (a *Agent) ProcessRequest(ctx context.Context, request models.AgentRequest) (*models.AgentResponse, error) {
 	utils.Debug("Temperature agent processing request: %+v", request)
@@ -53,15 +87,28 @@ func (a *Agent) ProcessRequest(ctx context.Context, request models.AgentRequest)
 		defer cancel()
 	}
 
-	// Call weather MCP server
-	tempData, err := a.mcpClient.CallWeather(reqCtx, request.City)
+	// Call weather MCP server, retrying transient failures with backoff
+	// while a tripped circuit breaker fails fast.
+	var tempData *models.TemperatureData
+	err := a.executor.Do(reqCtx, isRetryable, func() error {
+		data, callErr := a.mcpClient.CallWeather(reqCtx, request.City)
+		if callErr != nil {
+			return callErr
+		}
+		tempData = data
+		return nil
+	})
 	if err != nil {
 		utils.Error("Temperature agent failed to get weather data for %s: %v", request.City, err)
+		errMsg := fmt.Sprintf("Failed to retrieve temperature data: %v", err)
+		if errors.Is(err, resilience.ErrBreakerOpen) {
+			errMsg = fmt.Sprintf("Weather service unavailable (circuit breaker open): %v", err)
+		}
 		return &models.AgentResponse{
 			RequestID: request.RequestID,
 			TaskID:    request.TaskID,
 			Success:   false,
-			Error:     fmt.Sprintf("Failed to retrieve temperature data: %v", err),
+			Error:     errMsg,
 		}, nil
 	}
 
@@ -79,6 +126,51 @@ func (a *Agent) ProcessRequest(ctx context.Context, request models.AgentRequest)
 	return response, nil
 }
 
+// ProcessBatchRequest fetches temperature data for many cities in a single
+// CallWeatherBatch round-trip instead of one ProcessRequest per city. The
+// response is successful as long as the round-trip itself succeeds; a
+// per-city failure is reported in its models.TemperatureBatchEntry.Error
+// rather than failing the whole batch.
+func (a *Agent) ProcessBatchRequest(ctx context.Context, requestID, taskID string, cities []string) (*models.AgentResponse, error) {
+	utils.Debug("Temperature agent processing batch request for %d cities", len(cities))
+
+	if len(cities) == 0 {
+		return nil, fmt.Errorf("at least one city is required for a batch temperature request")
+	}
+
+	var entries []models.TemperatureBatchEntry
+	err := a.executor.Do(ctx, isRetryable, func() error {
+		result, callErr := a.mcpClient.CallWeatherBatch(ctx, cities)
+		if callErr != nil {
+			return callErr
+		}
+		entries = result
+		return nil
+	})
+	if err != nil {
+		utils.Error("Temperature agent failed to get weather batch for %d cities: %v", len(cities), err)
+		errMsg := fmt.Sprintf("Failed to retrieve temperature batch data: %v", err)
+		if errors.Is(err, resilience.ErrBreakerOpen) {
+			errMsg = fmt.Sprintf("Weather service unavailable (circuit breaker open): %v", err)
+		}
+		return &models.AgentResponse{
+			RequestID: requestID,
+			TaskID:    taskID,
+			Success:   false,
+			Error:     errMsg,
+		}, nil
+	}
+
+	utils.Info("Temperature agent retrieved batch data for %d cities", len(entries))
+
+	return &models.AgentResponse{
+		RequestID: requestID,
+		TaskID:    taskID,
+		Success:   true,
+		Data:      entries,
+	}, nil
+}
+
 // Close closes the agent and cleans up resources
 func (a *Agent) Close() {
 	if a.mcpClient != nil {
@@ -93,3 +185,14 @@ func (a *Agent) Validate() error {
 	}
 	return nil
 }
+
+// Describe reports this agent's identity and input fields to the
+// coordinator's LLM planner prompt.
+func (a *Agent) Describe() models.AgentDescriptor {
+	return models.AgentDescriptor{
+		Type:        models.AgentTypeTemperature,
+		Name:        "Temperature Agent",
+		Purpose:     `Retrieves the current temperature and weather conditions "right now" for US cities`,
+		InputFields: []string{"city"},
+	}
+}
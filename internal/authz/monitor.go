@@ -0,0 +1,88 @@
+package authz
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// Monitor holds the live Policy loaded from a file and keeps it current,
+// reloading on SIGHUP the same way tls.CertReloader does for certificates:
+// an operator can push a new policy file and signal the process instead of
+// restarting it.
+type Monitor struct {
+	path string
+
+	mu     sync.RWMutex
+	policy *Policy
+
+	sigCh  chan os.Signal
+	stopCh chan struct{}
+}
+
+// NewMonitor loads the policy at path, starts watching for SIGHUP, and
+// returns a Monitor ready for Allows.
+func NewMonitor(path string) (*Monitor, error) {
+	m := &Monitor{
+		path:   path,
+		sigCh:  make(chan os.Signal, 1),
+		stopCh: make(chan struct{}),
+	}
+
+	if err := m.reload(); err != nil {
+		return nil, err
+	}
+
+	signal.Notify(m.sigCh, syscall.SIGHUP)
+	go m.run()
+	return m, nil
+}
+
+// Reload forces an immediate re-read of the policy file, independent of the
+// automatic SIGHUP handling NewMonitor installs.
+func (m *Monitor) Reload() error {
+	return m.reload()
+}
+
+// Allows reports whether role may call tool under the currently loaded
+// policy.
+func (m *Monitor) Allows(role, tool string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.policy.Allows(role, tool)
+}
+
+// Stop stops watching for SIGHUP. The Monitor's last-loaded policy remains
+// usable; Stop just ends the background reload goroutine.
+func (m *Monitor) Stop() {
+	signal.Stop(m.sigCh)
+	close(m.stopCh)
+}
+
+func (m *Monitor) reload() error {
+	policy, err := LoadPolicy(m.path)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.policy = policy
+	m.mu.Unlock()
+	utils.Info("authz: loaded policy from %s", m.path)
+	return nil
+}
+
+func (m *Monitor) run() {
+	for {
+		select {
+		case <-m.sigCh:
+			if err := m.reload(); err != nil {
+				utils.Warn("authz: failed to reload policy from %s: %v", m.path, err)
+			}
+		case <-m.stopCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Package authz authorizes MCP tool calls against the caller's SPIFFE-style
+// client-certificate identity (spiffe://llm-agents/<role>/<agent-name>,
+// encoded by tls.CertificateManager.GenerateClientCertForIdentity), instead
+// of the coarser allow-any-identity-that-presents-a-cert model
+// httpx.ClientCertIdentity's CN allowlist provides on its own.
+package authz
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policy maps each SPIFFE role to the MCP tool names that role is permitted
+// to call, e.g. role "datetime-agent" may be the only one listed for tool
+// "getDateTime".
+type Policy struct {
+	Roles map[string][]string `yaml:"roles"`
+}
+
+// LoadPolicy loads and parses a Policy from a YAML file.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authz policy file: %w", err)
+	}
+
+	var policy Policy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse authz policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// Allows reports whether role may call tool. A role with no entry in p.Roles
+// is denied: unlike httpx.ClientCertIdentity's empty-allowlist-means-allow-all
+// convention, a configured Policy has no implicit default-allow, since its
+// whole purpose is to narrow what an already-authenticated caller can do.
+func (p *Policy) Allows(role, tool string) bool {
+	if p == nil {
+		return true
+	}
+	for _, allowed := range p.Roles[role] {
+		if allowed == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleFromSPIFFEID extracts <role> from a spiffe://llm-agents/<role>/<name>
+// URI, as encoded by tls.CertificateManager.GenerateClientCertForIdentity.
+// Returns "" if spiffeID isn't in that form (e.g. no client certificate was
+// presented, or it predates SPIFFE-identity issuance).
+func RoleFromSPIFFEID(spiffeID string) string {
+	const prefix = "spiffe://llm-agents/"
+	if !strings.HasPrefix(spiffeID, prefix) {
+		return ""
+	}
+	rest := strings.TrimPrefix(spiffeID, prefix)
+	role, _, ok := strings.Cut(rest, "/")
+	if !ok {
+		return ""
+	}
+	return role
+}
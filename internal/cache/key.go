@@ -0,0 +1,29 @@
+// Package cache provides the response cache and hit tracker backing the
+// coordinator's query prefetcher: a TTL cache of warmed QueryResponse data
+// keyed by (city, intent), and a persisted record of how often each
+// (city, intent) tuple is queried.
+package cache
+
+import "strings"
+
+// QueryKey identifies a cacheable query by its normalized city and intent,
+// so "Boston" and " boston " asking about the same thing share a cache
+// entry and hit count.
+type QueryKey struct {
+	City   string
+	Intent string
+}
+
+// NewQueryKey normalizes city and intent for use as a cache/tracker key.
+func NewQueryKey(city, intent string) QueryKey {
+	return QueryKey{
+		City:   strings.ToLower(strings.TrimSpace(city)),
+		Intent: strings.ToLower(strings.TrimSpace(intent)),
+	}
+}
+
+// String returns a stable textual form of the key, e.g. for log messages
+// and synthesized prefetch query IDs.
+func (k QueryKey) String() string {
+	return k.City + "|" + k.Intent
+}
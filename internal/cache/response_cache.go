@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/steve/llm-agents/internal/models"
+)
+
+// responseCacheEntry is a cached QueryResponse together with the time it
+// expires.
+type responseCacheEntry struct {
+	response  *models.QueryResponse
+	expiresAt time.Time
+}
+
+// ResponseCache is a small in-memory TTL cache of QueryResponse keyed by
+// QueryKey, so a Prefetcher's warmed response for a hot (city, intent)
+// tuple sits ready for ProcessQuery to return immediately instead of
+// re-running the orchestration plan.
+type ResponseCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[QueryKey]responseCacheEntry
+}
+
+// NewResponseCache creates a ResponseCache whose entries expire ttl after
+// they're set.
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	return &ResponseCache{
+		ttl:     ttl,
+		entries: make(map[QueryKey]responseCacheEntry),
+	}
+}
+
+// Get returns the cached response for key if it hasn't expired.
+func (c *ResponseCache) Get(key QueryKey) (*models.QueryResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.response, true
+}
+
+// Set caches response under key for the cache's configured TTL.
+func (c *ResponseCache) Set(key QueryKey, response *models.QueryResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = responseCacheEntry{
+		response:  response,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
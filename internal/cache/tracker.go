@@ -0,0 +1,179 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// hitRecord is one QueryKey's access history, persisted to disk so the
+// Prefetcher's notion of "hot" queries survives a restart instead of
+// starting cold every time.
+type hitRecord struct {
+	City       string    `json:"city"`
+	Intent     string    `json:"intent"`
+	Hits       int       `json:"hits"`
+	LastAccess time.Time `json:"last_access"`
+}
+
+// recordsPerSave is how many Record calls HitTracker batches before
+// persisting to disk, so a hot path that calls Record on every query
+// (including cache hits) doesn't pay a JSON marshal plus os.WriteFile +
+// os.Rename on every single one of them.
+const recordsPerSave = 20
+
+// HitTracker records how often each (city, intent) QueryKey is queried, so
+// a Prefetcher can pick the top-K hottest tuples to keep warm. path, if
+// set, persists the tracker as JSON via a temp file plus rename, same as
+// weather.CachedGeocoder's on-disk cache. Persistence is debounced (see
+// recordsPerSave and Close) rather than written on every Record, since
+// Record sits on the query-processing hot path.
+type HitTracker struct {
+	path string
+
+	mu          sync.Mutex
+	records     map[QueryKey]*hitRecord
+	unsavedHits int
+}
+
+// NewHitTracker creates a HitTracker persisted as JSON at path. An empty
+// or unreadable path starts with no history rather than failing - the
+// tracker still works, it just starts cold.
+func NewHitTracker(path string) *HitTracker {
+	t := &HitTracker{
+		path:    path,
+		records: make(map[QueryKey]*hitRecord),
+	}
+	t.load()
+	return t
+}
+
+// Record marks one access to key, incrementing its hit count and updating
+// its last-access time. The tracker is persisted to disk every
+// recordsPerSave calls rather than on every one, since Record runs on every
+// cacheable query (including cache hits, ahead of the cache lookup) and a
+// blocking write there would add disk latency to the path the response
+// cache exists to avoid. Call Close to flush any unsaved hits, e.g. on
+// shutdown.
+func (t *HitTracker) Record(key QueryKey) {
+	t.mu.Lock()
+	rec, ok := t.records[key]
+	if !ok {
+		rec = &hitRecord{City: key.City, Intent: key.Intent}
+		t.records[key] = rec
+	}
+	rec.Hits++
+	rec.LastAccess = time.Now()
+	t.unsavedHits++
+	shouldSave := t.unsavedHits >= recordsPerSave
+	if shouldSave {
+		t.unsavedHits = 0
+	}
+	t.mu.Unlock()
+
+	if shouldSave {
+		t.save()
+	}
+}
+
+// Close flushes any hits Record has batched since the last save. Callers
+// that want every recorded hit persisted before exiting (rather than only
+// every recordsPerSave-th one) should call this on shutdown.
+func (t *HitTracker) Close() {
+	t.mu.Lock()
+	t.unsavedHits = 0
+	t.mu.Unlock()
+	t.save()
+}
+
+// TopK returns the k QueryKeys with the highest hit count, ties broken by
+// most recent access, for a Prefetcher's warm-cache pass. It returns fewer
+// than k keys if the tracker hasn't seen that many distinct tuples yet.
+func (t *HitTracker) TopK(k int) []QueryKey {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keys := make([]QueryKey, 0, len(t.records))
+	for key := range t.records {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ri, rj := t.records[keys[i]], t.records[keys[j]]
+		if ri.Hits != rj.Hits {
+			return ri.Hits > rj.Hits
+		}
+		return ri.LastAccess.After(rj.LastAccess)
+	})
+
+	if k < len(keys) {
+		keys = keys[:k]
+	}
+	return keys
+}
+
+// load populates the tracker from t.path, if set and readable. A missing
+// or corrupt file is logged and otherwise ignored - the tracker starts
+// empty rather than failing construction.
+func (t *HitTracker) load() {
+	if t.path == "" {
+		return
+	}
+
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return
+	}
+
+	var records []hitRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		utils.Warn("hit tracker store at %s is corrupt, starting empty: %v", t.path, err)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range records {
+		rec := records[i]
+		t.records[QueryKey{City: rec.City, Intent: rec.Intent}] = &rec
+	}
+}
+
+// save writes the tracker to t.path as JSON via a temp file plus rename,
+// so a concurrent reader never observes a partial write.
+func (t *HitTracker) save() {
+	if t.path == "" {
+		return
+	}
+
+	t.mu.Lock()
+	records := make([]hitRecord, 0, len(t.records))
+	for _, rec := range t.records {
+		records = append(records, *rec)
+	}
+	t.mu.Unlock()
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		utils.Warn("failed to marshal hit tracker store: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		utils.Warn("failed to create hit tracker store directory: %v", err)
+		return
+	}
+
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		utils.Warn("failed to write hit tracker store: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		utils.Warn("failed to persist hit tracker store: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/steve/llm-agents/internal/agents/coordinator"
 	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/llm"
 	"github.com/steve/llm-agents/internal/models"
 	"github.com/steve/llm-agents/internal/utils"
 )
@@ -26,16 +28,33 @@ func NewApp() *App {
 	return &App{}
 }
 
-// Run runs the CLI application
+// Run runs the CLI application. args[1] == "cert" is dispatched to the
+// cert subcommand family ("check", "revoke"), args[1] == "repl" to the
+// interactive REPL, and args[1] == "daemon" to the prefetch daemon, instead
+// of the flat query flags every other invocation parses.
 func (a *App) Run(args []string) error {
+	if len(args) > 1 && args[1] == "cert" {
+		return runCertCommand(args[2:])
+	}
+	if len(args) > 1 && args[1] == "repl" {
+		return a.runInteractive(args[2:])
+	}
+	if len(args) > 1 && args[1] == "daemon" {
+		return a.runDaemon(args[2:])
+	}
+
 	// Parse command-line flags
 	fs := flag.NewFlagSet("llm-agents", flag.ExitOnError)
 
 	var (
-		city    = fs.String("city", "", "City name for weather/datetime queries (required)")
-		query   = fs.String("query", "", "Query text (required)")
-		verbose = fs.Bool("verbose", false, "Enable verbose output")
-		version = fs.Bool("version", false, "Show version information")
+		city        = fs.String("city", "", "City name for weather/datetime queries (required)")
+		timezone    = fs.String("timezone", "", "IANA timezone (e.g. Europe/Berlin) for datetime queries, bypassing city lookup")
+		query       = fs.String("query", "", "Query text (required)")
+		verbose     = fs.Bool("verbose", false, "Enable verbose output")
+		version     = fs.Bool("version", false, "Show version information")
+		format      = fs.String("format", "text", "Output format: text, json, ndjson")
+		interactive = fs.Bool("interactive", false, "Start an interactive REPL instead of a one-shot query")
+		stream      = fs.Bool("stream", false, "Stream orchestration events to stdout as NDJSON as they happen, instead of waiting for the final result")
 	)
 
 	fs.Usage = func() {
@@ -47,12 +66,21 @@ func (a *App) Run(args []string) error {
 		fmt.Fprintf(os.Stderr, "  %s -city \"New York\" -query \"What's the temperature?\"\n", fs.Name())
 		fmt.Fprintf(os.Stderr, "  %s -city \"Los Angeles\" -query \"What time is it?\"\n", fs.Name())
 		fmt.Fprintf(os.Stderr, "  %s -city \"Chicago\" -query \"What's the weather and time?\"\n", fs.Name())
+		fmt.Fprintf(os.Stderr, "  %s -city \"Denver\" -query \"5-day forecast\"\n", fs.Name())
 		fmt.Fprintf(os.Stderr, "  %s -query \"echo hello world\"\n", fs.Name())
+		fmt.Fprintf(os.Stderr, "  %s -city \"Tokyo\" -query \"What's the time?\" -format json\n", fs.Name())
+		fmt.Fprintf(os.Stderr, "  %s -timezone \"Europe/Berlin\" -query \"What's the time?\"\n", fs.Name())
+		fmt.Fprintf(os.Stderr, "  %s -city \"Denver\" -query \"weather and echo hello\" -stream\n", fs.Name())
+		fmt.Fprintf(os.Stderr, "  %s repl\n", fs.Name())
+		fmt.Fprintf(os.Stderr, "  %s daemon -prefetch-top=20 -prefetch-interval=10m\n", fs.Name())
 		fmt.Fprintf(os.Stderr, "\nEnvironment Variables:\n")
 		fmt.Fprintf(os.Stderr, "  OPENROUTER_API_KEY    OpenRouter API key for Claude access (required)\n")
 		fmt.Fprintf(os.Stderr, "  WEATHER_SERVER_URL    Weather MCP server URL (default: http://localhost:8081)\n")
 		fmt.Fprintf(os.Stderr, "  DATETIME_SERVER_URL   DateTime MCP server URL (default: http://localhost:8082)\n")
 		fmt.Fprintf(os.Stderr, "  ECHO_SERVER_URL       Echo MCP server URL (default: http://localhost:8083)\n")
+		fmt.Fprintf(os.Stderr, "  MCP_FORECAST_URL      Forecast MCP server URL (default: http://localhost:8085)\n")
+		fmt.Fprintf(os.Stderr, "  PREFETCH_CACHE_TTL    How long a prefetched response stays warm (default: 15m)\n")
+		fmt.Fprintf(os.Stderr, "  PREFETCH_STORE_PATH   Path to persist the prefetch hit tracker (default: none)\n")
 		fmt.Fprintf(os.Stderr, "  LOG_LEVEL            Log level: debug, info, warn, error (default: info)\n")
 	}
 
@@ -60,6 +88,16 @@ func (a *App) Run(args []string) error {
 		return fmt.Errorf("failed to parse flags: %w", err)
 	}
 
+	renderer, err := NewRenderer(*format)
+	if err != nil {
+		fs.Usage()
+		return err
+	}
+
+	if *interactive {
+		return a.runInteractive(nil)
+	}
+
 	// Show version if requested
 	if *version {
 		fmt.Printf("llm-agents version %s\n", getVersion())
@@ -72,8 +110,9 @@ func (a *App) Run(args []string) error {
 		return fmt.Errorf("query is required")
 	}
 
-	// Check if query requires a city (not echo queries)
-	if !isEchoQuery(*query) && *city == "" {
+	// Check if query requires a city (not echo queries); -timezone bypasses
+	// this for a datetime query that already names its IANA zone.
+	if !isEchoQuery(*query) && *city == "" && *timezone == "" {
 		fs.Usage()
 		return fmt.Errorf("city is required for weather/datetime queries")
 	}
@@ -100,14 +139,27 @@ func (a *App) Run(args []string) error {
 	}
 
 	// Process the query
-	return a.processQuery(*query, *city, *verbose)
+	if *stream {
+		return a.processQueryStream(*query, *city, *timezone)
+	}
+	return a.processQuery(*query, *city, *timezone, *verbose, renderer)
 }
 
 // initializeCoordinator initializes the coordinator with sub-agents
 func (a *App) initializeCoordinator() error {
-	// Validate OpenRouter API key
-	if a.config.OpenRouterAPIKey == "" {
-		return fmt.Errorf("OPENROUTER_API_KEY environment variable is required")
+	// Validate the LLM API key (not required for a keyless provider like
+	// "ollama")
+	if a.config.LLMProvider != "ollama" && a.config.LLMAPIKey == "" {
+		return fmt.Errorf("LLM_API_KEY (or OPENROUTER_API_KEY) environment variable is required")
+	}
+
+	planner, err := llm.New(a.config.LLMProvider, llm.Config{
+		APIKey:  a.config.LLMAPIKey,
+		BaseURL: a.config.LLMBaseURL,
+		Model:   a.config.LLMModel,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize LLM provider: %w", err)
 	}
 
 	// Create coordinator
@@ -116,24 +168,34 @@ func (a *App) initializeCoordinator() error {
 		a.config.WeatherMCPURL,
 		a.config.DateTimeMCPURL,
 		a.config.EchoMCPURL,
+		a.config.ForecastMCPURL,
 		a.config.MCPTimeout,
+		a.config.Resilience,
+		a.config.PrefetchCacheTTL,
+		a.config.PrefetchStorePath,
+		coordinator.WithPlanner(planner),
 	)
 
 	utils.Info("Coordinator initialized with servers:")
 	utils.Info("  Weather: %s", a.config.WeatherMCPURL)
 	utils.Info("  DateTime: %s", a.config.DateTimeMCPURL)
 	utils.Info("  Echo: %s", a.config.EchoMCPURL)
+	utils.Info("  Forecast: %s", a.config.ForecastMCPURL)
 
 	return nil
 }
 
-// processQuery processes the user query
-func (a *App) processQuery(queryText, city string, verbose bool) error {
+// processQuery processes the user query, rendering the outcome - success
+// or failure - through renderer so a JSON/NDJSON caller still gets a
+// structured record on failure instead of losing it to a bare stderr
+// message.
+func (a *App) processQuery(queryText, city, timezone string, verbose bool, renderer Renderer) error {
 	// Create query
 	query := models.Query{
 		ID:        generateQueryID(),
 		Text:      queryText,
 		City:      city,
+		Timezone:  timezone,
 		Timestamp: time.Now(),
 	}
 
@@ -141,6 +203,9 @@ func (a *App) processQuery(queryText, city string, verbose bool) error {
 	if city != "" {
 		utils.Info("Target city: %s", city)
 	}
+	if timezone != "" {
+		utils.Info("Target timezone: %s", timezone)
+	}
 
 	// Process query with coordinator
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -148,85 +213,57 @@ func (a *App) processQuery(queryText, city string, verbose bool) error {
 
 	response, err := a.coordinator.ProcessQuery(ctx, query)
 	if err != nil {
-		return fmt.Errorf("query processing failed: %w", err)
+		queryErr := fmt.Errorf("query processing failed: %w", err)
+		if renderErr := renderer.RenderError(queryErr); renderErr != nil {
+			utils.Error("Failed to render error record: %v", renderErr)
+		}
+		return queryErr
 	}
 
-	// Display results
-	a.displayResults(response, verbose)
+	if err := renderer.RenderResult(response, verbose); err != nil {
+		return fmt.Errorf("failed to render result: %w", err)
+	}
 
 	return nil
 }
 
-// displayResults displays the query results
-func (a *App) displayResults(response *models.QueryResponse, verbose bool) {
-	fmt.Printf("Query ID: %s\n", response.QueryID)
-	fmt.Printf("Message: %s\n", response.Message)
-	fmt.Printf("Duration: %s\n", response.Duration)
-	fmt.Printf("Invoked agents: %s\n", formatAgentList(response.InvokedAgents))
-
-	if len(response.Errors) > 0 {
-		fmt.Printf("Errors: %v\n", response.Errors)
+// processQueryStream is processQuery's -stream counterpart: it drives the
+// query through Coordinator.ProcessQueryStream and writes each
+// models.QueryEvent to stdout as one NDJSON line as soon as it happens,
+// instead of collecting a single QueryResponse and rendering it through a
+// Renderer once everything has finished. -format is ignored under -stream,
+// since there is no post-hoc result left to render in text/json form.
+func (a *App) processQueryStream(queryText, city, timezone string) error {
+	query := models.Query{
+		ID:        generateQueryID(),
+		Text:      queryText,
+		City:      city,
+		Timezone:  timezone,
+		Timestamp: time.Now(),
 	}
 
-	fmt.Println()
-
-	// Display results by type
-	if response.Temperature != nil {
-		a.displayTemperatureData(response.Temperature)
-	}
+	utils.Info("Streaming query: %s", queryText)
 
-	if response.DateTime != nil {
-		a.displayDateTimeData(response.DateTime)
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
 
-	if response.Echo != nil {
-		a.displayEchoData(response.Echo)
+	events, err := a.coordinator.ProcessQueryStream(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to start query stream: %w", err)
 	}
 
-	// Display verbose information if requested
-	if verbose {
-		a.displayVerboseInfo(response)
+	enc := json.NewEncoder(os.Stdout)
+	var queryErr error
+	for event := range events {
+		if err := enc.Encode(event); err != nil {
+			return fmt.Errorf("failed to encode stream event: %w", err)
+		}
+		if event.Type == models.EventQueryCompleted && event.Error != "" {
+			queryErr = fmt.Errorf("query processing failed: %s", event.Error)
+		}
 	}
-}
-
-// displayTemperatureData displays temperature information
-func (a *App) displayTemperatureData(data *models.TemperatureData) {
-	fmt.Printf("üå°Ô∏è  Temperature in %s:\n", data.City)
-	fmt.Printf("   Temperature: %.1f¬∞%s\n", data.Temperature, data.Unit)
-	fmt.Printf("   Conditions: %s\n", data.Description)
-	fmt.Printf("   Source: %s\n", data.Source)
-	fmt.Printf("   Retrieved: %s\n", data.Timestamp.Format(time.RFC3339))
-	fmt.Println()
-}
-
-// displayDateTimeData displays datetime information
-func (a *App) displayDateTimeData(data *models.DateTimeData) {
-	fmt.Printf("üïê Time in %s:\n", data.City)
-	fmt.Printf("   Local time: %s\n", data.DateTime.Format("2006-01-02 15:04:05"))
-	fmt.Printf("   Timezone: %s\n", data.Timezone)
-	fmt.Printf("   UTC offset: %s\n", data.UTCOffset)
-	fmt.Printf("   Retrieved: %s\n", data.Timestamp.Format(time.RFC3339))
-	fmt.Println()
-}
 
-// displayEchoData displays echo information
-func (a *App) displayEchoData(data *models.EchoData) {
-	fmt.Printf("üîä Echo result:\n")
-	fmt.Printf("   Original: %s\n", data.OriginalText)
-	fmt.Printf("   Echo: %s\n", data.EchoText)
-	fmt.Printf("   Retrieved: %s\n", data.Timestamp.Format(time.RFC3339))
-	fmt.Println()
-}
-
-// displayVerboseInfo displays verbose orchestration information
-func (a *App) displayVerboseInfo(response *models.QueryResponse) {
-	fmt.Println("üìã Orchestration Details:")
-
-	fmt.Printf("   Execution log:\n")
-	for i, entry := range response.OrchestrationLog {
-		fmt.Printf("     %d. %s\n", i+1, entry)
-	}
-	fmt.Println()
+	return queryErr
 }
 
 // Helper functions
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/steve/llm-agents/internal/agents/coordinator"
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// runDaemon starts the coordinator's Prefetcher and blocks until
+// SIGINT/SIGTERM, so the hottest (city, intent) queries stay warm in the
+// response cache without an interactive query driving each refresh.
+func (a *App) runDaemon(args []string) error {
+	fs := flag.NewFlagSet("llm-agents daemon", flag.ExitOnError)
+	prefetchTop := fs.Int("prefetch-top", 10, "Number of hottest (city, intent) queries to keep warm")
+	prefetchInterval := fs.Duration("prefetch-interval", 15*time.Minute, "How often to refresh the prefetch cache")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	a.config = config.Load()
+	utils.SetLogLevel(a.config.LogLevel)
+
+	if err := a.initializeCoordinator(); err != nil {
+		return fmt.Errorf("failed to initialize coordinator: %w", err)
+	}
+	defer a.coordinator.Close()
+
+	if err := a.coordinator.Validate(); err != nil {
+		return fmt.Errorf("coordinator validation failed: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	prefetcher := coordinator.NewPrefetcher(a.coordinator, *prefetchTop, *prefetchInterval)
+	prefetcher.Start(ctx)
+
+	utils.Info("Prefetch daemon running (top=%d, interval=%s) - press Ctrl-C to stop", *prefetchTop, *prefetchInterval)
+	<-ctx.Done()
+	utils.Info("Prefetch daemon shutting down")
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/steve/llm-agents/internal/models"
+)
+
+// Renderer formats a query's outcome - success or failure - for a specific
+// output consumer: TextRenderer for a human at a terminal, JSONRenderer and
+// NDJSONRenderer for a program piping llm-agents' stdout into something
+// like jq or a log pipeline.
+type Renderer interface {
+	// RenderResult renders a successful QueryResponse.
+	RenderResult(response *models.QueryResponse, verbose bool) error
+
+	// RenderError renders a query failure. TextRenderer's implementation is
+	// a no-op, since main already prints the error to stderr; the
+	// JSON/NDJSON renderers instead emit a structured record to stdout so a
+	// caller piping output still gets well-formed JSON on failure.
+	RenderError(err error) error
+}
+
+// NewRenderer constructs the Renderer named by format ("text", "json", or
+// "ndjson"), defaulting to TextRenderer for an empty name.
+func NewRenderer(format string) (Renderer, error) {
+	switch format {
+	case "", "text":
+		return &TextRenderer{}, nil
+	case "json":
+		return &JSONRenderer{}, nil
+	case "ndjson":
+		return &NDJSONRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format: %s (want text, json, or ndjson)", format)
+	}
+}
+
+// TextRenderer prints the emoji-decorated human-readable summary App has
+// always produced.
+type TextRenderer struct{}
+
+// RenderResult prints response as the original human-readable summary.
+func (r *TextRenderer) RenderResult(response *models.QueryResponse, verbose bool) error {
+	displayResults(response, verbose)
+	return nil
+}
+
+// RenderError is a no-op: main already prints "Error: %v" to stderr for
+// every format, which is all text mode needs.
+func (r *TextRenderer) RenderError(err error) error {
+	return nil
+}
+
+// JSONRenderer marshals the full models.QueryResponse - including
+// OrchestrationLog, per-agent timing, and errors - as a single JSON object
+// to stdout.
+type JSONRenderer struct{}
+
+// RenderResult writes response to stdout as one JSON object.
+func (r *JSONRenderer) RenderResult(response *models.QueryResponse, verbose bool) error {
+	return json.NewEncoder(os.Stdout).Encode(response)
+}
+
+// RenderError writes a structured error record to stdout, so a caller
+// piping output through jq still gets well-formed JSON on failure instead
+// of losing the error to stderr.
+func (r *JSONRenderer) RenderError(err error) error {
+	return json.NewEncoder(os.Stdout).Encode(jsonErrorRecord{Error: err.Error(), Timestamp: time.Now()})
+}
+
+// jsonErrorRecord is the structured error record JSONRenderer and
+// NDJSONRenderer emit on failure.
+type jsonErrorRecord struct {
+	Error     string    `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NDJSONRenderer streams one JSON line per orchestration event - each
+// OrchestrationLog entry, then a final "result" event carrying the full
+// response - instead of JSONRenderer's single object, for a consumer that
+// wants to ingest a query's progress as a log pipeline rather than wait
+// for one big object.
+type NDJSONRenderer struct{}
+
+// ndjsonEvent is one line of NDJSONRenderer's output.
+type ndjsonEvent struct {
+	Event     string                `json:"event"`
+	Message   string                `json:"message,omitempty"`
+	Response  *models.QueryResponse `json:"response,omitempty"`
+	Error     string                `json:"error,omitempty"`
+	Timestamp time.Time             `json:"timestamp"`
+}
+
+// RenderResult streams one "log" event per response.OrchestrationLog
+// entry, followed by a final "result" event carrying response in full.
+func (r *NDJSONRenderer) RenderResult(response *models.QueryResponse, verbose bool) error {
+	enc := json.NewEncoder(os.Stdout)
+
+	for _, entry := range response.OrchestrationLog {
+		if err := enc.Encode(ndjsonEvent{Event: "log", Message: entry, Timestamp: time.Now()}); err != nil {
+			return err
+		}
+	}
+
+	return enc.Encode(ndjsonEvent{Event: "result", Response: response, Timestamp: time.Now()})
+}
+
+// RenderError streams a single "error" event.
+func (r *NDJSONRenderer) RenderError(err error) error {
+	return json.NewEncoder(os.Stdout).Encode(ndjsonEvent{Event: "error", Error: err.Error(), Timestamp: time.Now()})
+}
+
+// displayResults prints response as TextRenderer's human-readable summary.
+func displayResults(response *models.QueryResponse, verbose bool) {
+	fmt.Printf("Query ID: %s\n", response.QueryID)
+	fmt.Printf("Message: %s\n", response.Message)
+	fmt.Printf("Duration: %s\n", response.Duration)
+	fmt.Printf("Invoked agents: %s\n", formatAgentList(response.InvokedAgents))
+
+	if len(response.Errors) > 0 {
+		fmt.Printf("Errors: %v\n", response.Errors)
+	}
+
+	fmt.Println()
+
+	if response.Temperature != nil {
+		displayTemperatureData(response.Temperature)
+	}
+
+	if response.DateTime != nil {
+		displayDateTimeData(response.DateTime)
+	}
+
+	if response.Echo != nil {
+		displayEchoData(response.Echo)
+	}
+
+	if response.Forecast != nil {
+		displayForecastData(response.Forecast)
+	}
+
+	if verbose {
+		displayVerboseInfo(response)
+	}
+}
+
+// displayTemperatureData displays temperature information
+func displayTemperatureData(data *models.TemperatureData) {
+	fmt.Printf("🌡️  Temperature in %s:\n", data.City)
+	fmt.Printf("   Temperature: %.1f°%s\n", data.Temperature, data.Unit)
+	fmt.Printf("   Conditions: %s\n", data.Description)
+	fmt.Printf("   Source: %s\n", data.Source)
+	fmt.Printf("   Retrieved: %s\n", data.Timestamp.Format(time.RFC3339))
+	fmt.Println()
+}
+
+// displayDateTimeData displays datetime information
+func displayDateTimeData(data *models.DateTimeData) {
+	label := data.City
+	if label == "" {
+		label = data.Timezone
+	}
+	fmt.Printf("🕐 Time in %s:\n", label)
+	fmt.Printf("   Local time: %s\n", data.DateTime.Format("2006-01-02 15:04:05"))
+	fmt.Printf("   Timezone: %s\n", data.Timezone)
+	fmt.Printf("   UTC offset: %s\n", data.UTCOffset)
+	fmt.Printf("   Daylight saving: %s\n", dstLabel(data))
+	fmt.Printf("   Retrieved: %s\n", data.Timestamp.Format(time.RFC3339))
+	fmt.Println()
+}
+
+// dstLabel renders a DateTimeData's DST fields as a one-line summary, e.g.
+// "yes (UTC+02:00, standard UTC+01:00)" or "no (UTC+01:00)" when standard
+// and DST offsets coincide.
+func dstLabel(data *models.DateTimeData) string {
+	if !data.IsDST {
+		return fmt.Sprintf("no (%s)", formatOffsetSeconds(data.StandardOffsetSeconds))
+	}
+	return fmt.Sprintf("yes (%s, standard %s)",
+		formatOffsetSeconds(data.DSTOffsetSeconds), formatOffsetSeconds(data.StandardOffsetSeconds))
+}
+
+// formatOffsetSeconds renders a UTC offset given in seconds as "UTC+02:00".
+func formatOffsetSeconds(offsetSeconds int) string {
+	sign := "+"
+	if offsetSeconds < 0 {
+		sign = "-"
+		offsetSeconds = -offsetSeconds
+	}
+	return fmt.Sprintf("UTC%s%02d:%02d", sign, offsetSeconds/3600, (offsetSeconds%3600)/60)
+}
+
+// displayEchoData displays echo information
+func displayEchoData(data *models.EchoData) {
+	fmt.Printf("🔊 Echo result:\n")
+	fmt.Printf("   Original: %s\n", data.OriginalText)
+	fmt.Printf("   Echo: %s\n", data.EchoText)
+	fmt.Printf("   Retrieved: %s\n", data.Timestamp.Format(time.RFC3339))
+	fmt.Println()
+}
+
+// displayForecastData displays a multi-day forecast as a compact table
+func displayForecastData(data *models.ForecastData) {
+	fmt.Printf("📅 Forecast for %s:\n", data.City)
+	fmt.Printf("   %-10s %8s %8s %6s  %s\n", "Date", "Low", "High", "Rain%", "Conditions")
+	for _, day := range data.Days {
+		fmt.Printf("   %-10s %7.1f%s %7.1f%s %5.0f%%  %s\n",
+			day.Date, day.TemperatureMin, data.Unit, day.TemperatureMax, data.Unit,
+			day.PrecipitationChance, day.Description)
+	}
+	fmt.Printf("   Retrieved: %s\n", data.Timestamp.Format(time.RFC3339))
+	fmt.Println()
+}
+
+// displayVerboseInfo displays verbose orchestration information
+func displayVerboseInfo(response *models.QueryResponse) {
+	fmt.Println("📋 Orchestration Details:")
+
+	fmt.Printf("   Execution log:\n")
+	for i, entry := range response.OrchestrationLog {
+		fmt.Printf("     %d. %s\n", i+1, entry)
+	}
+	fmt.Println()
+}
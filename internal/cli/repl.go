@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// replHistoryLimit bounds how many turns a REPL session keeps in its
+// rolling conversation history, so an all-day session doesn't grow the
+// orchestration prompt without bound.
+const replHistoryLimit = 20
+
+// runInteractive starts the REPL: a prompt loop that reuses a single
+// Coordinator across turns (no MCP reconnect per query, unlike the
+// one-shot path) and maintains a rolling conversation history that's woven
+// into the LLM prompt, so a follow-up like "and the weather there?"
+// resolves against whatever city a prior turn already named.
+func (a *App) runInteractive(args []string) error {
+	fs := flag.NewFlagSet("llm-agents repl", flag.ExitOnError)
+	verbose := fs.Bool("verbose", false, "Enable verbose output by default")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	a.config = config.Load()
+	utils.SetLogLevel(a.config.LogLevel)
+
+	if err := a.initializeCoordinator(); err != nil {
+		return fmt.Errorf("failed to initialize coordinator: %w", err)
+	}
+	defer a.coordinator.Close()
+
+	if err := a.coordinator.Validate(); err != nil {
+		return fmt.Errorf("coordinator validation failed: %w", err)
+	}
+
+	rl, err := readline.New("llm-agents> ")
+	if err != nil {
+		return fmt.Errorf("failed to start line editor: %w", err)
+	}
+	defer rl.Close()
+
+	session := &replSession{verbose: *verbose}
+	fmt.Println("llm-agents interactive mode. Type :help for commands, Ctrl-D to exit.")
+
+	for {
+		line, err := rl.Readline()
+		if err == readline.ErrInterrupt {
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("line editor error: %w", err)
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			if !session.handleCommand(line) {
+				return nil
+			}
+			continue
+		}
+
+		a.runTurn(context.Background(), session, line)
+	}
+}
+
+// replSession holds the state a REPL command can read or mutate: the
+// sticky default city, the verbose display toggle, and the rolling
+// conversation history the LLM prompt is built from.
+type replSession struct {
+	city    string
+	verbose bool
+	history []models.ConversationTurn
+}
+
+// handleCommand runs a ":"-prefixed REPL command, returning false if the
+// REPL should exit (":quit"/":exit").
+func (s *replSession) handleCommand(line string) bool {
+	fields := strings.Fields(line)
+	cmd := fields[0]
+
+	switch cmd {
+	case ":help":
+		printREPLHelp()
+	case ":city":
+		if len(fields) < 2 {
+			s.city = ""
+			fmt.Println("Sticky city cleared.")
+			return true
+		}
+		s.city = strings.Join(fields[1:], " ")
+		fmt.Printf("Sticky city set to %q.\n", s.city)
+	case ":history":
+		if len(s.history) == 0 {
+			fmt.Println("(no history yet)")
+			return true
+		}
+		for i, turn := range s.history {
+			fmt.Printf("%d. %s -> %s\n", i+1, turn.Query, turn.Response)
+		}
+	case ":clear":
+		s.history = nil
+		fmt.Println("Conversation history cleared.")
+	case ":verbose":
+		s.verbose = !s.verbose
+		fmt.Printf("Verbose output: %v\n", s.verbose)
+	case ":quit", ":exit":
+		return false
+	default:
+		fmt.Printf("Unknown command: %s (try :help)\n", cmd)
+	}
+	return true
+}
+
+// printREPLHelp lists the REPL's built-in commands.
+func printREPLHelp() {
+	fmt.Println(`Commands:
+  :help            Show this message
+  :city <name>     Set a sticky default city used when a query doesn't name one (no name clears it)
+  :history         Show this session's conversation history
+  :clear           Clear the conversation history
+  :verbose         Toggle verbose orchestration output
+  :quit, :exit     Leave the REPL (Ctrl-D also works)`)
+}
+
+// runTurn processes one REPL query against the shared coordinator, prints
+// the result, and appends the turn to session.history, trimming it to
+// replHistoryLimit entries.
+func (a *App) runTurn(ctx context.Context, session *replSession, queryText string) {
+	city := session.city
+
+	query := models.Query{
+		ID:        generateQueryID(),
+		Text:      queryText,
+		City:      city,
+		Timestamp: time.Now(),
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	response, err := a.coordinator.ProcessQueryWithContext(reqCtx, query, session.history)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	displayResults(response, session.verbose)
+
+	session.history = append(session.history, models.ConversationTurn{
+		Query:    queryText,
+		City:     city,
+		Response: response.Message,
+	})
+	if len(session.history) > replHistoryLimit {
+		session.history = session.history[len(session.history)-replHistoryLimit:]
+	}
+}
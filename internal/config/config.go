@@ -6,6 +6,8 @@ import (
 	"os"
 	"strconv"
 	"time"
+
+	"github.com/steve/llm-agents/internal/resilience"
 )
 
 // Config holds all configuration for the application
@@ -13,10 +15,27 @@ type Config struct {
 	// OpenRouter API configuration
 	OpenRouterAPIKey string
 
+	// LLMProvider selects which internal/llm provider generates
+	// orchestration plans (see llm.Providers for what's registered);
+	// defaults to "openrouter" so existing OPENROUTER_API_KEY-only setups
+	// keep working unchanged.
+	LLMProvider string
+	// LLMAPIKey is the provider-agnostic API key internal/llm.Config
+	// expects. Falls back to OpenRouterAPIKey when unset, so
+	// OPENROUTER_API_KEY alone still works with the default provider.
+	LLMAPIKey string
+	// LLMBaseURL overrides the selected provider's default endpoint, e.g.
+	// to point "openai" at an enterprise gateway or "ollama" at a
+	// non-default host.
+	LLMBaseURL string
+	// LLMModel overrides the selected provider's default model.
+	LLMModel string
+
 	// MCP Server URLs
 	WeatherMCPURL  string
 	DateTimeMCPURL string
 	EchoMCPURL     string
+	ForecastMCPURL string
 
 	// Timeouts
 	QueryTimeout time.Duration
@@ -29,6 +48,18 @@ type Config struct {
 
 	// CLI specific
 	City string
+
+	// Resilience governs retry/backoff, rate limiting, and circuit
+	// breaking for sub-agent MCP calls (internal/resilience).
+	Resilience resilience.Policy
+
+	// PrefetchCacheTTL bounds how long a warmed response stays in the
+	// coordinator's response cache before ProcessQuery treats it as stale.
+	PrefetchCacheTTL time.Duration
+	// PrefetchStorePath, if set, persists the coordinator's (city, intent)
+	// hit tracker to disk so the prefetch daemon's notion of "hot" queries
+	// survives a restart. Empty disables persistence.
+	PrefetchStorePath string
 }
 
 // Load loads configuration from environment variables with defaults
@@ -37,10 +68,17 @@ func Load() *Config {
 		// OpenRouter API
 		OpenRouterAPIKey: getEnv("OPENROUTER_API_KEY", ""),
 
+		// LLM provider selection
+		LLMProvider: getEnv("LLM_PROVIDER", "openrouter"),
+		LLMAPIKey:   getEnv("LLM_API_KEY", getEnv("OPENROUTER_API_KEY", "")),
+		LLMBaseURL:  getEnv("LLM_BASE_URL", ""),
+		LLMModel:    getEnv("LLM_MODEL", ""),
+
 		// MCP Server URLs
 		WeatherMCPURL:  getEnv("MCP_WEATHER_URL", "http://localhost:8081"),
 		DateTimeMCPURL: getEnv("MCP_DATETIME_URL", "http://localhost:8082"),
 		EchoMCPURL:     getEnv("MCP_ECHO_URL", "http://localhost:8083"),
+		ForecastMCPURL: getEnv("MCP_FORECAST_URL", "http://localhost:8085"),
 
 		// Timeouts
 		QueryTimeout: getDurationEnv("QUERY_TIMEOUT", 30*time.Second),
@@ -53,15 +91,44 @@ func Load() *Config {
 
 		// CLI
 		City: getEnv("DEFAULT_CITY", ""),
+
+		// Resilience
+		Resilience: loadResiliencePolicy(),
+
+		// Prefetch cache
+		PrefetchCacheTTL:  getDurationEnv("PREFETCH_CACHE_TTL", 15*time.Minute),
+		PrefetchStorePath: getEnv("PREFETCH_STORE_PATH", ""),
 	}
 
 	return config
 }
 
+// loadResiliencePolicy builds a resilience.Policy from environment
+// variables, falling back to resilience.DefaultPolicy()'s values for
+// anything unset.
+func loadResiliencePolicy() resilience.Policy {
+	policy := resilience.DefaultPolicy()
+
+	policy.Backoff.BaseDelay = getDurationEnv("RESILIENCE_RETRY_BASE_DELAY", policy.Backoff.BaseDelay)
+	policy.Backoff.MaxDelay = getDurationEnv("RESILIENCE_RETRY_MAX_DELAY", policy.Backoff.MaxDelay)
+	policy.Backoff.Multiplier = getFloatEnv("RESILIENCE_RETRY_MULTIPLIER", policy.Backoff.Multiplier)
+	policy.Backoff.Jitter = getFloatEnv("RESILIENCE_RETRY_JITTER", policy.Backoff.Jitter)
+	policy.Backoff.MaxAttempts = getIntEnv("RESILIENCE_RETRY_MAX_ATTEMPTS", policy.Backoff.MaxAttempts)
+
+	policy.RateLimit.RatePerSecond = getFloatEnv("RESILIENCE_RATE_LIMIT_PER_SECOND", policy.RateLimit.RatePerSecond)
+	policy.RateLimit.Burst = getIntEnv("RESILIENCE_RATE_LIMIT_BURST", policy.RateLimit.Burst)
+
+	policy.Breaker.MinRequests = getIntEnv("RESILIENCE_BREAKER_MIN_REQUESTS", policy.Breaker.MinRequests)
+	policy.Breaker.FailureRatio = getFloatEnv("RESILIENCE_BREAKER_FAILURE_RATIO", policy.Breaker.FailureRatio)
+	policy.Breaker.CooldownPeriod = getDurationEnv("RESILIENCE_BREAKER_COOLDOWN", policy.Breaker.CooldownPeriod)
+
+	return policy
+}
+
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	if c.OpenRouterAPIKey == "" {
-		return fmt.Errorf("OPENROUTER_API_KEY is required")
+	if c.LLMProvider != "ollama" && c.LLMAPIKey == "" {
+		return fmt.Errorf("LLM_API_KEY (or OPENROUTER_API_KEY) is required for provider %q", c.LLMProvider)
 	}
 
 	return nil
@@ -94,3 +161,23 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+// getFloatEnv gets a float64 environment variable with a default value
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getIntEnv gets an int environment variable with a default value
+func getIntEnv(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
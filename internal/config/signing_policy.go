@@ -0,0 +1,144 @@
+// Package config provides TLS configuration structures and validation
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// KeyAlgorithm identifies the key algorithm and size/curve a profile issues.
+type KeyAlgorithm string
+
+const (
+	KeyAlgorithmRSA2048   KeyAlgorithm = "rsa-2048"
+	KeyAlgorithmRSA3072   KeyAlgorithm = "rsa-3072"
+	KeyAlgorithmRSA4096   KeyAlgorithm = "rsa-4096"
+	KeyAlgorithmECDSAP256 KeyAlgorithm = "ecdsa-p256"
+	KeyAlgorithmECDSAP384 KeyAlgorithm = "ecdsa-p384"
+	KeyAlgorithmEd25519   KeyAlgorithm = "ed25519"
+)
+
+// SANType identifies a subject-alternative-name kind a profile may issue.
+type SANType string
+
+const (
+	SANTypeDNS SANType = "dns"
+	SANTypeIP  SANType = "ip"
+	SANTypeURI SANType = "uri"
+)
+
+// NameConstraints restricts the DNS domains a profile's certificates may
+// assert, mirroring x509.Certificate's PermittedDNSDomains/Excluded fields.
+type NameConstraints struct {
+	PermittedDNSDomains []string `json:"permitted_dns_domains,omitempty"`
+	ExcludedDNSDomains  []string `json:"excluded_dns_domains,omitempty"`
+}
+
+// CertProfile describes how a named class of certificate should be issued:
+// validity, key usages, allowed SAN types, key algorithm, and optional name
+// constraints. Modeled loosely on cfssl's signing profiles.
+type CertProfile struct {
+	Name            string           `json:"name"`
+	MaxValidity     time.Duration    `json:"max_validity"`
+	KeyUsages       []string         `json:"key_usages"`
+	ExtKeyUsages    []string         `json:"ext_key_usages"`
+	AllowedSANTypes []SANType        `json:"allowed_san_types"`
+	KeyAlgorithm    KeyAlgorithm     `json:"key_algorithm"`
+	NameConstraints *NameConstraints `json:"name_constraints,omitempty"`
+}
+
+// SigningPolicy is a JSON-loadable collection of named certificate
+// profiles, plus a default profile used when none is named explicitly.
+type SigningPolicy struct {
+	Default  CertProfile            `json:"default"`
+	Profiles map[string]CertProfile `json:"profiles"`
+}
+
+// LoadSigningPolicy loads and validates a SigningPolicy from a JSON file.
+func LoadSigningPolicy(path string) (*SigningPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing policy file: %w", err)
+	}
+
+	var policy SigningPolicy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse signing policy: %w", err)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid signing policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// Validate rejects policies that would produce insecure certificates:
+// RSA keys under 2048 bits, missing key usages, or unreasonable validity.
+func (p *SigningPolicy) Validate() error {
+	if err := p.Default.Validate(); err != nil {
+		return fmt.Errorf("default profile: %w", err)
+	}
+	for name, profile := range p.Profiles {
+		profile.Name = name
+		if err := profile.Validate(); err != nil {
+			return fmt.Errorf("profile %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Profile looks up a named profile, falling back to Default when name is
+// empty, and errors if an explicitly named profile doesn't exist.
+func (p *SigningPolicy) Profile(name string) (CertProfile, error) {
+	if name == "" {
+		return p.Default, nil
+	}
+	profile, ok := p.Profiles[name]
+	if !ok {
+		return CertProfile{}, fmt.Errorf("unknown certificate profile: %s", name)
+	}
+	if profile.Name == "" {
+		profile.Name = name
+	}
+	return profile, nil
+}
+
+// Validate rejects a profile that would issue insecure certificates.
+func (p *CertProfile) Validate() error {
+	switch p.KeyAlgorithm {
+	case KeyAlgorithmRSA2048, KeyAlgorithmRSA3072, KeyAlgorithmRSA4096,
+		KeyAlgorithmECDSAP256, KeyAlgorithmECDSAP384, KeyAlgorithmEd25519:
+		// valid
+	case "":
+		return fmt.Errorf("key algorithm is required")
+	default:
+		return fmt.Errorf("unsupported or insecure key algorithm: %s", p.KeyAlgorithm)
+	}
+
+	if p.MaxValidity <= 0 {
+		return fmt.Errorf("max validity must be > 0")
+	}
+	if p.MaxValidity > 10*365*24*time.Hour {
+		return fmt.Errorf("max validity must be <= 10 years")
+	}
+
+	if len(p.KeyUsages) == 0 {
+		return fmt.Errorf("at least one key usage is required")
+	}
+	if len(p.ExtKeyUsages) == 0 {
+		return fmt.Errorf("at least one extended key usage is required")
+	}
+
+	for _, sanType := range p.AllowedSANTypes {
+		switch sanType {
+		case SANTypeDNS, SANTypeIP, SANTypeURI:
+		default:
+			return fmt.Errorf("unsupported SAN type: %s", sanType)
+		}
+	}
+
+	return nil
+}
@@ -4,22 +4,253 @@ package config
 import (
 	"crypto/tls"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
 // TLSConfig represents TLS configuration for MCP servers and clients
 type TLSConfig struct {
-	CertDir       string `json:"cert_dir"`
-	ServerCert    string `json:"server_cert"`
-	ServerKey     string `json:"server_key"`
-	ClientCert    string `json:"client_cert"`
-	ClientKey     string `json:"client_key"`
-	CACert        string `json:"ca_cert"`
-	DemoMode      bool   `json:"demo_mode"`
-	MinTLSVersion uint16 `json:"min_tls_version"`
-	Port          int    `json:"port"`
+	CertDir    string `json:"cert_dir"`
+	ServerCert string `json:"server_cert"`
+	ServerKey  string `json:"server_key"`
+	ClientCert string `json:"client_cert"`
+	ClientKey  string `json:"client_key"`
+	CACert     string `json:"ca_cert"`
+	// IntermediateCert and IntermediateKey locate an intermediate CA signed
+	// by CACert's root, through which CertificateManager chains newly
+	// issued leaf certs once GenerateIntermediateCA has produced one.
+	// Empty defaults to CertDir/intermediate.{crt,key}; a deployment that
+	// never calls GenerateIntermediateCA keeps issuing leaf certs directly
+	// from the root, as before this field existed.
+	IntermediateCert string `json:"intermediate_cert,omitempty"`
+	IntermediateKey  string `json:"intermediate_key,omitempty"`
+	// KeyType selects the private key algorithm CertificateManager uses for
+	// every key it creates (root CA, intermediate CA, server, client) via
+	// internal/tls/kms.KeyManager. Empty defaults to KeyAlgorithmRSA2048,
+	// matching this package's fixed key size before key algorithms became
+	// pluggable.
+	KeyType KeyAlgorithm `json:"key_type,omitempty"`
+	// CAPath is a directory of PEM-encoded CA certificates appended to
+	// CACert's pool, one file per cert or one file with several
+	// concatenated. Used for CA rotation (trusting a new root alongside the
+	// old one during the switchover) and for federating trust across
+	// multiple agent deployments, mirroring Consul tlsutil's CAPath.
+	CAPath          string        `json:"ca_path,omitempty"`
+	DemoMode        bool          `json:"demo_mode"`
+	MinTLSVersion   uint16        `json:"min_tls_version"`
+	Port            int           `json:"port"`
+	AdditionalCerts []CertKeyPair `json:"additional_certs,omitempty"`
+	MTLSState       MTLSState     `json:"mtls_state"`
+
+	// ReloadInterval overrides how often a CertReloader started via
+	// TLSLoader.StartReloader polls the cert/key/CA files on disk as a
+	// fallback to fsnotify (some environments, e.g. network filesystems or
+	// certain container volume mounts, don't reliably deliver fs events). A
+	// zero value keeps defaultReloadPollInterval (30s).
+	ReloadInterval time.Duration `json:"reload_interval,omitempty"`
+
+	// ClientAuthMode is the raw TLS_CLIENT_AUTH value ("none", "request",
+	// "require", "verify-if-given", "require-and-verify"), translated to a
+	// tls.ClientAuthType by GetAuthType. It's a finer-grained alternative to
+	// MTLSState for servers that need the full stdlib ClientAuthType range
+	// (e.g. "request" or "require" without verification) rather than just
+	// disabled/enabled/verify-if-given. Empty defaults to
+	// RequireAndVerifyClientCert, matching this package's original
+	// always-mutual-TLS behavior.
+	ClientAuthMode string `json:"client_auth_mode,omitempty"`
+	// ClientCACert is a trust bundle (TLS_CLIENT_CA) used only to verify
+	// client certificates, letting a server authenticate clients against a
+	// different CA than the one that issued its own server certificate.
+	// Empty falls back to CACert/CAPath, same as before this field existed.
+	ClientCACert string `json:"client_ca_cert,omitempty"`
+
+	// RPC, HTTPS, and GRPC let a single agent host the mTLS MCP RPC
+	// listener, an admin HTTPS surface, and gRPC on different ports with
+	// different trust policies instead of sharing the top-level
+	// MinTLSVersion/MTLSState for everything. A nil section falls back to
+	// the top-level policy above.
+	RPC   *ProtocolTLSConfig `json:"rpc,omitempty"`
+	HTTPS *ProtocolTLSConfig `json:"https,omitempty"`
+	GRPC  *ProtocolTLSConfig `json:"grpc,omitempty"`
+
+	// AllowedPeerIDs authorizes peers by identity rather than by CA trust
+	// alone: each Role maps to the SPIFFE URI SANs or DNS SANs a peer
+	// presenting that role's certificate may use (e.g.
+	// "spiffe://llm-agents/agent/coordinator"). A peer is authorized if its
+	// certificate matches any ID under any role; an empty map leaves CA
+	// trust as the only check, matching prior behavior.
+	AllowedPeerIDs map[Role][]string `json:"allowed_peer_ids,omitempty"`
+
+	// SecurityProfile names a bundle of MinVersion/MaxVersion/CipherSuites/
+	// CurvePreferences for TLSLoader to apply, instead of the caller
+	// picking those knobs individually. Empty falls back to
+	// SecurityProfileIntermediate.
+	SecurityProfile SecurityProfile `json:"security_profile,omitempty"`
+
+	// CRLFile is a path to a PEM or DER-encoded CRL used as the fallback
+	// revocation check when a peer's certificate has no usable OCSP
+	// staple. Refreshed periodically from disk.
+	CRLFile string `json:"crl_file,omitempty"`
+	// CRLDistributionPoints are CRL URLs fetched and cached the same way
+	// as CRLFile, for certs whose issuer doesn't have a CRL on local disk.
+	// Checked if CRLFile is empty or doesn't cover the peer's issuer.
+	CRLDistributionPoints []string `json:"crl_distribution_points,omitempty"`
+
+	// IssuedCRLURL and IssuedOCSPURL are embedded as the CRLDistributionPoints
+	// and OCSPServer extensions of every leaf certificate generateCert
+	// issues, so a peer that doesn't already have CRLFile/CRLDistributionPoints
+	// configured can still discover where to check this CA's revocation
+	// status. Empty omits the corresponding extension, as before they existed.
+	IssuedCRLURL  string `json:"issued_crl_url,omitempty"`
+	IssuedOCSPURL string `json:"issued_ocsp_url,omitempty"`
+
+	// RevocationStorePath, if set, is loaded as a tls.RevocationStore and
+	// attached to the server-side CertReloader's VerifyPeerCertificate, so
+	// a client certificate this CA itself revoked (via the `cert revoke`
+	// CLI command) is rejected at handshake time, independent of
+	// CRLFile/CRLDistributionPoints which cover revocations from other
+	// issuers. Empty leaves local revocation unchecked, as before this
+	// field existed.
+	RevocationStorePath string `json:"revocation_store_path,omitempty"`
+
+	// ACME configures tls.ACMEIssuer as an alternative to the self-signed
+	// CertificateManager: a server certificate obtained from a public RFC
+	// 8555 directory (Let's Encrypt, smallstep, ZeroSSL) instead of this
+	// deployment's own CA. Nil means ACME issuance isn't used.
+	ACME *ACMEConfig `json:"acme,omitempty"`
+}
+
+// ACMEConfig configures tls.ACMEIssuer.
+type ACMEConfig struct {
+	// Enabled switches an MCP server's bootstrap from the self-signed
+	// CertificateManager/StartRotation path over to ACMEIssuer. false (the
+	// zero value) keeps every deployment that predates this field on the
+	// local-CA path unchanged.
+	Enabled bool `json:"enabled"`
+	// DirectoryURL is the ACME server's directory endpoint, e.g.
+	// "https://acme-v02.api.letsencrypt.org/directory".
+	DirectoryURL string `json:"directory_url"`
+	// Email is the account contact address submitted when registering with
+	// the directory.
+	Email string `json:"email"`
+	// Domains are the DNS names ACMEIssuer requests a certificate for;
+	// Domains[0] is the certificate's subject CommonName. Required when
+	// Enabled is true.
+	Domains []string `json:"domains,omitempty"`
+	// ChallengeType selects how ACMEIssuer proves domain control:
+	// "http-01", "tls-alpn-01", or "dns-01". dns-01 additionally requires
+	// DNSProvider.
+	ChallengeType string `json:"challenge_type"`
+	// DNSProvider names the go-acme/lego-compatible DNS provider to use for
+	// dns-01 challenges (e.g. "cloudflare", "route53"); ignored otherwise.
+	DNSProvider string `json:"dns_provider,omitempty"`
+	// CacheDir is where ACMEIssuer persists the issued certificate and key
+	// PEM (0600) between restarts, so a server doesn't request a fresh
+	// certificate — and risk the directory's rate limit — every time it
+	// starts. Empty defaults to CertDir/acme/cache.
+	CacheDir string `json:"cache_dir,omitempty"`
+}
+
+// SecurityProfile names a named TLS hardening level, modeled on the
+// Mozilla TLS configuration generator's Modern/Intermediate/Old profiles
+// plus a FIPS-restricted variant.
+type SecurityProfile string
+
+const (
+	// SecurityProfileModern pins TLS 1.3 only, trusting its built-in AEAD
+	// cipher suites and X25519/P-256 curve preferences.
+	SecurityProfileModern SecurityProfile = "modern"
+	// SecurityProfileIntermediate allows TLS 1.2 and 1.3, restricted to
+	// ECDHE-AEAD cipher suites on TLS 1.2. This is the default.
+	SecurityProfileIntermediate SecurityProfile = "intermediate"
+	// SecurityProfileFIPS restricts to FIPS-140-approved cipher suites and
+	// curves (P-256/P-384 only, no X25519 or ChaCha20-Poly1305).
+	SecurityProfileFIPS SecurityProfile = "fips"
+	// SecurityProfileLegacy allows down to the configured MinTLSVersion
+	// with the broadest cipher suite list, for interoperating with older
+	// peers that can't negotiate modern suites.
+	SecurityProfileLegacy SecurityProfile = "legacy"
+)
+
+// Role names a class of agent (e.g. "coordinator", "weather") for the
+// purpose of scoping AllowedPeerIDs. It's a plain string type so configs
+// can name roles without this package knowing about every agent kind.
+type Role string
+
+// ProtocolTLSConfig is a complete, protocol-scoped TLS policy: its own
+// minimum version, cipher suite list, client-auth policy, and ALPN
+// protocols to advertise, mirroring the per-listener configurators used by
+// tools like Consul's tlsutil. It's a full override, not a sparse patch, so
+// a zero-value MTLSState means "no client certs" for that protocol just as
+// it does at the top level.
+type ProtocolTLSConfig struct {
+	MinTLSVersion uint16    `json:"min_tls_version"`
+	CipherSuites  []uint16  `json:"cipher_suites,omitempty"`
+	MTLSState     MTLSState `json:"mtls_state"`
+	ALPNProtocols []string  `json:"alpn_protocols,omitempty"`
+}
+
+// CertKeyPair names an extra cert/key pair a server should present for a
+// given SNI ServerName, alongside the primary ServerCert/ServerKey. This
+// lets one listener serve multiple virtual hosts (e.g. weather.mcp.local,
+// datetime.mcp.local, echo.mcp.local) each under its own certificate.
+type CertKeyPair struct {
+	ServerName string `json:"server_name"`
+	CertFile   string `json:"cert_file"`
+	KeyFile    string `json:"key_file"`
+}
+
+// MTLSState selects how a TLS server treats client certificates, modeled
+// after rqlite's mutual-TLS modes. It replaces the old implicit behavior of
+// always requiring a client certificate whenever TLS was enabled.
+type MTLSState int
+
+const (
+	// MTLSStateDisabled presents a server certificate only; no client
+	// certificate is requested or verified (server-auth-only TLS).
+	MTLSStateDisabled MTLSState = iota
+	// MTLSStateEnabled requires and verifies a client certificate signed
+	// by CACert (mutual TLS).
+	MTLSStateEnabled
+	// MTLSStateVerifyIfGiven verifies a client certificate if one is
+	// presented, but does not require one.
+	MTLSStateVerifyIfGiven
+)
+
+// String returns the string representation of an MTLSState.
+func (s MTLSState) String() string {
+	switch s {
+	case MTLSStateEnabled:
+		return "enabled"
+	case MTLSStateVerifyIfGiven:
+		return "verify_if_given"
+	default:
+		return "disabled"
+	}
+}
+
+// GetAuthType maps ClientAuthMode to a tls.ClientAuthType: "none" (no
+// client cert requested), "request" (requested but not verified),
+// "require" (required but not verified), "verify-if-given" (verified if
+// presented, not required), and "require-and-verify" (required and
+// verified). An empty or unrecognized mode defaults to
+// RequireAndVerifyClientCert.
+func (c *TLSConfig) GetAuthType() tls.ClientAuthType {
+	switch c.ClientAuthMode {
+	case "none":
+		return tls.NoClientCert
+	case "request":
+		return tls.RequestClientCert
+	case "require":
+		return tls.RequireAnyClientCert
+	case "verify-if-given":
+		return tls.VerifyClientCertIfGiven
+	default:
+		return tls.RequireAndVerifyClientCert
+	}
 }
 
 // CertificateType represents the type of certificate
@@ -54,6 +285,12 @@ type Certificate struct {
 	Validity     time.Duration   `json:"validity"`
 	KeySize      int             `json:"key_size"`
 	SerialNumber int64           `json:"serial_number"`
+	// SPIFFEID optionally names the spiffe://<trust-domain>/<path> workload
+	// identity this certificate should encode as a URI SAN (see
+	// tls.CertificateManager.GenerateClientCertForIdentity and
+	// GenerateAllCertsForIdentities). Empty issues a certificate with no
+	// SPIFFE identity, as before this field existed.
+	SPIFFEID string `json:"spiffe_id,omitempty"`
 }
 
 // MCPServerConfig represents MCP server configuration with TLS support
@@ -74,7 +311,9 @@ type MCPClientConfig struct {
 	RetryAttempts int           `json:"retry_attempts"`
 }
 
-// NewTLSConfig creates a new TLS configuration with defaults
+// NewTLSConfig creates a new TLS configuration with defaults. MTLSState
+// defaults to MTLSStateDisabled for backwards compatibility; callers that
+// want mutual TLS set it explicitly after construction.
 func NewTLSConfig(certDir string, demoMode bool) *TLSConfig {
 	return &TLSConfig{
 		CertDir:       certDir,
@@ -85,6 +324,7 @@ func NewTLSConfig(certDir string, demoMode bool) *TLSConfig {
 		CACert:        filepath.Join(certDir, "ca.crt"),
 		DemoMode:      demoMode,
 		MinTLSVersion: tls.VersionTLS12,
+		MTLSState:     MTLSStateDisabled,
 	}
 }
 
@@ -109,6 +349,47 @@ func (c *TLSConfig) Validate() error {
 		}
 	}
 
+	for _, vhostCert := range c.AdditionalCerts {
+		if vhostCert.ServerName == "" {
+			return fmt.Errorf("additional certificate is missing a server name")
+		}
+		if !c.DemoMode {
+			if _, err := os.Stat(vhostCert.CertFile); os.IsNotExist(err) {
+				return fmt.Errorf("additional certificate file does not exist: %s", vhostCert.CertFile)
+			}
+			if _, err := os.Stat(vhostCert.KeyFile); os.IsNotExist(err) {
+				return fmt.Errorf("additional certificate key does not exist: %s", vhostCert.KeyFile)
+			}
+		}
+	}
+
+	if c.MTLSState == MTLSStateEnabled && c.CACert == "" {
+		return fmt.Errorf("mTLS is enabled but ca_cert is empty")
+	}
+
+	if c.CAPath != "" {
+		if info, err := os.Stat(c.CAPath); err != nil || !info.IsDir() {
+			return fmt.Errorf("ca_path does not exist or is not a directory: %s", c.CAPath)
+		}
+	}
+
+	for role, ids := range c.AllowedPeerIDs {
+		for _, id := range ids {
+			if !strings.HasPrefix(id, "spiffe://") {
+				continue // a bare DNS SAN, per SPIFFEAuthorizer's fallback match
+			}
+			if err := validateSPIFFEID(id); err != nil {
+				return fmt.Errorf("allowed_peer_ids[%s]: %w", role, err)
+			}
+		}
+	}
+
+	if c.ClientCACert != "" {
+		if _, err := os.Stat(c.ClientCACert); os.IsNotExist(err) {
+			return fmt.Errorf("client_ca_cert does not exist: %s", c.ClientCACert)
+		}
+	}
+
 	// Validate port range
 	if c.Port != 0 && (c.Port < 1024 || c.Port > 65535) {
 		return fmt.Errorf("port must be in range 1024-65535, got %d", c.Port)
@@ -149,6 +430,32 @@ func (c *Certificate) Validate() error {
 		return fmt.Errorf("validity period must be > 0 and <= 10 years")
 	}
 
+	if c.SPIFFEID != "" {
+		if err := validateSPIFFEID(c.SPIFFEID); err != nil {
+			return fmt.Errorf("invalid spiffe_id: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateSPIFFEID checks that id parses as a "spiffe://<trust-domain>/<path>"
+// URI per the SPIFFE ID specification: scheme "spiffe", a non-empty host
+// (the trust domain), and a non-empty path identifying the workload.
+func validateSPIFFEID(id string) error {
+	u, err := url.Parse(id)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid URI: %w", id, err)
+	}
+	if u.Scheme != "spiffe" {
+		return fmt.Errorf("%q must use the \"spiffe\" scheme, got %q", id, u.Scheme)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%q is missing a trust domain", id)
+	}
+	if u.Path == "" || u.Path == "/" {
+		return fmt.Errorf("%q is missing a workload path", id)
+	}
 	return nil
 }
 
@@ -192,4 +499,4 @@ func (c *MCPClientConfig) Validate() error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,59 @@
+// Package diag provides the diagnostic HTTP surface (liveness, readiness,
+// Prometheus metrics, and pprof profiles) that an MCP server or sub-agent
+// mounts on its own DIAG_ADDR listener, separate from the port serving MCP
+// traffic, so operators can scrape or profile it without exposing pprof
+// alongside client connections.
+package diag
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/steve/llm-agents/internal/metrics"
+)
+
+// ReadyFunc reports whether the server is ready to accept traffic, e.g.
+// that its MCP session initialized and, for agents backed by an upstream
+// provider, that the provider has responded recently. A nil ReadyFunc
+// means /readyz always reports ready. A non-nil error marks /readyz as not
+// ready, with the error text in the response body.
+type ReadyFunc func() error
+
+// NewMux builds the diagnostic handler: /healthz always reports process
+// liveness, /readyz defers to ready, /metrics serves the shared Prometheus
+// registry from internal/metrics, and /debug/pprof/* exposes the standard
+// net/http/pprof profiles.
+func NewMux(ready ReadyFunc) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if ready == nil {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, "ok")
+			return
+		}
+		if err := ready(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, "not ready: %v\n", err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+
+	mux.Handle("/metrics", metrics.Handler())
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	return mux
+}
@@ -0,0 +1,45 @@
+package diag
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Readiness tracks whether an upstream dependency (e.g. a weather
+// provider) is still responding, for use as a ReadyFunc. It starts "ready"
+// as of construction, so a freshly started agent with no traffic yet
+// reports ready during its MaxAge grace period; MarkSuccess should be
+// called on every successful upstream call to extend that window, and
+// Check fails once MaxAge has elapsed since the last success.
+type Readiness struct {
+	maxAge time.Duration
+
+	mu     sync.RWMutex
+	lastOK time.Time
+}
+
+// NewReadiness returns a Readiness whose Check passes as long as
+// MarkSuccess was called (or the Readiness was constructed) within maxAge.
+func NewReadiness(maxAge time.Duration) *Readiness {
+	return &Readiness{maxAge: maxAge, lastOK: time.Now()}
+}
+
+// MarkSuccess records that the upstream dependency just responded.
+func (r *Readiness) MarkSuccess() {
+	r.mu.Lock()
+	r.lastOK = time.Now()
+	r.mu.Unlock()
+}
+
+// Check implements ReadyFunc: it fails once more than maxAge has elapsed
+// since the last MarkSuccess call.
+func (r *Readiness) Check() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if age := time.Since(r.lastOK); age > r.maxAge {
+		return fmt.Errorf("no successful upstream response in over %s (last success %s ago)", r.maxAge, age.Round(time.Second))
+	}
+	return nil
+}
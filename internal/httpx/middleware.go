@@ -0,0 +1,217 @@
+// Package httpx provides structured request logging and request-correlation
+// middleware shared by every MCP server and the main CLI, replacing the ad
+// hoc responseCapture wrappers and scattered utils.Debug calls each binary
+// used to maintain on its own.
+package httpx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	loggerKey
+	clientIdentityKey
+	callerIdentityKey
+)
+
+// CallerIdentityHeader carries the calling agent's own SPIFFE ID, attached
+// by an mcp/client.Client pinned to a peer (see
+// client.NewTLSClientWithPeerPin) on every outgoing request via
+// WithCallerIdentity. ClientCertIdentity cross-checks it against the
+// identity its own mTLS handshake already authenticated, so a caller can't
+// silently claim to be someone other than who its certificate says it is.
+const CallerIdentityHeader = "X-LLM-Agents-Caller-Identity"
+
+// WithCallerIdentity returns a copy of ctx carrying identity as this call's
+// own SPIFFE ID, for a client's RoundTripper to attach as
+// CallerIdentityHeader on any outgoing request made with that context.
+func WithCallerIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, callerIdentityKey, identity)
+}
+
+// CallerIdentityFromContext returns the SPIFFE ID WithCallerIdentity stored
+// in ctx, or "" if none was set.
+func CallerIdentityFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(callerIdentityKey).(string)
+	return id
+}
+
+// RequestIDHeader is the response header RequestID sets on every request, so
+// a caller can correlate its own logs with the server's using the same ID
+// LoggingHandler prints.
+const RequestIDHeader = "X-Request-Id"
+
+// RequestID injects a random request ID into the request's context and the
+// response headers. Chain it before LoggingHandler (or any MCP tool handler
+// that wants to tag its own log lines) so they all report the same ID for a
+// given request.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := newRequestID()
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey, id)))
+	})
+}
+
+// RequestIDFromContext returns the request ID RequestID stored in ctx, or ""
+// if RequestID never ran on this request's handler chain.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// newRequestID returns a random 16-character hex request ID, falling back
+// to a timestamp if the system RNG is unavailable.
+func newRequestID() string {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf[:])
+}
+
+// BaseContext returns an http.Server.BaseContext hook that stashes logger
+// into the base context every connection's request context descends from,
+// so a handler reached through *http.Server (rather than a bare
+// mux.ServeHTTP call in a test) can fetch it via LoggerFromContext. A nil
+// logger is a valid value: LoggingHandler and LoggerFromContext callers fall
+// back to the package-level utils.Info/Debug/etc functions in that case.
+func BaseContext(logger *utils.Logger) func(net.Listener) context.Context {
+	return func(net.Listener) context.Context {
+		return context.WithValue(context.Background(), loggerKey, logger)
+	}
+}
+
+// LoggerFromContext returns the *utils.Logger BaseContext stashed in ctx, or
+// nil if BaseContext was never wired onto the serving *http.Server.
+func LoggerFromContext(ctx context.Context) *utils.Logger {
+	logger, _ := ctx.Value(loggerKey).(*utils.Logger)
+	return logger
+}
+
+// responseRecorder wraps http.ResponseWriter to capture the status code and
+// byte count LoggingHandler reports, without buffering the whole body the
+// way the responseCapture wrappers it replaces did.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	bytes      int
+}
+
+func (rec *responseRecorder) WriteHeader(statusCode int) {
+	rec.statusCode = statusCode
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// LoggingHandler wraps next, logging one line per request with the method,
+// path, status code, response byte count, latency, and request ID (empty if
+// RequestID didn't run earlier in the chain). A nil logger logs through the
+// package-level utils functions instead of a specific *utils.Logger
+// instance, which is what every MCP server main() uses today.
+func LoggingHandler(next http.Handler, logger *utils.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		requestID := RequestIDFromContext(r.Context())
+		if logger == nil {
+			logger = LoggerFromContext(r.Context())
+		}
+
+		logLine := fmt.Sprintf("%s %s -> %d (%d bytes) in %s [request_id=%s]",
+			r.Method, r.URL.Path, rec.statusCode, rec.bytes, time.Since(start), requestID)
+		if logger != nil {
+			logger.Info("%s", logLine)
+		} else {
+			utils.Info("%s", logLine)
+		}
+	})
+}
+
+// ClientIdentity is the caller identity ClientCertIdentity extracts from an
+// mTLS peer certificate: its Subject CommonName and, if present, its first
+// URI SAN (its SPIFFE ID). The zero value means no client certificate was
+// presented (plain HTTP, or TLS without client auth).
+type ClientIdentity struct {
+	CN       string
+	SPIFFEID string
+}
+
+// String renders identity for log lines: "CN (spiffeID)" if a SPIFFE URI is
+// present, just CN if not, or "anonymous" if no certificate was presented.
+func (id ClientIdentity) String() string {
+	if id.CN == "" {
+		return "anonymous"
+	}
+	if id.SPIFFEID != "" {
+		return fmt.Sprintf("%s (%s)", id.CN, id.SPIFFEID)
+	}
+	return id.CN
+}
+
+// ClientIdentityFromContext returns the ClientIdentity ClientCertIdentity
+// stashed in ctx, or the zero value if it never ran on this request's
+// handler chain or no client certificate was presented.
+func ClientIdentityFromContext(ctx context.Context) ClientIdentity {
+	id, _ := ctx.Value(clientIdentityKey).(ClientIdentity)
+	return id
+}
+
+// ClientCertIdentity extracts the caller's Subject CN and first URI SAN
+// from r.TLS.PeerCertificates[0] (if any) and stashes it in the request
+// context as a ClientIdentity, for downstream handlers and log lines to
+// read via ClientIdentityFromContext. If allowedCNs is non-empty, it also
+// enforces that allow-list: a request with no peer certificate, or whose CN
+// isn't listed, gets a 403 instead of reaching next. An empty allowedCNs
+// authorizes every presented identity, the same opt-in convention as
+// config.TLSConfig.AllowedPeerIDs.
+func ClientCertIdentity(allowedCNs []string, next http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(allowedCNs))
+	for _, cn := range allowedCNs {
+		allowed[cn] = true
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var identity ClientIdentity
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			cert := r.TLS.PeerCertificates[0]
+			identity.CN = cert.Subject.CommonName
+			if len(cert.URIs) > 0 {
+				identity.SPIFFEID = cert.URIs[0].String()
+			}
+		}
+
+		if len(allowed) > 0 && !allowed[identity.CN] {
+			utils.Warn("rejecting request from unauthorized client %s", identity)
+			http.Error(w, "forbidden: client certificate not authorized", http.StatusForbidden)
+			return
+		}
+
+		if declared := r.Header.Get(CallerIdentityHeader); declared != "" && identity.SPIFFEID != "" && declared != identity.SPIFFEID {
+			utils.Warn("rejecting request: declared caller identity %q does not match mTLS peer identity %q", declared, identity.SPIFFEID)
+			http.Error(w, "forbidden: caller identity header does not match client certificate", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIdentityKey, identity)))
+	})
+}
@@ -0,0 +1,157 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+func init() {
+	Register("anthropic", func(cfg Config) (Planner, error) { return NewAnthropicPlanner(cfg), nil })
+}
+
+const (
+	defaultAnthropicBaseURL = "https://api.anthropic.com"
+	defaultAnthropicModel   = "claude-3-5-sonnet-20241022"
+	anthropicAPIVersion     = "2023-06-01"
+	anthropicMaxTokens      = 4096
+)
+
+// AnthropicPlanner generates orchestration plans via Anthropic's native
+// Messages API, rather than through OpenRouter's OpenAI-compatible proxy
+// (see OpenRouterPlanner) - useful for an account with a direct
+// Anthropic contract instead of an OpenRouter key.
+type AnthropicPlanner struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+}
+
+// NewAnthropicPlanner builds an AnthropicPlanner from cfg, defaulting
+// BaseURL, Model, and Timeout to Anthropic's own endpoint, Claude 3.5
+// Sonnet, and 30s respectively when left zero.
+func NewAnthropicPlanner(cfg Config) *AnthropicPlanner {
+	return &AnthropicPlanner{
+		apiKey:     cfg.APIKey,
+		baseURL:    orDefault(cfg.BaseURL, defaultAnthropicBaseURL),
+		model:      orDefault(cfg.Model, defaultAnthropicModel),
+		httpClient: &http.Client{Timeout: orDefaultDuration(cfg.Timeout, 30*time.Second)},
+	}
+}
+
+// GenerateOrchestrationPlan implements Planner.
+func (p *AnthropicPlanner) GenerateOrchestrationPlan(ctx context.Context, query models.Query, descriptors []models.AgentDescriptor) (*models.OrchestrationPlan, error) {
+	return p.GenerateOrchestrationPlanWithHistory(ctx, query, nil, descriptors)
+}
+
+// GenerateOrchestrationPlanWithHistory implements Planner.
+func (p *AnthropicPlanner) GenerateOrchestrationPlanWithHistory(ctx context.Context, query models.Query, history []models.ConversationTurn, descriptors []models.AgentDescriptor) (*models.OrchestrationPlan, error) {
+	return generatePlan(ctx, query, history, descriptors, p.complete)
+}
+
+// anthropicMessagesRequest is the request body Anthropic's POST
+// /v1/messages expects.
+type anthropicMessagesRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	Messages  []anthropicMessage `json:"messages"`
+}
+
+// anthropicMessage is one message in an anthropicMessagesRequest.
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// anthropicMessagesResponse is the response body Anthropic's POST
+// /v1/messages returns on success.
+type anthropicMessagesResponse struct {
+	Content []anthropicContentBlock `json:"content"`
+	Error   *anthropicError         `json:"error,omitempty"`
+}
+
+// anthropicContentBlock is one block of an anthropicMessagesResponse's
+// content array; only "text" blocks carry a Text field.
+type anthropicContentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// anthropicError is the error body Anthropic returns in place of content.
+type anthropicError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// complete calls Anthropic's native Messages API directly, rather than
+// the OpenAI-compatible chat-completions shape callChatCompletions uses.
+// It always returns a free-text completion - the Messages API's own tool
+// use shape differs from the OpenAI-compatible one callChatCompletions
+// shares with OpenRouter/OpenAI, so Anthropic sticks to the brace-matching
+// fallback path rather than a second tool-calling implementation.
+func (p *AnthropicPlanner) complete(ctx context.Context, prompt string) (completion, error) {
+	request := anthropicMessagesRequest{
+		Model:     p.model,
+		MaxTokens: anthropicMaxTokens,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: prompt},
+		},
+	}
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return completion{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/v1/messages", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return completion{}, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", p.apiKey)
+	httpReq.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return completion{}, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return completion{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	utils.Debug("Anthropic response status: %d", resp.StatusCode)
+	utils.Debug("Anthropic response body: %s", string(responseBody))
+
+	if resp.StatusCode != http.StatusOK {
+		return completion{}, fmt.Errorf("API error %d: %s", resp.StatusCode, string(responseBody))
+	}
+
+	var response anthropicMessagesResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return completion{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if response.Error != nil {
+		return completion{}, fmt.Errorf("API error: %s", response.Error.Message)
+	}
+
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			return completion{Content: block.Text}, nil
+		}
+	}
+
+	return completion{}, fmt.Errorf("no text content block returned")
+}
@@ -0,0 +1,150 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// completion is a provider's raw reply to a single complete call: either
+// Content (a free-text reply, the original behavior) or ToolCallArgs (the
+// JSON arguments of the planTool call, when the provider supports tool
+// calling and the model used it) - never both. generatePlan picks
+// whichever is set to decide how to parse the reply into an
+// OrchestrationPlan.
+type completion struct {
+	Content      string
+	ToolCallArgs string
+}
+
+// chatCompletionRequest is the request body OpenRouter and any
+// OpenAI-compatible endpoint expect at POST /chat/completions.
+type chatCompletionRequest struct {
+	Model      string                  `json:"model"`
+	Messages   []chatCompletionMessage `json:"messages"`
+	Tools      []chatCompletionTool    `json:"tools,omitempty"`
+	ToolChoice any                     `json:"tool_choice,omitempty"`
+	Stream     bool                    `json:"stream,omitempty"`
+}
+
+// chatCompletionMessage is one message in a chatCompletionRequest, or (as
+// part of a chatCompletionChoice) one the model sent back - in which case
+// ToolCalls is set instead of Content whenever the model invoked a tool.
+type chatCompletionMessage struct {
+	Role      string                   `json:"role"`
+	Content   string                   `json:"content"`
+	ToolCalls []chatCompletionToolCall `json:"tool_calls,omitempty"`
+}
+
+// chatCompletionResponse is the response body OpenRouter and any
+// OpenAI-compatible endpoint return from POST /chat/completions.
+type chatCompletionResponse struct {
+	Choices []chatCompletionChoice `json:"choices"`
+	Error   *chatCompletionError   `json:"error,omitempty"`
+}
+
+// chatCompletionChoice is one completion choice in a
+// chatCompletionResponse.
+type chatCompletionChoice struct {
+	Message chatCompletionMessage `json:"message"`
+}
+
+// chatCompletionError is the error body OpenRouter and any
+// OpenAI-compatible endpoint return in place of choices.
+type chatCompletionError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Code    string `json:"code"`
+}
+
+// chatCompletionOptions carries the parts of a chatCompletionRequest that
+// vary by provider or call site: extraHeaders is applied on top of
+// Content-Type/Authorization (e.g. OpenRouter's HTTP-Referer/X-Title
+// attribution headers), and tools/toolChoice request structured tool
+// calling - both nil/empty for a provider that doesn't use it, which
+// leaves the request exactly as it was before tool calling existed.
+type chatCompletionOptions struct {
+	ExtraHeaders map[string]string
+	Tools        []chatCompletionTool
+	ToolChoice   any
+}
+
+// callChatCompletions POSTs prompt as the sole user message to
+// baseURL+"/chat/completions" in the OpenAI-compatible request shape
+// shared by OpenRouter and direct OpenAI/OpenAI-compatible endpoints (see
+// OpenRouterPlanner.complete, OpenAIPlanner.complete).
+func callChatCompletions(ctx context.Context, httpClient *http.Client, baseURL, apiKey, model, prompt string, opts chatCompletionOptions) (completion, error) {
+	request := chatCompletionRequest{
+		Model: model,
+		Messages: []chatCompletionMessage{
+			{Role: "user", Content: prompt},
+		},
+		Tools:      opts.Tools,
+		ToolChoice: opts.ToolChoice,
+	}
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return completion{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/chat/completions", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return completion{}, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	for k, v := range opts.ExtraHeaders {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return completion{}, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return completion{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	utils.Debug("chat completions response status: %d", resp.StatusCode)
+	utils.Debug("chat completions response body: %s", string(responseBody))
+
+	if resp.StatusCode != http.StatusOK {
+		return completion{}, &httpStatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			Body:       string(responseBody),
+		}
+	}
+
+	var response chatCompletionResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return completion{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if response.Error != nil {
+		return completion{}, fmt.Errorf("API error: %s", response.Error.Message)
+	}
+
+	if len(response.Choices) == 0 {
+		return completion{}, fmt.Errorf("no response choices returned")
+	}
+
+	message := response.Choices[0].Message
+	if args, ok := findToolCallArgs(message.ToolCalls, planToolName); ok {
+		return completion{ToolCallArgs: args}, nil
+	}
+
+	return completion{Content: message.Content}, nil
+}
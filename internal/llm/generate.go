@@ -0,0 +1,50 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/observability"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// completeFunc calls a provider's chat/completion endpoint with prompt as
+// the sole user message and returns its reply, either as free text or
+// (for a provider that requested it and a model that supports it) a
+// planToolName tool call's arguments - see completion.
+type completeFunc func(ctx context.Context, prompt string) (completion, error)
+
+// generatePlan is the provider-agnostic half of every Planner's
+// GenerateOrchestrationPlanWithHistory: it builds the prompt and parses
+// the result into an OrchestrationPlan, so each provider only has to
+// implement its own HTTP call shape via complete (see openrouter.go,
+// openai.go, anthropic.go, ollama.go).
+func generatePlan(ctx context.Context, query models.Query, history []models.ConversationTurn, descriptors []models.AgentDescriptor, complete completeFunc) (*models.OrchestrationPlan, error) {
+	utils.Debug("Generating orchestration plan for query: %s", query.Text)
+	start := time.Now()
+	defer func() { observability.ObservePlanGenerationDuration(time.Since(start).Seconds()) }()
+
+	prompt := buildOrchestrationPrompt(query, history, descriptors)
+
+	result, err := complete(ctx, prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call LLM provider: %w", err)
+	}
+
+	var plan *models.OrchestrationPlan
+	if result.ToolCallArgs != "" {
+		plan, err = parsePlanToolCall(result.ToolCallArgs, query)
+	} else {
+		plan, err = parseOrchestrationResponse(result.Content, query)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse orchestration response: %w", err)
+	}
+
+	utils.Info("Generated orchestration plan with %d tasks, strategy: %s",
+		len(plan.Tasks), plan.Strategy)
+
+	return plan, nil
+}
@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// httpStatusError is returned by a provider's complete when its HTTP call
+// completed but with a non-200 status, carrying enough detail for
+// isRetryableCompleteError and callWithRetry to decide whether to retry
+// it and how long to wait first.
+type httpStatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Body)
+}
+
+// retryableHTTPStatuses are the status codes worth retrying - transient
+// timeouts, rate limiting, and server-side errors - as opposed to a
+// non-retryable 4xx (e.g. 401, 400) that would just fail identically on
+// retry.
+var retryableHTTPStatuses = map[int]bool{
+	http.StatusRequestTimeout:      true, // 408
+	http.StatusTooEarly:            true, // 425
+	http.StatusTooManyRequests:     true, // 429
+	http.StatusInternalServerError: true, // 500
+	http.StatusBadGateway:          true, // 502
+	http.StatusServiceUnavailable:  true, // 503
+	http.StatusGatewayTimeout:      true, // 504
+}
+
+// isRetryableCompleteError reports whether err (as returned by a
+// provider's complete) is worth retrying: a retryable-status
+// httpStatusError, or any other error (e.g. a dial failure or a timeout),
+// which is also worth retrying since it never got a definitive response.
+func isRetryableCompleteError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return retryableHTTPStatuses[statusErr.StatusCode]
+	}
+	return true
+}
+
+// retryAfterOf returns the Retry-After duration carried by err, or 0 if
+// err isn't an httpStatusError or didn't have one.
+func retryAfterOf(err error) time.Duration {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.RetryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter parses an HTTP Retry-After header value, which is
+// either a number of seconds or an HTTP-date, returning 0 if header is
+// empty, unparseable, or already in the past.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if when, err := http.ParseTime(header); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
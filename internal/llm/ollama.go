@@ -0,0 +1,128 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+func init() {
+	Register("ollama", func(cfg Config) (Planner, error) { return NewOllamaPlanner(cfg), nil })
+}
+
+const (
+	defaultOllamaBaseURL = "http://localhost:11434"
+	defaultOllamaModel   = "llama3.1"
+)
+
+// OllamaPlanner generates orchestration plans via a local Ollama server's
+// chat API, requiring no API key - lets the multi-agent system run
+// offline against a self-hosted model instead of a hosted provider.
+type OllamaPlanner struct {
+	baseURL    string
+	model      string
+	httpClient *http.Client
+}
+
+// NewOllamaPlanner builds an OllamaPlanner from cfg, defaulting BaseURL,
+// Model, and Timeout to Ollama's default local address, llama3.1, and 30s
+// respectively when left zero. cfg.APIKey is ignored - Ollama doesn't
+// require one.
+func NewOllamaPlanner(cfg Config) *OllamaPlanner {
+	return &OllamaPlanner{
+		baseURL:    orDefault(cfg.BaseURL, defaultOllamaBaseURL),
+		model:      orDefault(cfg.Model, defaultOllamaModel),
+		httpClient: &http.Client{Timeout: orDefaultDuration(cfg.Timeout, 30*time.Second)},
+	}
+}
+
+// GenerateOrchestrationPlan implements Planner.
+func (p *OllamaPlanner) GenerateOrchestrationPlan(ctx context.Context, query models.Query, descriptors []models.AgentDescriptor) (*models.OrchestrationPlan, error) {
+	return p.GenerateOrchestrationPlanWithHistory(ctx, query, nil, descriptors)
+}
+
+// GenerateOrchestrationPlanWithHistory implements Planner.
+func (p *OllamaPlanner) GenerateOrchestrationPlanWithHistory(ctx context.Context, query models.Query, history []models.ConversationTurn, descriptors []models.AgentDescriptor) (*models.OrchestrationPlan, error) {
+	return generatePlan(ctx, query, history, descriptors, p.complete)
+}
+
+// ollamaChatRequest is the request body Ollama's POST /api/chat expects.
+// Stream is always false: generatePlan wants the whole reply at once,
+// not a token stream.
+type ollamaChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []ollamaChatMessage `json:"messages"`
+	Stream   bool                `json:"stream"`
+}
+
+// ollamaChatMessage is one message in an ollamaChatRequest.
+type ollamaChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ollamaChatResponse is the response body Ollama's POST /api/chat returns
+// when Stream is false.
+type ollamaChatResponse struct {
+	Message ollamaChatMessage `json:"message"`
+}
+
+// complete calls the local Ollama server's native chat API. It always
+// returns a free-text completion - Ollama's chat API has no tool-calling
+// support, so Ollama sticks to the brace-matching fallback path.
+func (p *OllamaPlanner) complete(ctx context.Context, prompt string) (completion, error) {
+	request := ollamaChatRequest{
+		Model: p.model,
+		Messages: []ollamaChatMessage{
+			{Role: "user", Content: prompt},
+		},
+		Stream: false,
+	}
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return completion{}, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/chat", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return completion{}, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return completion{}, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return completion{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	utils.Debug("Ollama response status: %d", resp.StatusCode)
+	utils.Debug("Ollama response body: %s", string(responseBody))
+
+	if resp.StatusCode != http.StatusOK {
+		return completion{}, fmt.Errorf("API error %d: %s", resp.StatusCode, string(responseBody))
+	}
+
+	var response ollamaChatResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return completion{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if response.Message.Content == "" {
+		return completion{}, fmt.Errorf("no response content returned")
+	}
+
+	return completion{Content: response.Message.Content}, nil
+}
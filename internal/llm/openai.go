@@ -0,0 +1,61 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/steve/llm-agents/internal/models"
+)
+
+func init() {
+	Register("openai", func(cfg Config) (Planner, error) { return NewOpenAIPlanner(cfg), nil })
+}
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	defaultOpenAIModel   = "gpt-4o"
+)
+
+// OpenAIPlanner generates orchestration plans via OpenAI's
+// chat-completions API, or any enterprise gateway that speaks the same
+// OpenAI-compatible wire format once BaseURL is pointed at it.
+type OpenAIPlanner struct {
+	apiKey     string
+	baseURL    string
+	model      string
+	httpClient *http.Client
+}
+
+// NewOpenAIPlanner builds an OpenAIPlanner from cfg, defaulting BaseURL,
+// Model, and Timeout to OpenAI's own endpoint, gpt-4o, and 30s
+// respectively when left zero.
+func NewOpenAIPlanner(cfg Config) *OpenAIPlanner {
+	return &OpenAIPlanner{
+		apiKey:     cfg.APIKey,
+		baseURL:    orDefault(cfg.BaseURL, defaultOpenAIBaseURL),
+		model:      orDefault(cfg.Model, defaultOpenAIModel),
+		httpClient: &http.Client{Timeout: orDefaultDuration(cfg.Timeout, 30*time.Second)},
+	}
+}
+
+// GenerateOrchestrationPlan implements Planner.
+func (p *OpenAIPlanner) GenerateOrchestrationPlan(ctx context.Context, query models.Query, descriptors []models.AgentDescriptor) (*models.OrchestrationPlan, error) {
+	return p.GenerateOrchestrationPlanWithHistory(ctx, query, nil, descriptors)
+}
+
+// GenerateOrchestrationPlanWithHistory implements Planner.
+func (p *OpenAIPlanner) GenerateOrchestrationPlanWithHistory(ctx context.Context, query models.Query, history []models.ConversationTurn, descriptors []models.AgentDescriptor) (*models.OrchestrationPlan, error) {
+	return generatePlan(ctx, query, history, descriptors, p.complete)
+}
+
+// complete calls OpenAI's (or an OpenAI-compatible gateway's)
+// /chat/completions with no provider-specific extra headers, but still
+// offering the orchestration tools so a tool-calling-capable model can
+// return a structured plan instead of free text.
+func (p *OpenAIPlanner) complete(ctx context.Context, prompt string) (completion, error) {
+	return callChatCompletions(ctx, p.httpClient, p.baseURL, p.apiKey, p.model, prompt, chatCompletionOptions{
+		Tools:      orchestrationTools(),
+		ToolChoice: planToolChoice(),
+	})
+}
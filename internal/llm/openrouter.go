@@ -0,0 +1,191 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// planStrategyPattern matches the "strategy" field's value as it
+// accumulates in a streamed planToolName tool call's arguments, ahead of
+// the full arguments string being valid JSON - see
+// OpenRouterPlanner.StreamOrchestrationPlan.
+var planStrategyPattern = regexp.MustCompile(`"strategy"\s*:\s*"(\w+)"`)
+
+func init() {
+	Register("openrouter", func(cfg Config) (Planner, error) { return NewOpenRouterPlanner(cfg), nil })
+}
+
+const (
+	defaultOpenRouterBaseURL = "https://openrouter.ai/api/v1"
+	defaultOpenRouterModel   = "anthropic/claude-3.5-sonnet"
+)
+
+// OpenRouterPlanner generates orchestration plans via OpenRouter's
+// OpenAI-compatible chat-completions API, defaulting to Claude 3.5
+// Sonnet. Its HTTP calls run through a retry-with-jitter policy and a
+// circuit breaker keyed on model name (see callWithRetry), so a 429/5xx
+// from one model is retried a few times before giving up, and a model
+// that keeps failing stops taking new attempts for a cooldown period
+// instead of blocking every incoming query behind it.
+type OpenRouterPlanner struct {
+	apiKey      string
+	baseURL     string
+	model       string
+	httpClient  *http.Client
+	retryPolicy RetryPolicy
+	breakers    *modelBreakers
+}
+
+// NewOpenRouterPlanner builds an OpenRouterPlanner from cfg, defaulting
+// BaseURL, Model, Timeout, RetryPolicy, and Breaker to OpenRouter's own
+// endpoint, Claude 3.5 Sonnet, 30s, DefaultRetryPolicy, and
+// resilience.DefaultPolicy().Breaker respectively when left zero/nil.
+func NewOpenRouterPlanner(cfg Config) *OpenRouterPlanner {
+	return &OpenRouterPlanner{
+		apiKey:      cfg.APIKey,
+		baseURL:     orDefault(cfg.BaseURL, defaultOpenRouterBaseURL),
+		model:       orDefault(cfg.Model, defaultOpenRouterModel),
+		httpClient:  &http.Client{Timeout: orDefaultDuration(cfg.Timeout, 30*time.Second)},
+		retryPolicy: resolveRetryPolicy(cfg.RetryPolicy),
+		breakers:    newModelBreakers(resolveBreakerConfig(cfg.Breaker)),
+	}
+}
+
+// GenerateOrchestrationPlan implements Planner.
+func (p *OpenRouterPlanner) GenerateOrchestrationPlan(ctx context.Context, query models.Query, descriptors []models.AgentDescriptor) (*models.OrchestrationPlan, error) {
+	return p.GenerateOrchestrationPlanWithHistory(ctx, query, nil, descriptors)
+}
+
+// GenerateOrchestrationPlanWithHistory implements Planner.
+func (p *OpenRouterPlanner) GenerateOrchestrationPlanWithHistory(ctx context.Context, query models.Query, history []models.ConversationTurn, descriptors []models.AgentDescriptor) (*models.OrchestrationPlan, error) {
+	return generatePlan(ctx, query, history, descriptors, p.complete)
+}
+
+// complete calls OpenRouter's /chat/completions, attributing the request
+// via the HTTP-Referer/X-Title headers OpenRouter uses for per-app usage
+// tracking, retrying a retryable failure under p.retryPolicy and tripping
+// p.breakers' per-model circuit breaker on persistent failure (see
+// callWithRetry).
+func (p *OpenRouterPlanner) complete(ctx context.Context, prompt string) (completion, error) {
+	return callWithRetry(ctx, p.breakers, p.retryPolicy, p.model, func() (completion, error) {
+		return callChatCompletions(ctx, p.httpClient, p.baseURL, p.apiKey, p.model, prompt, chatCompletionOptions{
+			ExtraHeaders: p.extraHeaders(),
+			Tools:        orchestrationTools(),
+			ToolChoice:   planToolChoice(),
+		})
+	})
+}
+
+// extraHeaders is the HTTP-Referer/X-Title attribution OpenRouter uses
+// for per-app usage tracking, shared by complete and
+// StreamOrchestrationPlan.
+func (p *OpenRouterPlanner) extraHeaders() map[string]string {
+	return map[string]string{
+		"HTTP-Referer": "https://github.com/steve/llm-agents",
+		"X-Title":      "LLM Multi-Agent System",
+	}
+}
+
+// StreamOrchestrationPlan implements StreamingPlanner. Unlike complete,
+// it is not retried through callWithRetry/p.breakers: a mid-stream
+// failure has already delivered some PlanEvents to the caller, so
+// silently retrying the whole request from scratch would either re-emit
+// tasks the caller already dispatched or require the caller to undo
+// them - instead the failure is reported as a terminal PlanEvent, which
+// the coordinator (see runStreamingPlan) reports as the query's error
+// without retrying through the non-streaming Planner path.
+func (p *OpenRouterPlanner) StreamOrchestrationPlan(ctx context.Context, query models.Query, history []models.ConversationTurn, descriptors []models.AgentDescriptor) (<-chan models.PlanEvent, error) {
+	prompt := buildOrchestrationPrompt(query, history, descriptors)
+
+	deltas, err := streamChatCompletions(ctx, p.httpClient, p.baseURL, p.apiKey, p.model, prompt, chatCompletionOptions{
+		ExtraHeaders: p.extraHeaders(),
+		Tools:        orchestrationTools(),
+		ToolChoice:   planToolChoice(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan models.PlanEvent, streamEventBufferSize)
+	go func() {
+		defer close(events)
+
+		var argsBuffer strings.Builder
+		taskParser := newStreamTaskParser()
+		nextTaskIndex := 0
+		eagerDispatchOK := false
+
+		for delta := range deltas {
+			if delta.err != nil {
+				events <- models.PlanEvent{Type: models.PlanEventDone, Error: delta.err.Error()}
+				return
+			}
+			if delta.content != "" {
+				events <- models.PlanEvent{Type: models.PlanEventReasoning, Delta: delta.content}
+			}
+			if delta.toolArgs == "" {
+				continue
+			}
+			argsBuffer.WriteString(delta.toolArgs)
+
+			// Only a task dispatched under ExecutionParallel/ExecutionDAG
+			// would have run concurrently with its siblings anyway, so only
+			// those strategies make emitting PlanEventTaskAdded ahead of
+			// PlanEventDone safe - see planStrategyPattern and
+			// Coordinator.runStreamingPlan. The schema behind planToolName
+			// lists "strategy" ahead of "tasks" (see planToolParameters), so
+			// in practice this is already known by the time any task
+			// object's braces close.
+			if !eagerDispatchOK {
+				if m := planStrategyPattern.FindStringSubmatch(argsBuffer.String()); m != nil {
+					eagerDispatchOK = m[1] == string(models.ExecutionParallel) || m[1] == string(models.ExecutionDAG)
+				}
+			}
+
+			fresh := taskParser.feed(delta.toolArgs)
+			if !eagerDispatchOK {
+				continue
+			}
+			for _, raw := range fresh {
+				var taskData orchestrationTaskData
+				if err := json.Unmarshal([]byte(raw), &taskData); err != nil {
+					utils.Error("streaming plan: failed to parse task object %q: %v", raw, err)
+					nextTaskIndex++
+					continue
+				}
+				task, err := agentTaskFromData(taskData, nextTaskIndex)
+				nextTaskIndex++
+				if err != nil {
+					utils.Error("streaming plan: failed to convert task object %q: %v", raw, err)
+					continue
+				}
+				// A dag task naming dependencies isn't safe to run the
+				// instant it's seen - it still has to wait on those sibling
+				// tasks, which executeDAG (via executePlanWithPrestarted)
+				// already handles once the full plan arrives at
+				// PlanEventDone.
+				if len(task.DependsOn) > 0 {
+					continue
+				}
+				events <- models.PlanEvent{Type: models.PlanEventTaskAdded, Task: &task}
+			}
+		}
+
+		plan, err := parsePlanToolCall(argsBuffer.String(), query)
+		if err != nil {
+			events <- models.PlanEvent{Type: models.PlanEventDone, Error: fmt.Sprintf("failed to parse orchestration plan: %v", err)}
+			return
+		}
+		events <- models.PlanEvent{Type: models.PlanEventDone, Plan: plan}
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,148 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/resilience"
+)
+
+// TestOpenRouterPlannerRetriesRetryableStatus verifies a 503 is retried
+// up to RetryPolicy.MaxAttempts, the request eventually succeeds once the
+// server stops failing, and the retry waits stayed within the policy's
+// backoff bounds.
+func TestOpenRouterPlannerRetriesRetryableStatus(t *testing.T) {
+	var attempts int32
+	var attemptTimes []time.Time
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptTimes = append(attemptTimes, time.Now())
+		n := atomic.AddInt32(&attempts, 1)
+		if n < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	retryPolicy := RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 100 * time.Millisecond, Jitter: 0.2}
+	planner := NewOpenRouterPlanner(Config{
+		APIKey:      "test-key",
+		BaseURL:     server.URL,
+		RetryPolicy: &retryPolicy,
+	})
+
+	start := time.Now()
+	result, err := planner.complete(context.Background(), "hello")
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("complete() error = %v, want nil", err)
+	}
+	if result.Content != "ok" {
+		t.Errorf("complete() = %q, want %q", result.Content, "ok")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("server saw %d attempts, want 3", got)
+	}
+
+	// Two retries at up to base*1 and base*2, each with +/-20% jitter:
+	// the lower bound is generous since a slow CI box shouldn't flake.
+	if elapsed < 5*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least a couple of backoff waits", elapsed)
+	}
+	if elapsed > 500*time.Millisecond {
+		t.Errorf("elapsed = %v, want well under MaxDelay-bounded retries", elapsed)
+	}
+}
+
+// TestOpenRouterPlannerNonRetryableStatusFailsFast verifies a
+// non-retryable 4xx (401) is not retried at all.
+func TestOpenRouterPlannerNonRetryableStatusFailsFast(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"message":"bad key"}}`))
+	}))
+	defer server.Close()
+
+	retryPolicy := RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 100 * time.Millisecond}
+	planner := NewOpenRouterPlanner(Config{APIKey: "bad-key", BaseURL: server.URL, RetryPolicy: &retryPolicy})
+
+	if _, err := planner.complete(context.Background(), "hello"); err == nil {
+		t.Fatal("complete() error = nil, want the 401 surfaced")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("server saw %d attempts, want 1 (no retry on a non-retryable status)", got)
+	}
+}
+
+// TestOpenRouterPlannerBreakerOpensAfterPersistentFailure verifies the
+// per-model circuit breaker trips once enough calls have failed, so a
+// subsequent call fails immediately (ErrBreakerOpen) without hitting the
+// server at all.
+func TestOpenRouterPlannerBreakerOpensAfterPersistentFailure(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	retryPolicy := RetryPolicy{MaxAttempts: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	breaker := resilience.BreakerConfig{MinRequests: 2, FailureRatio: 0.5, CooldownPeriod: time.Minute}
+	planner := NewOpenRouterPlanner(Config{
+		APIKey:      "test-key",
+		BaseURL:     server.URL,
+		RetryPolicy: &retryPolicy,
+		Breaker:     &breaker,
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := planner.complete(context.Background(), "hello"); err == nil {
+			t.Fatalf("call %d: error = nil, want the 500 surfaced", i)
+		}
+	}
+
+	seenBeforeTrip := atomic.LoadInt32(&attempts)
+
+	if _, err := planner.complete(context.Background(), "hello"); err != resilience.ErrBreakerOpen {
+		t.Fatalf("complete() error = %v, want resilience.ErrBreakerOpen", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != seenBeforeTrip {
+		t.Errorf("server saw %d more attempts after the breaker tripped, want 0", got-seenBeforeTrip)
+	}
+}
+
+// TestOpenRouterPlannerMalformedToolCallArguments verifies that a
+// tool_calls entry for planToolName whose arguments are truncated/invalid
+// JSON surfaces a clear parse error out of generatePlan, rather than
+// panicking or silently producing an empty plan.
+func TestOpenRouterPlannerMalformedToolCallArguments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","tool_calls":[{"id":"call_1","type":"function","function":{"name":"generate_orchestration_plan","arguments":"{\"strategy\": \"sequential\", \"tasks\": [truncated"}}]}}]}`))
+	}))
+	defer server.Close()
+
+	planner := NewOpenRouterPlanner(Config{APIKey: "test-key", BaseURL: server.URL})
+
+	result, err := planner.complete(context.Background(), "hello")
+	if err != nil {
+		t.Fatalf("complete() error = %v, want nil (the malformed JSON is inside ToolCallArgs, not an HTTP failure)", err)
+	}
+	if result.ToolCallArgs == "" {
+		t.Fatal("complete() ToolCallArgs = \"\", want the malformed arguments string")
+	}
+
+	if _, err := parsePlanToolCall(result.ToolCallArgs, models.Query{ID: "q1"}); err == nil {
+		t.Fatal("parsePlanToolCall() error = nil, want a JSON unmarshal error surfaced")
+	}
+}
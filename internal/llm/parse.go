@@ -0,0 +1,177 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/steve/llm-agents/internal/models"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// orchestrationPlanData is the wire shape of an orchestration plan,
+// whether it arrives as the planToolName tool call's arguments (see
+// parsePlanToolCall) or brace-matched out of a free-text reply (see
+// parseOrchestrationResponse) - both are parsed into this struct and then
+// converted by orchestrationPlanFromData.
+type orchestrationPlanData struct {
+	Strategy string `json:"strategy"`
+	Tasks    []struct {
+		TaskID        string            `json:"task_id"`
+		AgentType     string            `json:"agent_type"`
+		Priority      int               `json:"priority"`
+		Dependencies  []string          `json:"dependencies"`
+		InputBindings map[string]string `json:"input_bindings"`
+		Parameters    map[string]string `json:"parameters"`
+	} `json:"tasks"`
+	Reasoning string `json:"reasoning"`
+}
+
+// parsePlanToolCall parses argsJSON - the planToolName tool call's
+// arguments - directly into an OrchestrationPlan. Unlike
+// parseOrchestrationResponse, argsJSON is already exactly the JSON object
+// the model was asked for, so no brace-matching extraction is needed.
+func parsePlanToolCall(argsJSON string, query models.Query) (*models.OrchestrationPlan, error) {
+	var planData orchestrationPlanData
+	if err := json.Unmarshal([]byte(argsJSON), &planData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s tool call arguments: %w", planToolName, err)
+	}
+	return orchestrationPlanFromData(planData, query)
+}
+
+// parseOrchestrationResponse parses a provider's raw text reply into an
+// OrchestrationPlan - the fallback path for a model that doesn't support
+// tool calling (see parsePlanToolCall for the structured path).
+func parseOrchestrationResponse(response string, query models.Query) (*models.OrchestrationPlan, error) {
+	// Try to extract JSON from the response (the LLM might include
+	// explanation text). Find the JSON object by matching braces.
+	jsonStart := -1
+	braceCount := 0
+	jsonEnd := -1
+
+	for i, r := range response {
+		if r == '{' {
+			if jsonStart == -1 {
+				jsonStart = i
+			}
+			braceCount++
+		} else if r == '}' {
+			braceCount--
+			if braceCount == 0 && jsonStart != -1 {
+				jsonEnd = i + 1
+				break
+			}
+		}
+	}
+
+	if jsonStart == -1 || jsonEnd == -1 {
+		return nil, fmt.Errorf("no valid JSON found in response: %s", response)
+	}
+
+	jsonStr := response[jsonStart:jsonEnd]
+	utils.Info("Full LLM Response: %s", response)
+	utils.Info("Extracted JSON: %s", jsonStr)
+
+	var planData orchestrationPlanData
+	utils.Info("Parsing JSON into planData structure...")
+	if err := json.Unmarshal([]byte(jsonStr), &planData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal orchestration plan: %w", err)
+	}
+
+	return orchestrationPlanFromData(planData, query)
+}
+
+// orchestrationPlanFromData converts planData - already unmarshalled from
+// either a tool call's arguments or a brace-matched text reply - into an
+// OrchestrationPlan.
+func orchestrationPlanFromData(planData orchestrationPlanData, query models.Query) (*models.OrchestrationPlan, error) {
+	plan := &models.OrchestrationPlan{
+		QueryID:   query.ID,
+		Reasoning: planData.Reasoning,
+	}
+
+	// Set execution strategy
+	switch planData.Strategy {
+	case "parallel":
+		plan.Strategy = models.ExecutionParallel
+	case "sequential":
+		plan.Strategy = models.ExecutionSequential
+	case "dag":
+		plan.Strategy = models.ExecutionDAG
+	default:
+		return nil, fmt.Errorf("invalid execution strategy: %s", planData.Strategy)
+	}
+
+	// Convert tasks
+	for i, taskData := range planData.Tasks {
+		task, err := agentTaskFromData(taskData, i)
+		if err != nil {
+			return nil, err
+		}
+		plan.Tasks = append(plan.Tasks, task)
+	}
+
+	if len(plan.Tasks) == 0 {
+		return nil, fmt.Errorf("no tasks generated in orchestration plan")
+	}
+
+	return plan, nil
+}
+
+// orchestrationTaskData is the wire shape of a single entry in
+// orchestrationPlanData.Tasks; pulled out as its own name so
+// agentTaskFromData can also convert a task object decoded in isolation,
+// one array element at a time, off a streaming tool call (see
+// streamTaskParser.feed in stream.go).
+type orchestrationTaskData = struct {
+	TaskID        string            `json:"task_id"`
+	AgentType     string            `json:"agent_type"`
+	Priority      int               `json:"priority"`
+	Dependencies  []string          `json:"dependencies"`
+	InputBindings map[string]string `json:"input_bindings"`
+	Parameters    map[string]string `json:"parameters"`
+}
+
+// agentTaskFromData converts taskData - the i'th entry of a plan's tasks
+// array, whether unmarshalled as part of the whole plan or on its own off
+// a streaming tool call - into an AgentTask, defaulting TaskID to
+// "task-<i+1>" when the planner left it blank.
+func agentTaskFromData(taskData orchestrationTaskData, i int) (models.AgentTask, error) {
+	taskID := taskData.TaskID
+	if taskID == "" {
+		taskID = fmt.Sprintf("task-%d", i+1)
+	}
+	task := models.AgentTask{
+		TaskID:        taskID,
+		DependsOn:     taskData.Dependencies,
+		InputBindings: taskData.InputBindings,
+	}
+
+	switch taskData.AgentType {
+	case "temperature":
+		task.AgentType = models.AgentTypeTemperature
+		task.City = taskData.Parameters["city"]
+	case "datetime":
+		task.AgentType = models.AgentTypeDateTime
+		task.City = taskData.Parameters["city"]
+		task.Timezone = taskData.Parameters["timezone"]
+	case "echo":
+		task.AgentType = models.AgentTypeEcho
+		task.EchoText = taskData.Parameters["text"]
+		utils.Info("Echo task created with text: '%s'", task.EchoText)
+		// Validate that echo text is provided
+		if task.EchoText == "" {
+			return models.AgentTask{}, fmt.Errorf("echo agent requires non-empty 'text' parameter")
+		}
+	case "forecast":
+		task.AgentType = models.AgentTypeForecast
+		task.City = taskData.Parameters["city"]
+		if days, err := strconv.Atoi(taskData.Parameters["days"]); err == nil {
+			task.Days = days
+		}
+	default:
+		return models.AgentTask{}, fmt.Errorf("invalid agent type: %s", taskData.AgentType)
+	}
+
+	return task, nil
+}
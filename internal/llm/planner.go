@@ -0,0 +1,32 @@
+// Package llm provides a pluggable registry of orchestration-plan
+// generators (Planner implementations), one per LLM provider, so the
+// coordinator can run against OpenRouter, a direct Anthropic or
+// OpenAI-compatible endpoint, or a local Ollama server by config alone -
+// see Register/New. Modeled on Terraform's backend/init: every provider
+// registers a Factory under a unique name in an init(), and New looks it
+// up by that name.
+package llm
+
+import (
+	"context"
+
+	"github.com/steve/llm-agents/internal/models"
+)
+
+// Planner generates an orchestration plan for a user query, deciding
+// which sub-agents to invoke and how. coordinator.Coordinator depends on
+// this interface instead of any single provider, so it can be
+// unit-tested with a fake Planner and swapped between providers via
+// config without a coordinator code change.
+type Planner interface {
+	// GenerateOrchestrationPlan is GenerateOrchestrationPlanWithHistory
+	// with no conversation history.
+	GenerateOrchestrationPlan(ctx context.Context, query models.Query, descriptors []models.AgentDescriptor) (*models.OrchestrationPlan, error)
+
+	// GenerateOrchestrationPlanWithHistory analyzes query - plus a
+	// rolling conversation history, most recent last, for resolving
+	// follow-ups like "and the weather there?" - against descriptors
+	// (every registered sub-agent's Describe() output) and returns the
+	// resulting OrchestrationPlan.
+	GenerateOrchestrationPlanWithHistory(ctx context.Context, query models.Query, history []models.ConversationTurn, descriptors []models.AgentDescriptor) (*models.OrchestrationPlan, error)
+}
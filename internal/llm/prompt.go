@@ -0,0 +1,90 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steve/llm-agents/internal/models"
+)
+
+// buildOrchestrationPrompt creates the prompt for the LLM to analyze the
+// query. history, if non-empty, is rendered as a "Conversation history"
+// section ahead of the current query so the LLM can resolve a follow-up
+// against what a prior turn already established (e.g. which city).
+// descriptors renders the "Available sub-agents" section from each
+// registered agent's Describe() instead of a hard-coded list.
+func buildOrchestrationPrompt(query models.Query, history []models.ConversationTurn, descriptors []models.AgentDescriptor) string {
+	return fmt.Sprintf(`You are an intelligent agent coordinator. Analyze the following user query and determine which sub-agents should be invoked and how they should execute.
+
+Available sub-agents:
+%s
+%s
+Query: "%s"
+City: "%s"
+Timezone: "%s"
+
+IMPORTANT RULES:
+- Only invoke Echo Agent when the user explicitly asks to echo text (e.g., "echo hello world", "repeat this text")
+- For Echo Agent requests, extract the text to echo from the query (e.g., for "echo hello world", use "hello world" as the text parameter)
+- For weather/temperature and datetime queries, do NOT invoke the Echo Agent
+- Invoke Forecast Agent instead of Temperature Agent when the query asks about a future day or a range of days rather than the current conditions (e.g., "tomorrow", "this weekend", "5-day forecast", "will it rain Tuesday")
+- For Forecast Agent requests, set the "days" parameter to the number of days the query needs (e.g., "tomorrow" -> 2, so the forecast covers through tomorrow; "5-day forecast" -> 5); default to 5 if the query doesn't imply a specific number
+- Use Temperature Agent, not Forecast Agent, for "now"/"currently"/"right now" style queries
+- If Timezone is non-empty, a DateTime Agent task should set the "timezone" parameter to it instead of "city" - the user already named an IANA timezone (e.g. "Europe/Berlin"), so city resolution is unnecessary and should be skipped
+- Use parallel execution when multiple data types are requested (e.g., both weather and time)
+- Use sequential execution when one result depends on another or for single requests
+- Use "dag" execution only when a task's parameter must come from another task's result rather than from the query itself; give every task a short unique "task_id", list prerequisite task_ids in "dependencies", and for each field that must be copied from a prior task add an entry to "input_bindings" of the form {"<field>": "$tasks.<task_id>.output.<output_field>"} (output_field is the prior task's response field, e.g. "city", "timezone", "echo_text", "temperature")
+- Provide clear reasoning for your decisions
+
+Respond with a JSON object in this exact format:
+{
+  "strategy": "parallel" | "sequential" | "dag",
+  "tasks": [
+    {
+      "task_id": "t1",
+      "agent_type": "temperature" | "datetime" | "echo" | "forecast",
+      "priority": 1,
+      "dependencies": [],
+      "input_bindings": {},
+      "parameters": {
+        "city": "city_name",
+        "timezone": "iana_timezone_name",
+        "text": "text_to_echo",
+        "days": "number_of_days"
+      }
+    }
+  ],
+  "reasoning": "Explanation of why this orchestration plan was chosen"
+}`, formatAgentDescriptors(descriptors), formatConversationHistory(history), query.Text, query.City, query.Timezone)
+}
+
+// formatAgentDescriptors renders descriptors as the numbered "Available
+// sub-agents" list the prompt expects, one line per agent, in whatever
+// order the coordinator's registry returns them.
+func formatAgentDescriptors(descriptors []models.AgentDescriptor) string {
+	var b strings.Builder
+	for i, d := range descriptors {
+		b.WriteString(fmt.Sprintf("%d. %s: %s\n", i+1, d.Name, d.Purpose))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatConversationHistory renders history as a labeled prompt section, or
+// "" if history is empty so buildOrchestrationPrompt's output is unchanged
+// for a one-shot query.
+func formatConversationHistory(history []models.ConversationTurn) string {
+	if len(history) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nConversation history (oldest first, use this to resolve follow-ups like \"there\" or \"it\"):\n")
+	for i, turn := range history {
+		b.WriteString(fmt.Sprintf("%d. User asked: %q", i+1, turn.Query))
+		if turn.City != "" {
+			b.WriteString(fmt.Sprintf(" (city: %s)", turn.City))
+		}
+		b.WriteString(fmt.Sprintf(" -> %s\n", turn.Response))
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package llm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steve/llm-agents/internal/resilience"
+)
+
+// Config configures a Planner at construction time. Not every field
+// applies to every provider: BaseURL defaults to the provider's own
+// well-known endpoint when empty, APIKey is ignored by providers (e.g.
+// "ollama") that don't require one, and RetryPolicy/Breaker are currently
+// only honored by OpenRouterPlanner (see NewOpenRouterPlanner) - both nil
+// by default, which resolves to DefaultRetryPolicy and
+// resilience.DefaultPolicy().Breaker respectively.
+type Config struct {
+	APIKey  string
+	BaseURL string
+	Model   string
+	Timeout time.Duration
+
+	RetryPolicy *RetryPolicy
+	Breaker     *resilience.BreakerConfig
+}
+
+// Factory constructs a Planner from cfg. Registered by each provider's
+// init() under a unique name - see Register.
+type Factory func(cfg Config) (Planner, error)
+
+var factories = map[string]Factory{}
+
+// Register adds factory to the registry under name, replacing whatever
+// was previously registered for it. Called from each built-in provider's
+// init() (see openrouter.go, openai.go, anthropic.go, ollama.go).
+func Register(name string, factory Factory) {
+	factories[name] = factory
+}
+
+// New constructs the Planner registered under name, or an error if name
+// isn't registered.
+func New(name string, cfg Config) (Planner, error) {
+	factory, ok := factories[name]
+	if !ok {
+		return nil, fmt.Errorf("llm: unknown provider %q (available: %v)", name, Providers())
+	}
+	return factory(cfg)
+}
+
+// Providers returns the names currently registered, in no particular
+// order.
+func Providers() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	return names
+}
+
+// orDefault returns value, or fallback if value is the empty string.
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// orDefaultDuration returns value, or fallback if value is zero.
+func orDefaultDuration(value, fallback time.Duration) time.Duration {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,142 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/steve/llm-agents/internal/resilience"
+)
+
+// RetryPolicy configures callWithRetry's retry of a provider's HTTP call:
+// up to MaxAttempts, waiting BaseDelay times the attempt number (linear,
+// not exponential - a model rate limit is usually a flat per-minute
+// quota, so a steadily increasing wait clears it about as fast as a
+// fast-growing one while staying more predictable) plus uniform random
+// jitter, capped at MaxDelay - unless the response carried a Retry-After
+// header, which always overrides the computed delay.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      float64
+}
+
+// DefaultRetryPolicy is what callWithRetry falls back to when a provider
+// has none configured: up to 3 attempts, waiting attempt*500ms (+/- 20%
+// jitter), capped at 5s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+// resolveRetryPolicy returns *p, or DefaultRetryPolicy if p is nil.
+func resolveRetryPolicy(p *RetryPolicy) RetryPolicy {
+	if p != nil {
+		return *p
+	}
+	return DefaultRetryPolicy()
+}
+
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// jitteredDelay returns d adjusted by +/- a random fraction up to jitter
+// (e.g. jitter=0.2 returns a value within 20% of d in either direction).
+func jitteredDelay(d time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return d
+	}
+	delta := (rand.Float64()*2 - 1) * jitter
+	return time.Duration(float64(d) * (1 + delta))
+}
+
+// resolveBreakerConfig returns *cfg, or resilience.DefaultPolicy().Breaker
+// if cfg is nil.
+func resolveBreakerConfig(cfg *resilience.BreakerConfig) resilience.BreakerConfig {
+	if cfg != nil {
+		return *cfg
+	}
+	return resilience.DefaultPolicy().Breaker
+}
+
+// modelBreakers lazily creates one resilience.CircuitBreaker per model
+// name, so a failing model trips its own breaker without blocking every
+// other model a provider might be asked to serve.
+type modelBreakers struct {
+	mu      sync.Mutex
+	cfg     resilience.BreakerConfig
+	byModel map[string]*resilience.CircuitBreaker
+}
+
+func newModelBreakers(cfg resilience.BreakerConfig) *modelBreakers {
+	return &modelBreakers{cfg: cfg, byModel: map[string]*resilience.CircuitBreaker{}}
+}
+
+func (m *modelBreakers) forModel(model string) *resilience.CircuitBreaker {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if b, ok := m.byModel[model]; ok {
+		return b
+	}
+	b := resilience.NewCircuitBreaker(m.cfg)
+	m.byModel[model] = b
+	return b
+}
+
+// callWithRetry runs call - one provider HTTP attempt for model - through
+// breakers' per-model circuit breaker, retrying under policy while the
+// error is retryable (see isRetryableCompleteError). A Retry-After
+// duration carried by a retryable error overrides that attempt's computed
+// backoff.
+func callWithRetry(ctx context.Context, breakers *modelBreakers, policy RetryPolicy, model string, call func() (completion, error)) (completion, error) {
+	breaker := breakers.forModel(model)
+	if err := breaker.Allow(); err != nil {
+		return completion{}, err
+	}
+
+	attempts := policy.attempts()
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		result, err := call()
+		if err == nil {
+			breaker.Success()
+			return result, nil
+		}
+		lastErr = err
+
+		if !isRetryableCompleteError(err) || attempt == attempts {
+			break
+		}
+
+		delay := jitteredDelay(policy.BaseDelay*time.Duration(attempt), policy.Jitter)
+		if retryAfter := retryAfterOf(err); retryAfter > 0 {
+			delay = retryAfter
+		} else if delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+
+		select {
+		case <-ctx.Done():
+			breaker.Failure()
+			return completion{}, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	breaker.Failure()
+	if attempts == 1 {
+		return completion{}, lastErr
+	}
+	return completion{}, fmt.Errorf("failed after %d attempt(s): %w", attempts, lastErr)
+}
@@ -0,0 +1,248 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/steve/llm-agents/internal/models"
+)
+
+// streamEventBufferSize bounds how many PlanEvents a StreamingPlanner
+// implementation's output channel holds before the producing goroutine
+// blocks on a slow consumer - generous enough that a coordinator draining
+// it in the same select loop it dispatches tasks from never stalls the
+// HTTP read underneath.
+const streamEventBufferSize = 32
+
+// StreamingPlanner is implemented by a Planner whose provider supports
+// incrementally streaming an orchestration plan back as it's generated,
+// instead of only returning once the whole response has arrived (see
+// Planner, OpenRouterPlanner.StreamOrchestrationPlan).
+// coordinator.Coordinator probes for this optional capability the same
+// way cmd/datetime-mcp/main.go probes a TimezoneResolver for
+// datetime.CityLister, and falls back to the blocking Planner interface
+// for a provider that doesn't implement it.
+type StreamingPlanner interface {
+	// StreamOrchestrationPlan is GenerateOrchestrationPlanWithHistory, but
+	// returns a channel of PlanEvents as the plan is generated: a
+	// PlanEventTaskAdded as soon as each task's JSON object closes in the
+	// streamed tool call, optional PlanEventReasoning deltas as the
+	// model's free-text reasoning streams in, and a terminal
+	// PlanEventDone carrying the complete plan (with Error set instead if
+	// generation failed). The channel is always closed after
+	// PlanEventDone.
+	StreamOrchestrationPlan(ctx context.Context, query models.Query, history []models.ConversationTurn, descriptors []models.AgentDescriptor) (<-chan models.PlanEvent, error)
+}
+
+// streamDelta is one fragment read off a streamed chat-completions
+// response body: either free-text content, a tool call argument
+// fragment, or (if non-nil) a terminal read/parse error - never more than
+// one of the three at once.
+type streamDelta struct {
+	content  string
+	toolArgs string
+	err      error
+}
+
+// chatCompletionStreamChunk is one SSE "data:" line's JSON payload from
+// an OpenAI-compatible streaming chat-completions response - the
+// incremental counterpart of chatCompletionResponse's single Message.
+type chatCompletionStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Function struct {
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+// streamChatCompletions is callChatCompletions's streaming counterpart:
+// it POSTs the same OpenAI-compatible request shape with "stream": true
+// and returns a channel of streamDeltas read off the server-sent-events
+// response body as they arrive, instead of waiting for and unmarshalling
+// one complete JSON response body. The returned channel is always closed,
+// its last value carrying a non-nil err if the stream ended abnormally.
+func streamChatCompletions(ctx context.Context, httpClient *http.Client, baseURL, apiKey, model, prompt string, opts chatCompletionOptions) (<-chan streamDelta, error) {
+	request := chatCompletionRequest{
+		Model: model,
+		Messages: []chatCompletionMessage{
+			{Role: "user", Content: prompt},
+		},
+		Tools:      opts.Tools,
+		ToolChoice: opts.ToolChoice,
+		Stream:     true,
+	}
+
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/chat/completions", strings.NewReader(string(requestBody)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	for k, v := range opts.ExtraHeaders {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, &httpStatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			Body:       string(body),
+		}
+	}
+
+	deltas := make(chan streamDelta, 16)
+	go func() {
+		defer resp.Body.Close()
+		defer close(deltas)
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			payload := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+			if payload == "[DONE]" {
+				return
+			}
+
+			var chunk chatCompletionStreamChunk
+			if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+				deltas <- streamDelta{err: fmt.Errorf("failed to parse stream chunk: %w", err)}
+				return
+			}
+			if len(chunk.Choices) == 0 {
+				continue
+			}
+			delta := chunk.Choices[0].Delta
+			if delta.Content != "" {
+				deltas <- streamDelta{content: delta.Content}
+			}
+			for _, tc := range delta.ToolCalls {
+				if tc.Function.Arguments != "" {
+					deltas <- streamDelta{toolArgs: tc.Function.Arguments}
+				}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			deltas <- streamDelta{err: fmt.Errorf("stream read failed: %w", err)}
+		}
+	}()
+
+	return deltas, nil
+}
+
+// streamTaskParser incrementally extracts complete task objects out of a
+// planToolName tool call's arguments as they accumulate, fragment by
+// fragment, so a caller can emit a PlanEventTaskAdded for each one without
+// waiting for the whole arguments string (and therefore the whole plan)
+// to finish streaming.
+type streamTaskParser struct {
+	buffer     strings.Builder
+	tasksStart int // index into buffer.String() right after "tasks"'s '[', or -1 until found
+	emitted    int // number of task objects already extracted
+}
+
+func newStreamTaskParser() *streamTaskParser {
+	return &streamTaskParser{tasksStart: -1}
+}
+
+// feed appends argsFragment to the accumulated arguments text and returns
+// every task object (as raw JSON text) that has newly completed since the
+// last call.
+func (p *streamTaskParser) feed(argsFragment string) []string {
+	p.buffer.WriteString(argsFragment)
+	full := p.buffer.String()
+
+	if p.tasksStart < 0 {
+		key := strings.Index(full, `"tasks"`)
+		if key < 0 {
+			return nil
+		}
+		bracket := strings.IndexByte(full[key:], '[')
+		if bracket < 0 {
+			return nil
+		}
+		p.tasksStart = key + bracket + 1
+	}
+
+	objects := scanJSONObjects(full[p.tasksStart:])
+	if len(objects) <= p.emitted {
+		return nil
+	}
+	fresh := objects[p.emitted:]
+	p.emitted = len(objects)
+	return fresh
+}
+
+// scanJSONObjects returns every complete top-level {...} object found in
+// s, in the order they close, ignoring braces inside quoted strings. A
+// trailing object that hasn't closed yet (still streaming in) is left out
+// until a later call sees its closing brace.
+func scanJSONObjects(s string) []string {
+	var (
+		objects []string
+		depth   int
+		start   int
+		inStr   bool
+		escaped bool
+	)
+
+	for i, r := range s {
+		if inStr {
+			switch {
+			case escaped:
+				escaped = false
+			case r == '\\':
+				escaped = true
+			case r == '"':
+				inStr = false
+			}
+			continue
+		}
+
+		switch r {
+		case '"':
+			inStr = true
+		case '{':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				objects = append(objects, s[start:i+1])
+			}
+		}
+	}
+
+	return objects
+}
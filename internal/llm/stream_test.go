@@ -0,0 +1,110 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steve/llm-agents/internal/models"
+)
+
+// TestStreamTaskParserFeedEmitsOnlyCompleteObjects verifies a
+// streamTaskParser only reports a task object once its closing brace has
+// arrived, even when fragments split mid-object, and never re-reports one
+// already returned.
+func TestStreamTaskParserFeedEmitsOnlyCompleteObjects(t *testing.T) {
+	parser := newStreamTaskParser()
+
+	fragments := []string{
+		`{"strategy":"parallel","task`,
+		`s":[{"task_id":"t1","agent_type"`,
+		`:"temperature","parameters":{"city":"Denver"}},`,
+		`{"task_id":"t2","agent`,
+		`_type":"echo","parameters":{"text":"hi, {there}"}}`,
+		`],"reasoning":"..."}`,
+	}
+
+	var all []string
+	for _, fragment := range fragments {
+		all = append(all, parser.feed(fragment)...)
+	}
+
+	if len(all) != 2 {
+		t.Fatalf("got %d complete task objects, want 2: %v", len(all), all)
+	}
+	if all[0] != `{"task_id":"t1","agent_type":"temperature","parameters":{"city":"Denver"}}` {
+		t.Errorf("first task object = %q", all[0])
+	}
+	if all[1] != `{"task_id":"t2","agent_type":"echo","parameters":{"text":"hi, {there}"}}` {
+		t.Errorf("second task object = %q", all[1])
+	}
+}
+
+// TestStreamTaskParserFeedWaitsForTasksKey verifies feed reports nothing
+// until the "tasks" array itself has started arriving.
+func TestStreamTaskParserFeedWaitsForTasksKey(t *testing.T) {
+	parser := newStreamTaskParser()
+	if got := parser.feed(`{"strategy":"parallel",`); len(got) != 0 {
+		t.Errorf("feed before \"tasks\" key = %v, want none", got)
+	}
+	if got := parser.feed(`"tasks":[{"task_id":"t1","agent_type":"echo"}]`); len(got) != 1 {
+		t.Errorf("feed after \"tasks\" key = %v, want 1 object", got)
+	}
+}
+
+// TestOpenRouterPlannerStreamOrchestrationPlanDispatchesParallelTasksEarly
+// verifies StreamOrchestrationPlan against a fake SSE server: every task
+// in a "parallel" plan arrives as a PlanEventTaskAdded before the
+// terminal PlanEventDone, and PlanEventDone carries the same tasks in the
+// complete plan.
+func TestOpenRouterPlannerStreamOrchestrationPlanDispatchesParallelTasksEarly(t *testing.T) {
+	const sseBody = `data: {"choices":[{"delta":{"tool_calls":[{"function":{"arguments":"{\"strategy\":\"parallel\",\"tasks\":[{\"task_id\":\"t1\",\"agent_type\":\"echo\",\"parameters\":{\"text\":\"hi\"}}"}}]}}]}
+
+data: {"choices":[{"delta":{"tool_calls":[{"function":{"arguments":",{\"task_id\":\"t2\",\"agent_type\":\"echo\",\"parameters\":{\"text\":\"bye\"}}],\"reasoning\":\"ok\"}"}}]}}]}
+
+data: [DONE]
+
+`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, sseBody)
+	}))
+	defer server.Close()
+
+	planner := NewOpenRouterPlanner(Config{APIKey: "test-key", BaseURL: server.URL})
+
+	events, err := planner.StreamOrchestrationPlan(context.Background(), models.Query{ID: "q1", Text: "echo hi then bye"}, nil, nil)
+	if err != nil {
+		t.Fatalf("StreamOrchestrationPlan() error = %v, want nil", err)
+	}
+
+	var (
+		taskAddedIDs []string
+		done         *models.PlanEvent
+	)
+	for event := range events {
+		switch event.Type {
+		case models.PlanEventTaskAdded:
+			taskAddedIDs = append(taskAddedIDs, event.Task.TaskID)
+		case models.PlanEventDone:
+			e := event
+			done = &e
+		}
+	}
+
+	if len(taskAddedIDs) != 2 || taskAddedIDs[0] != "t1" || taskAddedIDs[1] != "t2" {
+		t.Errorf("task_added task ids = %v, want [t1 t2]", taskAddedIDs)
+	}
+	if done == nil {
+		t.Fatal("never saw a PlanEventDone")
+	}
+	if done.Error != "" {
+		t.Fatalf("PlanEventDone.Error = %q, want none", done.Error)
+	}
+	if done.Plan == nil || len(done.Plan.Tasks) != 2 {
+		t.Fatalf("PlanEventDone.Plan = %+v, want 2 tasks", done.Plan)
+	}
+}
@@ -0,0 +1,163 @@
+package llm
+
+// chatCompletionTool is one entry in chatCompletionRequest.Tools, the
+// OpenAI-compatible "function tool" shape.
+type chatCompletionTool struct {
+	Type     string                 `json:"type"`
+	Function chatCompletionFunction `json:"function"`
+}
+
+// chatCompletionFunction describes one callable tool: Name/Description
+// for the model to choose from, Parameters as a JSON Schema object.
+type chatCompletionFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"`
+}
+
+// chatCompletionToolCall is one entry in a reply message's tool_calls:
+// the model invoking a tool by name with JSON-encoded arguments.
+type chatCompletionToolCall struct {
+	ID       string                     `json:"id"`
+	Type     string                     `json:"type"`
+	Function chatCompletionToolCallFunc `json:"function"`
+}
+
+// chatCompletionToolCallFunc is the function half of a
+// chatCompletionToolCall.
+type chatCompletionToolCallFunc struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// findToolCallArgs returns the Arguments of the first tool call named
+// name in toolCalls, or "", false if none match.
+func findToolCallArgs(toolCalls []chatCompletionToolCall, name string) (string, bool) {
+	for _, tc := range toolCalls {
+		if tc.Function.Name == name {
+			return tc.Function.Arguments, true
+		}
+	}
+	return "", false
+}
+
+// planToolName is the function name the orchestration-plan tool is
+// registered under (see orchestrationTools, planToolChoice). Its
+// arguments are the same {strategy, tasks[], reasoning} shape
+// parseOrchestrationResponse expects out of a free-text reply, so both
+// paths feed the same conversion logic - see orchestrationPlanFromData.
+const planToolName = "generate_orchestration_plan"
+
+// orchestrationTools returns the tool declarations sent alongside the
+// orchestration prompt: one invoke_<agent_type> tool per sub-agent type
+// the planner is allowed to choose, documenting each agent's own
+// parameter shape, plus the planTool itself that the model is forced to
+// call (via planToolChoice) with the overall plan.
+func orchestrationTools() []chatCompletionTool {
+	return []chatCompletionTool{
+		{Type: "function", Function: chatCompletionFunction{
+			Name:        "invoke_temperature",
+			Description: "Get the current temperature/weather for a city.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"city": map[string]any{"type": "string"},
+				},
+				"required": []string{"city"},
+			},
+		}},
+		{Type: "function", Function: chatCompletionFunction{
+			Name:        "invoke_datetime",
+			Description: "Get the current date and time for a city or an IANA timezone.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"city":     map[string]any{"type": "string"},
+					"timezone": map[string]any{"type": "string"},
+				},
+			},
+		}},
+		{Type: "function", Function: chatCompletionFunction{
+			Name:        "invoke_echo",
+			Description: "Echo a piece of text back to the user verbatim.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"text": map[string]any{"type": "string"},
+				},
+				"required": []string{"text"},
+			},
+		}},
+		{Type: "function", Function: chatCompletionFunction{
+			Name:        "invoke_forecast",
+			Description: "Get a multi-day weather forecast for a city.",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"city": map[string]any{"type": "string"},
+					"days": map[string]any{"type": "string"},
+				},
+				"required": []string{"city"},
+			},
+		}},
+		{Type: "function", Function: chatCompletionFunction{
+			Name:        planToolName,
+			Description: "Submit the final orchestration plan: which agents to invoke, in what order, and why.",
+			Parameters:  planToolParameters(),
+		}},
+	}
+}
+
+// planToolChoice forces the model to respond by calling planToolName,
+// rather than leaving the choice of tool (or no tool at all) up to it -
+// every orchestration request wants exactly one plan back.
+func planToolChoice() any {
+	return map[string]any{
+		"type":     "function",
+		"function": map[string]any{"name": planToolName},
+	}
+}
+
+// planToolParameters is the JSON Schema for planToolName's arguments:
+// the same shape the brace-matching fallback parses out of a free-text
+// reply (see orchestrationPlanFromData), so either path produces a plan
+// through identical conversion logic.
+func planToolParameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"strategy": map[string]any{
+				"type": "string",
+				"enum": []string{"parallel", "sequential", "dag"},
+			},
+			"tasks": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"task_id":      map[string]any{"type": "string"},
+						"agent_type":   map[string]any{"type": "string", "enum": []string{"temperature", "datetime", "echo", "forecast"}},
+						"priority":     map[string]any{"type": "integer"},
+						"dependencies": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+						"input_bindings": map[string]any{
+							"type":                 "object",
+							"additionalProperties": map[string]any{"type": "string"},
+						},
+						"parameters": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"city":     map[string]any{"type": "string"},
+								"timezone": map[string]any{"type": "string"},
+								"text":     map[string]any{"type": "string"},
+								"days":     map[string]any{"type": "string"},
+							},
+						},
+					},
+					"required": []string{"agent_type"},
+				},
+			},
+			"reasoning": map[string]any{"type": "string"},
+		},
+		"required": []string{"strategy", "tasks", "reasoning"},
+	}
+}
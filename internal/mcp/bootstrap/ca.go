@@ -0,0 +1,98 @@
+// Package bootstrap implements a one-time-token enrollment flow that lets a
+// fresh client or server obtain its TLS identity from a small CA service
+// instead of requiring operators to pre-provision every cert under
+// TLSConfig.CertDir.
+package bootstrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	mcptls "github.com/steve/llm-agents/internal/tls"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// SignRequest is the JSON body POSTed to the CA's /sign endpoint: a
+// one-time bootstrap token and a PKCS#10 CSR proving the requester holds
+// the corresponding private key.
+type SignRequest struct {
+	Token string `json:"token"`
+	CSR   string `json:"csr"`
+}
+
+// SignResponse is the CA's reply to a successful /sign request: the issued
+// leaf certificate and the CA bundle the requester should trust, both
+// PEM-encoded.
+type SignResponse struct {
+	Certificate string `json:"certificate"`
+	CABundle    string `json:"ca_bundle"`
+}
+
+// TokenValidator reports whether token is a currently valid, unused
+// bootstrap token. Implementations are responsible for single-use
+// enforcement (e.g. deleting the token from a store once it's consumed).
+type TokenValidator func(token string) bool
+
+// CA serves the /sign endpoint a BootstrapClient or BootstrapServer
+// enrolls against: it checks the caller's one-time token, then issues a
+// certificate from their CSR under a fixed signing profile.
+type CA struct {
+	certManager *mcptls.CertificateManager
+	profile     string
+	caBundle    []byte
+	validate    TokenValidator
+}
+
+// NewCA creates a CA that signs enrollment CSRs under profile using
+// certManager, replying with caBundle as the trust anchor enrollees should
+// install. validate decides whether a presented token is accepted.
+func NewCA(certManager *mcptls.CertificateManager, profile string, caBundle []byte, validate TokenValidator) *CA {
+	return &CA{
+		certManager: certManager,
+		profile:     profile,
+		caBundle:    caBundle,
+		validate:    validate,
+	}
+}
+
+// Handler returns an http.Handler exposing the CA's /sign endpoint.
+func (ca *CA) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sign", ca.handleSign)
+	return mux
+}
+
+func (ca *CA) handleSign(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if req.Token == "" || !ca.validate(req.Token) {
+		utils.Warn("Bootstrap CA rejected enrollment: invalid or already-used token")
+		http.Error(w, "invalid or expired bootstrap token", http.StatusForbidden)
+		return
+	}
+
+	certPEM, err := ca.certManager.SignCSR([]byte(req.CSR), ca.profile)
+	if err != nil {
+		utils.Error("Bootstrap CA failed to sign enrollment CSR: %v", err)
+		http.Error(w, fmt.Sprintf("failed to sign CSR: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(SignResponse{
+		Certificate: string(certPEM),
+		CABundle:    string(ca.caBundle),
+	}); err != nil {
+		utils.Error("Bootstrap CA failed to encode sign response: %v", err)
+	}
+}
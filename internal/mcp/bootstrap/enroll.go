@@ -0,0 +1,254 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steve/llm-agents/internal/config"
+	mcptls "github.com/steve/llm-agents/internal/tls"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// renewalFraction re-enrolls after this fraction of the issued leaf's
+// lifetime has elapsed, mirroring smallstep's default renewal window.
+const renewalFraction = 2.0 / 3.0
+
+// Enrollment is a live TLS identity obtained from a CA's /sign endpoint
+// using a one-time bootstrap token. A background goroutine re-enrolls
+// before the leaf expires and hot-swaps the *tls.Config in place, so
+// TLSConfig() always returns a config usable for a new connection.
+type Enrollment struct {
+	caURL     string
+	token     string
+	name      string
+	forClient bool
+	base      *config.TLSConfig
+
+	mu        sync.RWMutex
+	tlsConfig *tls.Config
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// BootstrapClient enrolls for a client identity against the CA's /sign
+// endpoint at caURL, presenting token and name (used as the CSR's common
+// name). The returned Enrollment's TLSConfig() is suitable for
+// NewTLSClientFromTLSConfig and is kept current by a renewal goroutine, so
+// the caller never has to pre-provision a ClientCert/ClientKey under
+// TLSConfig.CertDir.
+func BootstrapClient(ctx context.Context, token, caURL, name string) (*Enrollment, error) {
+	e := &Enrollment{
+		caURL:     caURL,
+		token:     token,
+		name:      name,
+		forClient: true,
+		stopCh:    make(chan struct{}),
+	}
+	if err := e.enroll(ctx); err != nil {
+		return nil, fmt.Errorf("failed to bootstrap client identity for %q: %w", name, err)
+	}
+	go e.renewLoop(ctx)
+	return e, nil
+}
+
+// BootstrapServer enrolls for a server identity the same way, but builds a
+// *tls.Config that requires and verifies client certificates (mTLS), since
+// a bootstrapped server still needs to authenticate its callers. base
+// supplies MinTLSVersion (and is otherwise unused); pass nil to accept the
+// package default of TLS 1.2.
+func BootstrapServer(ctx context.Context, token, caURL, name string, base *config.TLSConfig) (*Enrollment, error) {
+	e := &Enrollment{
+		caURL:     caURL,
+		token:     token,
+		name:      name,
+		forClient: false,
+		base:      base,
+		stopCh:    make(chan struct{}),
+	}
+	if err := e.enroll(ctx); err != nil {
+		return nil, fmt.Errorf("failed to bootstrap server identity for %q: %w", name, err)
+	}
+	go e.renewLoop(ctx)
+	return e, nil
+}
+
+// TLSConfig returns the Enrollment's current *tls.Config. Safe to call
+// repeatedly; the renewal goroutine swaps the pointer under a lock rather
+// than mutating a config already handed to a live listener or transport.
+func (e *Enrollment) TLSConfig() *tls.Config {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.tlsConfig
+}
+
+// Close stops the renewal goroutine. It does not revoke the current
+// certificate; the caller's connections keep using whatever tls.Config
+// they already hold.
+func (e *Enrollment) Close() {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+	})
+}
+
+// enroll generates a fresh keypair and CSR, exchanges it for a signed leaf
+// certificate and CA bundle at the CA's /sign endpoint, and builds the
+// resulting *tls.Config, swapping it into e.tlsConfig on success, and logs
+// the issued certificate's lifetime. renewLoop reads that lifetime back
+// from the installed certificate (see currentLifetime) to schedule the next
+// enrollment.
+func (e *Enrollment) enroll(ctx context.Context) error {
+	_, lifetime, err := e.enrollOnce(ctx)
+	if err != nil {
+		return err
+	}
+	utils.Info("Bootstrap enrollment for %q succeeded, leaf valid %s", e.name, lifetime)
+	return nil
+}
+
+func (e *Enrollment) enrollOnce(ctx context.Context) (*x509.Certificate, time.Duration, error) {
+	csrPEM, keyPEM, err := mcptls.NewCertificateManager(nil).GenerateCSR(e.name, mcptls.SANs{DNSNames: []string{e.name}})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to generate enrollment CSR: %w", err)
+	}
+
+	signResp, err := e.requestSignature(ctx, csrPEM)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	leafKeyPair, err := tls.X509KeyPair([]byte(signResp.Certificate), keyPEM)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to parse issued certificate: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(leafKeyPair.Certificate[0])
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to parse issued certificate: %w", err)
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM([]byte(signResp.CABundle)) {
+		return nil, 0, fmt.Errorf("failed to parse CA bundle returned by CA")
+	}
+
+	minVersion := uint16(tls.VersionTLS12)
+	if e.base != nil && e.base.MinTLSVersion != 0 {
+		minVersion = e.base.MinTLSVersion
+	}
+
+	var tlsConfig *tls.Config
+	if e.forClient {
+		tlsConfig = &tls.Config{
+			Certificates: []tls.Certificate{leafKeyPair},
+			RootCAs:      caPool,
+			ServerName:   e.name,
+			MinVersion:   minVersion,
+		}
+	} else {
+		tlsConfig = &tls.Config{
+			Certificates: []tls.Certificate{leafKeyPair},
+			ClientCAs:    caPool,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			MinVersion:   minVersion,
+		}
+	}
+
+	e.mu.Lock()
+	e.tlsConfig = tlsConfig
+	e.mu.Unlock()
+
+	return leaf, leaf.NotAfter.Sub(leaf.NotBefore), nil
+}
+
+// requestSignature POSTs a SignRequest to the CA's /sign endpoint and
+// decodes the SignResponse.
+func (e *Enrollment) requestSignature(ctx context.Context, csrPEM []byte) (*SignResponse, error) {
+	body, err := json.Marshal(SignRequest{Token: e.token, CSR: string(csrPEM)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal sign request: %w", err)
+	}
+
+	url := strings.TrimRight(e.caURL, "/") + "/sign"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sign request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach CA at %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CA at %s rejected enrollment (%s): %s", url, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	var signResp SignResponse
+	if err := json.Unmarshal(respBody, &signResp); err != nil {
+		return nil, fmt.Errorf("failed to decode CA response: %w", err)
+	}
+	return &signResp, nil
+}
+
+// renewLoop re-enrolls after renewalFraction of the current leaf's
+// lifetime has elapsed, swapping a new *tls.Config into e.tlsConfig so the
+// next TLSConfig() call returns the renewed identity; a config a caller
+// already obtained from TLSConfig() keeps the old certificate. It stops
+// when ctx is done or Close is called.
+func (e *Enrollment) renewLoop(ctx context.Context) {
+	for {
+		_, lifetime, err := e.currentLifetime()
+		if err != nil {
+			utils.Error("Bootstrap enrollment for %q: %v", e.name, err)
+			return
+		}
+
+		select {
+		case <-time.After(time.Duration(float64(lifetime) * renewalFraction)):
+		case <-ctx.Done():
+			return
+		case <-e.stopCh:
+			return
+		}
+
+		if _, _, err := e.enrollOnce(ctx); err != nil {
+			utils.Error("Bootstrap renewal for %q failed, retrying with the existing certificate: %v", e.name, err)
+			continue
+		}
+		utils.Info("Bootstrap renewal for %q succeeded", e.name)
+	}
+}
+
+// currentLifetime reports the NotBefore/NotAfter span of the leaf
+// certificate currently installed in e.tlsConfig.
+func (e *Enrollment) currentLifetime() (*x509.Certificate, time.Duration, error) {
+	e.mu.RLock()
+	tlsConfig := e.tlsConfig
+	e.mu.RUnlock()
+
+	if tlsConfig == nil || len(tlsConfig.Certificates) == 0 {
+		return nil, 0, fmt.Errorf("no certificate currently enrolled")
+	}
+	leaf, err := x509.ParseCertificate(tlsConfig.Certificates[0].Certificate[0])
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to parse current leaf certificate: %w", err)
+	}
+	return leaf, leaf.NotAfter.Sub(leaf.NotBefore), nil
+}
@@ -3,33 +3,65 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/httpx"
 	"github.com/steve/llm-agents/internal/models"
 	mcptls "github.com/steve/llm-agents/internal/tls"
 	"github.com/steve/llm-agents/internal/utils"
 )
 
+// defaultEchoStreamChunkSize is used when CallEchoStream is given a
+// non-positive chunkSize.
+const defaultEchoStreamChunkSize = 200
+
 // Client represents an MCP client using the official SDK
 type Client struct {
-	endpoint      string
-	mcpClient     *mcp.Client
-	session       *mcp.ClientSession
-	tlsConfig     *config.TLSConfig
-	useTLS        bool
-	mu            sync.RWMutex
-	connected     bool
-	reconnectOnce sync.Once
+	endpoint   string
+	mcpClient  *mcp.Client
+	session    *mcp.ClientSession
+	tlsConfig  *config.TLSConfig
+	useTLS     bool
+	httpClient *http.Client
+	mu         sync.RWMutex
+	connected  bool
+
+	reconnectPolicy ReconnectPolicy
+	reconnectMu     sync.Mutex
+
+	breakerMu        sync.Mutex
+	state            ClientState
+	consecutiveFails int
+	totalFailures    int64
+	windowStart      time.Time
+	circuitOpenedAt  time.Time
+
+	progressSeq   atomic.Uint64
+	progressMu    sync.Mutex
+	progressChunk map[string]func(string) error
+
+	connMu  sync.RWMutex
+	tlsConn *tls.Conn
 }
 
-// NewClient creates a new MCP client without TLS
+// NewClient creates a new MCP client without TLS, using DefaultReconnectPolicy.
 func NewClient(endpoint string, timeout time.Duration) (*Client, error) {
+	return NewClientWithReconnectPolicy(endpoint, timeout, DefaultReconnectPolicy())
+}
+
+// NewClientWithReconnectPolicy creates a new MCP client without TLS,
+// reconnecting and circuit-breaking on transport failures according to policy.
+func NewClientWithReconnectPolicy(endpoint string, timeout time.Duration, policy ReconnectPolicy) (*Client, error) {
 	httpClient := &http.Client{
 		Timeout: timeout,
 		Transport: &http.Transport{
@@ -40,26 +72,24 @@ func NewClient(endpoint string, timeout time.Duration) (*Client, error) {
 	}
 
 	c := &Client{
-		endpoint: endpoint,
-		mcpClient: mcp.NewClient(&mcp.Implementation{
-			Name:    "llm-agents-client",
-			Version: "v1.0.0",
-		}, nil),
-		useTLS: false,
-	}
-
-	// Create transport
-	transport := &mcp.StreamableClientTransport{
-		Endpoint:   endpoint,
-		HTTPClient: httpClient,
-		MaxRetries: 5,
-	}
+		endpoint:        endpoint,
+		useTLS:          false,
+		httpClient:      httpClient,
+		reconnectPolicy: policy,
+		progressChunk:   make(map[string]func(string) error),
+	}
+	c.mcpClient = mcp.NewClient(&mcp.Implementation{
+		Name:    "llm-agents-client",
+		Version: "v1.0.0",
+	}, &mcp.ClientOptions{
+		ProgressNotificationHandler: c.handleProgressNotification,
+	})
 
 	// Connect to the server
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	session, err := c.mcpClient.Connect(ctx, transport, nil)
+	session, err := c.mcpClient.Connect(ctx, c.buildTransport(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to MCP server: %w", err)
 	}
@@ -71,65 +101,122 @@ func NewClient(endpoint string, timeout time.Duration) (*Client, error) {
 	return c, nil
 }
 
-// NewTLSClient creates a new MCP client with TLS support
+// NewTLSClient creates a new MCP client with TLS support, using
+// DefaultReconnectPolicy.
 func NewTLSClient(endpoint string, timeout time.Duration, tlsConfig *config.TLSConfig) (*Client, error) {
+	return NewTLSClientWithReconnectPolicy(endpoint, timeout, tlsConfig, DefaultReconnectPolicy())
+}
+
+// NewTLSClientWithReconnectPolicy creates a new MCP client with TLS support,
+// reconnecting and circuit-breaking on transport failures according to policy.
+func NewTLSClientWithReconnectPolicy(endpoint string, timeout time.Duration, tlsConfig *config.TLSConfig, policy ReconnectPolicy) (*Client, error) {
+	return newTLSClient(endpoint, timeout, tlsConfig, "", policy)
+}
+
+// NewTLSClientWithPeerPin is NewTLSClient, but additionally pins the
+// server's presented SPIFFE ID to expectedPeerID - rejecting any other
+// identity even one that chains to a trusted CA - via
+// tls.NewSingleIDAuthorizer, instead of trusting every certificate this
+// deployment's CA happens to have signed. Used by sub-agent MCP clients
+// that know exactly which server they're supposed to be talking to (see
+// each agent package's NewTLSAgent).
+func NewTLSClientWithPeerPin(endpoint string, timeout time.Duration, tlsConfig *config.TLSConfig, expectedPeerID string) (*Client, error) {
+	return newTLSClient(endpoint, timeout, tlsConfig, expectedPeerID, DefaultReconnectPolicy())
+}
+
+// newTLSClient is the shared body behind NewTLSClientWithReconnectPolicy and
+// NewTLSClientWithPeerPin: it loads tlsConfig's client TLS config - pinned to
+// expectedPeerID if non-empty - and connects. Falls back to a plaintext
+// client (logging the reason) if tlsConfig is nil or invalid, matching this
+// package's existing fail-open-with-a-log convention.
+func newTLSClient(endpoint string, timeout time.Duration, tlsConfig *config.TLSConfig, expectedPeerID string, policy ReconnectPolicy) (*Client, error) {
 	if tlsConfig == nil {
 		utils.Error("TLS configuration is required for TLS client")
-		return NewClient(endpoint, timeout)
+		return NewClientWithReconnectPolicy(endpoint, timeout, policy)
 	}
 
 	// Validate TLS configuration
 	if err := tlsConfig.Validate(); err != nil {
 		utils.Error("Invalid TLS configuration: %v", err)
-		return NewClient(endpoint, timeout)
+		return NewClientWithReconnectPolicy(endpoint, timeout, policy)
 	}
 
 	// Create TLS loader
 	tlsLoader := mcptls.NewTLSLoader(tlsConfig)
+	if expectedPeerID != "" {
+		tlsLoader = tlsLoader.WithPeerAuthorizer(mcptls.NewSingleIDAuthorizer(expectedPeerID))
+	}
 
-	// Extract server name from endpoint for TLS validation
-	serverName := "localhost" // Default for demo mode
+	// Derive the SNI ServerName from the endpoint's host so a client dialing
+	// e.g. https://weather.mcp.local:8443/mcp presents that name and lands
+	// on the matching vhost certificate (see TLSConfig.AdditionalCerts).
+	serverName := serverNameFromEndpoint(endpoint)
 
 	// Load client TLS configuration
 	clientTLSConfig, err := tlsLoader.LoadClientTLSConfig(serverName)
 	if err != nil {
 		utils.Error("Failed to load client TLS config: %v", err)
-		return NewClient(endpoint, timeout)
+		return NewClientWithReconnectPolicy(endpoint, timeout, policy)
 	}
 
-	// Create HTTP client with TLS transport
-	httpClient := &http.Client{
-		Timeout: timeout,
-		Transport: &http.Transport{
-			TLSClientConfig:     clientTLSConfig,
-			MaxIdleConns:        10,
-			MaxIdleConnsPerHost: 5,
-			IdleConnTimeout:     30 * time.Second,
-		},
+	c := &Client{
+		endpoint:        endpoint,
+		tlsConfig:       tlsConfig,
+		useTLS:          true,
+		reconnectPolicy: policy,
+		progressChunk:   make(map[string]func(string) error),
+	}
+	c.httpClient = c.buildTLSHTTPClient(timeout, clientTLSConfig)
+	c.mcpClient = mcp.NewClient(&mcp.Implementation{
+		Name:    "llm-agents-client",
+		Version: "v1.0.0",
+	}, &mcp.ClientOptions{
+		ProgressNotificationHandler: c.handleProgressNotification,
+	})
+
+	// Connect to the server
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	session, err := c.mcpClient.Connect(ctx, c.buildTransport(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MCP server: %w", err)
 	}
 
-	c := &Client{
-		endpoint:  endpoint,
-		tlsConfig: tlsConfig,
-		mcpClient: mcp.NewClient(&mcp.Implementation{
-			Name:    "llm-agents-client",
-			Version: "v1.0.0",
-		}, nil),
-		useTLS: true,
-	}
-
-	// Create transport with TLS-enabled HTTP client
-	transport := &mcp.StreamableClientTransport{
-		Endpoint:   endpoint,
-		HTTPClient: httpClient,
-		MaxRetries: 5,
+	c.session = session
+	c.connected = true
+
+	if expectedPeerID != "" {
+		utils.Info("MCP client connected to %s with mTLS enabled, pinned to peer %s", endpoint, expectedPeerID)
+	} else {
+		utils.Info("MCP client connected to %s with mTLS enabled", endpoint)
 	}
+	return c, nil
+}
+
+// NewTLSClientFromTLSConfig creates a new MCP client using an already
+// fully-formed *tls.Config rather than loading one from a TLSConfig's
+// CertDir paths — the entry point for identities obtained out-of-band, e.g.
+// via internal/mcp/bootstrap's CA enrollment flow.
+func NewTLSClientFromTLSConfig(endpoint string, timeout time.Duration, tlsConfig *tls.Config, policy ReconnectPolicy) (*Client, error) {
+	c := &Client{
+		endpoint:        endpoint,
+		useTLS:          true,
+		reconnectPolicy: policy,
+		progressChunk:   make(map[string]func(string) error),
+	}
+	c.httpClient = c.buildTLSHTTPClient(timeout, tlsConfig)
+	c.mcpClient = mcp.NewClient(&mcp.Implementation{
+		Name:    "llm-agents-client",
+		Version: "v1.0.0",
+	}, &mcp.ClientOptions{
+		ProgressNotificationHandler: c.handleProgressNotification,
+	})
 
-	// Connect to the server
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	session, err := c.mcpClient.Connect(ctx, transport, nil)
+	session, err := c.mcpClient.Connect(ctx, c.buildTransport(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to MCP server: %w", err)
 	}
@@ -137,40 +224,144 @@ func NewTLSClient(endpoint string, timeout time.Duration, tlsConfig *config.TLSC
 	c.session = session
 	c.connected = true
 
-	utils.Info("MCP client connected to %s with mTLS enabled", endpoint)
+	utils.Info("MCP client connected to %s with a bootstrapped TLS identity", endpoint)
 	return c, nil
 }
 
-// ensureConnection ensures the client is connected
+// identityRoundTripper wraps an http.RoundTripper, setting
+// httpx.CallerIdentityHeader on every outgoing request to whatever SPIFFE ID
+// the request's context carries via httpx.WithCallerIdentity, so the
+// server's ClientCertIdentity middleware can cross-check it against the
+// caller's mTLS peer certificate. A request whose context carries no
+// identity passes through unchanged.
+type identityRoundTripper struct {
+	base http.RoundTripper
+}
+
+func (rt *identityRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	identity := httpx.CallerIdentityFromContext(req.Context())
+	if identity == "" {
+		return rt.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(httpx.CallerIdentityHeader, identity)
+	return rt.base.RoundTrip(req)
+}
+
+// buildTLSHTTPClient builds the *http.Client used by a TLS-enabled Client.
+// DialTLSContext performs the handshake itself, rather than leaving it to
+// the transport, so it can retain the resulting *tls.Conn on c for
+// GetConnectionInfo to read real handshake state from afterward.
+func (c *Client) buildTLSHTTPClient(timeout time.Duration, tlsConfig *tls.Config) *http.Client {
+	transport := &http.Transport{
+		TLSClientConfig:     tlsConfig,
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 5,
+		IdleConnTimeout:     30 * time.Second,
+		DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			rawConn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			tlsConn := tls.Client(rawConn, tlsConfig)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				rawConn.Close()
+				return nil, err
+			}
+			c.connMu.Lock()
+			c.tlsConn = tlsConn
+			c.connMu.Unlock()
+			return tlsConn, nil
+		},
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &identityRoundTripper{base: transport},
+	}
+}
+
+// buildTransport constructs a fresh StreamableClientTransport for this
+// client's endpoint, reusing its (TLS-configured, if applicable) HTTP
+// client. Used both for the initial connect and for every reconnect.
+func (c *Client) buildTransport() *mcp.StreamableClientTransport {
+	return &mcp.StreamableClientTransport{
+		Endpoint:   c.endpoint,
+		HTTPClient: c.httpClient,
+		MaxRetries: 5,
+	}
+}
+
+// serverNameFromEndpoint extracts the hostname component of endpoint for use
+// as a TLS ServerName (SNI), falling back to "localhost" if endpoint can't be
+// parsed as a URL or has no host (e.g. demo/test endpoints).
+func serverNameFromEndpoint(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return "localhost"
+	}
+	return u.Hostname()
+}
+
+// ensureConnection ensures the client is connected, short-circuiting with
+// ErrCircuitOpen if the breaker is open and otherwise reconnecting (with
+// backoff) when the session has been torn down by a prior transport failure.
 func (c *Client) ensureConnection(ctx context.Context) error {
+	if err := c.checkCircuit(); err != nil {
+		return err
+	}
+
 	c.mu.RLock()
 	connected := c.connected
 	c.mu.RUnlock()
-
-	if !connected {
-		return fmt.Errorf("client not connected")
+	if connected {
+		return nil
 	}
 
+	if err := c.reconnect(ctx); err != nil {
+		c.recordFailure()
+		return fmt.Errorf("client not connected: %w", err)
+	}
 	return nil
 }
 
-// CallWeather makes a call to the weather MCP server
-func (c *Client) CallWeather(ctx context.Context, city string) (*models.TemperatureData, error) {
+// callTool ensures a connection, invokes the named tool, and treats any
+// error from the session as a transport failure: it tears down the session
+// (so the next call reconnects) and feeds the circuit breaker. A successful
+// call resets the breaker's failure tracking.
+func (c *Client) callTool(ctx context.Context, name string, args map[string]interface{}, progressToken any) (*mcp.CallToolResult, error) {
 	if err := c.ensureConnection(ctx); err != nil {
 		return nil, fmt.Errorf("failed to ensure connection: %w", err)
 	}
 
+	params := &mcp.CallToolParams{Name: name, Arguments: args}
+	if progressToken != nil {
+		params.SetProgressToken(progressToken)
+	}
+
+	result, err := c.session.CallTool(ctx, params)
+	if err != nil {
+		c.mu.Lock()
+		c.connected = false
+		c.mu.Unlock()
+		c.recordFailure()
+		return nil, fmt.Errorf("%s call failed: %w", name, err)
+	}
+
+	c.recordSuccess()
+	return result, nil
+}
+
+// CallWeather makes a call to the weather MCP server
+func (c *Client) CallWeather(ctx context.Context, city string) (*models.TemperatureData, error) {
 	// Call the getTemperature tool
 	args := map[string]interface{}{
 		"city": city,
 	}
 
-	result, err := c.session.CallTool(ctx, &mcp.CallToolParams{
-		Name:      "getTemperature",
-		Arguments: args,
-	})
+	result, err := c.callTool(ctx, "getTemperature", args, nil)
 	if err != nil {
-		return nil, fmt.Errorf("getTemperature call failed: %w", err)
+		return nil, err
 	}
 
 	// Log the complete result structure for debugging
@@ -217,23 +408,136 @@ func (c *Client) CallWeather(ctx context.Context, city string) (*models.Temperat
 	}, nil
 }
 
-// CallDateTime makes a call to the datetime MCP server
-func (c *Client) CallDateTime(ctx context.Context, city string) (*models.DateTimeData, error) {
-	if err := c.ensureConnection(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ensure connection: %w", err)
+// CallWeatherBatch makes a single call to the getTemperatureBatch tool for
+// many cities at once, returning one models.TemperatureBatchEntry per city
+// in request order. A per-city failure on the server is reported in that
+// entry's Error field rather than failing the whole batch; err is non-nil
+// only if the tool call itself failed or its response couldn't be parsed.
+func (c *Client) CallWeatherBatch(ctx context.Context, cities []string) ([]models.TemperatureBatchEntry, error) {
+	args := map[string]interface{}{
+		"cities": cities,
+	}
+
+	result, err := c.callTool(ctx, "getTemperatureBatch", args, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var batchData struct {
+		Results []struct {
+			City        string  `json:"city"`
+			Temperature float64 `json:"temperature"`
+			Unit        string  `json:"unit"`
+			Description string  `json:"description"`
+			Timestamp   string  `json:"timestamp"`
+			Error       string  `json:"error,omitempty"`
+		} `json:"results"`
+	}
+
+	if result.StructuredContent == nil {
+		return nil, fmt.Errorf("no structured content in result")
+	}
+	structuredJSON, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal structured content: %w", err)
+	}
+	if err := json.Unmarshal(structuredJSON, &batchData); err != nil {
+		utils.Error("Failed to parse structured content: %v", err)
+		return nil, fmt.Errorf("failed to parse weather batch data: %w", err)
+	}
+
+	entries := make([]models.TemperatureBatchEntry, 0, len(batchData.Results))
+	for _, r := range batchData.Results {
+		entry := models.TemperatureBatchEntry{City: r.City}
+		if r.Error != "" {
+			entry.Error = r.Error
+		} else {
+			entry.Data = &models.TemperatureData{
+				City:        r.City,
+				Temperature: r.Temperature,
+				Unit:        r.Unit,
+				Description: r.Description,
+				Timestamp:   time.Now(),
+				Source:      "weather-mcp",
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// CallForecast makes a call to the forecast MCP server's getForecast tool,
+// requesting days days of forecast for city.
+func (c *Client) CallForecast(ctx context.Context, city string, days int) (*models.ForecastData, error) {
+	args := map[string]interface{}{
+		"city": city,
+	}
+	if days > 0 {
+		args["days"] = days
+	}
+
+	result, err := c.callTool(ctx, "getForecast", args, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var forecastData struct {
+		City string `json:"city"`
+		Days []struct {
+			Date                string  `json:"date"`
+			TemperatureMin      float64 `json:"temperature_min"`
+			TemperatureMax      float64 `json:"temperature_max"`
+			Unit                string  `json:"unit"`
+			PrecipitationChance float64 `json:"precipitation_chance"`
+			Description         string  `json:"description"`
+		} `json:"days"`
+	}
+
+	if result.StructuredContent == nil {
+		return nil, fmt.Errorf("no structured content in result")
 	}
+	structuredJSON, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal structured content: %w", err)
+	}
+	if err := json.Unmarshal(structuredJSON, &forecastData); err != nil {
+		utils.Error("Failed to parse structured content: %v", err)
+		return nil, fmt.Errorf("failed to parse forecast data: %w", err)
+	}
+
+	unit := ""
+	forecastDays := make([]models.ForecastDay, 0, len(forecastData.Days))
+	for _, d := range forecastData.Days {
+		unit = d.Unit
+		forecastDays = append(forecastDays, models.ForecastDay{
+			Date:                d.Date,
+			TemperatureMin:      d.TemperatureMin,
+			TemperatureMax:      d.TemperatureMax,
+			PrecipitationChance: d.PrecipitationChance,
+			Description:         d.Description,
+		})
+	}
+
+	return &models.ForecastData{
+		City:      forecastData.City,
+		Unit:      unit,
+		Days:      forecastDays,
+		Timestamp: time.Now(),
+		Source:    "forecast-mcp",
+	}, nil
+}
 
+// CallDateTime makes a call to the datetime MCP server
+func (c *Client) CallDateTime(ctx context.Context, city string) (*models.DateTimeData, error) {
 	// Call the getDateTime tool
 	args := map[string]interface{}{
 		"city": city,
 	}
 
-	result, err := c.session.CallTool(ctx, &mcp.CallToolParams{
-		Name:      "getDateTime",
-		Arguments: args,
-	})
+	result, err := c.callTool(ctx, "getDateTime", args, nil)
 	if err != nil {
-		return nil, fmt.Errorf("getDateTime call failed: %w", err)
+		return nil, err
 	}
 
 	// Extract result from StructuredContent
@@ -274,21 +578,14 @@ func (c *Client) CallDateTime(ctx context.Context, city string) (*models.DateTim
 
 // CallEcho makes a call to the echo MCP server
 func (c *Client) CallEcho(ctx context.Context, text string) (*models.EchoData, error) {
-	if err := c.ensureConnection(ctx); err != nil {
-		return nil, fmt.Errorf("failed to ensure connection: %w", err)
-	}
-
 	// Call the echo tool
 	args := map[string]interface{}{
 		"text": text,
 	}
 
-	result, err := c.session.CallTool(ctx, &mcp.CallToolParams{
-		Name:      "echo",
-		Arguments: args,
-	})
+	result, err := c.callTool(ctx, "echo", args, nil)
 	if err != nil {
-		return nil, fmt.Errorf("echo call failed: %w", err)
+		return nil, err
 	}
 
 	// Extract result from StructuredContent
@@ -317,19 +614,92 @@ func (c *Client) CallEcho(ctx context.Context, text string) (*models.EchoData, e
 	}, nil
 }
 
-// GetConnectionInfo returns TLS connection information if available
+// CallEchoStream calls the echo tool in streaming mode, delivering each
+// chunk of the echoed text to onChunk, in order, as MCP progress
+// notifications arrive for this call's progress token. It blocks until the
+// server finishes the stream (or ctx is cancelled, which the server also
+// observes to stop echoing early). chunkSize <= 0 uses
+// defaultEchoStreamChunkSize.
+func (c *Client) CallEchoStream(ctx context.Context, text string, chunkSize int, onChunk func(chunk string) error) error {
+	if chunkSize <= 0 {
+		chunkSize = defaultEchoStreamChunkSize
+	}
+
+	token := fmt.Sprintf("echo-stream-%d", c.progressSeq.Add(1))
+
+	var callbackErr error
+	c.progressMu.Lock()
+	c.progressChunk[token] = func(chunk string) error {
+		if err := onChunk(chunk); err != nil {
+			callbackErr = err
+			return err
+		}
+		return nil
+	}
+	c.progressMu.Unlock()
+	defer func() {
+		c.progressMu.Lock()
+		delete(c.progressChunk, token)
+		c.progressMu.Unlock()
+	}()
+
+	args := map[string]interface{}{
+		"text":      text,
+		"stream":    true,
+		"chunkSize": chunkSize,
+	}
+
+	_, err := c.callTool(ctx, "echo", args, token)
+	if err != nil {
+		return err
+	}
+	if callbackErr != nil {
+		return fmt.Errorf("echo stream callback failed: %w", callbackErr)
+	}
+
+	return nil
+}
+
+// handleProgressNotification dispatches an incoming progress notification
+// to the chunk callback registered for its progress token, if any (e.g. one
+// registered by CallEchoStream for an in-flight call).
+func (c *Client) handleProgressNotification(ctx context.Context, req *mcp.ProgressNotificationClientRequest) {
+	token := fmt.Sprintf("%v", req.Params.ProgressToken)
+
+	c.progressMu.Lock()
+	handler, ok := c.progressChunk[token]
+	c.progressMu.Unlock()
+	if !ok {
+		return
+	}
+
+	if err := handler(req.Params.Message); err != nil {
+		utils.Warn("CallEchoStream: chunk callback returned error for token %s, ignoring further chunks: %v", token, err)
+	}
+}
+
+// GetConnectionInfo returns structured information about the client's live
+// TLS connection, read from the underlying *tls.Conn's ConnectionState, or
+// nil if the client isn't using TLS or hasn't dialed yet.
 func (c *Client) GetConnectionInfo() *mcptls.TLSConnectionInfo {
 	if !c.useTLS {
 		return nil
 	}
 
-	return &mcptls.TLSConnectionInfo{
-		RemoteAddr:        c.endpoint,
-		TLSVersion:        "TLS 1.2+",
-		CipherSuite:       "Negotiated",
-		ClientCertCN:      "mcp-client",
-		HandshakeComplete: true,
+	c.connMu.RLock()
+	tlsConn := c.tlsConn
+	c.connMu.RUnlock()
+	if tlsConn == nil {
+		return nil
+	}
+
+	loader := mcptls.NewTLSLoader(nil)
+	info, err := loader.GetTLSConnectionInfo(tlsConn)
+	if err != nil {
+		utils.Warn("Failed to read TLS connection info for %s: %v", c.endpoint, err)
+		return nil
 	}
+	return info
 }
 
 // IsSecure returns true if the client uses TLS
@@ -0,0 +1,231 @@
+// Package client provides MCP client functionality using the official Go SDK
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// ReconnectPolicy configures the exponential-backoff reconnect and circuit
+// breaker behavior a Client falls back to when a tool call looks like a
+// transport failure rather than an application-level error.
+type ReconnectPolicy struct {
+	BaseDelay         time.Duration
+	MaxDelay          time.Duration
+	Multiplier        float64
+	Jitter            float64
+	MaxReconnectTries int
+
+	// FailureThreshold consecutive failures within FailureWindow open the
+	// circuit; CooldownPeriod is how long it stays open before it turns
+	// half-open and lets calls through again (see checkCircuit).
+	FailureThreshold int
+	FailureWindow    time.Duration
+	CooldownPeriod   time.Duration
+}
+
+// DefaultReconnectPolicy returns a conservative policy: a handful of
+// reconnect attempts with capped exponential backoff, and a circuit breaker
+// that opens after 5 consecutive failures within a minute and probes again
+// after a 30 second cooldown.
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		BaseDelay:         200 * time.Millisecond,
+		MaxDelay:          10 * time.Second,
+		Multiplier:        2.0,
+		Jitter:            0.2,
+		MaxReconnectTries: 5,
+		FailureThreshold:  5,
+		FailureWindow:     time.Minute,
+		CooldownPeriod:    30 * time.Second,
+	}
+}
+
+// ClientState reports a Client's connection / circuit-breaker state.
+type ClientState int
+
+const (
+	StateConnected ClientState = iota
+	StateReconnecting
+	StateCircuitOpen
+	StateHalfOpen
+	StateDisconnected
+)
+
+// String returns the string representation of a ClientState.
+func (s ClientState) String() string {
+	switch s {
+	case StateConnected:
+		return "connected"
+	case StateReconnecting:
+		return "reconnecting"
+	case StateCircuitOpen:
+		return "circuit_open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "disconnected"
+	}
+}
+
+// ErrCircuitOpen is returned by CallWeather/CallDateTime/CallEcho/
+// CallEchoStream when the circuit breaker is open, short-circuiting the
+// call instead of waiting on a server that has been failing repeatedly.
+var ErrCircuitOpen = errors.New("mcp client circuit breaker open")
+
+// checkCircuit returns ErrCircuitOpen if the breaker is open and the
+// cooldown period hasn't elapsed yet. Once it has, it moves to half-open
+// and returns nil; it does not limit how many calls pass while the state
+// is half-open. The first recordSuccess/recordFailure after that decides
+// whether the circuit closes again or reopens.
+func (c *Client) checkCircuit() error {
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+
+	if c.state != StateCircuitOpen {
+		return nil
+	}
+
+	if time.Since(c.circuitOpenedAt) < c.reconnectPolicy.CooldownPeriod {
+		return ErrCircuitOpen
+	}
+
+	c.state = StateHalfOpen
+	utils.Info("MCP client circuit breaker for %s entering half-open probe", c.endpoint)
+	return nil
+}
+
+// recordFailure counts a transport failure toward the circuit breaker,
+// opening the circuit once FailureThreshold consecutive failures land
+// within FailureWindow, or immediately reopening it if a half-open probe
+// itself failed.
+func (c *Client) recordFailure() {
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+
+	now := time.Now()
+	if c.windowStart.IsZero() || now.Sub(c.windowStart) > c.reconnectPolicy.FailureWindow {
+		c.windowStart = now
+		c.consecutiveFails = 0
+	}
+	c.consecutiveFails++
+	c.totalFailures++
+
+	if c.state == StateHalfOpen || c.consecutiveFails >= c.reconnectPolicy.FailureThreshold {
+		c.state = StateCircuitOpen
+		c.circuitOpenedAt = now
+		utils.Warn("MCP client circuit breaker for %s opened after %d consecutive failure(s)", c.endpoint, c.consecutiveFails)
+	}
+}
+
+// recordSuccess resets the breaker's consecutive-failure tracking and
+// closes the circuit if a half-open probe just succeeded.
+func (c *Client) recordSuccess() {
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+
+	if c.state == StateHalfOpen {
+		utils.Info("MCP client circuit breaker for %s closed after a successful probe", c.endpoint)
+	}
+	c.consecutiveFails = 0
+	c.windowStart = time.Time{}
+	if c.state != StateReconnecting {
+		c.state = StateConnected
+	}
+}
+
+// setState records s, used around reconnect attempts so State() reflects
+// StateReconnecting instead of the stale pre-failure state.
+func (c *Client) setState(s ClientState) {
+	c.breakerMu.Lock()
+	c.state = s
+	c.breakerMu.Unlock()
+}
+
+// State returns the client's current connection / circuit-breaker state.
+func (c *Client) State() ClientState {
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+	return c.state
+}
+
+// FailureCount returns the total number of transport failures recorded for
+// this client's endpoint since it was created.
+func (c *Client) FailureCount() int64 {
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+	return c.totalFailures
+}
+
+// reconnect rebuilds the client's transport and session with exponential
+// backoff, up to ReconnectPolicy.MaxReconnectTries. reconnectMu serializes
+// concurrent callers onto a single reconnect attempt instead of each
+// racing to redial.
+func (c *Client) reconnect(ctx context.Context) error {
+	c.reconnectMu.Lock()
+	defer c.reconnectMu.Unlock()
+
+	c.mu.RLock()
+	alreadyConnected := c.connected
+	c.mu.RUnlock()
+	if alreadyConnected {
+		return nil
+	}
+
+	c.setState(StateReconnecting)
+
+	policy := c.reconnectPolicy
+	delay := policy.BaseDelay
+
+	var lastErr error
+	for attempt := 1; attempt <= policy.MaxReconnectTries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		session, err := c.mcpClient.Connect(ctx, c.buildTransport(), nil)
+		if err == nil {
+			c.mu.Lock()
+			c.session = session
+			c.connected = true
+			c.mu.Unlock()
+			utils.Info("MCP client reconnected to %s (attempt %d/%d)", c.endpoint, attempt, policy.MaxReconnectTries)
+			return nil
+		}
+
+		lastErr = err
+		utils.Warn("MCP client reconnect to %s failed (attempt %d/%d): %v", c.endpoint, attempt, policy.MaxReconnectTries, err)
+
+		if attempt == policy.MaxReconnectTries {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jitteredDelay(delay, policy.Jitter)):
+		}
+
+		delay = time.Duration(float64(delay) * policy.Multiplier)
+		if delay > policy.MaxDelay {
+			delay = policy.MaxDelay
+		}
+	}
+
+	return fmt.Errorf("failed to reconnect to %s after %d attempts: %w", c.endpoint, policy.MaxReconnectTries, lastErr)
+}
+
+// jitteredDelay returns d adjusted by +/- a random fraction up to jitter
+// (e.g. jitter=0.2 returns a value within 20% of d in either direction).
+func jitteredDelay(d time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return d
+	}
+	delta := (rand.Float64()*2 - 1) * jitter
+	return time.Duration(float64(d) * (1 + delta))
+}
@@ -0,0 +1,157 @@
+package datetime
+
+import (
+	"container/list"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// normalizeCacheKey lowercases and trims a city query for use as a cache
+// key, so "Paris", " paris", and "PARIS" all hit the same entry.
+func normalizeCacheKey(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
+
+// timezoneCacheEntry is one resolved city persisted to disk, keyed on the
+// normalized query that produced it.
+type timezoneCacheEntry struct {
+	Query        string `json:"query"`
+	ResolvedName string `json:"resolved_name"`
+	Timezone     string `json:"timezone"`
+}
+
+// timezoneCache is an in-memory LRU bounded to capacity entries, backed by a
+// JSON file on disk so OpenMeteoResolver lookups survive a restart instead
+// of re-hitting Open-Meteo from a cold cache every time.
+type timezoneCache struct {
+	capacity int
+	path     string
+
+	mu    sync.Mutex
+	order *list.List
+	index map[string]*list.Element
+}
+
+// newTimezoneCache creates an LRU cache of at most capacity entries,
+// persisted as JSON at path. An unreadable or missing path starts with an
+// empty cache rather than failing - resolution still works, it just isn't
+// warm yet.
+func newTimezoneCache(path string, capacity int) *timezoneCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	c := &timezoneCache{
+		capacity: capacity,
+		path:     path,
+		order:    list.New(),
+		index:    make(map[string]*list.Element),
+	}
+	c.load()
+	return c
+}
+
+// get returns the cached entry for key, if present, promoting it to
+// most-recently-used.
+func (c *timezoneCache) get(key string) (timezoneCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.index[key]
+	if !ok {
+		return timezoneCacheEntry{}, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(timezoneCacheEntry), true
+}
+
+// put inserts or refreshes entry as most-recently-used, evicting the
+// least-recently-used entry if the cache is over capacity, then persists
+// the cache to disk.
+func (c *timezoneCache) put(key string, entry timezoneCacheEntry) {
+	c.mu.Lock()
+	if elem, ok := c.index[key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+	} else {
+		c.index[key] = c.order.PushFront(entry)
+		if c.order.Len() > c.capacity {
+			oldest := c.order.Back()
+			if oldest != nil {
+				c.order.Remove(oldest)
+				delete(c.index, oldest.Value.(timezoneCacheEntry).Query)
+			}
+		}
+	}
+	c.mu.Unlock()
+
+	c.save()
+}
+
+// load populates the cache from c.path, most-recently-used first, starting
+// empty if the file doesn't exist (silently) or is malformed (with a warning).
+func (c *timezoneCache) load() {
+	if c.path == "" {
+		return
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return
+	}
+
+	var entries []timezoneCacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		utils.Warn("timezone geocode cache at %s is corrupt, starting empty: %v", c.path, err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entry := range entries {
+		if c.order.Len() >= c.capacity {
+			break
+		}
+		c.index[entry.Query] = c.order.PushBack(entry)
+	}
+}
+
+// save writes the cache to c.path as JSON, most-recently-used first, via a
+// temp file plus rename so a concurrent reader never observes a partial
+// write.
+func (c *timezoneCache) save() {
+	if c.path == "" {
+		return
+	}
+
+	c.mu.Lock()
+	entries := make([]timezoneCacheEntry, 0, c.order.Len())
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, elem.Value.(timezoneCacheEntry))
+	}
+	c.mu.Unlock()
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		utils.Warn("failed to marshal timezone geocode cache: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		utils.Warn("failed to create timezone geocode cache directory: %v", err)
+		return
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		utils.Warn("failed to write timezone geocode cache: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		utils.Warn("failed to persist timezone geocode cache: %v", err)
+	}
+}
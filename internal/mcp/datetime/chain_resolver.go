@@ -0,0 +1,64 @@
+package datetime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// ChainResolver tries each of its resolvers in order, returning the first
+// successful resolution. It's used to layer an operator's OverridesResolver
+// ahead of the primary backend (EmbeddedResolver or HTTPResolver), so an
+// override always wins without requiring the primary backend to support
+// overrides itself.
+type ChainResolver struct {
+	resolvers []TimezoneResolver
+}
+
+// NewChainResolver builds a ChainResolver trying resolvers in the given
+// order.
+func NewChainResolver(resolvers ...TimezoneResolver) *ChainResolver {
+	return &ChainResolver{resolvers: resolvers}
+}
+
+// Resolve tries each resolver in order, returning the first successful
+// result. If none resolve city, it returns the last resolver's error.
+func (c *ChainResolver) Resolve(ctx context.Context, city string) (resolvedName, timezone string, err error) {
+	if len(c.resolvers) == 0 {
+		return "", "", fmt.Errorf("timezone not found for city: %s", city)
+	}
+
+	for i, resolver := range c.resolvers {
+		resolvedName, timezone, err = resolver.Resolve(ctx, city)
+		if err == nil {
+			return resolvedName, timezone, nil
+		}
+		if i == len(c.resolvers)-1 {
+			return "", "", err
+		}
+	}
+	return "", "", err
+}
+
+// SupportedCities returns the union of every wrapped resolver's
+// SupportedCities, for those that implement CityLister, sorted and
+// de-duplicated.
+func (c *ChainResolver) SupportedCities() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, resolver := range c.resolvers {
+		lister, ok := resolver.(CityLister)
+		if !ok {
+			continue
+		}
+		for _, name := range lister.SupportedCities() {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
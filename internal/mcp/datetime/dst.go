@@ -0,0 +1,30 @@
+package datetime
+
+import "time"
+
+// DaylightSavingsOffset reports whether at is observing daylight saving
+// time in loc, by comparing its UTC offset against both a January 1 and a
+// July 1 reference date in the same year and location: whichever of the
+// two has the smaller UTC offset is standard time, and the other is DST -
+// this holds in either hemisphere, unlike assuming January is always
+// standard (Southern Hemisphere zones such as Australia/Sydney observe DST
+// in January and standard time in July, the opposite of the Northern
+// Hemisphere). dstOffsetSeconds and standardOffsetSeconds are the DST and
+// standard UTC offsets in seconds, regardless of which one currently
+// applies; isDST reports whether at itself currently sits at the DST offset.
+func DaylightSavingsOffset(loc *time.Location, at time.Time) (isDST bool, dstOffsetSeconds, standardOffsetSeconds int) {
+	_, currentOffset := at.In(loc).Zone()
+
+	january := time.Date(at.Year(), time.January, 1, 0, 0, 0, 0, loc)
+	_, januaryOffset := january.Zone()
+
+	july := time.Date(at.Year(), time.July, 1, 0, 0, 0, 0, loc)
+	_, julyOffset := july.Zone()
+
+	dstOffsetSeconds, standardOffsetSeconds = januaryOffset, julyOffset
+	if standardOffsetSeconds > dstOffsetSeconds {
+		dstOffsetSeconds, standardOffsetSeconds = standardOffsetSeconds, dstOffsetSeconds
+	}
+
+	return currentOffset != standardOffsetSeconds, dstOffsetSeconds, standardOffsetSeconds
+}
@@ -0,0 +1,102 @@
+package datetime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// embeddedCity is an EmbeddedResolver entry: the canonical display name
+// plus its IANA timezone.
+type embeddedCity struct {
+	name string
+	tz   string
+}
+
+// embeddedCities is the bundled city->timezone table EmbeddedResolver
+// serves, covering the same major US cities as the weather package's
+// StaticGeocoder plus a handful of international cities the original
+// getDateTime switch hardcoded.
+var embeddedCities = map[string]embeddedCity{
+	"new york":      {"New York", "America/New_York"},
+	"nyc":           {"New York", "America/New_York"},
+	"los angeles":   {"Los Angeles", "America/Los_Angeles"},
+	"la":            {"Los Angeles", "America/Los_Angeles"},
+	"chicago":       {"Chicago", "America/Chicago"},
+	"houston":       {"Houston", "America/Chicago"},
+	"phoenix":       {"Phoenix", "America/Phoenix"},
+	"philadelphia":  {"Philadelphia", "America/New_York"},
+	"san antonio":   {"San Antonio", "America/Chicago"},
+	"san diego":     {"San Diego", "America/Los_Angeles"},
+	"dallas":        {"Dallas", "America/Chicago"},
+	"san jose":      {"San Jose", "America/Los_Angeles"},
+	"austin":        {"Austin", "America/Chicago"},
+	"jacksonville":  {"Jacksonville", "America/New_York"},
+	"columbus":      {"Columbus", "America/New_York"},
+	"charlotte":     {"Charlotte", "America/New_York"},
+	"san francisco": {"San Francisco", "America/Los_Angeles"},
+	"indianapolis":  {"Indianapolis", "America/Indiana/Indianapolis"},
+	"seattle":       {"Seattle", "America/Los_Angeles"},
+	"denver":        {"Denver", "America/Denver"},
+	"washington":    {"Washington DC", "America/New_York"},
+	"washington dc": {"Washington DC", "America/New_York"},
+	"boston":        {"Boston", "America/New_York"},
+	"detroit":       {"Detroit", "America/Detroit"},
+	"nashville":     {"Nashville", "America/Chicago"},
+	"portland":      {"Portland", "America/Los_Angeles"},
+	"memphis":       {"Memphis", "America/Chicago"},
+	"las vegas":     {"Las Vegas", "America/Los_Angeles"},
+	"baltimore":     {"Baltimore", "America/New_York"},
+	"milwaukee":     {"Milwaukee", "America/Chicago"},
+	"albuquerque":   {"Albuquerque", "America/Denver"},
+	"sacramento":    {"Sacramento", "America/Los_Angeles"},
+	"atlanta":       {"Atlanta", "America/New_York"},
+	"kansas city":   {"Kansas City", "America/Chicago"},
+	"miami":         {"Miami", "America/New_York"},
+	"minneapolis":   {"Minneapolis", "America/Chicago"},
+	"london":        {"London", "Europe/London"},
+	"tokyo":         {"Tokyo", "Asia/Tokyo"},
+	"paris":         {"Paris", "Europe/Paris"},
+	"berlin":        {"Berlin", "Europe/Berlin"},
+	"sydney":        {"Sydney", "Australia/Sydney"},
+	"singapore":     {"Singapore", "Asia/Singapore"},
+}
+
+// EmbeddedResolver resolves against the fixed embeddedCities table shipped
+// with the binary, with no network access. It exists so offline tests and
+// demo deployments without network access still have a working resolver,
+// and as the default when no other backend is configured.
+type EmbeddedResolver struct{}
+
+// NewEmbeddedResolver creates an EmbeddedResolver.
+func NewEmbeddedResolver() *EmbeddedResolver {
+	return &EmbeddedResolver{}
+}
+
+// Resolve looks up city in embeddedCities, case-insensitive and trimmed.
+func (r *EmbeddedResolver) Resolve(ctx context.Context, city string) (resolvedName, timezone string, err error) {
+	key := strings.ToLower(strings.TrimSpace(city))
+
+	entry, ok := embeddedCities[key]
+	if !ok {
+		return "", "", fmt.Errorf("timezone not found for city: %s", city)
+	}
+	return entry.name, entry.tz, nil
+}
+
+// SupportedCities returns every display name embeddedCities covers, sorted
+// and de-duplicated (some cities have more than one key, e.g. "nyc").
+func (r *EmbeddedResolver) SupportedCities() []string {
+	seen := make(map[string]bool, len(embeddedCities))
+	names := make([]string, 0, len(embeddedCities))
+	for _, entry := range embeddedCities {
+		if seen[entry.name] {
+			continue
+		}
+		seen[entry.name] = true
+		names = append(names, entry.name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,58 @@
+package datetime
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// defaultOpenMeteoCacheCapacity bounds the on-disk geocode cache the
+// "openmeteo" backend keeps, the same capacity the weather package's
+// CachedGeocoder defaults to.
+const defaultOpenMeteoCacheCapacity = 1000
+
+// NewResolverByName constructs the named backend resolver ("embedded",
+// "http", "openmeteo") and, if overridesPath is non-empty, layers a
+// ChainResolver with an OverridesResolver loaded from it ahead of the
+// backend so operator corrections always win. httpURL is only required by
+// the "http" backend; geocodeCachePath is only used by "openmeteo" (a
+// disk cache is optional there, not required). An unreadable overridesPath
+// is logged and skipped rather than failing startup, the same as
+// EmbeddedResolver needing no external state.
+//
+// "openmeteo" itself is a ChainResolver of OpenMeteoResolver ahead of
+// EmbeddedResolver, so a failed or rate-limited Open-Meteo call still
+// resolves the embedded table's cities instead of failing outright -
+// offline/dev usage keeps working without a network.
+func NewResolverByName(name, httpURL, overridesPath, geocodeCachePath string) (TimezoneResolver, error) {
+	var base TimezoneResolver
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "embedded":
+		base = NewEmbeddedResolver()
+	case "http":
+		if httpURL == "" {
+			return nil, fmt.Errorf("http timezone resolver requires a backend URL")
+		}
+		base = NewHTTPResolver(httpURL)
+	case "openmeteo":
+		base = NewChainResolver(
+			NewOpenMeteoResolver(geocodeCachePath, defaultOpenMeteoCacheCapacity),
+			NewEmbeddedResolver(),
+		)
+	default:
+		return nil, fmt.Errorf("unknown timezone resolver: %s", name)
+	}
+
+	if overridesPath == "" {
+		return base, nil
+	}
+
+	overrides, err := LoadOverridesResolver(overridesPath)
+	if err != nil {
+		utils.Warn("failed to load timezone overrides from %s, continuing without them: %v", overridesPath, err)
+		return base, nil
+	}
+
+	return NewChainResolver(overrides, base), nil
+}
@@ -0,0 +1,78 @@
+package datetime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HTTPResolver resolves city names against a configurable HTTP geocoding
+// endpoint, for deployments that want broader or more current coverage
+// than EmbeddedResolver's bundled table without shipping a new binary. The
+// endpoint is expected to accept a "city" query parameter and respond with
+// {"resolved_name": "...", "timezone": "..."} on success.
+type HTTPResolver struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewHTTPResolver creates an HTTPResolver against baseURL, with a 10s
+// request timeout.
+func NewHTTPResolver(baseURL string) *HTTPResolver {
+	return &HTTPResolver{
+		BaseURL:    baseURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type httpResolverResponse struct {
+	ResolvedName string `json:"resolved_name"`
+	Timezone     string `json:"timezone"`
+}
+
+// Resolve queries r.BaseURL with city as the "city" parameter and decodes
+// the JSON response.
+func (r *HTTPResolver) Resolve(ctx context.Context, city string) (resolvedName, timezone string, err error) {
+	q := url.Values{}
+	q.Set("city", city)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.BaseURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to build geocoding request: %w", err)
+	}
+
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to reach geocoding endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", "", fmt.Errorf("timezone not found for city: %s", city)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("geocoding endpoint returned status %d", resp.StatusCode)
+	}
+
+	var result httpResolverResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", "", fmt.Errorf("failed to decode geocoding response: %w", err)
+	}
+	if result.Timezone == "" {
+		return "", "", fmt.Errorf("timezone not found for city: %s", city)
+	}
+
+	name := result.ResolvedName
+	if name == "" {
+		name = city
+	}
+	return name, result.Timezone, nil
+}
@@ -0,0 +1,34 @@
+package datetime
+
+import "strings"
+
+// countryAbbreviations expands the country abbreviations a user is likely to
+// type after a comma (e.g. "Paris, FR") to the full name Open-Meteo's
+// geocoder matches against, so those queries resolve instead of silently
+// falling through to "city not found".
+var countryAbbreviations = map[string]string{
+	"us":  "United States",
+	"usa": "United States",
+	"uk":  "United Kingdom",
+}
+
+// normalizeCity splits city on its first comma into a bare city name and an
+// administrative suffix (state, province, or country), expanding the
+// suffix via countryAbbreviations when it's a recognized one. query is what
+// should be sent to a geocoder - Open-Meteo's free-text "name" parameter
+// matches on the city alone, not "City, Country" - and country is the
+// expanded suffix (or "" if city had none), used to disambiguate between
+// same-named cities in different countries.
+func normalizeCity(city string) (query, country string) {
+	name, suffix, hasSuffix := strings.Cut(city, ",")
+	name = strings.TrimSpace(name)
+	if !hasSuffix {
+		return name, ""
+	}
+
+	suffix = strings.TrimSpace(suffix)
+	if expanded, ok := countryAbbreviations[strings.ToLower(suffix)]; ok {
+		suffix = expanded
+	}
+	return name, suffix
+}
@@ -0,0 +1,146 @@
+package datetime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Open-Meteo needs no API key; tests point GeocodeURL at an httptest.Server
+// instead of this.
+const defaultOpenMeteoGeocodeURL = "https://geocoding-api.open-meteo.com/v1/search"
+
+// OpenMeteoResolver resolves city names against Open-Meteo's free geocoding
+// API, the same backend the weather package's OpenMeteoProvider uses for
+// temperature lookups. Successful resolutions are cached to disk (if
+// CachePath is set) keyed on the normalized query, so a restart doesn't
+// re-hit the API for a city already seen.
+type OpenMeteoResolver struct {
+	GeocodeURL string
+	HTTPClient *http.Client
+
+	cache *timezoneCache
+}
+
+// NewOpenMeteoResolver creates a resolver against the real Open-Meteo
+// geocoding endpoint, persisting resolved cities to an LRU cache of at most
+// capacity entries backed by cachePath (ignored if cachePath is empty).
+func NewOpenMeteoResolver(cachePath string, capacity int) *OpenMeteoResolver {
+	return &OpenMeteoResolver{
+		GeocodeURL: defaultOpenMeteoGeocodeURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      newTimezoneCache(cachePath, capacity),
+	}
+}
+
+type openMeteoGeocodeResult struct {
+	Name       string  `json:"name"`
+	Admin1     string  `json:"admin1"`
+	Country    string  `json:"country"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Timezone   string  `json:"timezone"`
+	Population int     `json:"population"`
+}
+
+type openMeteoGeocodeResponse struct {
+	Results []openMeteoGeocodeResult `json:"results"`
+}
+
+// Resolve geocodes city against Open-Meteo, preferring the disk cache, and
+// falling back to an exact-name-then-most-populous pick among the API's
+// candidates.
+func (r *OpenMeteoResolver) Resolve(ctx context.Context, city string) (resolvedName, timezone string, err error) {
+	key := normalizeCacheKey(city)
+	if entry, ok := r.cache.get(key); ok {
+		return entry.ResolvedName, entry.Timezone, nil
+	}
+
+	query, country := normalizeCity(city)
+	result, err := r.geocode(ctx, query, country)
+	if err != nil {
+		return "", "", err
+	}
+
+	entry := timezoneCacheEntry{Query: key, ResolvedName: result.Name, Timezone: result.Timezone}
+	r.cache.put(key, entry)
+
+	return entry.ResolvedName, entry.Timezone, nil
+}
+
+// geocode queries Open-Meteo for query and picks the best candidate,
+// preferring country as a disambiguator between same-named cities.
+func (r *OpenMeteoResolver) geocode(ctx context.Context, query, country string) (openMeteoGeocodeResult, error) {
+	geocodeURL := r.GeocodeURL
+	if geocodeURL == "" {
+		geocodeURL = defaultOpenMeteoGeocodeURL
+	}
+
+	q := url.Values{}
+	q.Set("name", query)
+	q.Set("count", "10")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, geocodeURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return openMeteoGeocodeResult{}, fmt.Errorf("failed to build Open-Meteo geocode request: %w", err)
+	}
+
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return openMeteoGeocodeResult{}, fmt.Errorf("failed to reach Open-Meteo geocoder: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return openMeteoGeocodeResult{}, fmt.Errorf("Open-Meteo geocoder returned status %d", resp.StatusCode)
+	}
+
+	var geocodeResp openMeteoGeocodeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geocodeResp); err != nil {
+		return openMeteoGeocodeResult{}, fmt.Errorf("failed to decode Open-Meteo geocode response: %w", err)
+	}
+	if len(geocodeResp.Results) == 0 {
+		return openMeteoGeocodeResult{}, fmt.Errorf("timezone not found for city: %s", query)
+	}
+
+	return bestOpenMeteoMatch(query, country, geocodeResp.Results), nil
+}
+
+// bestOpenMeteoMatch scores each candidate - an exact name match outweighs a
+// country match, which outweighs population - and returns the winner, since
+// Open-Meteo often returns several same-named cities in different
+// countries.
+func bestOpenMeteoMatch(query, country string, results []openMeteoGeocodeResult) openMeteoGeocodeResult {
+	best := results[0]
+	bestScore := openMeteoMatchScore(query, country, best)
+
+	for _, candidate := range results[1:] {
+		score := openMeteoMatchScore(query, country, candidate)
+		if score > bestScore || (score == bestScore && candidate.Population > best.Population) {
+			best, bestScore = candidate, score
+		}
+	}
+	return best
+}
+
+// openMeteoMatchScore ranks r on name and country alone; population only
+// breaks ties between equal scores (see bestOpenMeteoMatch).
+func openMeteoMatchScore(query, country string, r openMeteoGeocodeResult) int {
+	score := 0
+	if strings.EqualFold(r.Name, query) {
+		score += 2
+	}
+	if country != "" && strings.EqualFold(r.Country, country) {
+		score += 1
+	}
+	return score
+}
@@ -0,0 +1,72 @@
+package datetime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// overridesEntry is one OverridesResolver entry: the canonical display
+// name plus its IANA timezone, the same shape EmbeddedResolver uses
+// internally.
+type overridesEntry struct {
+	name string
+	tz   string
+}
+
+// OverridesResolver resolves against a static city->timezone mapping an
+// operator supplies at startup (e.g. a deployment-specific city not in
+// EmbeddedResolver's bundled table, or a correction for one that is).
+type OverridesResolver struct {
+	entries map[string]overridesEntry
+}
+
+// LoadOverridesResolver reads path as a JSON object mapping city name to
+// IANA timezone (e.g. {"Springfield, IL": "America/Chicago"}) and returns
+// an OverridesResolver serving it. Keys are matched case-insensitively and
+// trimmed, the same as EmbeddedResolver.
+func LoadOverridesResolver(path string) (*OverridesResolver, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read timezone overrides file: %w", err)
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse timezone overrides file: %w", err)
+	}
+
+	entries := make(map[string]overridesEntry, len(raw))
+	for city, tz := range raw {
+		key := strings.ToLower(strings.TrimSpace(city))
+		entries[key] = overridesEntry{name: city, tz: tz}
+	}
+
+	return &OverridesResolver{entries: entries}, nil
+}
+
+// Resolve looks up city in the loaded overrides, case-insensitive and
+// trimmed.
+func (r *OverridesResolver) Resolve(ctx context.Context, city string) (resolvedName, timezone string, err error) {
+	key := strings.ToLower(strings.TrimSpace(city))
+
+	entry, ok := r.entries[key]
+	if !ok {
+		return "", "", fmt.Errorf("timezone not found for city: %s", city)
+	}
+	return entry.name, entry.tz, nil
+}
+
+// SupportedCities returns every display name this OverridesResolver
+// covers, sorted.
+func (r *OverridesResolver) SupportedCities() []string {
+	names := make([]string, 0, len(r.entries))
+	for _, entry := range r.entries {
+		names = append(names, entry.name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,24 @@
+// Package datetime provides pluggable city-to-timezone resolution for the
+// DateTime MCP server, replacing its original hardcoded six-city switch.
+package datetime
+
+import "context"
+
+// TimezoneResolver resolves a free-form city name to an IANA timezone name
+// (e.g. "America/New_York"). Implementations vary in coverage and cost:
+// EmbeddedResolver is a free, bundled, offline lookup; HTTPResolver calls
+// out to a configurable geocoding service; OverridesResolver loads an
+// operator-supplied static mapping; ChainResolver tries several in order.
+type TimezoneResolver interface {
+	// Resolve returns the canonical display name and IANA timezone for
+	// city, or an error if city isn't known to this resolver.
+	Resolve(ctx context.Context, city string) (resolvedName, timezone string, err error)
+}
+
+// CityLister is implemented by a TimezoneResolver that can enumerate every
+// city it covers, for the listSupportedCities tool. Not every resolver can
+// do this meaningfully (HTTPResolver's backend catalog is unbounded), so
+// it's a separate optional interface rather than part of TimezoneResolver.
+type CityLister interface {
+	SupportedCities() []string
+}
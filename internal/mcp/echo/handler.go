@@ -10,6 +10,17 @@ import (
 	"github.com/steve/llm-agents/internal/utils"
 )
 
+// maxEchoLength is the hard cap on text length in the non-streaming case,
+// per contract. Streaming mode raises this considerably since the response
+// is delivered in bounded chunks instead of one blob.
+const maxEchoLength = 1000
+
+// maxStreamedEchoLength is the cap applied when streaming is requested.
+const maxStreamedEchoLength = 100_000
+
+// defaultChunkSize is used when a streaming request omits chunkSize.
+const defaultChunkSize = 200
+
 // Handler implements echo MCP method handling
 type Handler struct{}
 
@@ -18,40 +29,127 @@ func NewHandler() *Handler {
 	return &Handler{}
 }
 
+// echoRequest is the echo method's decoded parameters, shared by Handle and
+// HandleStreaming.
+type echoRequest struct {
+	Text      string `json:"text"`
+	Stream    bool   `json:"stream"`
+	ChunkSize int    `json:"chunkSize"`
+}
+
+// echoResult is the echo method's response shape. Chunks is only populated
+// in streaming mode.
+type echoResult struct {
+	OriginalText string   `json:"original_text"`
+	EchoText     string   `json:"echo_text"`
+	Chunks       []string `json:"chunks,omitempty"`
+}
+
+// parseEcho decodes and validates params, returning the trimmed original
+// and echo text request.Text resolves to.
+func parseEcho(params json.RawMessage) (request echoRequest, originalText, echoText string, err error) {
+	if err := json.Unmarshal(params, &request); err != nil {
+		return echoRequest{}, "", "", fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	if request.Text == "" {
+		return echoRequest{}, "", "", fmt.Errorf("text parameter is required and cannot be empty")
+	}
+
+	maxLength := maxEchoLength
+	if request.Stream {
+		maxLength = maxStreamedEchoLength
+	}
+	if len(request.Text) > maxLength {
+		return echoRequest{}, "", "", fmt.Errorf("text too long: maximum %d characters allowed", maxLength)
+	}
+
+	// Trim only leading/trailing whitespace if any (preserve internal formatting)
+	originalText = strings.TrimSpace(request.Text)
+	echoText = strings.TrimSpace(request.Text)
+	return request, originalText, echoText, nil
+}
+
 // Handle handles the echo method
 func (h *Handler) Handle(ctx context.Context, params json.RawMessage) (interface{}, error) {
-	var request struct {
-		Text string `json:"text"`
+	request, originalText, echoText, err := parseEcho(params)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := json.Unmarshal(params, &request); err != nil {
-		return nil, fmt.Errorf("invalid parameters: %w", err)
+	if !request.Stream {
+		utils.Debug("Echo processed: %q -> %q", originalText, echoText)
+		return echoResult{OriginalText: originalText, EchoText: echoText}, nil
 	}
 
-	if request.Text == "" {
-		return nil, fmt.Errorf("text parameter is required and cannot be empty")
+	chunkSize := request.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	chunks := chunkText(echoText, chunkSize)
+
+	utils.Debug("Echo processed in streaming mode: %q -> %d chunk(s) of size %d", originalText, len(chunks), chunkSize)
+	return echoResult{OriginalText: originalText, EchoText: echoText, Chunks: chunks}, nil
+}
+
+// echoChunkPartial is one partial HandleStreaming pushes through emit for a
+// streaming echo request, ahead of its final echoResult.
+type echoChunkPartial struct {
+	Index int    `json:"index"`
+	Total int    `json:"total"`
+	Chunk string `json:"chunk"`
+}
+
+// HandleStreaming implements server.StreamingHandler: a non-streaming
+// request behaves exactly like Handle, but a streaming one pushes each
+// chunk through emit as soon as it's computed, ahead of returning the same
+// echoResult Handle would have returned in one shot.
+func (h *Handler) HandleStreaming(ctx context.Context, params json.RawMessage, emit func(partial interface{}) error) (interface{}, error) {
+	request, originalText, echoText, err := parseEcho(params)
+	if err != nil {
+		return nil, err
 	}
 
-	// Validate text length (max 1000 characters as per contract)
-	if len(request.Text) > 1000 {
-		return nil, fmt.Errorf("text too long: maximum 1000 characters allowed")
+	if !request.Stream {
+		utils.Debug("Echo processed: %q -> %q", originalText, echoText)
+		return echoResult{OriginalText: originalText, EchoText: echoText}, nil
 	}
 
-	// Simple echo - return the text exactly as received
-	echoText := request.Text
+	chunkSize := request.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	chunks := chunkText(echoText, chunkSize)
 
-	// Trim only leading/trailing whitespace if any (preserve internal formatting)
-	originalText := strings.TrimSpace(request.Text)
-	echoText = strings.TrimSpace(echoText)
+	if emit != nil {
+		for i, chunk := range chunks {
+			if err := emit(echoChunkPartial{Index: i, Total: len(chunks), Chunk: chunk}); err != nil {
+				return nil, fmt.Errorf("failed to emit chunk %d: %w", i, err)
+			}
+		}
+	}
+
+	utils.Debug("Echo processed in streaming mode: %q -> %d chunk(s) of size %d", originalText, len(chunks), chunkSize)
+	return echoResult{OriginalText: originalText, EchoText: echoText, Chunks: chunks}, nil
+}
 
-	result := struct {
-		OriginalText string `json:"original_text"`
-		EchoText     string `json:"echo_text"`
-	}{
-		OriginalText: originalText,
-		EchoText:     echoText,
+// chunkText splits text into chunks of at most chunkSize runes, preserving
+// order. Handle returns every chunk in its single response, for callers that
+// replay them as a stream (e.g. the official SDK server's progress
+// notifications); HandleStreaming pushes each one through emit as it goes.
+func chunkText(text string, chunkSize int) []string {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return nil
 	}
 
-	utils.Debug("Echo processed: %q -> %q", originalText, echoText)
-	return result, nil
+	chunks := make([]string, 0, (len(runes)+chunkSize-1)/chunkSize)
+	for start := 0; start < len(runes); start += chunkSize {
+		end := start + chunkSize
+		if end > len(runes) {
+			end = len(runes)
+		}
+		chunks = append(chunks, string(runes[start:end]))
+	}
+	return chunks
 }
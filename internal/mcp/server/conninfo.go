@@ -0,0 +1,96 @@
+package server
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+
+	mcptls "github.com/steve/llm-agents/internal/tls"
+)
+
+// maxConnectionHistory bounds how many recently closed connections
+// connectionTracker remembers, so a long-lived server doesn't grow this
+// slice without bound.
+const maxConnectionHistory = 50
+
+// connectionTracker records live TLS connection info per remote address,
+// plus a bounded rolling history of recently closed connections, so
+// Server.GetConnectionInfo can answer for both active and just-closed
+// sessions.
+type connectionTracker struct {
+	mu      sync.RWMutex
+	active  map[string]*mcptls.TLSConnectionInfo
+	history []mcptls.TLSConnectionInfo
+}
+
+func newConnectionTracker() *connectionTracker {
+	return &connectionTracker{
+		active: make(map[string]*mcptls.TLSConnectionInfo),
+	}
+}
+
+// track is an http.Server.ConnState callback: by the time a connection
+// reaches StateActive its TLS handshake (if any) is complete, so conn can
+// be type-asserted to *tls.Conn and read for real connection info.
+func (t *connectionTracker) track(loader *mcptls.TLSLoader) func(net.Conn, http.ConnState) {
+	return func(conn net.Conn, state http.ConnState) {
+		switch state {
+		case http.StateActive:
+			tlsConn, ok := conn.(*tls.Conn)
+			if !ok || loader == nil {
+				return
+			}
+			info, err := loader.GetTLSConnectionInfo(tlsConn)
+			if err != nil {
+				return
+			}
+			t.mu.Lock()
+			if _, exists := t.active[info.RemoteAddr]; !exists {
+				t.active[info.RemoteAddr] = info
+			}
+			t.mu.Unlock()
+
+		case http.StateClosed, http.StateHijacked:
+			remoteAddr := conn.RemoteAddr().String()
+			t.mu.Lock()
+			if info, ok := t.active[remoteAddr]; ok {
+				delete(t.active, remoteAddr)
+				t.history = append(t.history, *info)
+				if len(t.history) > maxConnectionHistory {
+					t.history = t.history[len(t.history)-maxConnectionHistory:]
+				}
+			}
+			t.mu.Unlock()
+		}
+	}
+}
+
+// activeCount returns the number of connections currently tracked as open.
+func (t *connectionTracker) activeCount() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.active)
+}
+
+// get returns the tracked info for remoteAddr: live info if the connection
+// is still active, or the most recent closed record otherwise. Returns nil
+// if remoteAddr was never seen.
+func (t *connectionTracker) get(remoteAddr string) *mcptls.TLSConnectionInfo {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if info, ok := t.active[remoteAddr]; ok {
+		infoCopy := *info
+		return &infoCopy
+	}
+
+	for i := len(t.history) - 1; i >= 0; i-- {
+		if t.history[i].RemoteAddr == remoteAddr {
+			infoCopy := t.history[i]
+			return &infoCopy
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// discoverMethod is the built-in JSON-RPC method orchestrators/agents call
+// to bootstrap dynamically against this server instead of hard-coding
+// method names and shapes (config.Config.WeatherMCPURL and friends), the
+// same way a service mesh control plane publishes its catalog.
+const discoverMethod = "mcp.discover"
+
+// MethodSchema pairs a registered method's params/result JSON schemas, as
+// supplied to RegisterHandlerWithSchema. Either field may be nil if that
+// side of the method isn't schema-documented.
+type MethodSchema struct {
+	ParamsSchema interface{} `json:"params_schema,omitempty"`
+	ResultSchema interface{} `json:"result_schema,omitempty"`
+}
+
+// DiscoveredMethod describes one registered method in a DiscoveryInfo
+// response.
+type DiscoveredMethod struct {
+	Name         string      `json:"name"`
+	ParamsSchema interface{} `json:"params_schema,omitempty"`
+	ResultSchema interface{} `json:"result_schema,omitempty"`
+}
+
+// DiscoveryInfo is the result of the mcp.discover method: every registered
+// method (with whatever schema it was registered with), whether this
+// listener is TLS-protected, how long it's been up, and whether its
+// configured dependency check currently passes.
+type DiscoveryInfo struct {
+	Server     string             `json:"server"`
+	TLSEnabled bool               `json:"tls_enabled"`
+	UptimeSecs float64            `json:"uptime_seconds"`
+	Methods    []DiscoveredMethod `json:"methods"`
+	Ready      bool               `json:"ready"`
+	ReadyError string             `json:"ready_error,omitempty"`
+}
+
+// SetReadyFunc attaches a dependency-reachability check mcp.discover and
+// /readyz consult before reporting ready, the same convention as
+// internal/diag.ReadyFunc for the standalone cmd/* binaries. A nil
+// readyFunc (the default) always reports ready.
+func (s *Server) SetReadyFunc(readyFunc func() error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readyFunc = readyFunc
+}
+
+// registerDiscoveryHandler wires up mcp.discover as a shared handler,
+// called once from NewServer/NewTLSServer so every Server instance
+// advertises its catalog without the caller having to register it
+// explicitly.
+func (s *Server) registerDiscoveryHandler() {
+	s.handlers[discoverMethod] = HandlerFunc(func(ctx context.Context, params json.RawMessage) (interface{}, error) {
+		return s.discover(), nil
+	})
+}
+
+// discover builds the current DiscoveryInfo snapshot.
+func (s *Server) discover() *DiscoveryInfo {
+	s.mu.RLock()
+	readyFunc := s.readyFunc
+	startedAt := s.startedAt
+	s.mu.RUnlock()
+
+	methods := make([]DiscoveredMethod, 0, len(s.handlers))
+	for name := range s.handlers {
+		schema := s.methodSchemas[name]
+		methods = append(methods, DiscoveredMethod{
+			Name:         name,
+			ParamsSchema: schema.ParamsSchema,
+			ResultSchema: schema.ResultSchema,
+		})
+	}
+
+	var uptime time.Duration
+	if !startedAt.IsZero() {
+		uptime = time.Since(startedAt)
+	}
+
+	info := &DiscoveryInfo{
+		Server:     s.name,
+		TLSEnabled: s.IsSecure(),
+		UptimeSecs: uptime.Seconds(),
+		Methods:    methods,
+		Ready:      true,
+	}
+
+	if readyFunc != nil {
+		if err := readyFunc(); err != nil {
+			info.Ready = false
+			info.ReadyError = err.Error()
+		}
+	}
+
+	return info
+}
+
+// handleHealthz reports process liveness unconditionally, matching
+// internal/diag's /healthz: a server that can answer HTTP at all is alive,
+// regardless of whether its dependencies are reachable.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok\n"))
+}
+
+// handleReadyz reports whether the configured SetReadyFunc dependency check
+// currently passes, the same contract as internal/diag's /readyz.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	readyFunc := s.readyFunc
+	s.mu.RUnlock()
+
+	if readyFunc == nil {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok\n"))
+		return
+	}
+	if err := readyFunc(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("not ready: " + err.Error() + "\n"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok\n"))
+}
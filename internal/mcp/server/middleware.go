@@ -0,0 +1,184 @@
+package server
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steve/llm-agents/internal/resilience"
+	mcptls "github.com/steve/llm-agents/internal/tls"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// Middleware wraps an http.Handler to add cross-cutting behavior - auth,
+// rate limiting, logging - ahead of it. Middlewares registered via Use wrap
+// every transport the same way, since Start and StartTLS apply the chain to
+// the whole mux (/rpc, /events, and /ws alike) rather than to individual
+// routes.
+type Middleware func(http.Handler) http.Handler
+
+// Use appends mw to the server's middleware chain, in the order they should
+// run: the first one registered is outermost, seeing a request (and its
+// response) before any later one. Must be called before Start/StartTLS; a
+// server that's already serving ignores later Use calls.
+func (s *Server) Use(mw ...Middleware) {
+	s.middleware = append(s.middleware, mw...)
+}
+
+// wrap applies the server's middleware chain around h, outermost first.
+func (s *Server) wrap(h http.Handler) http.Handler {
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		h = s.middleware[i](h)
+	}
+	return h
+}
+
+// RateLimitMiddleware rate-limits requests per client IP, giving each
+// address its own resilience.TokenBucket so one noisy client can't starve
+// others out of a single server-wide bucket. A non-positive
+// cfg.RatePerSecond disables limiting entirely, per TokenBucket's own
+// convention.
+func RateLimitMiddleware(cfg resilience.RateLimitConfig) Middleware {
+	var mu sync.Mutex
+	buckets := make(map[string]*resilience.TokenBucket)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				host = r.RemoteAddr
+			}
+
+			mu.Lock()
+			bucket, ok := buckets[host]
+			if !ok {
+				bucket = resilience.NewTokenBucket(cfg)
+				buckets[host] = bucket
+			}
+			mu.Unlock()
+
+			if !bucket.Allow() {
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AuthConfig configures AuthMiddleware. A request is admitted if either
+// list is satisfied: its Authorization: Bearer header names a token in
+// Tokens, or its TLS client certificate's Common Name is in AllowedCNs. An
+// empty AuthConfig (both lists nil) admits everything, so a server that
+// never calls Use(AuthMiddleware(...)) keeps today's open-access behavior.
+type AuthConfig struct {
+	Tokens     []string
+	AllowedCNs []string
+}
+
+// AuthMiddleware enforces AuthConfig ahead of next, consulting tlsLoader to
+// resolve a client certificate's CN the same way handleTLSRPC already does
+// for connection logging. tlsLoader may be nil for a plain HTTP server with
+// only Tokens configured.
+func AuthMiddleware(cfg AuthConfig, tlsLoader *mcptls.TLSLoader) Middleware {
+	tokens := make(map[string]struct{}, len(cfg.Tokens))
+	for _, t := range cfg.Tokens {
+		tokens[t] = struct{}{}
+	}
+	cns := make(map[string]struct{}, len(cfg.AllowedCNs))
+	for _, cn := range cfg.AllowedCNs {
+		cns[cn] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(tokens) == 0 && len(cns) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if token, ok := bearerToken(r); ok {
+				if _, allowed := tokens[token]; allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			if connInfo, ok := tlsConnectionInfo(r, tlsLoader); ok && connInfo.ClientCertCN != "" {
+				if _, allowed := cns[connInfo.ClientCertCN]; allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		})
+	}
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>"
+// header, reporting false if the header is absent or a different scheme.
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(header, prefix), true
+}
+
+// tlsConnectionInfo resolves r's TLS connection info via tlsLoader the same
+// way handleTLSRPC does for connection logging, reporting false if r wasn't
+// served over TLS or tlsLoader is nil.
+func tlsConnectionInfo(r *http.Request, tlsLoader *mcptls.TLSLoader) (*mcptls.TLSConnectionInfo, bool) {
+	if tlsLoader == nil || r.TLS == nil {
+		return nil, false
+	}
+	tlsConn, ok := r.Context().Value("tls-conn").(*tls.Conn)
+	if !ok {
+		return nil, false
+	}
+	connInfo, err := tlsLoader.GetTLSConnectionInfo(tlsConn)
+	if err != nil {
+		return nil, false
+	}
+	return connInfo, true
+}
+
+// AccessLogMiddleware logs one structured line per request: remote address,
+// TLS version (if any), HTTP method and path, status code, and duration.
+// Chain it outermost (the first Use call) so its duration covers every
+// other middleware too.
+func AccessLogMiddleware(serverName string, tlsLoader *mcptls.TLSLoader) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(rec, r)
+
+			tlsVersion := "none"
+			if connInfo, ok := tlsConnectionInfo(r, tlsLoader); ok {
+				tlsVersion = connInfo.TLSVersion
+			}
+
+			utils.Info("[%s] %s %s remote=%s tls=%s status=%d duration=%s",
+				serverName, r.Method, r.URL.Path, r.RemoteAddr, tlsVersion, rec.statusCode, time.Since(start))
+		})
+	}
+}
+
+// statusRecorder captures the status code a handler writes, so
+// AccessLogMiddleware can log it without buffering the response body.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (rec *statusRecorder) WriteHeader(statusCode int) {
+	rec.statusCode = statusCode
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
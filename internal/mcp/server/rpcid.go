@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/steve/llm-agents/internal/mcperrors"
+)
+
+// RequestID carries a JSON-RPC 2.0 request/response "id" verbatim: a
+// string, a number, or null, per spec. It replaces the earlier *int field,
+// which silently coerced a string or null id to 0 and made a true
+// notification (no "id" member at all) indistinguishable from an explicit
+// "id": 0 call.
+//
+// The zero value (nil) represents a notification - the "id" member was
+// absent entirely. An explicit "id": null decodes to the non-nil raw bytes
+// "null" and is NOT a notification: it still gets a response, with a null
+// id, per spec (only a missing member suppresses the response).
+type RequestID json.RawMessage
+
+// IsNotification reports whether the "id" member was omitted from the
+// request entirely.
+func (id RequestID) IsNotification() bool {
+	return len(id) == 0
+}
+
+// String renders id for logging: its raw JSON text, or "<notification>" if
+// none was present.
+func (id RequestID) String() string {
+	if id.IsNotification() {
+		return "<notification>"
+	}
+	return string(id)
+}
+
+// MarshalJSON renders a notification's absent id as JSON null, since a
+// response is never written for one, and every other id verbatim.
+func (id RequestID) MarshalJSON() ([]byte, error) {
+	if id.IsNotification() {
+		return []byte("null"), nil
+	}
+	return id, nil
+}
+
+// UnmarshalJSON stores data verbatim, so later re-encoding it as a response
+// id reproduces the client's original string/number/null exactly.
+func (id *RequestID) UnmarshalJSON(data []byte) error {
+	*id = append((*id)[:0], data...)
+	return nil
+}
+
+// ServerError lets a Handler return a JSON-RPC error with an explicit code
+// and optional structured Data (e.g. validation details), instead of a
+// plain error that dispatch would otherwise map to the blanket -32603
+// Internal error.
+type ServerError struct {
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+// NewServerError builds a ServerError a Handler can return directly.
+func NewServerError(code int, message string, data interface{}) *ServerError {
+	return &ServerError{Code: code, Message: message, Data: data}
+}
+
+func (e *ServerError) Error() string {
+	return e.Message
+}
+
+// classifyError maps err to a JSON-RPC code, message, and optional Data,
+// using the shared internal/mcperrors vocabulary: a *mcperrors.Error's
+// fields verbatim (including any RetryHint in Data), a *ServerError's
+// fields verbatim, a context deadline to mcperrors.ErrUpstreamTimeout, a
+// "not found" message (e.g. an unknown city) to mcperrors.ErrCityNotFound,
+// and anything else to the blanket mcperrors.ErrInternalError, same as
+// before this classification existed.
+func classifyError(err error) (code int, message string, data json.RawMessage) {
+	var mcpErr *mcperrors.Error
+	if errors.As(err, &mcpErr) {
+		return mcpErr.Code, mcpErr.Message, mcpErr.Data
+	}
+	var serverErr *ServerError
+	if errors.As(err, &serverErr) {
+		data, marshalErr := json.Marshal(serverErr.Data)
+		if marshalErr != nil || serverErr.Data == nil {
+			data = nil
+		}
+		return serverErr.Code, serverErr.Message, data
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return mcperrors.ErrUpstreamTimeout, "Request timeout", nil
+	}
+	if strings.Contains(err.Error(), "not found") {
+		return mcperrors.ErrCityNotFound, err.Error(), nil
+	}
+	return mcperrors.ErrInternalError, err.Error(), nil
+}
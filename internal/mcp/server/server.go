@@ -2,16 +2,21 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/mcperrors"
+	"github.com/steve/llm-agents/internal/metrics"
 	"github.com/steve/llm-agents/internal/models"
 	mcptls "github.com/steve/llm-agents/internal/tls"
 	"github.com/steve/llm-agents/internal/utils"
@@ -24,49 +29,90 @@ type Handler interface {
 
 // Server represents an MCP server with optional TLS support
 type Server struct {
-	handlers   map[string]Handler
-	port       int
-	tlsPort    int
-	name       string
-	tlsConfig  *config.TLSConfig
-	tlsLoader  *mcptls.TLSLoader
-	httpServer *http.Server
-	tlsServer  *http.Server
-	mu         sync.RWMutex
-	started    bool
+	handlers      map[string]Handler
+	vhostHandlers map[string]map[string]Handler
+	port          int
+	tlsPort       int
+	name          string
+	tlsConfig     *config.TLSConfig
+	tlsLoader     *mcptls.TLSLoader
+	httpServer    *http.Server
+	tlsServer     *http.Server
+	connTracker   *connectionTracker
+	middleware    []Middleware
+	mu            sync.RWMutex
+	started       bool
+	reloadCancel  context.CancelFunc
+
+	methodSchemas map[string]MethodSchema
+	readyFunc     func() error
+	startedAt     time.Time
 }
 
 // NewServer creates a new MCP server
 func NewServer(name string, port int) *Server {
-	return &Server{
-		handlers: make(map[string]Handler),
-		port:     port,
-		name:     name,
+	s := &Server{
+		handlers:      make(map[string]Handler),
+		vhostHandlers: make(map[string]map[string]Handler),
+		port:          port,
+		name:          name,
+		connTracker:   newConnectionTracker(),
+		methodSchemas: make(map[string]MethodSchema),
 	}
+	s.registerDiscoveryHandler()
+	return s
 }
 
 // NewTLSServer creates a new MCP server with TLS support
 func NewTLSServer(name string, httpPort, tlsPort int, tlsConfig *config.TLSConfig) *Server {
 	server := &Server{
-		handlers:  make(map[string]Handler),
-		port:      httpPort,
-		tlsPort:   tlsPort,
-		name:      name,
-		tlsConfig: tlsConfig,
+		handlers:      make(map[string]Handler),
+		vhostHandlers: make(map[string]map[string]Handler),
+		port:          httpPort,
+		tlsPort:       tlsPort,
+		name:          name,
+		tlsConfig:     tlsConfig,
+		connTracker:   newConnectionTracker(),
+		methodSchemas: make(map[string]MethodSchema),
 	}
 
 	if tlsConfig != nil {
 		server.tlsLoader = mcptls.NewTLSLoader(tlsConfig)
 	}
 
+	server.registerDiscoveryHandler()
 	return server
 }
 
-// RegisterHandler registers a method handler
+// RegisterHandler registers a method handler shared by every virtual host
 func (s *Server) RegisterHandler(method string, handler Handler) {
 	s.handlers[method] = handler
 }
 
+// RegisterHandlerWithSchema is RegisterHandler plus the method's JSON
+// schemas for its params and result, so they travel with the handler
+// registration instead of living in separate, easily-stale documentation.
+// mcp.discover (see discovery.go) reports them back to callers that want to
+// bootstrap dynamically instead of hard-coding method names and shapes.
+func (s *Server) RegisterHandlerWithSchema(method string, handler Handler, paramsSchema, resultSchema interface{}) {
+	s.RegisterHandler(method, handler)
+	s.methodSchemas[method] = MethodSchema{
+		ParamsSchema: paramsSchema,
+		ResultSchema: resultSchema,
+	}
+}
+
+// RegisterVHostHandler registers a method handler that only applies to TLS
+// connections whose SNI ServerName matches vhost (see TLSConfig.AdditionalCerts).
+// A vhost with no handler registered for a given method falls back to the
+// server's shared handlers.
+func (s *Server) RegisterVHostHandler(vhost, method string, handler Handler) {
+	if _, ok := s.vhostHandlers[vhost]; !ok {
+		s.vhostHandlers[vhost] = make(map[string]Handler)
+	}
+	s.vhostHandlers[vhost][method] = handler
+}
+
 // Start starts the HTTP MCP server
 func (s *Server) Start() error {
 	s.mu.Lock()
@@ -78,15 +124,23 @@ func (s *Server) Start() error {
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/rpc", s.handleRPC)
+	mux.HandleFunc("/events", s.handleEvents)
+	mux.HandleFunc("/ws", s.handleWS)
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
 
 	s.httpServer = &http.Server{
 		Addr:         ":" + strconv.Itoa(s.port),
-		Handler:      mux,
+		Handler:      s.wrap(mux),
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 	}
 
 	s.started = true
+	if s.startedAt.IsZero() {
+		s.startedAt = time.Now()
+	}
 	utils.Info("[%s] HTTP MCP server starting on port %d", s.name, s.port)
 
 	go func() {
@@ -116,6 +170,18 @@ func (s *Server) StartTLS() error {
 		return fmt.Errorf("invalid TLS configuration: %w", err)
 	}
 
+	// Start the background cert/key/CA reloader before loading the TLS
+	// config, so LoadServerTLSConfig wires tlsConfig's GetCertificate and
+	// client-CA verification to the reloader's live state instead of a
+	// one-time read. Watched files changing on disk (or a SIGHUP) then
+	// rotates certs without a restart or dropping in-flight connections.
+	reloadCtx, cancel := context.WithCancel(context.Background())
+	if err := s.tlsLoader.StartReloader(reloadCtx); err != nil {
+		cancel()
+		return fmt.Errorf("failed to start certificate reloader: %w", err)
+	}
+	s.reloadCancel = cancel
+
 	// Load TLS configuration
 	tlsConfig, err := s.tlsLoader.LoadServerTLSConfig()
 	if err != nil {
@@ -124,15 +190,30 @@ func (s *Server) StartTLS() error {
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/rpc", s.handleTLSRPC)
+	mux.HandleFunc("/events", s.handleEvents)
+	mux.HandleFunc("/ws", s.handleWS)
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
 
 	s.tlsServer = &http.Server{
 		Addr:         ":" + strconv.Itoa(s.tlsPort),
-		Handler:      mux,
+		Handler:      s.wrap(mux),
 		TLSConfig:    tlsConfig,
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
+		ConnState:    s.connTracker.track(s.tlsLoader),
+		// Expose the *tls.Conn under "tls-conn" for handleTLSRPC and the
+		// tlsConnectionInfo-based middlewares (AuthMiddleware,
+		// AccessLogMiddleware).
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, "tls-conn", c)
+		},
 	}
 
+	if s.startedAt.IsZero() {
+		s.startedAt = time.Now()
+	}
 	utils.Info("[%s] HTTPS MCP server starting on port %d (TLS enabled)", s.name, s.tlsPort)
 
 	go func() {
@@ -144,6 +225,28 @@ func (s *Server) StartTLS() error {
 	return nil
 }
 
+// ReloadTLS forces an immediate reload of the TLS certificate and CA pool
+// from disk, independent of the reloader's fsnotify watcher, polling
+// ticker, or SIGHUP handling installed by StartTLS. It returns an error on
+// a server built without a TLS config; on a TLS server whose StartTLS
+// hasn't been called yet it reloads nothing and returns nil, since no
+// reloader is running. Existing connections are left
+// alone; only handshakes after the reload pick up the new material.
+func (s *Server) ReloadTLS() error {
+	s.mu.RLock()
+	loader := s.tlsLoader
+	s.mu.RUnlock()
+
+	if loader == nil {
+		return fmt.Errorf("TLS loader not initialized")
+	}
+	if err := loader.Reload(); err != nil {
+		return err
+	}
+	utils.Info("[%s] TLS certificates reloaded", s.name)
+	return nil
+}
+
 // StartBoth starts both HTTP and HTTPS servers
 func (s *Server) StartBoth() error {
 	// Start HTTP server
@@ -190,6 +293,11 @@ func (s *Server) Stop() error {
 		s.tlsServer = nil
 	}
 
+	if s.reloadCancel != nil {
+		s.reloadCancel()
+		s.reloadCancel = nil
+	}
+
 	s.started = false
 
 	if len(errors) > 0 {
@@ -200,8 +308,40 @@ func (s *Server) Stop() error {
 	return nil
 }
 
-// handleRPC handles JSON-RPC requests
+// rpcRequest is a single JSON-RPC 2.0 call. ID is a RequestID rather than an
+// int so a string or null id round-trips verbatim instead of being coerced
+// to 0, and so a request with no "id" member at all (a notification, per
+// spec) is distinguishable from one with an explicit "id": 0.
+type rpcRequest struct {
+	JSONRpc string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	ID      RequestID       `json:"id"`
+}
+
+// rpcResponse is a single JSON-RPC 2.0 response. A notification produces no
+// rpcResponse at all (see dispatch), so every response dispatch builds
+// echoes back the request's original ID. sendError's responses to a parse
+// error or a malformed batch carry the zero-value ID, which MarshalJSON
+// renders as null.
+type rpcResponse struct {
+	JSONRpc string           `json:"jsonrpc"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *models.MCPError `json:"error,omitempty"`
+	ID      RequestID        `json:"id"`
+}
+
+// handleRPC handles JSON-RPC requests using the server's shared handlers
 func (s *Server) handleRPC(w http.ResponseWriter, r *http.Request) {
+	s.handleRPCWithHandlers(w, r, s.handlers)
+}
+
+// handleRPCWithHandlers handles a JSON-RPC request by dispatching against the
+// given method table, so TLS connections can be routed to per-vhost handlers
+// while plain HTTP keeps using the shared ones. The request body may be a
+// single request object or a JSON-RPC 2.0 batch (an array of request
+// objects), per spec.
+func (s *Server) handleRPCWithHandlers(w http.ResponseWriter, r *http.Request, handlers map[string]Handler) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
@@ -209,71 +349,149 @@ func (s *Server) handleRPC(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
-	var request struct {
-		JSONRpc string          `json:"jsonrpc"`
-		Method  string          `json:"method"`
-		Params  json.RawMessage `json:"params"`
-		ID      int             `json:"id"`
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s.sendError(w, mcperrors.ErrParseError, "Parse error", nil)
+		return
 	}
+	body = bytes.TrimSpace(body)
 
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		s.sendError(w, -32700, "Parse error", 0)
+	if len(body) > 0 && body[0] == '[' {
+		s.handleBatch(r.Context(), w, body, handlers)
 		return
 	}
+	s.handleSingle(r.Context(), w, body, handlers)
+}
 
-	if request.JSONRpc != "2.0" {
-		s.sendError(w, -32600, "Invalid Request", request.ID)
+// handleSingle dispatches a single JSON-RPC request object and writes its
+// response, or no body at all if it was a notification.
+func (s *Server) handleSingle(ctx context.Context, w http.ResponseWriter, body []byte, handlers map[string]Handler) {
+	var req rpcRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		s.sendError(w, mcperrors.ErrParseError, "Parse error", nil)
 		return
 	}
 
-	handler, exists := s.handlers[request.Method]
-	if !exists {
-		s.sendError(w, -32601, "Method not found", request.ID)
+	resp := s.dispatch(ctx, req, handlers, nil)
+	if resp == nil {
+		w.WriteHeader(http.StatusNoContent)
 		return
 	}
+	json.NewEncoder(w).Encode(resp)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+// handleBatch dispatches every request in a JSON-RPC batch and writes back
+// the array of responses, omitting an entry for each notification. A batch
+// made up entirely of notifications produces no response body, per spec.
+func (s *Server) handleBatch(ctx context.Context, w http.ResponseWriter, body []byte, handlers map[string]Handler) {
+	var reqs []rpcRequest
+	if err := json.Unmarshal(body, &reqs); err != nil {
+		s.sendError(w, mcperrors.ErrParseError, "Parse error", nil)
+		return
+	}
+	if len(reqs) == 0 {
+		s.sendError(w, mcperrors.ErrInvalidRequest, "Invalid Request", nil)
+		return
+	}
 
-	result, err := handler.Handle(ctx, request.Params)
-	if err != nil {
-		s.sendError(w, -32603, err.Error(), request.ID)
+	responses := make([]*rpcResponse, 0, len(reqs))
+	for _, req := range reqs {
+		if resp := s.dispatch(ctx, req, handlers, nil); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
 		return
 	}
+	json.NewEncoder(w).Encode(responses)
+}
 
-	response := struct {
-		JSONRpc string      `json:"jsonrpc"`
-		Result  interface{} `json:"result"`
-		ID      int         `json:"id"`
-	}{
-		JSONRpc: "2.0",
-		Result:  result,
-		ID:      request.ID,
+// dispatch runs req against handlers and returns its rpcResponse, or nil if
+// req was a notification (no "id"), per spec: a notification's result or
+// error is never sent back, so a failure is only observable in the server's
+// own logs. If the resolved handler implements StreamingHandler and emit is
+// non-nil, its HandleStreaming is called with emit so it can push partial results
+// before returning its final one; emit is nil for the single-shot /rpc
+// transport, which has nowhere to deliver a partial.
+func (s *Server) dispatch(ctx context.Context, req rpcRequest, handlers map[string]Handler, emit func(interface{}) error) *rpcResponse {
+	metrics.IncRPCInFlight()
+	start := time.Now()
+	var resp *rpcResponse
+	defer func() {
+		code := 0
+		if resp != nil && resp.Error != nil {
+			code = resp.Error.Code
+		}
+		metrics.ObserveRPCRequest(req.Method, code, time.Since(start))
+		metrics.DecRPCInFlight()
+	}()
+
+	if req.JSONRpc != "2.0" {
+		resp = s.errorResponse(mcperrors.ErrInvalidRequest, "Invalid Request", req.ID)
+		return resp
+	}
+
+	handler, exists := handlers[req.Method]
+	if !exists {
+		resp = s.errorResponse(mcperrors.ErrMethodNotFound, "Method not found", req.ID)
+		return resp
 	}
 
-	json.NewEncoder(w).Encode(response)
+	callCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var result interface{}
+	var err error
+	if sh, ok := handler.(StreamingHandler); ok && emit != nil {
+		result, err = sh.HandleStreaming(callCtx, req.Params, emit)
+	} else {
+		result, err = handler.Handle(callCtx, req.Params)
+	}
+
+	if req.ID.IsNotification() {
+		if err != nil {
+			utils.Warn("[%s] notification %q failed: %v", s.name, req.Method, err)
+		}
+		return nil
+	}
+	if err != nil {
+		code, message, data := classifyError(err)
+		resp = s.errorResponseWithData(code, message, data, req.ID)
+		return resp
+	}
+	resp = &rpcResponse{JSONRpc: "2.0", Result: result, ID: req.ID}
+	return resp
+}
+
+// errorResponse builds the rpcResponse for a failed call.
+func (s *Server) errorResponse(code int, message string, id RequestID) *rpcResponse {
+	return s.errorResponseWithData(code, message, nil, id)
 }
 
-// sendError sends an error response
-func (s *Server) sendError(w http.ResponseWriter, code int, message string, id int) {
-	response := struct {
-		JSONRpc string           `json:"jsonrpc"`
-		Error   *models.MCPError `json:"error"`
-		ID      int              `json:"id"`
-	}{
+// errorResponseWithData is errorResponse plus a structured Data payload
+// (e.g. a *mcperrors.Error's RetryHint, or validation details from a
+// *ServerError), omitted from the wire entirely when nil.
+func (s *Server) errorResponseWithData(code int, message string, data json.RawMessage, id RequestID) *rpcResponse {
+	return &rpcResponse{
 		JSONRpc: "2.0",
-		Error: &models.MCPError{
-			Code:    code,
-			Message: message,
-		},
-		ID: id,
+		Error:   &models.MCPError{Code: code, Message: message, Data: data},
+		ID:      id,
 	}
+}
 
+// sendError writes a single JSON-RPC error response directly to w, for
+// failures (parse errors, malformed batches) that happen before a request ID
+// is even known.
+func (s *Server) sendError(w http.ResponseWriter, code int, message string, id RequestID) {
 	w.WriteHeader(http.StatusOK) // JSON-RPC errors are still HTTP 200
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(s.errorResponse(code, message, id))
 }
 
-// handleTLSRPC handles JSON-RPC requests over TLS with connection logging
+// handleTLSRPC handles JSON-RPC requests over TLS with connection logging,
+// routing to the vhost handler table for the SNI ServerName the client
+// requested (see RegisterVHostHandler), falling back to the shared handlers.
 func (s *Server) handleTLSRPC(w http.ResponseWriter, r *http.Request) {
 	// Log TLS connection information
 	if r.TLS != nil && s.tlsLoader != nil {
@@ -288,8 +506,27 @@ func (s *Server) handleTLSRPC(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Handle the request same as HTTP
-	s.handleRPC(w, r)
+	s.handleRPCWithHandlers(w, r, s.handlersForRequest(r))
+}
+
+// handlersForRequest returns the vhost-specific method table for r's SNI
+// ServerName, if one was registered via RegisterVHostHandler, and the shared
+// handlers otherwise.
+func (s *Server) handlersForRequest(r *http.Request) map[string]Handler {
+	if r.TLS != nil && r.TLS.ServerName != "" {
+		if vhost, ok := s.vhostHandlers[r.TLS.ServerName]; ok {
+			return vhost
+		}
+	}
+	return s.handlers
+}
+
+// GetConnectionInfo returns structured TLS connection info for remoteAddr,
+// either live (if the connection is still active) or from a short rolling
+// history of recently closed connections. Returns nil if remoteAddr was
+// never seen on the TLS listener.
+func (s *Server) GetConnectionInfo(remoteAddr string) *mcptls.TLSConnectionInfo {
+	return s.connTracker.get(remoteAddr)
 }
 
 // IsSecure returns true if the server has TLS enabled
@@ -324,14 +561,13 @@ func (s *Server) GetStatus() *ServerStatus {
 	defer s.mu.RUnlock()
 
 	return &ServerStatus{
-		ServerName: s.name,
-		HTTPPort:   s.port,
-		TLSPort:    s.tlsPort,
-		TLSEnabled: s.tlsConfig != nil,
-		Secure:     s.IsSecure(),
-		Started:    s.started,
-		// ActiveConns would require additional connection tracking
-		ActiveConns: 0,
+		ServerName:  s.name,
+		HTTPPort:    s.port,
+		TLSPort:     s.tlsPort,
+		TLSEnabled:  s.tlsConfig != nil,
+		Secure:      s.IsSecure(),
+		Started:     s.started,
+		ActiveConns: s.connTracker.activeCount(),
 	}
 }
 
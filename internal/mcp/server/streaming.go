@@ -0,0 +1,185 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/steve/llm-agents/internal/mcperrors"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// StreamingHandler lets a handler push partial results through emit before
+// returning its final one, for tool calls whose output arrives
+// incrementally (e.g. echo's chunked streaming mode). A handler only needs
+// to implement this in addition to Handler if it has partials to push; the
+// SSE (/events) and WebSocket (/ws) transports type-assert for it and fall
+// back to plain Handler.Handle otherwise. HandleStreaming is named
+// separately from Handler.Handle (rather than overloading "Handle" with a
+// third parameter) so one concrete type can implement both interfaces at
+// once - Go has no method overloading, so a shared name with different
+// signatures is never satisfiable by any type. The single-shot /rpc
+// transport has no channel to deliver a partial over, so it passes dispatch
+// a nil emit and dispatch calls plain Handler.Handle instead (see dispatch).
+type StreamingHandler interface {
+	HandleStreaming(ctx context.Context, params json.RawMessage, emit func(partial interface{}) error) (interface{}, error)
+}
+
+// wsUpgrader upgrades /ws connections. CheckOrigin always allows: MCP
+// clients are trusted backend services reached directly, not browser pages
+// subject to cross-origin concerns.
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	CheckOrigin:     func(r *http.Request) bool { return true },
+}
+
+// handleEvents serves Server-Sent Events at GET /events?method=...&params=...:
+// it runs one method call and streams each partial emitted by a
+// StreamingHandler as an "partial" event, followed by a final "result" or
+// "error" event once the call returns.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	method := r.URL.Query().Get("method")
+	handler, exists := s.handlersForRequest(r)[method]
+	if !exists {
+		http.Error(w, "method not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	emit := func(partial interface{}) error {
+		return writeSSEEvent(w, flusher, "partial", partial)
+	}
+
+	params := json.RawMessage(r.URL.Query().Get("params"))
+
+	var result interface{}
+	var err error
+	if sh, ok := handler.(StreamingHandler); ok {
+		result, err = sh.HandleStreaming(r.Context(), params, emit)
+	} else {
+		result, err = handler.Handle(r.Context(), params)
+	}
+
+	if err != nil {
+		_ = writeSSEEvent(w, flusher, "error", map[string]string{"error": err.Error()})
+		return
+	}
+	_ = writeSSEEvent(w, flusher, "result", result)
+}
+
+// writeSSEEvent writes one SSE frame and flushes it immediately, so the
+// client sees it as soon as it's written rather than once the handler's
+// response buffer fills.
+func writeSSEEvent(w http.ResponseWriter, flusher http.Flusher, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// wsPartialMessage is what handleWS sends over the socket for each partial
+// a StreamingHandler emits, ahead of the call's final rpcResponse.
+type wsPartialMessage struct {
+	JSONRpc string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Partial interface{} `json:"partial"`
+}
+
+// handleWS upgrades the connection to a WebSocket at /ws and serves
+// JSON-RPC requests (single or batch, same as /rpc) over it for the
+// connection's lifetime, so a long-lived MCP client can issue many calls
+// without reconnecting and can receive StreamingHandler partials pushed
+// out-of-band ahead of each call's final response.
+func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		utils.Warn("[%s] WebSocket upgrade failed: %v", s.name, err)
+		return
+	}
+	defer conn.Close()
+
+	handlers := s.handlersForRequest(r)
+	var writeMu sync.Mutex
+	writeJSON := func(v interface{}) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		return conn.WriteJSON(v)
+	}
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		message = bytes.TrimSpace(message)
+
+		if len(message) > 0 && message[0] == '[' {
+			s.handleWSBatch(r.Context(), message, handlers, writeJSON)
+			continue
+		}
+		s.handleWSSingle(r.Context(), message, handlers, writeJSON)
+	}
+}
+
+// handleWSSingle decodes and dispatches one request received over a
+// WebSocket connection, writing its response (if any) via writeJSON.
+func (s *Server) handleWSSingle(ctx context.Context, message []byte, handlers map[string]Handler, writeJSON func(interface{}) error) {
+	var req rpcRequest
+	if err := json.Unmarshal(message, &req); err != nil {
+		_ = writeJSON(s.errorResponse(mcperrors.ErrParseError, "Parse error", nil))
+		return
+	}
+
+	if resp := s.dispatch(ctx, req, handlers, wsEmit(req.Method, writeJSON)); resp != nil {
+		_ = writeJSON(resp)
+	}
+}
+
+// handleWSBatch decodes and dispatches a JSON-RPC batch received over a
+// WebSocket connection, writing back the array of responses (if any) via
+// writeJSON.
+func (s *Server) handleWSBatch(ctx context.Context, message []byte, handlers map[string]Handler, writeJSON func(interface{}) error) {
+	var reqs []rpcRequest
+	if err := json.Unmarshal(message, &reqs); err != nil {
+		_ = writeJSON(s.errorResponse(mcperrors.ErrParseError, "Parse error", nil))
+		return
+	}
+
+	responses := make([]*rpcResponse, 0, len(reqs))
+	for _, req := range reqs {
+		if resp := s.dispatch(ctx, req, handlers, wsEmit(req.Method, writeJSON)); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+	if len(responses) > 0 {
+		_ = writeJSON(responses)
+	}
+}
+
+// wsEmit returns the emit callback dispatch passes to a StreamingHandler
+// for method, wrapping each partial in a wsPartialMessage before writing it.
+func wsEmit(method string, writeJSON func(interface{}) error) func(interface{}) error {
+	return func(partial interface{}) error {
+		return writeJSON(wsPartialMessage{JSONRpc: "2.0", Method: method, Partial: partial})
+	}
+}
@@ -0,0 +1,245 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/steve/llm-agents/internal/authz"
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/diag"
+	"github.com/steve/llm-agents/internal/httpx"
+	httpserver "github.com/steve/llm-agents/internal/server"
+	mcptls "github.com/steve/llm-agents/internal/tls"
+	"github.com/steve/llm-agents/internal/tls/upgrade"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// ToolServer bundles an official-SDK mcp.Server with the HTTP/TLS bootstrap
+// that datetime-mcp, echo-mcp, and weather-mcp each used to hand-roll in
+// their own main.go: the /mcp StreamableHTTPHandler, client-cert identity
+// enforcement, request-ID/logging middleware, certificate rotation, an
+// optional STARTTLS-style upgrade.Listen for a single-port deployment, a
+// diag listener, and httpserver.Run's graceful-shutdown lifecycle. A caller
+// still owns its own tool handlers and env var parsing; ToolServer only
+// takes over the boilerplate that was identical across all three binaries.
+type ToolServer struct {
+	Name string
+
+	HTTPPort int
+	TLSPort  int
+
+	// TLSConfig, if non-nil, enables TLS: on TLSPort by default, or folded
+	// into HTTPPort instead when UpgradeMode is set.
+	TLSConfig   *config.TLSConfig
+	UpgradeMode bool
+
+	// AllowedClientCNs restricts which client certificate Subject CNs may
+	// call this server over mTLS; empty authorizes any caller whose
+	// certificate chains to the configured CA.
+	AllowedClientCNs []string
+
+	// AuthZ, if set, restricts each tool call to the SPIFFE roles the
+	// policy lists for that tool name, on top of whatever AllowedClientCNs
+	// already enforces. The caller's role comes from its client
+	// certificate's spiffe://llm-agents/<role>/<name> URI SAN (see
+	// tls.CertificateManager.GenerateClientCertForIdentity); a caller with
+	// no such URI, or whose role isn't listed for the tool, is rejected.
+	AuthZ *authz.Monitor
+
+	// DiagAddr, if set, serves /healthz, /readyz, /metrics, and
+	// /debug/pprof/* on a separate listener. DiagReady backs /readyz; nil
+	// means always ready.
+	DiagAddr  string
+	DiagReady diag.ReadyFunc
+
+	mcpServer *mcp.Server
+	mux       *http.ServeMux
+}
+
+// NewToolServer creates a ToolServer wrapping a fresh mcp.Server identified
+// as name/version. Tools are added to it with RegisterTool; extra HTTP
+// routes (e.g. /metrics) can be added with Handle before calling Run.
+func NewToolServer(name, version string) *ToolServer {
+	return &ToolServer{
+		Name:      name,
+		mcpServer: mcp.NewServer(&mcp.Implementation{Name: name, Version: version}, nil),
+		mux:       http.NewServeMux(),
+	}
+}
+
+// RegisterTool adds a tool to ts using the official SDK's generic AddTool,
+// so a cmd/*-mcp main.go only needs to import this package, not mcp itself,
+// to wire up a handler. If ts.AuthZ is set, the handler is wrapped to reject
+// callers whose client-certificate SPIFFE role isn't authorized for name
+// before it ever runs.
+func RegisterTool[Args, Result any](ts *ToolServer, name, description string, handler func(context.Context, *mcp.CallToolRequest, Args) (*mcp.CallToolResult, Result, error)) {
+	if ts.AuthZ != nil {
+		handler = authorizeTool(ts.AuthZ, name, handler)
+	}
+	mcp.AddTool(ts.mcpServer, &mcp.Tool{Name: name, Description: description}, handler)
+}
+
+// authorizeTool wraps handler so it's only invoked for callers whose client
+// certificate's SPIFFE role policy authorizes for toolName; everyone else
+// gets an IsError CallToolResult, the same shape a handler uses to report
+// its own failures, rather than a raw error that would surface as a
+// transport-level failure instead of a normal tool response.
+func authorizeTool[Args, Result any](monitor *authz.Monitor, toolName string, handler func(context.Context, *mcp.CallToolRequest, Args) (*mcp.CallToolResult, Result, error)) func(context.Context, *mcp.CallToolRequest, Args) (*mcp.CallToolResult, Result, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args Args) (*mcp.CallToolResult, Result, error) {
+		identity := httpx.ClientIdentityFromContext(ctx)
+		role := authz.RoleFromSPIFFEID(identity.SPIFFEID)
+		if !monitor.Allows(role, toolName) {
+			utils.Warn("authz: rejecting %s call from %s (role %q not authorized)", toolName, identity, role)
+			var zero Result
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("forbidden: role %q is not authorized to call %q", role, toolName)},
+				},
+			}, zero, nil
+		}
+		return handler(ctx, req, args)
+	}
+}
+
+// Handle registers an additional route on ts's MCP mux, alongside the /mcp
+// endpoint Run wires up.
+func (ts *ToolServer) Handle(pattern string, handler http.Handler) {
+	ts.mux.Handle(pattern, handler)
+}
+
+// Run builds the /mcp StreamableHTTPHandler (wrapped with client-cert
+// identity enforcement and request logging), obtains a server certificate
+// and starts the TLS listener if ts.TLSConfig is set (from the self-signed
+// CertificateManager, or an ACME directory if ts.TLSConfig.ACME.Enabled),
+// starts the diag listener if ts.DiagAddr is set, and blocks in
+// httpserver.Run until ctx is canceled, SIGINT/SIGTERM arrives, or a server
+// fails to start.
+func (ts *ToolServer) Run(ctx context.Context) error {
+	handler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
+		return ts.mcpServer
+	}, &mcp.StreamableHTTPOptions{JSONResponse: true})
+
+	identityHandler := httpx.ClientCertIdentity(ts.AllowedClientCNs, handler)
+	ts.mux.Handle("/mcp", httpx.RequestID(httpx.LoggingHandler(identityHandler, nil)))
+
+	httpSrv := &http.Server{
+		Addr:        fmt.Sprintf(":%d", ts.HTTPPort),
+		Handler:     ts.mux,
+		BaseContext: httpx.BaseContext(nil),
+	}
+	httpserver.ApplyDefaultTimeouts(httpSrv)
+
+	var httpsSrv *http.Server
+	var httpListener net.Listener
+	if ts.TLSConfig != nil {
+		var serverTLSConfig *tls.Config
+		var err error
+		if ts.TLSConfig.ACME != nil && ts.TLSConfig.ACME.Enabled {
+			serverTLSConfig, err = ts.acmeServerTLSConfig()
+		} else {
+			serverTLSConfig, err = ts.selfSignedServerTLSConfig(ctx)
+		}
+		if err != nil {
+			return err
+		}
+
+		if ts.UpgradeMode {
+			ln, err := net.Listen("tcp", httpSrv.Addr)
+			if err != nil {
+				return fmt.Errorf("failed to listen for TLS upgrade mode: %w", err)
+			}
+			httpListener = upgrade.Listen(ln, serverTLSConfig)
+			httpSrv.TLSConfig = serverTLSConfig
+			utils.Info("[%s] TLS upgrade mode enabled, serving HTTP and TLS on port %d", ts.Name, ts.HTTPPort)
+		} else {
+			httpsSrv = &http.Server{
+				Addr:        fmt.Sprintf(":%d", ts.TLSPort),
+				Handler:     ts.mux,
+				TLSConfig:   serverTLSConfig,
+				BaseContext: httpx.BaseContext(nil),
+			}
+			httpserver.ApplyDefaultTimeouts(httpsSrv)
+		}
+	}
+
+	var diagSrv *http.Server
+	if ts.DiagAddr != "" {
+		diagSrv = &http.Server{
+			Addr:        ts.DiagAddr,
+			Handler:     diag.NewMux(ts.DiagReady),
+			BaseContext: httpx.BaseContext(nil),
+		}
+		httpserver.ApplyDefaultTimeouts(diagSrv)
+		utils.Info("[%s] diagnostics listening on %s", ts.Name, ts.DiagAddr)
+	}
+
+	utils.Info("[%s] started with official SDK StreamableHTTPHandler", ts.Name)
+
+	return httpserver.Run(ctx,
+		httpserver.NamedServer{Name: "HTTP", Server: httpSrv, Listener: httpListener},
+		httpserver.NamedServer{Name: "HTTPS", Server: httpsSrv},
+		httpserver.NamedServer{Name: "Diag", Server: diagSrv},
+	)
+}
+
+// selfSignedServerTLSConfig starts certificate rotation and the
+// RenewalMonitor's metrics against ts.TLSConfig's local CA, returning a
+// server TLS config that serves the live, reloadable certificate.
+func (ts *ToolServer) selfSignedServerTLSConfig(ctx context.Context) (*tls.Config, error) {
+	tlsLoader := mcptls.NewTLSLoader(ts.TLSConfig)
+	renewer, err := mcptls.StartRotation(ctx, tlsLoader, ts.TLSConfig, ts.Name, mcptls.DefaultRotationCheckInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start certificate rotation: %w", err)
+	}
+
+	serverTLSConfig, err := tlsLoader.LoadServerTLSConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS config: %w", err)
+	}
+
+	renewalMonitor := mcptls.NewRenewalMonitor(mcptls.NewCertificateManager(ts.TLSConfig))
+	renewalMonitor.Track(ts.TLSConfig.ServerCert, renewer)
+	renewalMonitor.Start(ctx, mcptls.DefaultRotationCheckInterval)
+
+	return serverTLSConfig, nil
+}
+
+// acmeServerTLSConfig obtains ts's server certificate from the ACME
+// directory named in ts.TLSConfig.ACME instead of the self-signed
+// CertificateManager. For the http-01 challenge type, it mounts the
+// issuer's ChallengeHandler on ts's own mux, since that's the listener the
+// ACME directory reaches at this deployment's hostname on port 80 (or the
+// consolidated port in TLS_UPGRADE_MODE).
+func (ts *ToolServer) acmeServerTLSConfig() (*tls.Config, error) {
+	acmeCfg := ts.TLSConfig.ACME
+	if len(acmeCfg.Domains) == 0 {
+		return nil, fmt.Errorf("ACME is enabled but no domains are configured")
+	}
+
+	issuer, err := mcptls.NewACMEIssuer(context.Background(), ts.TLSConfig, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ACME issuer: %w", err)
+	}
+
+	if acmeCfg.ChallengeType == "http-01" {
+		ts.mux.Handle("/.well-known/acme-challenge/", issuer.ChallengeHandler())
+	}
+
+	certPEM, keyPEM, err := issuer.IssueServerCert(acmeCfg.Domains)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain ACME certificate: %w", err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse issued ACME certificate: %w", err)
+	}
+
+	utils.Info("[%s] serving ACME certificate for %v from %s", ts.Name, acmeCfg.Domains, acmeCfg.DirectoryURL)
+	return &tls.Config{Certificates: []tls.Certificate{cert}}, nil
+}
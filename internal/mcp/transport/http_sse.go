@@ -3,12 +3,18 @@ package transport
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	mathrand "math/rand"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -16,19 +22,98 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/metrics"
 	mcptls "github.com/steve/llm-agents/internal/tls"
 	"github.com/steve/llm-agents/internal/utils"
 )
 
+// sessionIDHeader is the MCP Streamable HTTP header a server uses to tell a
+// client which session its POSTs belong to, and a client echoes back on
+// every subsequent request so its responses and notifications land on the
+// right SSE stream instead of being broadcast to every connected client.
+const sessionIDHeader = "Mcp-Session-Id"
+
+// lastEventIDHeader is the standard SSE reconnection header: a client sets
+// it to the "id:" of the last frame it saw, so handleSSERequest can replay
+// anything it missed from that session's ring buffer instead of silently
+// dropping it.
+const lastEventIDHeader = "Last-Event-ID"
+
+// sseRingBufferSize bounds how many past events a session replays on
+// reconnect. Older events beyond this are assumed lost, same as any SSE
+// resumability window.
+const sseRingBufferSize = 256
+
+// sseHeartbeatInterval is how often handleSSERequest writes a ": ping"
+// comment to a connected stream, so load balancers and proxies that close
+// idle connections don't tear down a client that's simply waiting on its
+// next message.
+const sseHeartbeatInterval = 15 * time.Second
+
+// ReconnectPolicy controls how a client's long-lived GET stream to /mcp
+// reconnects once the response ends - from EOF, a network error, or a
+// non-2xx status - with capped exponential backoff and jitter, the same
+// shape as internal/mcp/client.ReconnectPolicy uses for its own reconnects.
+// MaxRetries of 0 means retry forever, matching a browser EventSource.
+type ReconnectPolicy struct {
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	Multiplier float64
+	Jitter     float64
+	MaxRetries int
+}
+
+// DefaultReconnectPolicy is what startSSEReader falls back to when the
+// transport has none configured: backoff from 500ms capped at 30 seconds,
+// and unlimited retries.
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   30 * time.Second,
+		Multiplier: 2.0,
+		Jitter:     0.2,
+		MaxRetries: 0,
+	}
+}
+
+// resolveReconnectPolicy returns *p, or DefaultReconnectPolicy if p is nil.
+func resolveReconnectPolicy(p *ReconnectPolicy) ReconnectPolicy {
+	if p != nil {
+		return *p
+	}
+	return DefaultReconnectPolicy()
+}
+
 // HTTPSSETransport implements MCP Streaming Protocol over HTTP with SSE support
 type HTTPSSETransport struct {
-	ServerURL   string
-	TLSConfig   *config.TLSConfig
-	tlsLoader   *mcptls.TLSLoader
-	httpClient  *http.Client
-	isClient    bool
-	serverPort  int
-	mu          sync.RWMutex
+	ServerURL       string
+	TLSConfig       *config.TLSConfig
+	tlsLoader       *mcptls.TLSLoader
+	certReloader    *mcptls.CertReloader
+	httpClient      *http.Client
+	isClient        bool
+	serverPort      int
+	auth            *AuthConfig
+	reconnectPolicy *ReconnectPolicy
+	mu              sync.RWMutex
+}
+
+// SetAuth enables OAuth 2.1 bearer-token authorization on t, composable
+// with whatever mTLS t already has configured. Call before Connect; a
+// nil cfg (the default) leaves t open to any mTLS-authorized peer.
+func (t *HTTPSSETransport) SetAuth(cfg *AuthConfig) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.auth = cfg
+}
+
+// SetReconnectPolicy overrides how t's client-mode SSE reader reconnects
+// after its stream to /mcp ends. Call before Connect; nil (the default)
+// uses DefaultReconnectPolicy. Has no effect on server-mode transports.
+func (t *HTTPSSETransport) SetReconnectPolicy(policy *ReconnectPolicy) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reconnectPolicy = policy
 }
 
 // NewClientTransport creates a new HTTP/SSE transport for MCP clients
@@ -65,6 +150,32 @@ func NewClientTransport(serverURL string, tlsConfig *config.TLSConfig) *HTTPSSET
 	return transport
 }
 
+// NewClientTransportWithReloader creates an HTTP/SSE client transport whose
+// TLS configuration is backed by a CertReloader, so a CA or leaf certificate
+// rotated on disk takes effect on the next connection without restarting
+// the process.
+func NewClientTransportWithReloader(serverURL string, tlsConfig *config.TLSConfig, reloader *mcptls.CertReloader) *HTTPSSETransport {
+	transport := &HTTPSSETransport{
+		ServerURL:    serverURL,
+		TLSConfig:    tlsConfig,
+		certReloader: reloader,
+		isClient:     true,
+	}
+
+	transport.httpClient = &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:     reloader.TLSConfig(tlsConfig.MinTLSVersion),
+			MaxIdleConns:        10,
+			MaxIdleConnsPerHost: 5,
+			IdleConnTimeout:     30 * time.Second,
+		},
+		Timeout: 30 * time.Second,
+	}
+	utils.Info("MCP HTTP/SSE client transport created with hot-reloadable mTLS")
+
+	return transport
+}
+
 // NewServerTransport creates a new HTTP/SSE transport for MCP servers
 func NewServerTransport(port int, tlsConfig *config.TLSConfig) *HTTPSSETransport {
 	transport := &HTTPSSETransport{
@@ -83,6 +194,21 @@ func NewServerTransport(port int, tlsConfig *config.TLSConfig) *HTTPSSETransport
 	return transport
 }
 
+// NewServerTransportWithReloader creates an HTTP/SSE server transport whose
+// TLS configuration is backed by a CertReloader instead of a static
+// *tls.Config built at startup.
+func NewServerTransportWithReloader(port int, tlsConfig *config.TLSConfig, reloader *mcptls.CertReloader) *HTTPSSETransport {
+	transport := &HTTPSSETransport{
+		TLSConfig:    tlsConfig,
+		certReloader: reloader,
+		isClient:     false,
+		serverPort:   port,
+	}
+
+	utils.Info("MCP HTTP/SSE server transport created with hot-reloadable mTLS on port %d", port)
+	return transport
+}
+
 // Connect implements the mcp.Transport interface
 func (t *HTTPSSETransport) Connect(ctx context.Context) (mcp.Connection, error) {
 	t.mu.Lock()
@@ -122,12 +248,14 @@ func (t *HTTPSSETransport) connectServer(ctx context.Context) (mcp.Connection, e
 	utils.Info("Starting MCP server HTTP/SSE transport on port %d", t.serverPort)
 
 	conn := &HTTPSSEConnection{
-		transport:    t,
-		isClient:     false,
-		messageQueue: make(chan jsonrpc.Message, 100),
-		closeSignal:  make(chan struct{}),
-		sessionID:    fmt.Sprintf("server-%d", time.Now().UnixNano()),
-		clients:      make(map[string]*SSEClient),
+		transport:       t,
+		isClient:        false,
+		messageQueue:    make(chan jsonrpc.Message, 100),
+		closeSignal:     make(chan struct{}),
+		sessionID:       fmt.Sprintf("server-%d", time.Now().UnixNano()),
+		sessions:        make(map[string]*mcpSession),
+		pendingByID:     make(map[string]string),
+		responseWaiters: make(map[string]chan []byte),
 	}
 
 	// Start HTTP server with SSE support
@@ -141,25 +269,254 @@ func (t *HTTPSSETransport) connectServer(ctx context.Context) (mcp.Connection, e
 
 // HTTPSSEConnection implements mcp.Connection for HTTP/SSE transport
 type HTTPSSEConnection struct {
-	transport    *HTTPSSETransport
-	isClient     bool
-	serverURL    string
-	httpClient   *http.Client
-	httpServer   *http.Server
-	messageQueue chan jsonrpc.Message
-	closeSignal  chan struct{}
-	sessionID    string
-	clients      map[string]*SSEClient // For server mode
-	mu           sync.RWMutex
-	closed       bool
-}
-
-// SSEClient represents a connected SSE client
+	transport       *HTTPSSETransport
+	isClient        bool
+	serverURL       string
+	httpClient      *http.Client
+	httpServer      *http.Server
+	messageQueue    chan jsonrpc.Message
+	closeSignal     chan struct{}
+	sessionID       string
+	sessions        map[string]*mcpSession // For server mode, keyed by Mcp-Session-Id
+	pendingByID     map[string]string      // JSON-RPC request id -> owning session, for routing responses
+	responseWaiters map[string]chan []byte // JSON-RPC request id -> in-flight /mcp POST awaiting its response
+	mu              sync.RWMutex
+	closed          bool
+}
+
+// sseSendQueueSize bounds how many pending frames an SSEClient's writer
+// goroutine will buffer before the client is considered a stalled consumer
+// and evicted, rather than let a slow reader block writeServerMessage for
+// every other client indefinitely.
+const sseSendQueueSize = 32
+
+// sseWriteDeadline bounds how long a single frame write to a client may
+// block, so a client whose TCP stack is accepting bytes but never draining
+// them can't stall its writer goroutine forever either.
+const sseWriteDeadline = 5 * time.Second
+
+// SSEClient represents a connected SSE client. Frames are handed to it via
+// enqueue and written by a dedicated writeLoop goroutine, so the
+// http.ResponseWriter (which is not safe for concurrent use) is only ever
+// touched from that one goroutine, and a slow or stuck client is bounded by
+// sseSendQueueSize/sseWriteDeadline instead of blocking the caller.
 type SSEClient struct {
 	Writer   http.ResponseWriter
 	Flusher  http.Flusher
 	Request  *http.Request
 	ClientID string
+
+	cancel context.CancelFunc
+
+	// mu guards queue/closed: enqueue is called concurrently from deliver
+	// (possibly several broadcasts in flight at once) and the heartbeat
+	// ticker, while evict can run from writeLoop (on a write failure) or
+	// handleSSERequest's goroutine (on disconnect/shutdown) - a bare
+	// channel close races any of those sends, panicking with "send on
+	// closed channel". Every send and the close itself happen under mu, so
+	// a send can never be in flight (or start) once closed is true.
+	mu     sync.Mutex
+	queue  chan []byte
+	closed bool
+	done   chan struct{}
+}
+
+// newSSEClient starts client's writer goroutine and registers it with the
+// connected-clients gauge. cancel tears down the http.Request's context on
+// eviction, which unblocks handleSSERequest's select loop. done is closed
+// once writeLoop exits, for a caller (like streamResponse) that needs to
+// know the client's buffered frames have all been flushed before it tears
+// down the underlying http.ResponseWriter.
+func newSSEClient(w http.ResponseWriter, flusher http.Flusher, r *http.Request, clientID string, cancel context.CancelFunc) *SSEClient {
+	c := &SSEClient{
+		Writer:   w,
+		Flusher:  flusher,
+		Request:  r,
+		ClientID: clientID,
+		cancel:   cancel,
+		queue:    make(chan []byte, sseSendQueueSize),
+		done:     make(chan struct{}),
+	}
+	metrics.IncSSEClients()
+	go c.writeLoop()
+	return c
+}
+
+// enqueue hands frame to the client's writer goroutine, evicting the client
+// instead of blocking if its queue has stayed full past sseSendQueueSize -
+// i.e. it isn't draining frames fast enough to keep up. Holding mu across
+// the send keeps it mutually exclusive with evict's close, so this never
+// races a concurrent eviction into a "send on closed channel" panic.
+func (c *SSEClient) enqueue(frame []byte) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+
+	select {
+	case c.queue <- frame:
+		c.mu.Unlock()
+		metrics.SetSSEQueueDepth(len(c.queue))
+	default:
+		c.mu.Unlock()
+		metrics.IncSSEDroppedFrames()
+		utils.Warn("SSE client %s send queue full, evicting stalled consumer", c.ClientID)
+		c.evict()
+	}
+}
+
+// writeLoop pulls frames off the client's queue and writes them to its
+// http.ResponseWriter, bounding each write with sseWriteDeadline so a
+// connection that accepts bytes but never reads a response can't stall this
+// goroutine forever. It exits once the queue is closed (by evict) or a write
+// fails.
+func (c *SSEClient) writeLoop() {
+	defer close(c.done)
+	defer metrics.DecSSEClients()
+
+	rc := http.NewResponseController(c.Writer)
+	for frame := range c.queue {
+		if err := rc.SetWriteDeadline(time.Now().Add(sseWriteDeadline)); err != nil {
+			utils.Debug("SSE client %s: set write deadline: %v", c.ClientID, err)
+		}
+		if _, err := c.Writer.Write(frame); err != nil {
+			utils.Warn("SSE client %s: write failed, evicting: %v", c.ClientID, err)
+			c.evict()
+			return
+		}
+		c.Flusher.Flush()
+	}
+}
+
+// evict cancels the client's request context, unblocking handleSSERequest's
+// select loop, and closes its send queue so writeLoop exits. Safe to call
+// more than once (e.g. concurrently from enqueue and writeLoop) and safe to
+// race a concurrent enqueue, since both take mu before touching queue.
+func (c *SSEClient) evict() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.cancel()
+	close(c.queue)
+}
+
+// mcpSession tracks one MCP session's SSE subscriber (if currently
+// connected) plus a bounded ring buffer of the frames most recently sent to
+// it, so a client that reconnects with Last-Event-ID replays what it missed
+// instead of the server silently dropping it.
+type mcpSession struct {
+	id       string
+	mu       sync.Mutex
+	client   *SSEClient
+	nextSeq  uint64
+	buffer   []sseEvent
+}
+
+// sseEvent is one buffered "data:" frame tagged with the monotonically
+// increasing "id:" it was sent under.
+type sseEvent struct {
+	id   uint64
+	data []byte
+}
+
+// newMCPSession creates an empty session record for id, with no SSE
+// subscriber attached yet.
+func newMCPSession(id string) *mcpSession {
+	return &mcpSession{id: id}
+}
+
+// record appends data to the session's ring buffer under the next sequence
+// number and returns the frame, trimming the buffer to sseRingBufferSize so
+// memory doesn't grow unbounded for a long-lived session.
+func (s *mcpSession) record(data []byte) sseEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextSeq++
+	evt := sseEvent{id: s.nextSeq, data: data}
+	s.buffer = append(s.buffer, evt)
+	if len(s.buffer) > sseRingBufferSize {
+		s.buffer = s.buffer[len(s.buffer)-sseRingBufferSize:]
+	}
+	return evt
+}
+
+// replay returns the buffered events with id greater than lastEventID, for
+// handleSSERequest to resend to a client reconnecting with Last-Event-ID.
+func (s *mcpSession) replay(lastEventID uint64) []sseEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]sseEvent, 0, len(s.buffer))
+	for _, evt := range s.buffer {
+		if evt.id > lastEventID {
+			out = append(out, evt)
+		}
+	}
+	return out
+}
+
+// attach sets client as the session's current SSE subscriber, replacing any
+// previous one (a reconnect supersedes the stream it's resuming).
+func (s *mcpSession) attach(client *SSEClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = client
+}
+
+// detach clears the session's SSE subscriber if it is still client, leaving
+// it in place otherwise (a newer reconnect has already taken over).
+func (s *mcpSession) detach(client *SSEClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client == client {
+		s.client = nil
+	}
+}
+
+// subscriber returns the session's current SSE client, or nil if none is
+// connected right now.
+func (s *mcpSession) subscriber() *SSEClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.client
+}
+
+// newSessionID returns a random 16-character hex session ID, falling back
+// to a timestamp if the system RNG is unavailable.
+func newSessionID() string {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return fmt.Sprintf("session-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf[:])
+}
+
+// jsonrpcRequestID extracts the "id" field from a raw JSON-RPC message
+// without depending on the SDK's internal ID representation, returning ok
+// == false for notifications (no id) or a malformed payload.
+func jsonrpcRequestID(raw []byte) (string, bool) {
+	var envelope struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil || len(envelope.ID) == 0 || string(envelope.ID) == "null" {
+		return "", false
+	}
+	return string(envelope.ID), true
+}
+
+// jsonrpcMethod extracts the "method" field from a raw JSON-RPC message, for
+// attaching to a log line - empty for a response, which carries no method.
+func jsonrpcMethod(raw []byte) string {
+	var envelope struct {
+		Method string `json:"method"`
+	}
+	json.Unmarshal(raw, &envelope)
+	return envelope.Method
 }
 
 // Read implements mcp.Connection.Read
@@ -196,55 +553,135 @@ func (c *HTTPSSEConnection) writeClientMessage(ctx context.Context, msg jsonrpc.
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	// Use POST for client-to-server messages
-	req, err := http.NewRequestWithContext(ctx, "POST", c.serverURL+"/mcp", strings.NewReader(string(jsonData)))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.serverURL+"/mcp", strings.NewReader(string(jsonData)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		req.Header.Set(sessionIDHeader, c.sessionID)
+		return req, nil
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json, text/event-stream")
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := doWithBearerAuth(ctx, c.transport.auth, c.httpClient, newRequest)
 	if err != nil {
 		return fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		// msg was a notification - the server never replies to those, so
+		// there's nothing left to read back.
+		return nil
+	case http.StatusOK:
+	default:
 		return fmt.Errorf("server responded with status %d", resp.StatusCode)
 	}
 
+	if strings.Contains(resp.Header.Get("Content-Type"), "text/event-stream") {
+		// The server upgraded its reply into a one-shot stream so it could
+		// interleave progress notifications ahead of the final response;
+		// consume it exactly like the long-lived GET stream does. This
+		// exchange never reconnects on its own - the POST itself failed if
+		// the stream breaks - so a throwaway state is fine here.
+		return c.consumeEventStream(resp.Body, &eventSourceState{})
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	reply, err := jsonrpc.DecodeMessage(body)
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	select {
+	case c.messageQueue <- reply:
+	case <-c.closeSignal:
+	}
 	return nil
 }
 
-// writeServerMessage sends message from server to client via SSE
+// writeServerMessage delivers message to whichever exchange is waiting for
+// it: a response or an error is first offered to the Streamable HTTP POST
+// that's still holding its response open for this id (see
+// handleMCPPost/responseWaiters); if nothing is listening anymore (the
+// handler already timed out or its request context was cancelled), it
+// falls back to the SSE stream of the session that issued the request, same
+// as before Streamable HTTP. Anything without an id (a notification) goes
+// out to every session, since nothing in the JSON-RPC envelope names a
+// single target for it.
 func (c *HTTPSSEConnection) writeServerMessage(ctx context.Context, msg jsonrpc.Message) error {
 	jsonData, err := jsonrpc.EncodeMessage(msg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
+	if id, ok := jsonrpcRequestID(jsonData); ok {
+		c.mu.Lock()
+		waiter, hasWaiter := c.responseWaiters[id]
+		delete(c.responseWaiters, id)
+		sessionID, found := c.pendingByID[id]
+		delete(c.pendingByID, id)
+		session := c.sessions[sessionID]
+		c.mu.Unlock()
+
+		if hasWaiter {
+			select {
+			case waiter <- jsonData:
+				return nil
+			default:
+				// The handler isn't receiving anymore - fall through to
+				// the session's persistent stream instead of dropping it.
+			}
+		}
+
+		if !found || session == nil {
+			utils.Warn("No session found for response id %s, dropping message", id)
+			return nil
+		}
+		c.deliver(session, jsonData)
+		return nil
+	}
+
 	c.mu.RLock()
-	clients := make([]*SSEClient, 0, len(c.clients))
-	for _, client := range c.clients {
-		clients = append(clients, client)
+	sessions := make([]*mcpSession, 0, len(c.sessions))
+	for _, session := range c.sessions {
+		sessions = append(sessions, session)
 	}
 	c.mu.RUnlock()
 
-	// Send to all connected SSE clients
-	for _, client := range clients {
-		sseData := fmt.Sprintf("data: %s\n\n", string(jsonData))
-		if _, err := fmt.Fprint(client.Writer, sseData); err != nil {
-			utils.Error("Failed to write SSE data to client %s: %v", client.ClientID, err)
-			continue
-		}
-		client.Flusher.Flush()
+	for _, session := range sessions {
+		c.deliver(session, jsonData)
 	}
 
 	return nil
 }
 
+// deliver records data in session's ring buffer under the next event id and,
+// if session currently has an SSE subscriber, writes it out immediately.
+// Recording always happens, even with no subscriber connected, so a client
+// that reconnects moments later can resume via Last-Event-ID instead of
+// losing the message.
+func (c *HTTPSSEConnection) deliver(session *mcpSession, data []byte) {
+	evt := session.record(data)
+
+	client := session.subscriber()
+	if client == nil {
+		return
+	}
+	client.enqueue(formatSSEFrame(evt))
+}
+
+// formatSSEFrame renders one "id:"/"data:" SSE frame for evt.
+func formatSSEFrame(evt sseEvent) []byte {
+	return []byte(fmt.Sprintf("id: %d\ndata: %s\n\n", evt.id, evt.data))
+}
+
 // Close implements mcp.Connection.Close
 func (c *HTTPSSEConnection) Close() error {
 	c.mu.Lock()
@@ -273,84 +710,316 @@ func (c *HTTPSSEConnection) SessionID() string {
 	return c.sessionID
 }
 
-// startSSEReader starts reading SSE events for client mode
+// sseMaxLineLength bounds how long a single line within an SSE frame may be
+// before readEventSourceStream gives up on the stream (triggering a
+// reconnect) instead of bufio.Scanner's behavior of silently dropping
+// anything past a fixed 64KiB token size.
+const sseMaxLineLength = 1 << 20 // 1 MiB
+
+// eventSourceMessage is one message dispatched by readEventSourceStream,
+// assembled per the WHATWG EventSource algorithm: zero or more "data:"
+// lines joined with "\n", plus whatever "event:"/"id:" fields preceded the
+// blank line that terminated it.
+type eventSourceMessage struct {
+	id    string
+	event string
+	data  string
+}
+
+// eventSourceState carries SSE reconnection state across stream attempts:
+// the last event id seen, echoed back as Last-Event-ID so the server can
+// replay anything sent while disconnected, and the delay the server last
+// requested via a "retry:" field.
+type eventSourceState struct {
+	lastEventID string
+	retry       time.Duration
+}
+
+// startSSEReader opens the long-lived GET stream at /mcp for client mode
+// and, once the first connection succeeds, hands it to a background
+// goroutine that reconnects with backoff+jitter per c.transport's
+// ReconnectPolicy whenever the stream ends - from EOF, a network error, or
+// a non-2xx status - resuming from the last event id seen each time so the
+// server can replay what was missed.
 func (c *HTTPSSEConnection) startSSEReader(ctx context.Context) error {
-	req, err := http.NewRequestWithContext(ctx, "GET", c.serverURL+"/sse", nil)
+	state := &eventSourceState{}
+
+	resp, err := c.openEventStream(ctx, state.lastEventID)
 	if err != nil {
-		return fmt.Errorf("failed to create SSE request: %w", err)
+		return err
 	}
 
-	req.Header.Set("Accept", "text/event-stream")
-	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Connection", "keep-alive")
+	go c.runEventSourceLoop(ctx, resp, state, resolveReconnectPolicy(c.transport.reconnectPolicy))
+	return nil
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to connect to SSE stream: %w", err)
+// openEventStream issues the GET to /mcp that opens (or, given a non-empty
+// lastEventID, resumes) the server-push stream. It sets the connection's
+// session id on the request so this stream and the client's POSTs land on
+// the same server-side session.
+func (c *HTTPSSEConnection) openEventStream(ctx context.Context, lastEventID string) (*http.Response, error) {
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", c.serverURL+"/mcp", nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SSE request: %w", err)
+		}
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set("Cache-Control", "no-cache")
+		req.Header.Set("Connection", "keep-alive")
+		req.Header.Set(sessionIDHeader, c.sessionID)
+		if lastEventID != "" {
+			req.Header.Set(lastEventIDHeader, lastEventID)
+		}
+		return req, nil
 	}
 
+	resp, err := doWithBearerAuth(ctx, c.transport.auth, c.httpClient, newRequest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to SSE stream: %w", err)
+	}
 	if resp.StatusCode != http.StatusOK {
 		resp.Body.Close()
-		return fmt.Errorf("SSE endpoint responded with status %d", resp.StatusCode)
+		return nil, fmt.Errorf("SSE endpoint responded with status %d", resp.StatusCode)
 	}
+	return resp, nil
+}
 
-	go func() {
-		defer resp.Body.Close()
-		scanner := bufio.NewScanner(resp.Body)
-
-		for scanner.Scan() {
-			line := strings.TrimSpace(scanner.Text())
-			if strings.HasPrefix(line, "data: ") {
-				data := strings.TrimPrefix(line, "data: ")
-				if data == "" {
-					continue
-				}
+// runEventSourceLoop consumes resp, the already-open stream from
+// startSSEReader, and keeps reconnecting through openEventStream per policy
+// - honoring a server-supplied "retry:" delay over the computed backoff
+// when one was sent - until ctx is done, the connection closes, or
+// policy.MaxRetries is exhausted.
+func (c *HTTPSSEConnection) runEventSourceLoop(ctx context.Context, resp *http.Response, state *eventSourceState, policy ReconnectPolicy) {
+	delay := policy.BaseDelay
+	attempt := 0
+
+	for {
+		err := c.consumeEventStream(resp.Body, state)
+		resp.Body.Close()
+		if err != nil {
+			utils.Warn("SSE stream ended with error, reconnecting: %v", err)
+		}
 
-				msg, err := jsonrpc.DecodeMessage([]byte(data))
-				if err != nil {
-					utils.Error("Failed to parse SSE message: %v", err)
-					continue
-				}
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.closeSignal:
+			return
+		default:
+		}
+
+		for {
+			attempt++
+			if policy.MaxRetries > 0 && attempt > policy.MaxRetries {
+				utils.Error("SSE stream exhausted %d reconnect attempt(s), giving up", policy.MaxRetries)
+				return
+			}
+
+			wait := state.retry
+			if wait <= 0 {
+				wait = jitteredDelay(delay, policy.Jitter)
+			}
+
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			case <-c.closeSignal:
+				return
+			}
 
-				select {
-				case c.messageQueue <- msg:
-				case <-c.closeSignal:
-					return
+			next, connErr := c.openEventStream(ctx, state.lastEventID)
+			if connErr != nil {
+				utils.Warn("SSE reconnect attempt %d failed: %v", attempt, connErr)
+				delay = nextBackoffDelay(delay, policy)
+				continue
+			}
+
+			resp = next
+			delay = policy.BaseDelay
+			break
+		}
+	}
+}
+
+// nextBackoffDelay advances d by policy.Multiplier, capped at
+// policy.MaxDelay.
+func nextBackoffDelay(d time.Duration, policy ReconnectPolicy) time.Duration {
+	d = time.Duration(float64(d) * policy.Multiplier)
+	if d > policy.MaxDelay {
+		d = policy.MaxDelay
+	}
+	return d
+}
+
+// jitteredDelay returns d adjusted by +/- a random fraction up to jitter
+// (e.g. jitter=0.2 returns a value within 20% of d in either direction).
+func jitteredDelay(d time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return d
+	}
+	delta := (mathrand.Float64()*2 - 1) * jitter
+	return time.Duration(float64(d) * (1 + delta))
+}
+
+// consumeEventStream parses body per readEventSourceStream, decoding each
+// dispatched message's data as a JSON-RPC message and queueing it for Read.
+// state.lastEventID/state.retry are updated as events arrive so a caller
+// driving a reconnect loop around this can resume where the stream left
+// off. Returns nil once body reaches EOF - the ordinary way such a stream
+// ends - or the error that ended it otherwise.
+func (c *HTTPSSEConnection) consumeEventStream(body io.Reader, state *eventSourceState) error {
+	return c.readEventSourceStream(body, state, func(msg eventSourceMessage) bool {
+		if msg.data == "" {
+			return true
+		}
+
+		rpcMsg, err := jsonrpc.DecodeMessage([]byte(msg.data))
+		if err != nil {
+			utils.Error("Failed to parse SSE message: %v", err)
+			return true
+		}
+
+		select {
+		case c.messageQueue <- rpcMsg:
+			return true
+		case <-c.closeSignal:
+			return false
+		}
+	})
+}
+
+// readEventSourceStream parses body following the WHATWG EventSource
+// algorithm: a line starting with ":" is a comment and ignored; a
+// "field: value" line accumulates onto the message in progress, with
+// repeated "data:" lines joined by "\n"; and a blank line dispatches
+// whatever accumulated via dispatch, which returns false to stop reading
+// early (e.g. because the connection is closing). id and retry fields
+// update state as they're seen, rather than only once dispatch fires, so a
+// stream that's cut off mid-message still leaves state.lastEventID usable
+// for the next reconnect. Returns nil on a clean EOF or when dispatch stops
+// the read, or an error if reading fails or a single line exceeds
+// sseMaxLineLength.
+func (c *HTTPSSEConnection) readEventSourceStream(body io.Reader, state *eventSourceState, dispatch func(eventSourceMessage) bool) error {
+	reader := bufio.NewReaderSize(body, sseMaxLineLength)
+
+	var data strings.Builder
+	var event, id string
+	var haveFields bool
+
+	for {
+		line, err := readSSELine(reader)
+
+		switch {
+		case line == "" && err == nil:
+			if haveFields {
+				msg := eventSourceMessage{
+					id:    id,
+					event: event,
+					data:  strings.TrimSuffix(data.String(), "\n"),
+				}
+				data.Reset()
+				event, id, haveFields = "", "", false
+				if !dispatch(msg) {
+					return nil
+				}
+			}
+		case strings.HasPrefix(line, ":"):
+			// comment, often used as a heartbeat - ignored
+		case line != "":
+			haveFields = true
+			field, value := splitSSEField(line)
+			switch field {
+			case "data":
+				data.WriteString(value)
+				data.WriteByte('\n')
+			case "event":
+				event = value
+			case "id":
+				id = value
+				state.lastEventID = value
+			case "retry":
+				if ms, convErr := strconv.Atoi(value); convErr == nil {
+					state.retry = time.Duration(ms) * time.Millisecond
 				}
 			}
 		}
 
-		if err := scanner.Err(); err != nil {
-			utils.Error("SSE scanner error: %v", err)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
 		}
-	}()
+	}
+}
 
-	return nil
+// readSSELine reads one line, with its trailing CRLF/LF stripped, from
+// reader. It returns an error if the line exceeds sseMaxLineLength instead
+// of silently truncating it the way bufio.Scanner's fixed 64KiB token limit
+// would. A final line with no trailing newline (the stream ended right
+// after it) is still returned, paired with the io.EOF that ended it.
+func readSSELine(reader *bufio.Reader) (string, error) {
+	slice, err := reader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return "", fmt.Errorf("SSE line exceeds %d bytes", sseMaxLineLength)
+	}
+	line := strings.TrimRight(string(slice), "\r\n")
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return line, err
+}
+
+// splitSSEField splits an SSE "field: value" line on its first colon, per
+// the EventSource spec trimming exactly one leading space off the value. A
+// line with no colon is the field name with an empty value.
+func splitSSEField(line string) (field, value string) {
+	colon := strings.IndexByte(line, ':')
+	if colon == -1 {
+		return line, ""
+	}
+	return line[:colon], strings.TrimPrefix(line[colon+1:], " ")
 }
 
 // startHTTPServer starts HTTP server with SSE support for server mode
 func (c *HTTPSSEConnection) startHTTPServer(ctx context.Context) error {
 	mux := http.NewServeMux()
 
-	// MCP endpoint for receiving messages
-	mux.HandleFunc("/mcp", c.handleMCPRequest)
+	// /mcp is the single Streamable HTTP endpoint (2025-03-26): POST
+	// delivers a message and replies in-exchange, GET opens the long-lived
+	// server-push stream, DELETE ends a session. /sse is kept pointed at
+	// the same GET handler purely for legacy 2024-11-05 clients that still
+	// open it directly instead of discovering everything through /mcp.
+	// Both are gated behind the transport's bearer-token check (a no-op
+	// wrapper if c.transport.auth doesn't enable server-side enforcement).
+	mux.HandleFunc("/mcp", requireBearerAuth(c.transport.auth, c.handleMCPRequest))
+	mux.HandleFunc("/sse", requireBearerAuth(c.transport.auth, c.handleSSERequest))
+
+	if c.transport.auth.serverEnabled() {
+		mux.HandleFunc("/.well-known/oauth-protected-resource", handleProtectedResourceMetadata(c.transport.auth))
+	}
 
-	// SSE endpoint for sending messages to clients
-	mux.HandleFunc("/sse", c.handleSSERequest)
+	// Prometheus endpoint covering this transport's SSE fan-out (connected
+	// clients, dropped frames, queue depth) alongside the package's other
+	// instruments, so operators can see the fan-out degrade under load.
+	mux.Handle("/metrics", metrics.Handler())
 
 	var tlsConfig *tls.Config
-	if c.transport.TLSConfig != nil {
+	if c.transport.certReloader != nil {
+		tlsConfig = c.transport.certReloader.TLSConfig(c.transport.TLSConfig.MinTLSVersion)
+	} else if c.transport.TLSConfig != nil {
 		var err error
 		tlsConfig, err = c.transport.tlsLoader.LoadServerTLSConfig()
 		if err != nil {
 			return fmt.Errorf("failed to load TLS config: %w", err)
 		}
+		applyMTLSState(tlsConfig, c.transport.TLSConfig.MTLSState)
 	}
 
 	c.httpServer = &http.Server{
 		Addr:         fmt.Sprintf(":%d", c.transport.serverPort),
 		Handler:      mux,
-		TLSConfig:    tlsConfig,
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
 	}
@@ -358,7 +1027,15 @@ func (c *HTTPSSEConnection) startHTTPServer(ctx context.Context) error {
 	go func() {
 		var err error
 		if tlsConfig != nil {
-			err = c.httpServer.ListenAndServeTLS("", "")
+			// Listen and wrap manually instead of ListenAndServeTLS so each
+			// accepted connection gets its own VerifyConnection closure,
+			// which is what lets metrics.NewHandshakeListener time the
+			// handshake per connection rather than per server.
+			var ln net.Listener
+			ln, err = net.Listen("tcp", c.httpServer.Addr)
+			if err == nil {
+				err = c.httpServer.Serve(metrics.NewHandshakeListener(ln, tlsConfig))
+			}
 		} else {
 			err = c.httpServer.ListenAndServe()
 		}
@@ -370,77 +1047,433 @@ func (c *HTTPSSEConnection) startHTTPServer(ctx context.Context) error {
 	return nil
 }
 
-// handleMCPRequest handles incoming MCP messages
+// applyMTLSState overrides tlsConfig.ClientAuth according to state, giving
+// operators explicit control over whether the server requires, optionally
+// accepts, or ignores client certificates instead of the previous behavior
+// of always requiring one (via LoadServerTLSConfig) whenever TLS was
+// enabled.
+func applyMTLSState(tlsConfig *tls.Config, state config.MTLSState) {
+	switch state {
+	case config.MTLSStateDisabled:
+		tlsConfig.ClientAuth = tls.NoClientCert
+	case config.MTLSStateVerifyIfGiven:
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	case config.MTLSStateEnabled:
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+}
+
+// streamableResponseTimeout bounds how long a POST to /mcp holds its HTTP
+// response open waiting for the request's matching JSON-RPC response,
+// whether that arrives as a single JSON body or as the terminal frame of a
+// stream this exchange was upgraded into.
+const streamableResponseTimeout = 30 * time.Second
+
+// handleMCPRequest implements the single /mcp endpoint of the 2025-03-26
+// Streamable HTTP transport: POST delivers one client message (or batch)
+// and replies in-exchange, GET opens the same long-lived server-push stream
+// /sse has always served, and DELETE ends a session.
 func (c *HTTPSSEConnection) handleMCPRequest(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
+	switch r.Method {
+	case http.MethodPost:
+		c.handleMCPPost(w, r)
+	case http.MethodGet:
+		c.handleSSERequest(w, r)
+	case http.MethodDelete:
+		c.handleMCPDelete(w, r)
+	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
 	}
+}
 
-	// Read the body first
+// acceptsEventStream reports whether r's Accept header allows the server to
+// upgrade its response into an SSE stream rather than a single JSON body.
+func acceptsEventStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// isBatchPayload reports whether raw is a JSON-RPC batch (a top-level JSON
+// array of messages) rather than a single message.
+func isBatchPayload(raw []byte) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// mcpLogFields builds the structured fields attached to a log line for one
+// decoded JSON-RPC message.
+func mcpLogFields(sessionID string, r *http.Request, raw []byte, id string, isRequest bool) utils.Fields {
+	fields := utils.Fields{
+		"session_id":  sessionID,
+		"method":      jsonrpcMethod(raw),
+		"remote_addr": r.RemoteAddr,
+	}
+	if isRequest {
+		fields["jsonrpc_id"] = id
+	}
+	return fields
+}
+
+// handleMCPPost decodes the JSON-RPC message (or batch) POSTed to /mcp and
+// attaches it to the caller's session, generating one on first contact. A
+// notification is queued for Read and acknowledged with 202 immediately,
+// since nothing will ever reply to it. A request is queued for Read and
+// then this exchange blocks for its matching response: as a single JSON
+// body by default, or as a one-shot SSE stream - so the server can also
+// push progress notifications through it before the final reply - if the
+// client's Accept header allows it.
+func (c *HTTPSSEConnection) handleMCPPost(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		http.Error(w, "Failed to read body", http.StatusBadRequest)
 		return
 	}
 
-	// Decode the message
+	sessionID := c.sessionForRequest(r)
+	w.Header().Set(sessionIDHeader, sessionID)
+
+	if isBatchPayload(body) {
+		c.handleMCPBatch(w, r, sessionID, body)
+		return
+	}
+
 	msg, err := jsonrpc.DecodeMessage(body)
 	if err != nil {
 		http.Error(w, "Invalid JSON-RPC message", http.StatusBadRequest)
 		return
 	}
 
+	id, isRequest := jsonrpcRequestID(body)
+	utils.FromContext(utils.ContextWithFields(r.Context(), mcpLogFields(sessionID, r, body, id, isRequest))).
+		Debug("MCP message received")
+
+	if !isRequest {
+		if c.enqueueMessage(w, msg) {
+			w.WriteHeader(http.StatusAccepted)
+		}
+		return
+	}
+
+	// Buffered so writeServerMessage's non-blocking send lands even if the
+	// response arrives before this handler starts waiting on it.
+	waiter := make(chan []byte, 1)
+	c.mu.Lock()
+	c.pendingByID[id] = sessionID
+	c.responseWaiters[id] = waiter
+	c.mu.Unlock()
+	cleanup := func() {
+		c.mu.Lock()
+		delete(c.responseWaiters, id)
+		c.mu.Unlock()
+	}
+
+	if !c.enqueueMessage(w, msg) {
+		cleanup()
+		return
+	}
+
+	if acceptsEventStream(r) {
+		c.streamResponse(w, r, sessionID, waiter, cleanup)
+		return
+	}
+	c.writeJSONResponse(w, r, waiter, cleanup)
+}
+
+// enqueueMessage queues msg for Read, writing an error response and
+// returning false if the connection is closed or its queue is already
+// full.
+func (c *HTTPSSEConnection) enqueueMessage(w http.ResponseWriter, msg jsonrpc.Message) bool {
 	select {
 	case c.messageQueue <- msg:
-		w.WriteHeader(http.StatusOK)
-		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]string{"status": "received"})
+		return true
 	case <-c.closeSignal:
 		http.Error(w, "Connection closed", http.StatusServiceUnavailable)
+		return false
 	default:
 		http.Error(w, "Message queue full", http.StatusServiceUnavailable)
+		return false
 	}
 }
 
-// handleSSERequest handles SSE connections from clients
-func (c *HTTPSSEConnection) handleSSERequest(w http.ResponseWriter, r *http.Request) {
+// writeJSONResponse blocks until waiter delivers the request's matching
+// response and writes it back as the POST's sole JSON body - the
+// non-streaming reply mode, used when the client's Accept header doesn't
+// allow an SSE upgrade.
+func (c *HTTPSSEConnection) writeJSONResponse(w http.ResponseWriter, r *http.Request, waiter chan []byte, cleanup func()) {
+	select {
+	case data := <-waiter:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	case <-time.After(streamableResponseTimeout):
+		cleanup()
+		http.Error(w, "Timed out waiting for response", http.StatusGatewayTimeout)
+	case <-r.Context().Done():
+		cleanup()
+	case <-c.closeSignal:
+		cleanup()
+		http.Error(w, "Connection closed", http.StatusServiceUnavailable)
+	}
+}
+
+// streamResponse upgrades w into an SSE stream for the lifetime of this one
+// exchange: the request's session is attached to it like a normal SSE
+// subscriber, so any progress notifications or server-initiated requests
+// broadcast to the session while we wait land in this stream too, and the
+// request's own response - delivered via waiter rather than the session's
+// usual id-routed delivery - is written as the terminal frame before the
+// stream closes.
+func (c *HTTPSSEConnection) streamResponse(w http.ResponseWriter, r *http.Request, sessionID string, waiter chan []byte, cleanup func()) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
+		cleanup()
 		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
 		return
 	}
 
+	c.mu.RLock()
+	session := c.sessions[sessionID]
+	c.mu.RUnlock()
+
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Headers", "Cache-Control")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
 
+	_, cancel := context.WithCancel(r.Context())
 	clientID := fmt.Sprintf("client-%d", time.Now().UnixNano())
-	client := &SSEClient{
-		Writer:   w,
-		Flusher:  flusher,
-		Request:  r,
-		ClientID: clientID,
+	client := newSSEClient(w, flusher, r, clientID, cancel)
+	session.attach(client)
+
+	select {
+	case data := <-waiter:
+		evt := session.record(data)
+		client.enqueue(formatSSEFrame(evt))
+	case <-time.After(streamableResponseTimeout):
+		cleanup()
+	case <-r.Context().Done():
+		cleanup()
+	case <-c.closeSignal:
+		cleanup()
+	}
+
+	session.detach(client)
+	client.evict()
+	<-client.done
+}
+
+// idWaiter pairs a batch entry's JSON-RPC id with the channel its response
+// will arrive on.
+type idWaiter struct {
+	id     string
+	waiter chan []byte
+}
+
+// handleMCPBatch processes a JSON-RPC batch: each notification is queued
+// immediately, each request gets its own response waiter, and once every
+// request in the batch has resolved (or the exchange times out) the results
+// are written back as a single JSON array. Unlike a single request, a batch
+// is always answered as plain JSON - interleaving a batch's several
+// responses into one SSE stream isn't worth the complexity for how rarely
+// batches are used in practice.
+func (c *HTTPSSEConnection) handleMCPBatch(w http.ResponseWriter, r *http.Request, sessionID string, body []byte) {
+	var rawMessages []json.RawMessage
+	if err := json.Unmarshal(body, &rawMessages); err != nil {
+		http.Error(w, "Invalid JSON-RPC batch", http.StatusBadRequest)
+		return
+	}
+
+	var waiters []idWaiter
+	for _, raw := range rawMessages {
+		msg, err := jsonrpc.DecodeMessage(raw)
+		if err != nil {
+			http.Error(w, "Invalid JSON-RPC message in batch", http.StatusBadRequest)
+			return
+		}
+
+		id, isRequest := jsonrpcRequestID(raw)
+		utils.FromContext(utils.ContextWithFields(r.Context(), mcpLogFields(sessionID, r, raw, id, isRequest))).
+			Debug("MCP batch message received")
+
+		if isRequest {
+			// Buffered so a response that arrives while the loop below is
+			// still waiting on an earlier request isn't diverted.
+			waiter := make(chan []byte, 1)
+			c.mu.Lock()
+			c.pendingByID[id] = sessionID
+			c.responseWaiters[id] = waiter
+			c.mu.Unlock()
+			waiters = append(waiters, idWaiter{id: id, waiter: waiter})
+		}
+
+		if !c.enqueueMessage(w, msg) {
+			c.cleanupWaiters(waiters)
+			return
+		}
+	}
+
+	if len(waiters) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
 	}
 
+	deadline := time.After(streamableResponseTimeout)
+	responses := make([]json.RawMessage, 0, len(waiters))
+	for _, p := range waiters {
+		select {
+		case data := <-p.waiter:
+			responses = append(responses, data)
+		case <-deadline:
+			c.cleanupWaiters(waiters)
+			http.Error(w, "Timed out waiting for batch response", http.StatusGatewayTimeout)
+			return
+		case <-r.Context().Done():
+			c.cleanupWaiters(waiters)
+			return
+		case <-c.closeSignal:
+			c.cleanupWaiters(waiters)
+			http.Error(w, "Connection closed", http.StatusServiceUnavailable)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(responses)
+}
+
+// cleanupWaiters discards any of waiters' response waiters that never
+// resolved, so writeServerMessage falls back to the session's persistent
+// stream instead of trying to deliver into a channel nobody reads anymore.
+func (c *HTTPSSEConnection) cleanupWaiters(waiters []idWaiter) {
 	c.mu.Lock()
-	c.clients[clientID] = client
+	defer c.mu.Unlock()
+	for _, p := range waiters {
+		delete(c.responseWaiters, p.id)
+	}
+}
+
+// handleMCPDelete terminates the session named by the Mcp-Session-Id header:
+// its SSE subscriber (if any) is dropped and its ring buffer discarded, so a
+// client that's done can signal the server to stop holding state for it.
+func (c *HTTPSSEConnection) handleMCPDelete(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.Header.Get(sessionIDHeader)
+	if sessionID == "" {
+		http.Error(w, "Missing "+sessionIDHeader+" header", http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	session := c.sessions[sessionID]
+	delete(c.sessions, sessionID)
 	c.mu.Unlock()
 
-	utils.Info("SSE client connected: %s", clientID)
+	if session != nil {
+		if client := session.subscriber(); client != nil {
+			client.evict()
+		}
+	}
 
-	// Send initial connection message
-	fmt.Fprintf(w, "data: {\"type\":\"connection\",\"clientId\":\"%s\"}\n\n", clientID)
-	flusher.Flush()
+	w.WriteHeader(http.StatusNoContent)
+}
 
-	// Keep connection alive until client disconnects
-	<-r.Context().Done()
+// sessionForRequest returns the Mcp-Session-Id r was sent with, creating and
+// registering a new session (and the header's first value) if this is the
+// caller's first contact.
+func (c *HTTPSSEConnection) sessionForRequest(r *http.Request) string {
+	sessionID := r.Header.Get(sessionIDHeader)
 
 	c.mu.Lock()
-	delete(c.clients, clientID)
-	c.mu.Unlock()
+	defer c.mu.Unlock()
+
+	if sessionID == "" || c.sessions[sessionID] == nil {
+		if sessionID == "" {
+			sessionID = newSessionID()
+		}
+		c.sessions[sessionID] = newMCPSession(sessionID)
+	}
+	return sessionID
+}
+
+// handleSSERequest handles SSE connections from clients, attaching the
+// stream to its Mcp-Session-Id session (creating one on first contact) and
+// replaying any buffered events newer than Last-Event-ID so a reconnecting
+// client doesn't lose messages sent while it was offline. It then holds the
+// connection open, writing a heartbeat comment every sseHeartbeatInterval so
+// intermediaries don't close it for being idle, until the client disconnects.
+func (c *HTTPSSEConnection) handleSSERequest(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Headers", "Cache-Control")
+
+	sessionID := c.sessionForRequest(r)
+	w.Header().Set(sessionIDHeader, sessionID)
 
-	utils.Info("SSE client disconnected: %s", clientID)
-}
\ No newline at end of file
+	c.mu.RLock()
+	session := c.sessions[sessionID]
+	c.mu.RUnlock()
+
+	clientID := fmt.Sprintf("client-%d", time.Now().UnixNano())
+	ctx := utils.ContextWithFields(r.Context(), utils.Fields{
+		"session_id":  sessionID,
+		"client_id":   clientID,
+		"remote_addr": r.RemoteAddr,
+	})
+	log := utils.FromContext(ctx)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	client := newSSEClient(w, flusher, r, clientID, cancel)
+	session.attach(client)
+	defer session.detach(client)
+
+	log.Info("SSE client connected")
+
+	if lastEventID, ok := parseLastEventID(r); ok {
+		for _, evt := range session.replay(lastEventID) {
+			client.enqueue(formatSSEFrame(evt))
+		}
+	} else {
+		client.enqueue([]byte(fmt.Sprintf("data: {\"type\":\"connection\",\"clientId\":\"%s\"}\n\n", clientID)))
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("SSE client disconnected")
+			client.evict()
+			return
+		case <-c.closeSignal:
+			client.evict()
+			return
+		case <-heartbeat.C:
+			client.enqueue([]byte(": ping\n\n"))
+		}
+	}
+}
+
+// parseLastEventID returns the Last-Event-ID header as a uint64 and true, or
+// 0 and false if absent or not a valid event id.
+func parseLastEventID(r *http.Request) (uint64, bool) {
+	raw := r.Header.Get(lastEventIDHeader)
+	if raw == "" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,635 @@
+package transport
+
+// OAuth 2.1 protected-resource support for the HTTP/SSE transport. It's
+// layered alongside mTLS rather than replacing it: a server can require a
+// verified client certificate and a valid bearer token on the same request,
+// and either can be enabled independently of the other.
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// AuthConfig enables OAuth 2.1 bearer-token authorization on an
+// HTTPSSETransport. A nil *AuthConfig (the default) leaves /mcp and /sse
+// open to any mTLS-authorized peer, same as before this existed.
+type AuthConfig struct {
+	// JWKSURL is fetched - and re-fetched whenever a token names a kid the
+	// cache doesn't recognize, to ride out key rotation - to validate
+	// incoming bearer token signatures. Required to enable server-side
+	// enforcement.
+	JWKSURL string
+	// Audience and Issuer must match the token's aud/iss claims exactly.
+	Audience string
+	Issuer string
+	// RequiredScopes must all appear in the token's space-delimited scope
+	// claim.
+	RequiredScopes []string
+	// ResourceMetadataURL is served at /.well-known/oauth-protected-resource
+	// and advertised in a 401's WWW-Authenticate header, per RFC 9728, so a
+	// client discovers where to go to obtain a token.
+	ResourceMetadataURL string
+	// TokenSource supplies bearer tokens for this transport's outgoing
+	// requests. Nil disables client-side bearer auth.
+	TokenSource TokenSource
+
+	jwks     *jwksCache
+	jwksOnce sync.Once
+}
+
+// serverEnabled reports whether cfg is configured to enforce bearer tokens
+// on incoming requests.
+func (cfg *AuthConfig) serverEnabled() bool {
+	return cfg != nil && cfg.JWKSURL != ""
+}
+
+// cache lazily creates cfg's JWKS cache on first use, so a zero-value
+// AuthConfig never dials out.
+func (cfg *AuthConfig) cache() *jwksCache {
+	cfg.jwksOnce.Do(func() {
+		cfg.jwks = newJWKSCache(cfg.JWKSURL)
+	})
+	return cfg.jwks
+}
+
+// validateBearerToken parses and validates tokenStr's signature against
+// cfg's JWKS, then checks its iss/aud/scope claims against cfg.
+func (cfg *AuthConfig) validateBearerToken(tokenStr string) error {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenStr, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return cfg.cache().keyFor(kid)
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	if err != nil {
+		return fmt.Errorf("bearer token validation failed: %w", err)
+	}
+
+	if cfg.Issuer != "" {
+		if iss, _ := claims.GetIssuer(); iss != cfg.Issuer {
+			return fmt.Errorf("unexpected issuer %q", iss)
+		}
+	}
+	if cfg.Audience != "" {
+		aud, err := claims.GetAudience()
+		if err != nil || !containsString(aud, cfg.Audience) {
+			return fmt.Errorf("token not valid for audience %q", cfg.Audience)
+		}
+	}
+	if len(cfg.RequiredScopes) > 0 {
+		granted := strings.Fields(stringClaim(claims, "scope"))
+		for _, want := range cfg.RequiredScopes {
+			if !containsString(granted, want) {
+				return fmt.Errorf("token missing required scope %q", want)
+			}
+		}
+	}
+	return nil
+}
+
+func stringClaim(claims jwt.MapClaims, key string) string {
+	s, _ := claims[key].(string)
+	return s
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
+// requireBearerAuth wraps next with cfg's bearer-token check, only if cfg
+// enables server-side enforcement. A missing, malformed, or invalid token
+// gets a 401 carrying a WWW-Authenticate header that points at
+// cfg.ResourceMetadataURL, per RFC 9728, so a client knows where to start.
+func requireBearerAuth(cfg *AuthConfig, next http.HandlerFunc) http.HandlerFunc {
+	if !cfg.serverEnabled() {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			writeUnauthorized(w, cfg, "missing bearer token")
+			return
+		}
+		if err := cfg.validateBearerToken(token); err != nil {
+			writeUnauthorized(w, cfg, err.Error())
+			return
+		}
+		next(w, r)
+	}
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>"
+// header, reporting false if the header is absent or a different scheme.
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(header, prefix), true
+}
+
+// doWithBearerAuth builds a request via newRequest, attaches a bearer token
+// from cfg.TokenSource if one is configured, and sends it with client. A 401
+// response invalidates the cached token and is retried exactly once with a
+// freshly obtained one, so a server-side key rotation or token expiry is
+// transparent to the caller. A nil cfg or nil cfg.TokenSource sends the
+// request unmodified, same as before bearer auth existed.
+func doWithBearerAuth(ctx context.Context, cfg *AuthConfig, client *http.Client, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	attempt := func() (*http.Response, error) {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+		if cfg != nil && cfg.TokenSource != nil {
+			token, err := cfg.TokenSource.Token(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("obtain bearer token: %w", err)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return client.Do(req)
+	}
+
+	resp, err := attempt()
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || cfg == nil || cfg.TokenSource == nil {
+		return resp, err
+	}
+
+	resp.Body.Close()
+	cfg.TokenSource.Invalidate()
+	return attempt()
+}
+
+// writeUnauthorized responds 401 with a WWW-Authenticate: Bearer header
+// naming reason and, if configured, cfg.ResourceMetadataURL.
+func writeUnauthorized(w http.ResponseWriter, cfg *AuthConfig, reason string) {
+	challenge := fmt.Sprintf("Bearer error=\"invalid_token\", error_description=%q", reason)
+	if cfg.ResourceMetadataURL != "" {
+		challenge += fmt.Sprintf(", resource_metadata=%q", cfg.ResourceMetadataURL)
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, "unauthorized", http.StatusUnauthorized)
+}
+
+// handleProtectedResourceMetadata serves the RFC 9728 protected-resource
+// metadata document at /.well-known/oauth-protected-resource, naming
+// cfg.Issuer as the authorization server clients should use to obtain a
+// token for this resource.
+func handleProtectedResourceMetadata(cfg *AuthConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"resource":              cfg.ResourceMetadataURL,
+			"authorization_servers": []string{cfg.Issuer},
+			"bearer_methods_supported": []string{"header"},
+			"scopes_supported":         cfg.RequiredScopes,
+		})
+	}
+}
+
+// jwksCache fetches and caches a JWKS document's RSA keys by kid, refreshing
+// from jwksCache.url whenever a lookup misses so key rotation on the
+// authorization server side takes effect without a restart here.
+type jwksCache struct {
+	url        string
+	httpClient *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+}
+
+func newJWKSCache(url string) *jwksCache {
+	return &jwksCache{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// keyFor returns the RSA public key for kid, refreshing the cache once if
+// kid isn't already known.
+func (c *jwksCache) keyFor(kid string) (*rsa.PublicKey, error) {
+	c.mu.RLock()
+	key, ok := c.keys[kid]
+	c.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	if err := c.refresh(); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key, ok = c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("jwks: no key found for kid %q at %s", kid, c.url)
+	}
+	return key, nil
+}
+
+// jwk is the subset of RFC 7517 fields needed to reconstruct an RSA public
+// key from a JWKS document.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// refresh re-fetches c.url and replaces the cached key set wholesale.
+func (c *jwksCache) refresh() error {
+	resp, err := c.httpClient.Get(c.url)
+	if err != nil {
+		return fmt.Errorf("jwks: fetch %s: %w", c.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("jwks: %s returned status %d", c.url, resp.StatusCode)
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return fmt.Errorf("jwks: decode %s: %w", c.url, err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(doc.Keys))
+	for _, k := range doc.Keys {
+		pub, err := k.rsaPublicKey()
+		if err != nil {
+			utils.Warn("jwks: skipping key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	c.mu.Lock()
+	c.keys = keys
+	c.mu.Unlock()
+	return nil
+}
+
+// rsaPublicKey reconstructs an *rsa.PublicKey from k's base64url-encoded
+// modulus (n) and exponent (e), per RFC 7518 section 6.3.1.
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	if k.Kty != "RSA" {
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+	nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode n: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode e: %w", err)
+	}
+
+	e := 0
+	for _, b := range eBytes {
+		e = e<<8 | int(b)
+	}
+
+	n := new(big.Int).SetBytes(nBytes)
+	return &rsa.PublicKey{N: n, E: e}, nil
+}
+
+// TokenSource supplies bearer tokens for an HTTPSSETransport's outgoing
+// client requests, abstracting over however the token is actually obtained
+// so writeClientMessage/startSSEReader don't need to know whether it came
+// from a static value, a cached refresh token, or a full authorization-code
+// flow.
+type TokenSource interface {
+	// Token returns a currently-valid bearer token, refreshing or obtaining
+	// one as needed.
+	Token(ctx context.Context) (string, error)
+	// Invalidate discards any cached token, so the next Token call fetches
+	// a fresh one. Called after a request comes back 401.
+	Invalidate()
+}
+
+// StaticTokenSource is a TokenSource that always returns the same
+// pre-obtained token, for callers that manage their own refresh out of
+// band.
+type StaticTokenSource string
+
+func (s StaticTokenSource) Token(ctx context.Context) (string, error) { return string(s), nil }
+func (s StaticTokenSource) Invalidate()                               {}
+
+// OAuthTokenSource is the default TokenSource: it performs RFC 8414
+// authorization-server discovery and RFC 7591 dynamic client registration
+// once, then an authorization-code + PKCE flow to obtain its first token,
+// caching and refreshing it afterward. The authorization step is
+// interactive - Token logs the URL to visit and blocks on the local
+// RedirectURL callback listener - which fits this codebase's MCP servers
+// and CLIs better than a fully headless flow would.
+type OAuthTokenSource struct {
+	IssuerURL   string
+	RedirectURL string
+	Scopes      []string
+	httpClient  *http.Client
+
+	mu           sync.Mutex
+	metadata     *authServerMetadata
+	clientID     string
+	clientSecret string
+	token        *oauthToken
+}
+
+// NewOAuthTokenSource returns an OAuthTokenSource that discovers issuerURL's
+// authorization server lazily on its first Token call, using redirectURL
+// (a loopback address this process listens on, e.g.
+// "http://127.0.0.1:8765/callback") as the PKCE callback target.
+func NewOAuthTokenSource(issuerURL, redirectURL string, scopes []string) *OAuthTokenSource {
+	return &OAuthTokenSource{
+		IssuerURL:   issuerURL,
+		RedirectURL: redirectURL,
+		Scopes:      scopes,
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+type authServerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	RegistrationEndpoint  string `json:"registration_endpoint"`
+}
+
+type oauthToken struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// Token implements TokenSource.
+func (s *OAuthTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && time.Now().Before(s.token.ExpiresAt) {
+		return s.token.AccessToken, nil
+	}
+	if s.token != nil && s.token.RefreshToken != "" {
+		if tok, err := s.refreshToken(ctx, s.token.RefreshToken); err == nil {
+			s.token = tok
+			return tok.AccessToken, nil
+		}
+		utils.Warn("OAuth refresh token rejected, re-authorizing from scratch")
+	}
+
+	if s.metadata == nil {
+		if err := s.discover(ctx); err != nil {
+			return "", fmt.Errorf("authorization server discovery: %w", err)
+		}
+	}
+	if s.clientID == "" {
+		if err := s.register(ctx); err != nil {
+			return "", fmt.Errorf("dynamic client registration: %w", err)
+		}
+	}
+
+	tok, err := s.authorizeWithPKCE(ctx)
+	if err != nil {
+		return "", fmt.Errorf("authorization code flow: %w", err)
+	}
+	s.token = tok
+	return tok.AccessToken, nil
+}
+
+// Invalidate implements TokenSource.
+func (s *OAuthTokenSource) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = nil
+}
+
+// discover fetches s.IssuerURL's RFC 8414 authorization server metadata.
+func (s *OAuthTokenSource) discover(ctx context.Context) error {
+	url := strings.TrimRight(s.IssuerURL, "/") + "/.well-known/oauth-authorization-server"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("discovery endpoint %s returned status %d", url, resp.StatusCode)
+	}
+
+	var meta authServerMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return err
+	}
+	s.metadata = &meta
+	return nil
+}
+
+// register performs RFC 7591 dynamic client registration against
+// s.metadata's registration endpoint, requesting a public client suitable
+// for the PKCE flow (no client_secret needed).
+func (s *OAuthTokenSource) register(ctx context.Context) error {
+	if s.metadata.RegistrationEndpoint == "" {
+		return fmt.Errorf("authorization server does not advertise a registration_endpoint")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"redirect_uris":              []string{s.RedirectURL},
+		"grant_types":                []string{"authorization_code", "refresh_token"},
+		"response_types":             []string{"code"},
+		"token_endpoint_auth_method": "none",
+		"client_name":                "llm-agents",
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.metadata.RegistrationEndpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("registration endpoint returned status %d", resp.StatusCode)
+	}
+
+	var reg struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reg); err != nil {
+		return err
+	}
+	s.clientID = reg.ClientID
+	s.clientSecret = reg.ClientSecret
+	return nil
+}
+
+// authorizeWithPKCE runs one authorization-code + PKCE round trip: it opens
+// a loopback listener on s.RedirectURL, prints the authorization URL for
+// the user to visit, waits for the callback carrying ?code=..., and
+// exchanges it at the token endpoint.
+func (s *OAuthTokenSource) authorizeWithPKCE(ctx context.Context) (*oauthToken, error) {
+	verifier, challenge, err := newPKCEPair()
+	if err != nil {
+		return nil, err
+	}
+
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+	listener, err := newCallbackListener(s.RedirectURL, codeCh, errCh)
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+
+	authURL := fmt.Sprintf("%s?response_type=code&client_id=%s&redirect_uri=%s&scope=%s&code_challenge=%s&code_challenge_method=S256",
+		s.metadata.AuthorizationEndpoint, s.clientID, s.RedirectURL, strings.Join(s.Scopes, "+"), challenge)
+	utils.Info("Visit the following URL to authorize this client: %s", authURL)
+
+	select {
+	case code := <-codeCh:
+		return s.exchangeCode(ctx, code, verifier)
+	case err := <-errCh:
+		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// exchangeCode redeems code (with its PKCE verifier) at the token endpoint.
+func (s *OAuthTokenSource) exchangeCode(ctx context.Context, code, verifier string) (*oauthToken, error) {
+	form := fmt.Sprintf("grant_type=authorization_code&code=%s&redirect_uri=%s&client_id=%s&code_verifier=%s",
+		code, s.RedirectURL, s.clientID, verifier)
+	return s.requestToken(ctx, form)
+}
+
+// refreshToken redeems refreshToken at the token endpoint for a new access
+// token.
+func (s *OAuthTokenSource) refreshToken(ctx context.Context, refreshToken string) (*oauthToken, error) {
+	form := fmt.Sprintf("grant_type=refresh_token&refresh_token=%s&client_id=%s", refreshToken, s.clientID)
+	return s.requestToken(ctx, form)
+}
+
+func (s *OAuthTokenSource) requestToken(ctx context.Context, form string) (*oauthToken, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.metadata.TokenEndpoint, strings.NewReader(form))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("token endpoint returned status %d: %s", resp.StatusCode, errBody)
+	}
+
+	var payload struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	return &oauthToken{
+		AccessToken:  payload.AccessToken,
+		RefreshToken: payload.RefreshToken,
+		ExpiresAt:    time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// newPKCEPair generates an RFC 7636 code_verifier and its S256
+// code_challenge.
+func newPKCEPair() (verifier, challenge string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	verifier = base64.RawURLEncoding.EncodeToString(buf)
+	sum := sha256.Sum256([]byte(verifier))
+	challenge = base64.RawURLEncoding.EncodeToString(sum[:])
+	return verifier, challenge, nil
+}
+
+// newCallbackListener starts an HTTP server on redirectURL's host:port that
+// serves redirect requests until the returned Closer is closed: each one
+// reads the "code" query parameter from a PKCE authorization redirect, sends
+// it on codeCh (or an error on errCh), and replies with a page telling the
+// user they can close the tab.
+func newCallbackListener(redirectURL string, codeCh chan<- string, errCh chan<- error) (io.Closer, error) {
+	addr, path, err := splitCallbackURL(redirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	server := &http.Server{Addr: addr, Handler: mux}
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if errParam := r.URL.Query().Get("error"); errParam != "" {
+			errCh <- fmt.Errorf("authorization server returned error: %s", errParam)
+		} else if code := r.URL.Query().Get("code"); code != "" {
+			codeCh <- code
+		} else {
+			errCh <- fmt.Errorf("callback missing code parameter")
+		}
+		fmt.Fprint(w, "Authorization complete, you may close this tab.")
+	})
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on PKCE callback address %s: %w", addr, err)
+	}
+	go server.Serve(ln)
+	return server, nil
+}
+
+// splitCallbackURL extracts the host:port to listen on and the path to
+// route from a redirect URL like "http://127.0.0.1:8765/callback".
+func splitCallbackURL(redirectURL string) (addr, path string, err error) {
+	u, err := url.Parse(redirectURL)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid redirect URL %q: %w", redirectURL, err)
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.Host, u.Path, nil
+}
@@ -0,0 +1,92 @@
+package weather
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ttlCacheEntry is a cached Observation together with the time it expires.
+type ttlCacheEntry struct {
+	observation Observation
+	expiresAt   time.Time
+}
+
+// ttlCache is a small in-memory cache keyed on normalized city name, so
+// repeated MCP calls for the same city within ttl don't burn provider API
+// quota re-fetching data that hasn't gone stale yet.
+type ttlCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]ttlCacheEntry
+}
+
+func newTTLCache(ttl time.Duration) *ttlCache {
+	return &ttlCache{
+		ttl:     ttl,
+		entries: make(map[string]ttlCacheEntry),
+	}
+}
+
+func (c *ttlCache) get(key string) (Observation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return Observation{}, false
+	}
+	return entry.observation, true
+}
+
+func (c *ttlCache) set(key string, obs Observation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = ttlCacheEntry{
+		observation: obs,
+		expiresAt:   time.Now().Add(c.ttl),
+	}
+}
+
+// normalizeCacheKey lowercases and trims a city name for use as a cache key,
+// so "Paris", " paris ", and "PARIS" all hit the same cache entry.
+func normalizeCacheKey(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
+
+// CachingProvider wraps another Provider with a ttlCache keyed on
+// normalized city name, so repeated MCP calls for the same city don't
+// re-hit the backend until the cached observation goes stale.
+type CachingProvider struct {
+	inner Provider
+	cache *ttlCache
+}
+
+// NewCachingProvider wraps inner with a TTL cache, caching each successful
+// observation for ttl.
+func NewCachingProvider(inner Provider, ttl time.Duration) *CachingProvider {
+	return &CachingProvider{
+		inner: inner,
+		cache: newTTLCache(ttl),
+	}
+}
+
+// Get returns the cached observation for city if it hasn't expired,
+// otherwise fetches a fresh one from inner and caches it.
+func (p *CachingProvider) Get(ctx context.Context, city string) (Observation, error) {
+	key := normalizeCacheKey(city)
+	if obs, ok := p.cache.get(key); ok {
+		return obs, nil
+	}
+
+	obs, err := p.inner.Get(ctx, city)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	p.cache.set(key, obs)
+	return obs, nil
+}
@@ -0,0 +1,173 @@
+package weather
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// geocodeCacheEntry is one resolved Location persisted to disk, keyed on
+// the normalized query that produced it.
+type geocodeCacheEntry struct {
+	Query        string  `json:"query"`
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	ResolvedName string  `json:"resolved_name"`
+	CountryCode  string  `json:"country_code"`
+}
+
+// CachedGeocoder wraps another Geocoder with an in-memory LRU bounded to
+// Capacity entries, backed by a JSON file on disk so lookups survive a
+// restart instead of re-hitting a rate-limited backend like
+// NominatimGeocoder from a cold cache every time.
+type CachedGeocoder struct {
+	inner    Geocoder
+	capacity int
+	path     string
+
+	mu    sync.Mutex
+	order *list.List
+	index map[string]*list.Element
+}
+
+// NewCachedGeocoder wraps inner with an LRU cache of at most capacity
+// entries, persisted as JSON at path. An unreadable or missing path starts
+// with an empty cache rather than failing - geocoding still works, it just
+// isn't warm yet.
+func NewCachedGeocoder(inner Geocoder, path string, capacity int) *CachedGeocoder {
+	if capacity < 1 {
+		capacity = 1
+	}
+	g := &CachedGeocoder{
+		inner:    inner,
+		capacity: capacity,
+		path:     path,
+		order:    list.New(),
+		index:    make(map[string]*list.Element),
+	}
+	g.load()
+	return g
+}
+
+// Lookup returns the cached Location for query if present, promoting it to
+// most-recently-used; otherwise it resolves via inner, caches the result,
+// and persists the cache to disk.
+func (g *CachedGeocoder) Lookup(ctx context.Context, query string) (lat, lon float64, resolvedName, countryCode string, err error) {
+	key := normalizeCacheKey(query)
+
+	g.mu.Lock()
+	if elem, ok := g.index[key]; ok {
+		g.order.MoveToFront(elem)
+		entry := elem.Value.(geocodeCacheEntry)
+		g.mu.Unlock()
+		return entry.Latitude, entry.Longitude, entry.ResolvedName, entry.CountryCode, nil
+	}
+	g.mu.Unlock()
+
+	lat, lon, resolvedName, countryCode, err = g.inner.Lookup(ctx, query)
+	if err != nil {
+		return 0, 0, "", "", err
+	}
+
+	g.put(key, geocodeCacheEntry{
+		Query:        key,
+		Latitude:     lat,
+		Longitude:    lon,
+		ResolvedName: resolvedName,
+		CountryCode:  countryCode,
+	})
+	g.save()
+
+	return lat, lon, resolvedName, countryCode, nil
+}
+
+// put inserts or refreshes entry as most-recently-used, evicting the
+// least-recently-used entry if the cache is over capacity.
+func (g *CachedGeocoder) put(key string, entry geocodeCacheEntry) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if elem, ok := g.index[key]; ok {
+		elem.Value = entry
+		g.order.MoveToFront(elem)
+		return
+	}
+
+	g.index[key] = g.order.PushFront(entry)
+	if g.order.Len() > g.capacity {
+		oldest := g.order.Back()
+		if oldest != nil {
+			g.order.Remove(oldest)
+			delete(g.index, oldest.Value.(geocodeCacheEntry).Query)
+		}
+	}
+}
+
+// load populates the cache from g.path, most-recently-used first, starting
+// empty if the file doesn't exist (silently) or is malformed (with a warning).
+func (g *CachedGeocoder) load() {
+	if g.path == "" {
+		return
+	}
+
+	data, err := os.ReadFile(g.path)
+	if err != nil {
+		return
+	}
+
+	var entries []geocodeCacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		utils.Warn("geocoder cache at %s is corrupt, starting empty: %v", g.path, err)
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, entry := range entries {
+		if g.order.Len() >= g.capacity {
+			break
+		}
+		g.index[entry.Query] = g.order.PushBack(entry)
+	}
+}
+
+// save writes the cache to g.path as JSON, most-recently-used first, via a
+// temp file plus rename so a concurrent reader never observes a partial
+// write.
+func (g *CachedGeocoder) save() {
+	if g.path == "" {
+		return
+	}
+
+	g.mu.Lock()
+	entries := make([]geocodeCacheEntry, 0, g.order.Len())
+	for elem := g.order.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, elem.Value.(geocodeCacheEntry))
+	}
+	g.mu.Unlock()
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		utils.Warn("failed to marshal geocoder cache: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(g.path), 0755); err != nil {
+		utils.Warn("failed to create geocoder cache directory: %v", err)
+		return
+	}
+
+	tmp := g.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		utils.Warn("failed to write geocoder cache: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, g.path); err != nil {
+		utils.Warn("failed to persist geocoder cache: %v", err)
+	}
+}
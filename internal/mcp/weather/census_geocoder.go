@@ -0,0 +1,92 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// defaultCensusGeocoderURL is the US Census Bureau's free one-line address
+// geocoder; tests point CensusGeocoder.BaseURL at an httptest.Server
+// instead.
+const defaultCensusGeocoderURL = "https://geocoding.geo.census.gov/geocoder/locations/onelineaddress"
+
+// CensusGeocoder resolves US place names using the Census Bureau's
+// geocoder. It requires no API key and no rate limiting, unlike
+// NominatimGeocoder, but only covers the United States - callers should
+// fall back to NominatimGeocoder for anything it can't resolve.
+type CensusGeocoder struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewCensusGeocoder creates a geocoder against the public Census Bureau
+// endpoint.
+func NewCensusGeocoder() *CensusGeocoder {
+	return &CensusGeocoder{
+		BaseURL:    defaultCensusGeocoderURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type censusResponse struct {
+	Result struct {
+		AddressMatches []struct {
+			MatchedAddress string `json:"matchedAddress"`
+			Coordinates    struct {
+				X float64 `json:"x"`
+				Y float64 `json:"y"`
+			} `json:"coordinates"`
+		} `json:"addressMatches"`
+	} `json:"result"`
+}
+
+// Lookup resolves query via the Census Bureau's one-line address endpoint.
+// CountryCode is always "US": the Census geocoder has no concept of
+// addresses outside the United States, so a query it matches is US by
+// definition.
+func (g *CensusGeocoder) Lookup(ctx context.Context, query string) (lat, lon float64, resolvedName, countryCode string, err error) {
+	baseURL := g.BaseURL
+	if baseURL == "" {
+		baseURL = defaultCensusGeocoderURL
+	}
+
+	q := url.Values{}
+	q.Set("address", query)
+	q.Set("benchmark", "Public_AR_Current")
+	q.Set("format", "json")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return 0, 0, "", "", fmt.Errorf("failed to build Census geocoder request: %w", err)
+	}
+
+	client := g.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, 0, "", "", fmt.Errorf("failed to reach Census geocoder: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, "", "", fmt.Errorf("Census geocoder returned status %d", resp.StatusCode)
+	}
+
+	var data censusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return 0, 0, "", "", fmt.Errorf("failed to decode Census geocoder response: %w", err)
+	}
+	if len(data.Result.AddressMatches) == 0 {
+		return 0, 0, "", "", fmt.Errorf("address not found: %s", query)
+	}
+
+	match := data.Result.AddressMatches[0]
+	return match.Coordinates.Y, match.Coordinates.X, match.MatchedAddress, "US", nil
+}
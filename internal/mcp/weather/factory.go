@@ -0,0 +1,54 @@
+package weather
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steve/llm-agents/internal/config"
+)
+
+// geocodeCacheCapacity bounds how many resolved locations NewProviderByName's
+// "nws" provider keeps in its on-disk geocoder cache.
+const geocodeCacheCapacity = 1000
+
+// NewProviderByName constructs the named backend provider ("mock",
+// "openweathermap", "open-meteo", "nws") and wraps it with retry/timeout
+// behavior from cfg and a TTL cache, so callers always get a Provider ready
+// to use behind the getTemperature tool. apiKey is only required by the
+// openweathermap provider. units is normalized with parseUnits, defaulting
+// to metric for an empty or unrecognized value. geocodeCachePath is only
+// used by the nws provider, to persist its geocoder's resolutions across
+// restarts; an empty path disables that persistence.
+func NewProviderByName(name, apiKey, units string, cfg config.MCPClientConfig, cacheTTL time.Duration, geocodeCachePath string) (Provider, error) {
+	parsedUnits := parseUnits(units)
+
+	var base Provider
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "mock":
+		base = NewMockProvider(parsedUnits)
+	case "openweathermap":
+		if apiKey == "" {
+			return nil, fmt.Errorf("openweathermap provider requires an API key")
+		}
+		base = NewOpenWeatherMapProvider(apiKey, parsedUnits)
+	case "open-meteo", "openmeteo":
+		base = NewOpenMeteoProvider(parsedUnits)
+	case "nws":
+		geocoder := NewCachedGeocoder(NewNominatimGeocoder(), geocodeCachePath, geocodeCacheCapacity)
+		base = NewNWSProvider(geocoder, NewOpenMeteoProvider(parsedUnits), parsedUnits)
+	default:
+		return nil, fmt.Errorf("unknown weather provider: %s", name)
+	}
+
+	return NewCachingProvider(NewRetryingProvider(base, cfg), cacheTTL), nil
+}
+
+// parseUnits normalizes the WEATHER_UNITS env var / --units flag value to a
+// Units, defaulting to UnitsMetric for an empty or unrecognized value.
+func parseUnits(units string) Units {
+	if strings.EqualFold(strings.TrimSpace(units), string(UnitsImperial)) {
+		return UnitsImperial
+	}
+	return UnitsMetric
+}
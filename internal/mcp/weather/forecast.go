@@ -0,0 +1,49 @@
+package weather
+
+import "context"
+
+// defaultForecastDays is how many days GetForecast fetches when a caller
+// passes a non-positive days argument.
+const defaultForecastDays = 5
+
+// maxForecastDays bounds how many days GetForecast will ever fetch in one
+// call, since Open-Meteo's free daily forecast only guarantees this many.
+const maxForecastDays = 16
+
+// ForecastDay is one day's low/high temperature, precipitation
+// probability, and conditions, normalized across ForecastProvider
+// implementations regardless of backend.
+type ForecastDay struct {
+	Date                string
+	TemperatureMin      float64
+	TemperatureMax      float64
+	Unit                string
+	PrecipitationChance float64
+	Description         string
+}
+
+// ForecastObservation is a multi-day forecast for a city.
+type ForecastObservation struct {
+	City string
+	Days []ForecastDay
+}
+
+// ForecastProvider retrieves a multi-day forecast for a city. Each backend
+// implements this the same way Provider does for current conditions, so
+// callers can swap providers via configuration without touching the MCP
+// tool schema built on top of it.
+type ForecastProvider interface {
+	GetForecast(ctx context.Context, city string, days int) (ForecastObservation, error)
+}
+
+// clampForecastDays normalizes a requested day count to [1, maxForecastDays],
+// defaulting a non-positive value to defaultForecastDays.
+func clampForecastDays(days int) int {
+	if days <= 0 {
+		return defaultForecastDays
+	}
+	if days > maxForecastDays {
+		return maxForecastDays
+	}
+	return days
+}
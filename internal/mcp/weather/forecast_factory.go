@@ -0,0 +1,32 @@
+package weather
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steve/llm-agents/internal/config"
+)
+
+// NewForecastProviderByName constructs the named forecast backend ("mock",
+// "open-meteo") and wraps it with retry/timeout behavior from cfg, so
+// callers always get a ForecastProvider ready to use behind the
+// getForecast tool. units is normalized with parseUnits, defaulting to
+// metric for an empty or unrecognized value. Unlike NewProviderByName,
+// there is no caching layer: a forecast changes day to day rather than
+// minute to minute, so the TTL cache that pays off for current conditions
+// wouldn't buy much here.
+func NewForecastProviderByName(name, units string, cfg config.MCPClientConfig) (ForecastProvider, error) {
+	parsedUnits := parseUnits(units)
+
+	var base ForecastProvider
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "mock":
+		base = NewMockForecastProvider(parsedUnits)
+	case "open-meteo", "openmeteo":
+		base = NewOpenMeteoForecastProvider(parsedUnits)
+	default:
+		return nil, fmt.Errorf("unknown forecast provider: %s", name)
+	}
+
+	return NewRetryingForecastProvider(base, cfg), nil
+}
@@ -0,0 +1,48 @@
+package weather
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// MockForecastProvider generates a plausible-looking random forecast
+// without calling out to any real backend - the default forecast provider
+// for local demos and tests that shouldn't depend on network access.
+type MockForecastProvider struct {
+	Units Units
+}
+
+// NewMockForecastProvider creates a MockForecastProvider reporting
+// temperatures in units.
+func NewMockForecastProvider(units Units) *MockForecastProvider {
+	return &MockForecastProvider{Units: units}
+}
+
+// GetForecast returns days random-but-plausible forecast entries for city,
+// starting from today.
+func (p *MockForecastProvider) GetForecast(ctx context.Context, city string, days int) (ForecastObservation, error) {
+	days = clampForecastDays(days)
+	unit := unitSuffix(p.Units)
+
+	today := time.Now()
+	forecastDays := make([]ForecastDay, 0, days)
+	for i := 0; i < days; i++ {
+		low := 10.0 + rand.Float64()*15.0  // 10-25C
+		high := low + 3.0 + rand.Float64()*10.0
+		if p.Units == UnitsImperial {
+			low = celsiusToFahrenheit(low)
+			high = celsiusToFahrenheit(high)
+		}
+		forecastDays = append(forecastDays, ForecastDay{
+			Date:                today.AddDate(0, 0, i).Format("2006-01-02"),
+			TemperatureMin:      low,
+			TemperatureMax:      high,
+			Unit:                unit,
+			PrecipitationChance: rand.Float64() * 100,
+			Description:         mockConditions[rand.Intn(len(mockConditions))],
+		})
+	}
+
+	return ForecastObservation{City: city, Days: forecastDays}, nil
+}
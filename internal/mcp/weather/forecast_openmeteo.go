@@ -0,0 +1,135 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// OpenMeteoForecastProvider fetches a multi-day forecast from Open-Meteo:
+// it first geocodes the city name to coordinates, then asks for the
+// "daily" block (temperature min/max, precipitation probability,
+// weathercode) referenced in the external glance code.
+type OpenMeteoForecastProvider struct {
+	Units       Units
+	GeocodeURL  string
+	ForecastURL string
+	HTTPClient  *http.Client
+}
+
+// NewOpenMeteoForecastProvider creates a provider that calls the real
+// Open-Meteo geocoding and forecast endpoints, reporting temperatures in
+// units.
+func NewOpenMeteoForecastProvider(units Units) *OpenMeteoForecastProvider {
+	return &OpenMeteoForecastProvider{
+		Units:       units,
+		GeocodeURL:  defaultOpenMeteoGeocodeURL,
+		ForecastURL: defaultOpenMeteoForecastURL,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type openMeteoDailyForecastResponse struct {
+	Daily struct {
+		Time                       []string  `json:"time"`
+		TemperatureMax             []float64 `json:"temperature_2m_max"`
+		TemperatureMin             []float64 `json:"temperature_2m_min"`
+		PrecipitationProbabilities []float64 `json:"precipitation_probability_max"`
+		WeatherCode                []int     `json:"weathercode"`
+	} `json:"daily"`
+}
+
+// GetForecast geocodes city and fetches its daily forecast from Open-Meteo.
+func (p *OpenMeteoForecastProvider) GetForecast(ctx context.Context, city string, days int) (ForecastObservation, error) {
+	days = clampForecastDays(days)
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	geocoder := &OpenMeteoProvider{GeocodeURL: p.GeocodeURL, HTTPClient: client}
+	loc, err := geocoder.geocode(ctx, client, city)
+	if err != nil {
+		return ForecastObservation{}, err
+	}
+
+	forecastURL := p.ForecastURL
+	if forecastURL == "" {
+		forecastURL = defaultOpenMeteoForecastURL
+	}
+
+	query := url.Values{}
+	query.Set("latitude", strconv.FormatFloat(loc.Latitude, 'f', -1, 64))
+	query.Set("longitude", strconv.FormatFloat(loc.Longitude, 'f', -1, 64))
+	query.Set("daily", "temperature_2m_max,temperature_2m_min,precipitation_probability_max,weathercode")
+	query.Set("forecast_days", strconv.Itoa(days))
+	query.Set("timezone", "auto")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, forecastURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return ForecastObservation{}, fmt.Errorf("failed to build Open-Meteo forecast request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ForecastObservation{}, fmt.Errorf("failed to reach Open-Meteo forecast: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return ForecastObservation{}, fmt.Errorf("%w: Open-Meteo forecast returned status %d", ErrRateLimited, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return ForecastObservation{}, fmt.Errorf("Open-Meteo forecast returned status %d", resp.StatusCode)
+	}
+
+	var forecast openMeteoDailyForecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&forecast); err != nil {
+		return ForecastObservation{}, fmt.Errorf("failed to decode Open-Meteo forecast response: %w", err)
+	}
+
+	unit := unitSuffix(p.Units)
+	forecastDays := make([]ForecastDay, 0, len(forecast.Daily.Time))
+	for i, date := range forecast.Daily.Time {
+		min := valueAt(forecast.Daily.TemperatureMin, i)
+		max := valueAt(forecast.Daily.TemperatureMax, i)
+		if p.Units == UnitsImperial {
+			min = celsiusToFahrenheit(min)
+			max = celsiusToFahrenheit(max)
+		}
+
+		forecastDays = append(forecastDays, ForecastDay{
+			Date:                date,
+			TemperatureMin:      min,
+			TemperatureMax:      max,
+			Unit:                unit,
+			PrecipitationChance: valueAt(forecast.Daily.PrecipitationProbabilities, i),
+			Description:         openMeteoWeatherCodeDescription(intAt(forecast.Daily.WeatherCode, i)),
+		})
+	}
+
+	return ForecastObservation{City: loc.Name, Days: forecastDays}, nil
+}
+
+// valueAt returns values[i], or 0 if i is out of range - Open-Meteo's daily
+// arrays are always the same length as Daily.Time, but a malformed or
+// truncated response shouldn't panic the caller.
+func valueAt(values []float64, i int) float64 {
+	if i < 0 || i >= len(values) {
+		return 0
+	}
+	return values[i]
+}
+
+// intAt is valueAt for an []int.
+func intAt(values []int, i int) int {
+	if i < 0 || i >= len(values) {
+		return 0
+	}
+	return values[i]
+}
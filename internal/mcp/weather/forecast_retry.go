@@ -0,0 +1,67 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// RetryingForecastProvider wraps another ForecastProvider with timeout and
+// retry behavior driven by the existing MCPClientConfig.Timeout/
+// RetryAttempts fields, mirroring RetryingProvider for current conditions.
+type RetryingForecastProvider struct {
+	inner    ForecastProvider
+	timeout  time.Duration
+	attempts int
+}
+
+// NewRetryingForecastProvider wraps inner, retrying a failed GetForecast up
+// to cfg.RetryAttempts times (at least once) and bounding each attempt to
+// cfg.Timeout when set.
+func NewRetryingForecastProvider(inner ForecastProvider, cfg config.MCPClientConfig) *RetryingForecastProvider {
+	attempts := cfg.RetryAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &RetryingForecastProvider{
+		inner:    inner,
+		timeout:  cfg.Timeout,
+		attempts: attempts,
+	}
+}
+
+// GetForecast calls inner.GetForecast, retrying on error up to
+// p.attempts times. A ErrRateLimited failure is returned immediately
+// without retrying, same as RetryingProvider.Get.
+func (p *RetryingForecastProvider) GetForecast(ctx context.Context, city string, days int) (ForecastObservation, error) {
+	var lastErr error
+	for attempt := 1; attempt <= p.attempts; attempt++ {
+		attemptCtx := ctx
+		if p.timeout > 0 {
+			var cancel context.CancelFunc
+			attemptCtx, cancel = context.WithTimeout(ctx, p.timeout)
+			defer cancel()
+		}
+
+		obs, err := p.inner.GetForecast(attemptCtx, city, days)
+		if err == nil {
+			return obs, nil
+		}
+
+		lastErr = err
+		if errors.Is(err, ErrRateLimited) {
+			utils.Warn("weather forecast provider call for %q rate limited, not retrying: %v", city, err)
+			break
+		}
+
+		utils.Warn("weather forecast provider call for %q failed (attempt %d/%d): %v", city, attempt, p.attempts, err)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return ForecastObservation{}, lastErr
+}
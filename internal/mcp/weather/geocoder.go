@@ -0,0 +1,12 @@
+package weather
+
+import "context"
+
+// Geocoder resolves a free-form place name (city, "city, country", postal
+// code, ...) to coordinates. Implementations vary in coverage and cost:
+// StaticGeocoder is a free, US-only, offline fallback; NominatimGeocoder
+// and CensusGeocoder call out to a real geocoding service; CachedGeocoder
+// wraps any of them to avoid repeating identical lookups.
+type Geocoder interface {
+	Lookup(ctx context.Context, query string) (lat, lon float64, resolvedName, countryCode string, err error)
+}
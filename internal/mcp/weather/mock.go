@@ -0,0 +1,38 @@
+package weather
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// mockConditions mirrors the demo descriptions the inline getTemperature
+// handler used to generate before it moved behind the Provider interface.
+var mockConditions = []string{"Sunny", "Partly cloudy", "Cloudy", "Light rain", "Clear"}
+
+// MockProvider generates a plausible-looking random observation without
+// calling out to any real backend - the default provider for local demos
+// and tests that shouldn't depend on network access or an API key.
+type MockProvider struct {
+	Units Units
+}
+
+// NewMockProvider creates a MockProvider reporting temperatures in units.
+func NewMockProvider(units Units) *MockProvider {
+	return &MockProvider{Units: units}
+}
+
+// Get returns a random-but-plausible observation for city.
+func (p *MockProvider) Get(ctx context.Context, city string) (Observation, error) {
+	temperature := 20.0 + rand.Float64()*25.0 // 20-45C, matching the prior inline range
+	if p.Units == UnitsImperial {
+		temperature = celsiusToFahrenheit(temperature)
+	}
+	return Observation{
+		City:        city,
+		Temperature: temperature,
+		Unit:        unitSuffix(p.Units),
+		Description: mockConditions[rand.Intn(len(mockConditions))],
+		Timestamp:   time.Now(),
+	}, nil
+}
@@ -0,0 +1,119 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steve/llm-agents/internal/resilience"
+)
+
+// defaultNominatimURL is OpenStreetMap's public Nominatim search endpoint;
+// tests point NominatimGeocoder.BaseURL at an httptest.Server instead.
+const defaultNominatimURL = "https://nominatim.openstreetmap.org/search"
+
+// nominatimUserAgent identifies this deployment to Nominatim, as required
+// by its usage policy (https://operations.osmfoundation.org/policies/nominatim/):
+// unidentified traffic is blocked outright.
+const nominatimUserAgent = "llm-agents-weather-mcp/1.0"
+
+// nominatimRateLimit caps requests to Nominatim's public instance at 1/s,
+// the limit its usage policy asks self-hosted clients to respect.
+var nominatimRateLimit = resilience.RateLimitConfig{RatePerSecond: 1, Burst: 1}
+
+// NominatimGeocoder resolves place names worldwide using OpenStreetMap's
+// Nominatim service. Unlike CensusGeocoder it isn't limited to the US, so
+// it's the default geocoder for international city names.
+type NominatimGeocoder struct {
+	BaseURL    string
+	HTTPClient *http.Client
+
+	limiter *resilience.TokenBucket
+}
+
+// NewNominatimGeocoder creates a geocoder against the public Nominatim
+// instance, rate limited to nominatimRateLimit as its usage policy requires.
+func NewNominatimGeocoder() *NominatimGeocoder {
+	return &NominatimGeocoder{
+		BaseURL:    defaultNominatimURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		limiter:    resilience.NewTokenBucket(nominatimRateLimit),
+	}
+}
+
+type nominatimResult struct {
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	DisplayName string `json:"display_name"`
+	Address     struct {
+		CountryCode string `json:"country_code"`
+	} `json:"address"`
+}
+
+// Lookup resolves query via Nominatim's /search endpoint, waiting on the
+// rate limiter first so a burst of calls doesn't violate its usage policy.
+func (g *NominatimGeocoder) Lookup(ctx context.Context, query string) (lat, lon float64, resolvedName, countryCode string, err error) {
+	if err := g.limiter.Wait(ctx); err != nil {
+		return 0, 0, "", "", fmt.Errorf("rate limit wait canceled: %w", err)
+	}
+
+	baseURL := g.BaseURL
+	if baseURL == "" {
+		baseURL = defaultNominatimURL
+	}
+
+	q := url.Values{}
+	q.Set("q", query)
+	q.Set("format", "jsonv2")
+	q.Set("addressdetails", "1")
+	q.Set("limit", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return 0, 0, "", "", fmt.Errorf("failed to build Nominatim request: %w", err)
+	}
+	req.Header.Set("User-Agent", nominatimUserAgent)
+
+	client := g.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, 0, "", "", fmt.Errorf("failed to reach Nominatim: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return 0, 0, "", "", fmt.Errorf("%w: Nominatim returned status %d", ErrRateLimited, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, 0, "", "", fmt.Errorf("Nominatim returned status %d", resp.StatusCode)
+	}
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return 0, 0, "", "", fmt.Errorf("failed to decode Nominatim response: %w", err)
+	}
+	if len(results) == 0 {
+		return 0, 0, "", "", fmt.Errorf("location not found: %s", query)
+	}
+
+	result := results[0]
+	lat, err = strconv.ParseFloat(result.Lat, 64)
+	if err != nil {
+		return 0, 0, "", "", fmt.Errorf("invalid latitude in Nominatim response: %w", err)
+	}
+	lon, err = strconv.ParseFloat(result.Lon, 64)
+	if err != nil {
+		return 0, 0, "", "", fmt.Errorf("invalid longitude in Nominatim response: %w", err)
+	}
+
+	return lat, lon, result.DisplayName, strings.ToUpper(result.Address.CountryCode), nil
+}
@@ -0,0 +1,170 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// defaultNWSBaseURL is the National Weather Service's public API; tests
+// point NWSProvider.BaseURL at an httptest.Server instead.
+const defaultNWSBaseURL = "https://api.weather.gov"
+
+// nwsUserAgent identifies this deployment to api.weather.gov, which
+// requires one on every request.
+const nwsUserAgent = "llm-agents/1.0"
+
+// NWSProvider fetches current conditions from the National Weather
+// Service. api.weather.gov only has grid data for US coordinates, so a
+// Geocoder resolution outside the US is routed to Fallback (typically
+// Open-Meteo) instead of being sent to NWS at all.
+type NWSProvider struct {
+	Geocoder Geocoder
+	Fallback Provider
+	Units    Units
+
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewNWSProvider creates a provider that geocodes each city via geocoder,
+// serves US results from api.weather.gov, and routes everything else to
+// fallback.
+func NewNWSProvider(geocoder Geocoder, fallback Provider, units Units) *NWSProvider {
+	return &NWSProvider{
+		Geocoder:   geocoder,
+		Fallback:   fallback,
+		Units:      units,
+		BaseURL:    defaultNWSBaseURL,
+		HTTPClient: &http.Client{Timeout: 25 * time.Second},
+	}
+}
+
+// Get resolves city to coordinates and a country code via p.Geocoder, then
+// either fetches current conditions from NWS (US) or delegates to
+// p.Fallback (everywhere else).
+func (p *NWSProvider) Get(ctx context.Context, city string) (Observation, error) {
+	lat, lon, resolvedName, countryCode, err := p.Geocoder.Lookup(ctx, city)
+	if err != nil {
+		return Observation{}, fmt.Errorf("city not found: %s: %w", city, err)
+	}
+
+	if countryCode != "" && countryCode != "US" {
+		if p.Fallback == nil {
+			return Observation{}, fmt.Errorf("%s resolved outside the US (%s) and no fallback provider is configured", city, countryCode)
+		}
+		return p.Fallback.Get(ctx, city)
+	}
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	baseURL := p.BaseURL
+	if baseURL == "" {
+		baseURL = defaultNWSBaseURL
+	}
+
+	forecastURL, err := p.gridForecastURL(ctx, client, baseURL, lat, lon)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	temperature, description, err := p.currentConditions(ctx, client, forecastURL)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	// NWS always reports Fahrenheit; convert down to Celsius if the caller
+	// asked for metric.
+	if p.Units == UnitsMetric {
+		temperature = (temperature - 32) * 5 / 9
+	}
+
+	return Observation{
+		City:        resolvedName,
+		Temperature: temperature,
+		Unit:        unitSuffix(p.Units),
+		Description: description,
+		Timestamp:   time.Now(),
+	}, nil
+}
+
+// gridForecastURL looks up the forecast endpoint for lat/lon via NWS's
+// /points/{lat},{lon} grid lookup.
+func (p *NWSProvider) gridForecastURL(ctx context.Context, client *http.Client, baseURL string, lat, lon float64) (string, error) {
+	gridURL := fmt.Sprintf("%s/points/%.4f,%.4f", baseURL, lat, lon)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gridURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create grid request: %w", err)
+	}
+	req.Header.Set("User-Agent", nwsUserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch grid data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return "", fmt.Errorf("%w: NWS grid lookup returned status %d", ErrRateLimited, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("NWS grid lookup returned status %d", resp.StatusCode)
+	}
+
+	var gridData struct {
+		Properties struct {
+			ForecastURL string `json:"forecast"`
+		} `json:"properties"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&gridData); err != nil {
+		return "", fmt.Errorf("failed to parse grid response: %w", err)
+	}
+
+	return gridData.Properties.ForecastURL, nil
+}
+
+// currentConditions fetches forecastURL and returns the first (current)
+// period's temperature in Fahrenheit and its short description.
+func (p *NWSProvider) currentConditions(ctx context.Context, client *http.Client, forecastURL string) (float64, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, forecastURL, nil)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to create forecast request: %w", err)
+	}
+	req.Header.Set("User-Agent", nwsUserAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to fetch forecast: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return 0, "", fmt.Errorf("%w: NWS forecast returned status %d", ErrRateLimited, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, "", fmt.Errorf("NWS forecast returned status %d", resp.StatusCode)
+	}
+
+	var forecastData struct {
+		Properties struct {
+			Periods []struct {
+				Temperature   float64 `json:"temperature"`
+				ShortForecast string  `json:"shortForecast"`
+			} `json:"periods"`
+		} `json:"properties"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&forecastData); err != nil {
+		return 0, "", fmt.Errorf("failed to parse forecast: %w", err)
+	}
+	if len(forecastData.Properties.Periods) == 0 {
+		return 0, "", fmt.Errorf("no forecast data available")
+	}
+
+	current := forecastData.Properties.Periods[0]
+	return current.Temperature, current.ShortForecast, nil
+}
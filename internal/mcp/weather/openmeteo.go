@@ -0,0 +1,186 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Open-Meteo needs no API key, unlike OpenWeatherMap; tests point
+// GeocodeURL/ForecastURL at httptest.Servers instead of these.
+const (
+	defaultOpenMeteoGeocodeURL  = "https://geocoding-api.open-meteo.com/v1/search"
+	defaultOpenMeteoForecastURL = "https://api.open-meteo.com/v1/forecast"
+)
+
+// OpenMeteoProvider fetches current conditions from Open-Meteo: it first
+// geocodes the city name to coordinates, then asks for the current weather
+// at that location.
+type OpenMeteoProvider struct {
+	Units       Units
+	GeocodeURL  string
+	ForecastURL string
+	HTTPClient  *http.Client
+}
+
+// NewOpenMeteoProvider creates a provider that calls the real Open-Meteo
+// geocoding and forecast endpoints, reporting temperatures in units.
+func NewOpenMeteoProvider(units Units) *OpenMeteoProvider {
+	return &OpenMeteoProvider{
+		Units:       units,
+		GeocodeURL:  defaultOpenMeteoGeocodeURL,
+		ForecastURL: defaultOpenMeteoForecastURL,
+		HTTPClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type openMeteoGeocodeResponse struct {
+	Results []struct {
+		Name      string  `json:"name"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	} `json:"results"`
+}
+
+type openMeteoForecastResponse struct {
+	CurrentWeather struct {
+		Temperature float64 `json:"temperature"`
+		WeatherCode int     `json:"weathercode"`
+	} `json:"current_weather"`
+}
+
+// Get geocodes city and fetches its current conditions from Open-Meteo.
+func (p *OpenMeteoProvider) Get(ctx context.Context, city string) (Observation, error) {
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	loc, err := p.geocode(ctx, client, city)
+	if err != nil {
+		return Observation{}, err
+	}
+
+	forecastURL := p.ForecastURL
+	if forecastURL == "" {
+		forecastURL = defaultOpenMeteoForecastURL
+	}
+
+	query := url.Values{}
+	query.Set("latitude", strconv.FormatFloat(loc.Latitude, 'f', -1, 64))
+	query.Set("longitude", strconv.FormatFloat(loc.Longitude, 'f', -1, 64))
+	query.Set("current_weather", "true")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, forecastURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return Observation{}, fmt.Errorf("failed to build Open-Meteo forecast request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Observation{}, fmt.Errorf("failed to reach Open-Meteo forecast: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return Observation{}, fmt.Errorf("%w: Open-Meteo forecast returned status %d", ErrRateLimited, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Observation{}, fmt.Errorf("Open-Meteo forecast returned status %d", resp.StatusCode)
+	}
+
+	var forecast openMeteoForecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&forecast); err != nil {
+		return Observation{}, fmt.Errorf("failed to decode Open-Meteo forecast response: %w", err)
+	}
+
+	// Open-Meteo's current_weather is always Celsius regardless of units
+	// requested; convert here since there's no request-side unit knob to
+	// set like OpenWeatherMap's.
+	temperature := forecast.CurrentWeather.Temperature
+	if p.Units == UnitsImperial {
+		temperature = celsiusToFahrenheit(temperature)
+	}
+
+	return Observation{
+		City:        loc.Name,
+		Temperature: temperature,
+		Unit:        unitSuffix(p.Units),
+		Description: openMeteoWeatherCodeDescription(forecast.CurrentWeather.WeatherCode),
+		Timestamp:   time.Now(),
+	}, nil
+}
+
+type openMeteoLocation struct {
+	Name      string
+	Latitude  float64
+	Longitude float64
+}
+
+// geocode resolves city to coordinates using Open-Meteo's geocoding API.
+func (p *OpenMeteoProvider) geocode(ctx context.Context, client *http.Client, city string) (openMeteoLocation, error) {
+	geocodeURL := p.GeocodeURL
+	if geocodeURL == "" {
+		geocodeURL = defaultOpenMeteoGeocodeURL
+	}
+
+	query := url.Values{}
+	query.Set("name", city)
+	query.Set("count", "1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, geocodeURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return openMeteoLocation{}, fmt.Errorf("failed to build Open-Meteo geocode request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return openMeteoLocation{}, fmt.Errorf("failed to reach Open-Meteo geocoder: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return openMeteoLocation{}, fmt.Errorf("%w: Open-Meteo geocoder returned status %d", ErrRateLimited, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return openMeteoLocation{}, fmt.Errorf("Open-Meteo geocoder returned status %d", resp.StatusCode)
+	}
+
+	var geocodeResp openMeteoGeocodeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&geocodeResp); err != nil {
+		return openMeteoLocation{}, fmt.Errorf("failed to decode Open-Meteo geocode response: %w", err)
+	}
+	if len(geocodeResp.Results) == 0 {
+		return openMeteoLocation{}, fmt.Errorf("city not found: %s", city)
+	}
+
+	result := geocodeResp.Results[0]
+	return openMeteoLocation{Name: result.Name, Latitude: result.Latitude, Longitude: result.Longitude}, nil
+}
+
+// openMeteoWeatherCodeDescription maps a subset of Open-Meteo's WMO weather
+// codes to a short human-readable description.
+func openMeteoWeatherCodeDescription(code int) string {
+	switch {
+	case code == 0:
+		return "Clear"
+	case code <= 3:
+		return "Partly cloudy"
+	case code <= 48:
+		return "Fog"
+	case code <= 67:
+		return "Rain"
+	case code <= 77:
+		return "Snow"
+	case code <= 82:
+		return "Rain showers"
+	case code <= 86:
+		return "Snow showers"
+	default:
+		return "Thunderstorm"
+	}
+}
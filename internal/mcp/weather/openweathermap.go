@@ -0,0 +1,103 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// defaultOpenWeatherMapBaseURL is OpenWeatherMap's current-weather
+// endpoint; tests point BaseURL at an httptest.Server instead.
+const defaultOpenWeatherMapBaseURL = "https://api.openweathermap.org/data/2.5/weather"
+
+// OpenWeatherMapProvider fetches current conditions from OpenWeatherMap's
+// REST API.
+type OpenWeatherMapProvider struct {
+	APIKey     string
+	Units      Units
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// NewOpenWeatherMapProvider creates a provider that calls the real
+// OpenWeatherMap endpoint using apiKey, reporting temperatures in units.
+func NewOpenWeatherMapProvider(apiKey string, units Units) *OpenWeatherMapProvider {
+	return &OpenWeatherMapProvider{
+		APIKey:     apiKey,
+		Units:      units,
+		BaseURL:    defaultOpenWeatherMapBaseURL,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type openWeatherMapResponse struct {
+	Main struct {
+		Temp float64 `json:"temp"`
+	} `json:"main"`
+	Weather []struct {
+		Description string `json:"description"`
+	} `json:"weather"`
+	Name string `json:"name"`
+}
+
+// Get fetches the current conditions for city from OpenWeatherMap.
+func (p *OpenWeatherMapProvider) Get(ctx context.Context, city string) (Observation, error) {
+	baseURL := p.BaseURL
+	if baseURL == "" {
+		baseURL = defaultOpenWeatherMapBaseURL
+	}
+
+	owmUnits := "metric"
+	if p.Units == UnitsImperial {
+		owmUnits = "imperial"
+	}
+
+	query := url.Values{}
+	query.Set("q", city)
+	query.Set("appid", p.APIKey)
+	query.Set("units", owmUnits)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return Observation{}, fmt.Errorf("failed to build OpenWeatherMap request: %w", err)
+	}
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Observation{}, fmt.Errorf("failed to reach OpenWeatherMap: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return Observation{}, fmt.Errorf("%w: OpenWeatherMap returned status %d", ErrRateLimited, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Observation{}, fmt.Errorf("OpenWeatherMap returned status %d", resp.StatusCode)
+	}
+
+	var data openWeatherMapResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return Observation{}, fmt.Errorf("failed to decode OpenWeatherMap response: %w", err)
+	}
+
+	description := ""
+	if len(data.Weather) > 0 {
+		description = data.Weather[0].Description
+	}
+
+	return Observation{
+		City:        city,
+		Temperature: data.Main.Temp,
+		Unit:        unitSuffix(p.Units),
+		Description: description,
+		Timestamp:   time.Now(),
+	}, nil
+}
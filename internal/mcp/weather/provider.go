@@ -0,0 +1,54 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrRateLimited marks a provider error as a rate-limit response (HTTP 429)
+// rather than an ordinary failure, so callers can distinguish "try again
+// later" from a broken request or an unreachable backend.
+var ErrRateLimited = errors.New("weather provider rate limit exceeded")
+
+// Units selects the measurement system a Provider reports temperatures in.
+type Units string
+
+const (
+	UnitsMetric   Units = "metric"
+	UnitsImperial Units = "imperial"
+)
+
+// unitSuffix returns the "°C"/"°F" suffix NewProviderByName's providers use
+// to label an Observation.Unit, defaulting to metric for an empty or
+// unrecognized value.
+func unitSuffix(units Units) string {
+	if units == UnitsImperial {
+		return "°F"
+	}
+	return "°C"
+}
+
+// celsiusToFahrenheit converts a Celsius reading for providers whose API
+// always returns metric regardless of the units a caller asked for.
+func celsiusToFahrenheit(celsius float64) float64 {
+	return celsius*9/5 + 32
+}
+
+// Observation is a single point-in-time weather reading for a city,
+// normalized across all Provider implementations regardless of backend.
+type Observation struct {
+	City        string
+	Temperature float64
+	Unit        string
+	Description string
+	Timestamp   time.Time
+}
+
+// Provider retrieves the current weather observation for a city. Each
+// backend (mock, OpenWeatherMap, Open-Meteo) implements this the same way,
+// so callers can swap providers via configuration without touching the MCP
+// tool schema built on top of it.
+type Provider interface {
+	Get(ctx context.Context, city string) (Observation, error)
+}
@@ -0,0 +1,68 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// RetryingProvider wraps another Provider with timeout and retry behavior
+// driven by the existing MCPClientConfig.Timeout/RetryAttempts fields, so a
+// transient backend failure doesn't surface as an MCP tool-call error.
+type RetryingProvider struct {
+	inner    Provider
+	timeout  time.Duration
+	attempts int
+}
+
+// NewRetryingProvider wraps inner, retrying a failed Get up to
+// cfg.RetryAttempts times (at least once) and bounding each attempt to
+// cfg.Timeout when set.
+func NewRetryingProvider(inner Provider, cfg config.MCPClientConfig) *RetryingProvider {
+	attempts := cfg.RetryAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	return &RetryingProvider{
+		inner:    inner,
+		timeout:  cfg.Timeout,
+		attempts: attempts,
+	}
+}
+
+// Get calls inner.Get, retrying on error up to p.attempts times. A
+// ErrRateLimited failure is returned immediately without retrying: hammering
+// a provider that just told us to back off would only make the rate limit
+// worse.
+func (p *RetryingProvider) Get(ctx context.Context, city string) (Observation, error) {
+	var lastErr error
+	for attempt := 1; attempt <= p.attempts; attempt++ {
+		attemptCtx := ctx
+		if p.timeout > 0 {
+			var cancel context.CancelFunc
+			attemptCtx, cancel = context.WithTimeout(ctx, p.timeout)
+			defer cancel()
+		}
+
+		obs, err := p.inner.Get(attemptCtx, city)
+		if err == nil {
+			return obs, nil
+		}
+
+		lastErr = err
+		if errors.Is(err, ErrRateLimited) {
+			utils.Warn("weather provider call for %q rate limited, not retrying: %v", city, err)
+			break
+		}
+
+		utils.Warn("weather provider call for %q failed (attempt %d/%d): %v", city, attempt, p.attempts, err)
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return Observation{}, lastErr
+}
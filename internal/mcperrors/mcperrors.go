@@ -0,0 +1,83 @@
+// Package mcperrors defines the JSON-RPC 2.0 error vocabulary shared by the
+// MCP servers (datetime-mcp, weather-mcp, echo-mcp), the MCP client, and the
+// sub-agents/coordinator built on top of it. Every layer constructs and
+// inspects the same *Error type instead of matching substrings of an error
+// message, so a caller can tell a permanent failure from one worth retrying.
+package mcperrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Standard JSON-RPC 2.0 error codes.
+// See https://www.jsonrpc.org/specification#error_object.
+const (
+	ErrParseError     = -32700
+	ErrInvalidRequest = -32600
+	ErrMethodNotFound = -32601
+	ErrInvalidParams  = -32602
+	ErrInternalError  = -32603
+)
+
+// Server-defined error codes, in the -32000..-32099 range the spec reserves
+// for implementation-specific errors.
+const (
+	ErrCityNotFound    = -32001
+	ErrUpstreamTimeout = -32002
+	ErrRateLimited     = -32003
+)
+
+// RetryHint is the structured payload an Error carries in Data for a code
+// worth retrying (e.g. ErrUpstreamTimeout, ErrRateLimited). RetryAfterMs is
+// a suggestion, not a guarantee.
+type RetryHint struct {
+	RetryAfterMs int  `json:"retry_after_ms"`
+	Retryable    bool `json:"retryable"`
+}
+
+// Error is a JSON-RPC 2.0 error, with the same wire shape as
+// models.MCPError so a server's response and a client's decoded error
+// round-trip through the same fields.
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("mcp error %d: %s", e.Code, e.Message)
+}
+
+// New builds a non-retryable Error.
+func New(code int, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// NewRetryable builds an Error carrying a RetryHint that tells a caller to
+// retry after retryAfter (e.g. for ErrUpstreamTimeout or ErrRateLimited).
+func NewRetryable(code int, message string, retryAfter time.Duration) *Error {
+	data, err := json.Marshal(RetryHint{
+		RetryAfterMs: int(retryAfter.Milliseconds()),
+		Retryable:    true,
+	})
+	if err != nil {
+		// RetryHint is a fixed, always-marshalable struct.
+		panic(fmt.Sprintf("mcperrors: marshal RetryHint: %v", err))
+	}
+	return &Error{Code: code, Message: message, Data: data}
+}
+
+// RetryHint extracts the RetryHint carried in e.Data, if any was set.
+func (e *Error) RetryHint() (RetryHint, bool) {
+	if len(e.Data) == 0 {
+		return RetryHint{}, false
+	}
+	var hint RetryHint
+	if err := json.Unmarshal(e.Data, &hint); err != nil {
+		return RetryHint{}, false
+	}
+	return hint, true
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+)
+
+// SetCertExpiryFromFile parses the PEM certificate at certPath and records
+// its days-until-expiry under label in the cert_expiry_days gauge.
+func SetCertExpiryFromFile(label, certPath string) error {
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return fmt.Errorf("failed to read certificate %s: %w", certPath, err)
+	}
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return fmt.Errorf("failed to parse certificate PEM: %s", certPath)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse certificate %s: %w", certPath, err)
+	}
+
+	SetCertExpiryDays(label, cert.NotAfter)
+	return nil
+}
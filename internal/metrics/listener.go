@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// NewHandshakeListener wraps inner so every accepted connection is upgraded
+// to TLS with a per-connection VerifyConnection hook that records handshake
+// duration (accept to verified) and tracks the active-connection gauge for
+// as long as the connection stays open.
+func NewHandshakeListener(inner net.Listener, tlsConfig *tls.Config) net.Listener {
+	return &handshakeListener{Listener: inner, tlsConfig: tlsConfig}
+}
+
+type handshakeListener struct {
+	net.Listener
+	tlsConfig *tls.Config
+}
+
+func (l *handshakeListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	acceptedAt := time.Now()
+	cfg := l.tlsConfig.Clone()
+	priorVerify := cfg.VerifyConnection
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		ObserveTLSHandshakeDuration(time.Since(acceptedAt))
+		if priorVerify != nil {
+			if err := priorVerify(cs); err != nil {
+				IncTLSHandshakeFailure()
+				ctx := utils.ContextWithFields(context.Background(), utils.Fields{
+					"remote_addr": conn.RemoteAddr().String(),
+				})
+				utils.FromContext(ctx).Warn("TLS handshake failed: %v", err)
+				return err
+			}
+		}
+		return nil
+	}
+
+	IncActiveConnections()
+	return &countedConn{Conn: tls.Server(conn, cfg)}, nil
+}
+
+// countedConn decrements the active-connection gauge exactly once, the
+// first time Close is called, since callers (and net/http) may close a
+// connection more than once during error handling.
+type countedConn struct {
+	net.Conn
+	closeOnce sync.Once
+}
+
+func (c *countedConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		DecActiveConnections()
+		err = c.Conn.Close()
+	})
+	return err
+}
@@ -0,0 +1,262 @@
+// Package metrics exposes the Prometheus instruments backing the MCP
+// servers' observability surface: TLS handshake latency, per-tool call
+// latency split by scheme, active connection count, certificate expiry, and
+// the RSS growth incurred by server startup.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Scheme labels distinguish plaintext HTTP calls from TLS-protected ones in
+// the per-tool call latency histogram.
+const (
+	SchemeHTTP  = "http"
+	SchemeHTTPS = "https"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	tlsHandshakeDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "weather_mcp_tls_handshake_duration_seconds",
+		Help:    "Time from TCP accept to completed TLS handshake verification.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	toolCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "weather_mcp_tool_call_duration_seconds",
+		Help:    "MCP tool call latency, labeled by tool name and transport scheme.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"tool", "scheme"})
+
+	activeConnections = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "weather_mcp_active_tls_connections",
+		Help: "Number of currently open TLS connections accepted by the server.",
+	})
+
+	certExpiryDays = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "weather_mcp_cert_expiry_days",
+		Help: "Days remaining before a tracked certificate's NotAfter, labeled by cert role.",
+	}, []string{"cert"})
+
+	startupRSSDeltaBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "weather_mcp_startup_rss_delta_bytes",
+		Help: "Resident set size growth between process start and server readiness.",
+	})
+
+	tlsHandshakeFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "weather_mcp_tls_handshake_failures_total",
+		Help: "TLS handshakes that failed VerifyConnection (untrusted chain, revoked, unauthorized peer).",
+	})
+
+	rpcRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "weather_mcp_rpc_requests_total",
+		Help: "JSON-RPC requests handled, labeled by method.",
+	}, []string{"method"})
+
+	// rpcRequestDuration uses exponential buckets from 100µs so per-method
+	// latency is resolved to sub-millisecond precision instead of the
+	// integer-millisecond granularity DefBuckets' 5ms floor would give an
+	// in-process call like this.
+	rpcRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "weather_mcp_rpc_request_duration_seconds",
+		Help:    "JSON-RPC request latency, labeled by method.",
+		Buckets: prometheus.ExponentialBuckets(0.0001, 2, 20),
+	}, []string{"method"})
+
+	rpcInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "weather_mcp_rpc_requests_in_flight",
+		Help: "JSON-RPC requests currently being handled.",
+	})
+
+	rpcErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "weather_mcp_rpc_errors_total",
+		Help: "JSON-RPC error responses, labeled by JSON-RPC error code.",
+	}, []string{"code"})
+
+	certExpirySeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "weather_mcp_cert_expiry_seconds",
+		Help: "Seconds remaining before a RenewalMonitor-tracked certificate's NotAfter, labeled by subject.",
+	}, []string{"subject"})
+
+	certRenewalsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "weather_mcp_cert_renewals_total",
+		Help: "Certificate renewals performed by a RenewalMonitor, labeled by subject.",
+	}, []string{"subject"})
+
+	sseConnectedClients = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "weather_mcp_sse_connected_clients",
+		Help: "Number of currently connected SSE clients across all sessions.",
+	})
+
+	sseDroppedFramesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "weather_mcp_sse_dropped_frames_total",
+		Help: "Frames dropped because an SSE client's send queue stayed full, evicting the stalled consumer.",
+	})
+
+	sseQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "weather_mcp_sse_queue_depth",
+		Help: "Most recently observed depth of an SSE client's pending-frame send queue.",
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		tlsHandshakeDuration,
+		toolCallDuration,
+		activeConnections,
+		certExpiryDays,
+		startupRSSDeltaBytes,
+		tlsHandshakeFailures,
+		rpcRequestsTotal,
+		rpcRequestDuration,
+		rpcInFlight,
+		rpcErrorsTotal,
+		certExpirySeconds,
+		certRenewalsTotal,
+		sseConnectedClients,
+		sseDroppedFramesTotal,
+		sseQueueDepth,
+	)
+}
+
+// Handler returns the /metrics endpoint for this package's registry.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveTLSHandshakeDuration records the time elapsed between accepting a
+// connection and its TLS handshake completing verification.
+func ObserveTLSHandshakeDuration(d time.Duration) {
+	tlsHandshakeDuration.Observe(d.Seconds())
+}
+
+// ObserveToolCallDuration records how long an MCP tool call took, split by
+// the scheme (http/https) the call arrived on.
+func ObserveToolCallDuration(tool, scheme string, d time.Duration) {
+	toolCallDuration.WithLabelValues(tool, scheme).Observe(d.Seconds())
+}
+
+// IncActiveConnections marks a new TLS connection as open.
+func IncActiveConnections() {
+	activeConnections.Inc()
+}
+
+// DecActiveConnections marks a previously counted TLS connection as closed.
+func DecActiveConnections() {
+	activeConnections.Dec()
+}
+
+// IncTLSHandshakeFailure records a handshake that failed VerifyConnection,
+// e.g. an untrusted chain, a revoked certificate, or an unauthorized peer
+// identity.
+func IncTLSHandshakeFailure() {
+	tlsHandshakeFailures.Inc()
+}
+
+// ObserveRPCRequest records one completed JSON-RPC call: its per-method
+// request counter and latency, plus an error-code counter if code is
+// non-zero (the JSON-RPC success case has no error code to count).
+func ObserveRPCRequest(method string, code int, d time.Duration) {
+	rpcRequestsTotal.WithLabelValues(method).Inc()
+	rpcRequestDuration.WithLabelValues(method).Observe(d.Seconds())
+	if code != 0 {
+		rpcErrorsTotal.WithLabelValues(strconv.Itoa(code)).Inc()
+	}
+}
+
+// IncRPCInFlight marks a JSON-RPC request as started.
+func IncRPCInFlight() {
+	rpcInFlight.Inc()
+}
+
+// DecRPCInFlight marks a previously counted JSON-RPC request as finished.
+func DecRPCInFlight() {
+	rpcInFlight.Dec()
+}
+
+// SetCertExpiryDays records the number of days remaining before notAfter
+// for the certificate identified by label (e.g. "server", "ca").
+func SetCertExpiryDays(label string, notAfter time.Time) {
+	days := time.Until(notAfter).Hours() / 24
+	certExpiryDays.WithLabelValues(label).Set(days)
+}
+
+// SetCertExpirySeconds records the seconds remaining before notAfter for
+// the certificate identified by subject (its parsed Subject DN), for a
+// RenewalMonitor's CheckExpiry scan. Unlike SetCertExpiryDays, callers pass
+// the cert's own subject rather than a fixed role label, since a
+// RenewalMonitor tracks an arbitrary set of certs rather than one
+// deployment's fixed server/client/CA roles.
+func SetCertExpirySeconds(subject string, notAfter time.Time) {
+	certExpirySeconds.WithLabelValues(subject).Set(time.Until(notAfter).Seconds())
+}
+
+// IncCertRenewals increments the renewal counter for subject, called once
+// per successful RenewalMonitor.Renew.
+func IncCertRenewals(subject string) {
+	certRenewalsTotal.WithLabelValues(subject).Inc()
+}
+
+// RecordStartupRSSDelta sets the startup RSS gauge to the difference
+// between the process's current RSS and baselineBytes (typically sampled
+// at the top of main before any TLS/cert work runs). Errors reading the
+// current RSS are ignored; the gauge is simply left unset.
+func RecordStartupRSSDelta(baselineBytes int64) {
+	current, err := ReadProcessRSSBytes()
+	if err != nil {
+		return
+	}
+	startupRSSDeltaBytes.Set(float64(current - baselineBytes))
+}
+
+// IncSSEClients marks a new SSE client as connected.
+func IncSSEClients() {
+	sseConnectedClients.Inc()
+}
+
+// DecSSEClients marks a previously counted SSE client as disconnected.
+func DecSSEClients() {
+	sseConnectedClients.Dec()
+}
+
+// IncSSEDroppedFrames records a frame dropped by evicting a stalled SSE
+// client whose send queue stayed full.
+func IncSSEDroppedFrames() {
+	sseDroppedFramesTotal.Inc()
+}
+
+// SetSSEQueueDepth records the depth of an SSE client's send queue just
+// after a frame was enqueued, as a point-in-time signal of fan-out
+// backpressure building up.
+func SetSSEQueueDepth(depth int) {
+	sseQueueDepth.Set(float64(depth))
+}
+
+type contextKey int
+
+const schemeContextKey contextKey = iota
+
+// ContextWithScheme attaches the transport scheme (SchemeHTTP/SchemeHTTPS)
+// a request arrived on to ctx, so downstream tool handlers can label their
+// call-latency observations without threading the *http.Request through.
+func ContextWithScheme(ctx context.Context, scheme string) context.Context {
+	return context.WithValue(ctx, schemeContextKey, scheme)
+}
+
+// SchemeFromContext returns the scheme attached by ContextWithScheme,
+// defaulting to SchemeHTTP if none was attached.
+func SchemeFromContext(ctx context.Context) string {
+	if scheme, ok := ctx.Value(schemeContextKey).(string); ok {
+		return scheme
+	}
+	return SchemeHTTP
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ReadProcessRSSBytes reads the process's current resident set size from
+// /proc/self/status. It is Linux-specific; on other platforms it returns an
+// error and callers should treat RSS tracking as best-effort.
+func ReadProcessRSSBytes() (int64, error) {
+	f, err := os.Open("/proc/self/status")
+	if err != nil {
+		return 0, fmt.Errorf("failed to open /proc/self/status: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "VmRSS:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return 0, fmt.Errorf("unexpected VmRSS line format: %q", line)
+		}
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("failed to parse VmRSS value: %w", err)
+		}
+		return kb * 1024, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to scan /proc/self/status: %w", err)
+	}
+	return 0, fmt.Errorf("VmRSS not found in /proc/self/status")
+}
@@ -1,13 +1,20 @@
 // Package models defines the core data structures for the multi-agent system
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Query represents user's natural language input
 type Query struct {
-	ID        string    `json:"id"`
-	Text      string    `json:"text"`
-	City      string    `json:"city"`
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	City string `json:"city"`
+	// Timezone, if set, bypasses city-based timezone resolution entirely
+	// for datetime queries (e.g. "Europe/Berlin" from the CLI's
+	// -timezone flag).
+	Timezone  string    `json:"timezone,omitempty"`
 	QueryType QueryType `json:"query_type"`
 	Timestamp time.Time `json:"timestamp"`
 }
@@ -15,6 +22,17 @@ type Query struct {
 // QueryType defines the type of query being made
 type QueryType int
 
+// ConversationTurn records one exchange in an interactive session: the
+// user's query text, the city it resolved against (sticky or explicit),
+// and the coordinator's reply. A rolling slice of these lets the
+// orchestration prompt resolve a follow-up like "and the weather there?"
+// against what a prior turn already established.
+type ConversationTurn struct {
+	Query    string `json:"query"`
+	City     string `json:"city"`
+	Response string `json:"response"`
+}
+
 const (
 	QueryTypeTemperature QueryType = iota
 	QueryTypeDateTime
@@ -57,6 +75,35 @@ type TemperatureData struct {
 	Source      string    `json:"source"`
 }
 
+// TemperatureBatchEntry reports the outcome of one city within a
+// CallWeatherBatch request: Data is set on success, Error on failure, so a
+// batch can partially succeed without failing every city in it.
+type TemperatureBatchEntry struct {
+	City  string           `json:"city"`
+	Data  *TemperatureData `json:"data,omitempty"`
+	Error string           `json:"error,omitempty"`
+}
+
+// ForecastDay is one day within a ForecastData response: the day's
+// low/high temperature, precipitation probability, and a short conditions
+// description.
+type ForecastDay struct {
+	Date                string  `json:"date"`
+	TemperatureMin      float64 `json:"temperature_min"`
+	TemperatureMax      float64 `json:"temperature_max"`
+	PrecipitationChance float64 `json:"precipitation_chance"`
+	Description         string  `json:"description"`
+}
+
+// ForecastData contains a multi-day forecast from the MCP forecast server.
+type ForecastData struct {
+	City      string        `json:"city"`
+	Unit      string        `json:"unit"`
+	Days      []ForecastDay `json:"days"`
+	Timestamp time.Time     `json:"timestamp"`
+	Source    string        `json:"source"`
+}
+
 // DateTimeData contains datetime information from MCP datetime server
 type DateTimeData struct {
 	City      string    `json:"city"`
@@ -64,6 +111,13 @@ type DateTimeData struct {
 	Timezone  string    `json:"timezone"`
 	UTCOffset string    `json:"utc_offset"`
 	Timestamp time.Time `json:"timestamp"`
+	// IsDST, DSTOffsetSeconds, and StandardOffsetSeconds distinguish
+	// summer/winter time, computed by comparing the current UTC offset
+	// against a January reference date in the same timezone (see
+	// datetime.DaylightSavingsOffset).
+	IsDST                 bool `json:"is_dst"`
+	DSTOffsetSeconds      int  `json:"dst_offset_seconds"`
+	StandardOffsetSeconds int  `json:"standard_offset_seconds"`
 }
 
 // EchoData contains echo response from MCP echo server
@@ -87,6 +141,11 @@ type ExecutionStrategy string
 const (
 	ExecutionParallel   ExecutionStrategy = "parallel"
 	ExecutionSequential ExecutionStrategy = "sequential"
+	// ExecutionDAG runs AgentTasks as a dependency graph instead of a flat
+	// list: each task's DependsOn gates when it starts, and InputBindings
+	// let it consume a prior task's output field instead of a value the
+	// LLM planner had to know up front (see coordinator.executeDAG).
+	ExecutionDAG ExecutionStrategy = "dag"
 )
 
 // AgentTask represents a task to be executed by a specific agent
@@ -94,8 +153,17 @@ type AgentTask struct {
 	TaskID    string    `json:"task_id"`
 	AgentType AgentType `json:"agent_type"`
 	City      string    `json:"city,omitempty"`
+	Timezone  string    `json:"timezone,omitempty"`
 	EchoText  string    `json:"echo_text,omitempty"`
+	Days      int       `json:"days,omitempty"`
 	DependsOn []string  `json:"depends_on,omitempty"`
+	// InputBindings maps an AgentRequest field name ("city", "timezone",
+	// "echo_text", or "days") to an expression of the form
+	// "$tasks.<task_id>.output.<field>" that's resolved against a prior
+	// task's response Data once that task (named in DependsOn) completes,
+	// overriding whatever the planner set on that field directly. Only
+	// meaningful under ExecutionDAG.
+	InputBindings map[string]string `json:"input_bindings,omitempty"`
 }
 
 // AgentType defines the type of agent
@@ -105,15 +173,30 @@ const (
 	AgentTypeTemperature AgentType = "temperature"
 	AgentTypeDateTime    AgentType = "datetime"
 	AgentTypeEcho        AgentType = "echo"
+	AgentTypeForecast    AgentType = "forecast"
 )
 
+// AgentDescriptor is what a sub-agent's Describe method returns: its name,
+// what it's for, and which AgentRequest fields it reads. The coordinator's
+// LLM planner prompt is built from every registered agent's descriptor
+// instead of a hard-coded agent list, so a newly registered agent type is
+// automatically offered to the planner.
+type AgentDescriptor struct {
+	Type        AgentType
+	Name        string
+	Purpose     string
+	InputFields []string
+}
+
 // AgentRequest represents a request from coordinator to sub-agent
 type AgentRequest struct {
 	RequestID string        `json:"request_id"`
 	TaskID    string        `json:"task_id"`
 	AgentType AgentType     `json:"agent_type"`
 	City      string        `json:"city,omitempty"`
+	Timezone  string        `json:"timezone,omitempty"`
 	EchoText  string        `json:"echo_text,omitempty"`
+	Days      int           `json:"days,omitempty"`
 	Timeout   time.Duration `json:"timeout"`
 }
 
@@ -126,6 +209,73 @@ type AgentResponse struct {
 	Error     string      `json:"error,omitempty"`
 }
 
+// QueryEventType labels one occurrence on a Coordinator.ProcessQueryStream
+// channel.
+type QueryEventType string
+
+const (
+	// EventPlanGenerated fires once, right after the LLM orchestration plan
+	// is generated, carrying the plan itself.
+	EventPlanGenerated QueryEventType = "plan_generated"
+	// EventTaskStarted fires once per AgentTask, right before it dispatches
+	// to its sub-agent.
+	EventTaskStarted QueryEventType = "task_started"
+	// EventTaskCompleted fires once per AgentTask that returned a
+	// successful AgentResponse.
+	EventTaskCompleted QueryEventType = "task_completed"
+	// EventTaskFailed fires once per AgentTask that errored or returned a
+	// Success:false AgentResponse.
+	EventTaskFailed QueryEventType = "task_failed"
+	// EventQueryCompleted fires exactly once, last, carrying the final
+	// QueryResponse on success or Error set on failure.
+	EventQueryCompleted QueryEventType = "query_completed"
+)
+
+// QueryEvent is one occurrence streamed by Coordinator.ProcessQueryStream
+// as a query's orchestration plan is generated and its tasks execute,
+// rather than only a fully-assembled QueryResponse at the end. Which of
+// Plan/Task/Response/Result/Error is set depends on Type.
+type QueryEvent struct {
+	Type     QueryEventType     `json:"type"`
+	QueryID  string             `json:"query_id"`
+	Plan     *OrchestrationPlan `json:"plan,omitempty"`
+	Task     *AgentTask         `json:"task,omitempty"`
+	Response *AgentResponse     `json:"response,omitempty"`
+	Result   *QueryResponse     `json:"result,omitempty"`
+	Error    string             `json:"error,omitempty"`
+}
+
+// PlanEventType labels one occurrence on a StreamingPlanner's
+// StreamOrchestrationPlan channel.
+type PlanEventType string
+
+const (
+	// PlanEventReasoning fires zero or more times, as the LLM's free-text
+	// reasoning content streams in, carrying the incremental text in Delta.
+	PlanEventReasoning PlanEventType = "reasoning"
+	// PlanEventTaskAdded fires once per AgentTask, as soon as its JSON
+	// object in the streamed tool call's tasks array closes - before the
+	// rest of the plan (or even the model's reasoning) has finished
+	// streaming.
+	PlanEventTaskAdded PlanEventType = "task_added"
+	// PlanEventDone fires exactly once, last, carrying the complete
+	// OrchestrationPlan assembled from every streamed chunk.
+	PlanEventDone PlanEventType = "done"
+)
+
+// PlanEvent is one occurrence streamed by a StreamingPlanner as an
+// orchestration plan arrives incrementally from the LLM, rather than only
+// a fully-assembled OrchestrationPlan once the whole response has
+// streamed. Which of Task/Delta/Plan is set depends on Type; Error is set
+// only on the terminal PlanEventDone if streaming failed.
+type PlanEvent struct {
+	Type  PlanEventType      `json:"type"`
+	Task  *AgentTask         `json:"task,omitempty"`
+	Delta string             `json:"delta,omitempty"`
+	Plan  *OrchestrationPlan `json:"plan,omitempty"`
+	Error string             `json:"error,omitempty"`
+}
+
 // QueryResponse represents the final response to user
 type QueryResponse struct {
 	QueryID          string           `json:"query_id"`
@@ -133,6 +283,7 @@ type QueryResponse struct {
 	Temperature      *TemperatureData `json:"temperature,omitempty"`
 	DateTime         *DateTimeData    `json:"datetime,omitempty"`
 	Echo             *EchoData        `json:"echo,omitempty"`
+	Forecast         *ForecastData    `json:"forecast,omitempty"`
 	InvokedAgents    []AgentType      `json:"invoked_agents"`
 	OrchestrationLog []string         `json:"orchestration_log"`
 	Errors           []string         `json:"errors,omitempty"`
@@ -206,8 +357,13 @@ type EchoResponse struct {
 	ID    int       `json:"id"`
 }
 
-// MCPError represents an error in MCP protocol
+// MCPError represents an error in MCP protocol. Code follows the shared
+// internal/mcperrors vocabulary (standard JSON-RPC codes plus the
+// -32000..-32099 server-defined range); Data carries that package's
+// structured RetryHint verbatim when the error is worth retrying, so a
+// client only has to do one json.Unmarshal to act on it.
 type MCPError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
 }
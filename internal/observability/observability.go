@@ -0,0 +1,130 @@
+// Package observability provides the OpenTelemetry tracing and Prometheus
+// metrics instruments wired into Coordinator's orchestration path: plan
+// generation, task dispatch, and execution. Every span is tagged with the
+// request_id/task_id that identify it in the orchestration log, and every
+// Prometheus series is labeled so a query's plan-strategy choice and
+// per-agent latency can be correlated after the fact.
+package observability
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	noopmetric "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	nooptrace "go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationName identifies this package's spans and metric
+// instruments to whatever TracerProvider/MeterProvider Coordinator is
+// configured with.
+const instrumentationName = "github.com/steve/llm-agents/internal/agents/coordinator"
+
+// Tracer returns a Tracer for provider, or OpenTelemetry's no-op
+// implementation if provider is nil, so a Coordinator built without
+// WithTracerProvider still has a safe, inert Tracer to call.
+func Tracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = nooptrace.NewTracerProvider()
+	}
+	return provider.Tracer(instrumentationName)
+}
+
+// Meter returns a Meter for provider, or OpenTelemetry's no-op
+// implementation if provider is nil.
+func Meter(provider metric.MeterProvider) metric.Meter {
+	if provider == nil {
+		provider = noopmetric.NewMeterProvider()
+	}
+	return provider.Meter(instrumentationName)
+}
+
+// RequestAttributes returns the span attributes every coordinator span
+// tags itself with for a request_id/task_id pair; either may be "" and is
+// omitted.
+func RequestAttributes(requestID, taskID string) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if requestID != "" {
+		attrs = append(attrs, attribute.String("request_id", requestID))
+	}
+	if taskID != "" {
+		attrs = append(attrs, attribute.String("task_id", taskID))
+	}
+	return attrs
+}
+
+// registry backs this package's Prometheus instruments, separate from
+// internal/metrics' registry (which instruments the MCP servers, not the
+// coordinator that calls them).
+var registry = prometheus.NewRegistry()
+
+var (
+	queryCount = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "coordinator_queries_total",
+		Help: "Total queries processed by the coordinator.",
+	})
+
+	planStrategyCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "coordinator_plan_strategy_total",
+		Help: "Orchestration plans generated, labeled by strategy (parallel, sequential, dag).",
+	}, []string{"strategy"})
+
+	agentCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "coordinator_agent_call_duration_seconds",
+		Help:    "executeTask dispatch latency, labeled by agent type.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"agent_type"})
+
+	taskOutcomeCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "coordinator_task_outcome_total",
+		Help: "Sub-agent task outcomes, labeled by agent type and outcome (success, failure).",
+	}, []string{"agent_type", "outcome"})
+
+	planGenerationDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "coordinator_plan_generation_duration_seconds",
+		Help:    "Latency of the LLM orchestration-plan generation call.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+func init() {
+	registry.MustRegister(queryCount, planStrategyCount, agentCallDuration, taskOutcomeCount, planGenerationDuration)
+}
+
+// Handler returns the HTTP handler exposing this package's Prometheus
+// registry, for mounting at e.g. "/metrics".
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// IncQueryCount increments the total processed-query counter.
+func IncQueryCount() {
+	queryCount.Inc()
+}
+
+// ObservePlanStrategy records one orchestration plan generated with
+// strategy.
+func ObservePlanStrategy(strategy string) {
+	planStrategyCount.WithLabelValues(strategy).Inc()
+}
+
+// ObserveAgentCallDuration records how long one executeTask dispatch to
+// agentType took.
+func ObserveAgentCallDuration(agentType string, seconds float64) {
+	agentCallDuration.WithLabelValues(agentType).Observe(seconds)
+}
+
+// ObserveTaskOutcome records one task's terminal outcome ("success" or
+// "failure") for agentType.
+func ObserveTaskOutcome(agentType, outcome string) {
+	taskOutcomeCount.WithLabelValues(agentType, outcome).Inc()
+}
+
+// ObservePlanGenerationDuration records how long one LLM
+// orchestration-plan generation call took.
+func ObservePlanGenerationDuration(seconds float64) {
+	planGenerationDuration.Observe(seconds)
+}
@@ -0,0 +1,25 @@
+package resilience
+
+import (
+	"math/rand"
+	"time"
+)
+
+// jitteredDelay returns d adjusted by +/- a random fraction up to jitter
+// (e.g. jitter=0.2 returns a value within 20% of d in either direction).
+func jitteredDelay(d time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return d
+	}
+	delta := (rand.Float64()*2 - 1) * jitter
+	return time.Duration(float64(d) * (1 + delta))
+}
+
+// maxAttempts normalizes BackoffPolicy.MaxAttempts to at least 1, so an
+// unconfigured Policy still makes one attempt instead of none.
+func maxAttempts(b BackoffPolicy) int {
+	if b.MaxAttempts < 1 {
+		return 1
+	}
+	return b.MaxAttempts
+}
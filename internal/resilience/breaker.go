@@ -0,0 +1,124 @@
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// BreakerState reports a CircuitBreaker's current state.
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+// String returns the string representation of a BreakerState.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// ErrBreakerOpen is returned by CircuitBreaker.Allow (and, from it,
+// Executor.Do) when the circuit is open and its cooldown hasn't elapsed.
+var ErrBreakerOpen = errors.New("resilience: circuit breaker open")
+
+// CircuitBreaker trips to BreakerOpen once at least MinRequests calls have
+// been observed since it last closed and the fraction that failed reaches
+// FailureRatio, staying open for CooldownPeriod before moving to half-open.
+// Every call made while half-open is let through; the first outcome
+// recorded decides the state: a success closes the circuit again, a failure
+// reopens it immediately.
+type CircuitBreaker struct {
+	cfg BreakerConfig
+
+	mu        sync.Mutex
+	state     BreakerState
+	successes int
+	failures  int
+	openedAt  time.Time
+}
+
+// NewCircuitBreaker returns a closed CircuitBreaker configured by cfg.
+func NewCircuitBreaker(cfg BreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{cfg: cfg}
+}
+
+// Allow returns ErrBreakerOpen if the circuit is open and still cooling
+// down; otherwise it returns nil, moving an open circuit whose cooldown
+// has elapsed to half-open. It does not limit how many calls pass while
+// the circuit is half-open.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BreakerOpen {
+		return nil
+	}
+	if time.Since(b.openedAt) < b.cfg.CooldownPeriod {
+		return ErrBreakerOpen
+	}
+	b.state = BreakerHalfOpen
+	return nil
+}
+
+// Success records a successful call. A half-open probe succeeding closes
+// the circuit and resets its counters; otherwise it just counts toward
+// FailureRatio's denominator.
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.reset()
+		return
+	}
+	b.successes++
+}
+
+// Failure records a failed call. A half-open probe failing reopens the
+// circuit immediately; otherwise the circuit opens once MinRequests calls
+// have been observed and FailureRatio of them failed.
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerHalfOpen {
+		b.trip()
+		return
+	}
+
+	b.failures++
+	total := b.successes + b.failures
+	if total >= b.cfg.MinRequests && float64(b.failures)/float64(total) >= b.cfg.FailureRatio {
+		b.trip()
+	}
+}
+
+func (b *CircuitBreaker) trip() {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+	b.successes = 0
+	b.failures = 0
+}
+
+func (b *CircuitBreaker) reset() {
+	b.state = BreakerClosed
+	b.successes = 0
+	b.failures = 0
+}
+
+// State returns the breaker's current state.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -0,0 +1,82 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Executor runs calls to a single dependency (e.g. one MCP server
+// endpoint) through a token-bucket rate limiter, a circuit breaker, and
+// exponential backoff with jitter between retries, so transient failures
+// are retried while persistent ones trip the breaker instead of hammering
+// a server that's already down.
+type Executor struct {
+	policy  Policy
+	limiter *TokenBucket
+	breaker *CircuitBreaker
+}
+
+// NewExecutor returns an Executor enforcing policy for a single
+// dependency.
+func NewExecutor(policy Policy) *Executor {
+	return &Executor{
+		policy:  policy,
+		limiter: NewTokenBucket(policy.RateLimit),
+		breaker: NewCircuitBreaker(policy.Breaker),
+	}
+}
+
+// Do runs fn, retrying with exponential backoff while isRetryable(err) is
+// true, up to Policy.Backoff.MaxAttempts. It first checks the circuit
+// breaker, returning ErrBreakerOpen immediately without calling fn if it's
+// open, then blocks on the rate limiter until a token is available or ctx
+// is done. The breaker records one outcome per Do call: a success, or a
+// single failure once the attempts are used up or ctx is done.
+func (e *Executor) Do(ctx context.Context, isRetryable func(error) bool, fn func() error) error {
+	if err := e.breaker.Allow(); err != nil {
+		return err
+	}
+	if err := e.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	attempts := maxAttempts(e.policy.Backoff)
+	delay := e.policy.Backoff.BaseDelay
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		lastErr = fn()
+		if lastErr == nil {
+			e.breaker.Success()
+			return nil
+		}
+
+		if !isRetryable(lastErr) || attempt == attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			e.breaker.Failure()
+			return ctx.Err()
+		case <-time.After(jitteredDelay(delay, e.policy.Backoff.Jitter)):
+		}
+
+		delay = time.Duration(float64(delay) * e.policy.Backoff.Multiplier)
+		if delay > e.policy.Backoff.MaxDelay {
+			delay = e.policy.Backoff.MaxDelay
+		}
+	}
+
+	e.breaker.Failure()
+	if attempts == 1 {
+		return lastErr
+	}
+	return fmt.Errorf("failed after %d attempt(s): %w", attempts, lastErr)
+}
+
+// State returns the underlying circuit breaker's current state.
+func (e *Executor) State() BreakerState {
+	return e.breaker.State()
+}
@@ -0,0 +1,72 @@
+// Package resilience provides generic retry, rate-limiting, and circuit
+// breaking primitives for calls to flaky dependencies, independent of any
+// particular transport. Sub-agents use it to wrap their MCP client calls so
+// transient failures are retried with backoff while persistent failures
+// trip a per-endpoint circuit breaker instead of hammering a server that's
+// already down.
+package resilience
+
+import "time"
+
+// BackoffPolicy configures exponential backoff with jitter between retry
+// attempts.
+type BackoffPolicy struct {
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Multiplier  float64
+	Jitter      float64
+	MaxAttempts int
+}
+
+// RateLimitConfig configures a token-bucket rate limiter.
+type RateLimitConfig struct {
+	RatePerSecond float64
+	Burst         int
+}
+
+// BreakerConfig configures a CircuitBreaker's failure ratio and cooldown.
+type BreakerConfig struct {
+	// MinRequests is the minimum number of calls observed since the
+	// circuit last closed before FailureRatio is evaluated at all, so a
+	// single early failure doesn't trip it.
+	MinRequests int
+	// FailureRatio is the fraction of calls (in [0,1]) that must have
+	// failed, once MinRequests is reached, for the circuit to open.
+	FailureRatio float64
+	// CooldownPeriod is how long the circuit stays open before a single
+	// half-open probe is let through.
+	CooldownPeriod time.Duration
+}
+
+// Policy bundles the backoff, rate-limit, and circuit-breaker settings an
+// Executor applies together for a single dependency.
+type Policy struct {
+	Backoff   BackoffPolicy
+	RateLimit RateLimitConfig
+	Breaker   BreakerConfig
+}
+
+// DefaultPolicy returns a conservative policy: up to 3 attempts with
+// capped exponential backoff, a token bucket generous enough not to
+// throttle normal traffic, and a breaker that opens once at least 5 calls
+// have been seen and half of them failed, probing again after 30 seconds.
+func DefaultPolicy() Policy {
+	return Policy{
+		Backoff: BackoffPolicy{
+			BaseDelay:   200 * time.Millisecond,
+			MaxDelay:    5 * time.Second,
+			Multiplier:  2.0,
+			Jitter:      0.2,
+			MaxAttempts: 3,
+		},
+		RateLimit: RateLimitConfig{
+			RatePerSecond: 10,
+			Burst:         10,
+		},
+		Breaker: BreakerConfig{
+			MinRequests:    5,
+			FailureRatio:   0.5,
+			CooldownPeriod: 30 * time.Second,
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package resilience
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket is a token-bucket rate limiter: tokens refill continuously
+// at RatePerSecond up to Burst, and each call consumes one. A
+// non-positive RatePerSecond disables limiting entirely.
+type TokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a TokenBucket configured by cfg, starting full.
+func NewTokenBucket(cfg RateLimitConfig) *TokenBucket {
+	burst := float64(cfg.Burst)
+	return &TokenBucket{
+		rate:   cfg.RatePerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+func (b *TokenBucket) refill() {
+	now := time.Now()
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+	b.tokens += elapsed * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+// Allow reports whether a call may proceed right now, consuming a token if
+// so without blocking.
+func (b *TokenBucket) Allow() bool {
+	if b.rate <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Wait blocks until a token is available or ctx is done, whichever comes
+// first.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return nil
+	}
+
+	for {
+		b.mu.Lock()
+		b.refill()
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Package server provides a shared startup/shutdown helper for the
+// StreamableHTTP-based MCP servers, which (unlike internal/mcp/server.Server)
+// build their own *http.Server values directly rather than going through
+// that type's Start/Stop lifecycle. internal/mcp/server.ToolServer wraps Run
+// for the common case of one MCP endpoint plus an optional diag listener;
+// weather-mcp's bootstrap-token enrollment path still calls Run directly.
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// ShutdownGracePeriod bounds how long Run waits for in-flight requests to
+// finish once SIGINT/SIGTERM arrives before Shutdown gives up and returns.
+const ShutdownGracePeriod = 10 * time.Second
+
+// ApplyDefaultTimeouts sets the ReadTimeout, WriteTimeout, IdleTimeout, and
+// ReadHeaderTimeout every MCP HTTP(S) server should have, so a slow or
+// malicious client can't hold a connection open indefinitely. Call it before
+// Run on any server that doesn't already set its own timeouts.
+func ApplyDefaultTimeouts(srv *http.Server) {
+	srv.ReadTimeout = 30 * time.Second
+	srv.WriteTimeout = 30 * time.Second
+	srv.IdleTimeout = 120 * time.Second
+	srv.ReadHeaderTimeout = 10 * time.Second
+}
+
+// NamedServer pairs an *http.Server with the label Run uses in its log
+// lines and shutdown-error messages (e.g. "HTTP", "HTTPS", "Diag"). Server
+// may be nil, in which case Run skips it entirely, the same as passing a
+// nil httpsServer did before this type existed.
+type NamedServer struct {
+	Name   string
+	Server *http.Server
+
+	// Listener, if set, is served directly via Server.Serve(Listener)
+	// instead of the Addr-based ListenAndServe(TLS) dispatch below. This is
+	// for a server whose listener needs to be constructed specially before
+	// Run starts it, such as one from tls/upgrade.Listen that multiplexes
+	// plaintext HTTP and TLS off a single port.
+	Listener net.Listener
+}
+
+// Run starts every non-nil server in servers, then blocks until ctx is
+// cancelled or the process receives SIGINT/SIGTERM, whichever comes first.
+// A server is started with ListenAndServeTLS if its TLSConfig is set,
+// ListenAndServe otherwise. Any server failing to start is also treated as
+// a stop signal. Once stopped, every server is shut down concurrently with
+// a ShutdownGracePeriod timeout.
+//
+// "Graceful" here means what http.Server.Shutdown already gives us: stop
+// accepting new connections, close idle ones, and wait for in-flight
+// handlers to return. The MCP Go SDK's StreamableHTTPHandler does not
+// currently expose a way to count or drain its open MCP sessions
+// (its closeAll method is unexported and test-only), so a client mid-session
+// when the grace period expires is simply cut off, same as any other
+// in-flight request would be.
+func Run(ctx context.Context, servers ...NamedServer) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErrs := make(chan error, len(servers))
+	for _, ns := range servers {
+		if ns.Server == nil {
+			continue
+		}
+		name, srv, listener := ns.Name, ns.Server, ns.Listener
+		serve := srv.ListenAndServe
+		addr := srv.Addr
+		switch {
+		case listener != nil:
+			serve = func() error { return srv.Serve(listener) }
+			addr = listener.Addr().String()
+		case srv.TLSConfig != nil:
+			serve = func() error { return srv.ListenAndServeTLS("", "") }
+		}
+		go func() {
+			utils.Info("%s server listening on %s", name, addr)
+			if err := serve(); err != nil && err != http.ErrServerClosed {
+				serveErrs <- fmt.Errorf("%s server: %w", name, err)
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+	case err := <-serveErrs:
+		return err
+	}
+
+	utils.Info("shutdown requested, waiting up to %s for in-flight requests", ShutdownGracePeriod)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownGracePeriod)
+	defer cancel()
+
+	var errs []error
+	for _, ns := range servers {
+		if ns.Server == nil {
+			continue
+		}
+		if err := ns.Server.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("%s server shutdown: %w", ns.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("server shutdown errors: %v", errs)
+	}
+	utils.Info("shutdown complete")
+	return nil
+}
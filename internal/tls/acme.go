@@ -0,0 +1,361 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/acme"
+
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// DNSProvider presents and tears down a DNS-01 challenge record, matching
+// go-acme/lego's challenge.Provider interface so an existing lego DNS
+// provider plugin (Cloudflare, Route53, ...) can be wrapped and passed to
+// NewACMEIssuer without modification.
+type DNSProvider interface {
+	Present(domain, token, keyAuth string) error
+	CleanUp(domain, token, keyAuth string) error
+}
+
+// acmeOrderState is what ACMEIssuer persists per domain set under
+// CertDir/acme/, so a restart mid-validation resumes against the existing
+// order instead of requesting a new one (and tripping the ACME server's
+// rate limits).
+type acmeOrderState struct {
+	Names    []string `json:"names"`
+	OrderURL string   `json:"order_url"`
+}
+
+// ACMEIssuer implements Issuer by obtaining certificates from a public RFC
+// 8555 (ACME) directory — Let's Encrypt, smallstep, ZeroSSL, or any other —
+// instead of this deployment's own CA. It's the alternative to
+// CertificateManager for MCP servers reachable at a public hostname, where
+// operators would rather not run and distribute trust for a private root.
+type ACMEIssuer struct {
+	cfg       *config.TLSConfig
+	client    *acme.Client
+	accountID string
+
+	dns DNSProvider
+
+	// httpChallenges holds the key authorization for each in-flight http-01
+	// challenge, token -> keyAuth, for ChallengeHandler to serve while
+	// satisfyAuthorization waits on the ACME server to fetch it.
+	httpChallenges sync.Map
+}
+
+// NewACMEIssuer registers (or resumes, if CertDir/acme/account.json already
+// exists) an ACME account against cfg.ACME.DirectoryURL and returns an
+// issuer ready for IssueServerCert. dnsProvider is required when
+// cfg.ACME.ChallengeType is "dns-01" and ignored otherwise.
+func NewACMEIssuer(ctx context.Context, cfg *config.TLSConfig, dnsProvider DNSProvider) (*ACMEIssuer, error) {
+	if cfg.ACME == nil {
+		return nil, fmt.Errorf("ACME configuration is required")
+	}
+	if cfg.ACME.ChallengeType == "dns-01" && dnsProvider == nil {
+		return nil, fmt.Errorf("dns-01 challenge type requires a DNSProvider")
+	}
+
+	accountKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ACME account key: %w", err)
+	}
+
+	client := &acme.Client{
+		Key:          accountKey,
+		DirectoryURL: cfg.ACME.DirectoryURL,
+	}
+
+	if err := os.MkdirAll(acmeStateDir(cfg), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create ACME state directory: %w", err)
+	}
+
+	// Registering with a key that already has an account is idempotent per
+	// RFC 8555 section 7.3.1: the directory returns the existing account
+	// rather than erroring, so there's no separate "resume" path needed
+	// here beyond the persisted order state in CertDir/acme/.
+	account, err := client.Register(ctx, &acme.Account{Contact: []string{"mailto:" + cfg.ACME.Email}}, acme.AcceptTOS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register ACME account: %w", err)
+	}
+
+	return &ACMEIssuer{cfg: cfg, client: client, dns: dnsProvider, accountID: account.URI}, nil
+}
+
+// IssueServerCert implements Issuer: it creates (or resumes) an order for
+// names, satisfies the configured challenge type for each one, finalizes
+// the order with a freshly generated key's CSR, and returns the issued
+// certificate chain and key PEM-encoded.
+func (a *ACMEIssuer) IssueServerCert(names []string) (certPEM, keyPEM []byte, err error) {
+	if len(names) == 0 {
+		return nil, nil, fmt.Errorf("at least one name is required")
+	}
+
+	if certPEM, keyPEM, err := a.loadCachedCert(names); err == nil {
+		utils.Info("ACMEIssuer: serving cached certificate for %v from %s", names, acmeCacheDir(a.cfg))
+		return certPEM, keyPEM, nil
+	}
+
+	ctx := context.Background()
+
+	order, err := a.orderFor(ctx, names)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create ACME order: %w", err)
+	}
+
+	for _, authzURL := range order.AuthzURLs {
+		if err := a.satisfyAuthorization(ctx, authzURL); err != nil {
+			return nil, nil, fmt.Errorf("failed to satisfy ACME authorization: %w", err)
+		}
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate leaf private key: %w", err)
+	}
+
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: names[0]},
+		DNSNames: names,
+	}, leafKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create CSR: %w", err)
+	}
+
+	order, err = a.client.WaitOrder(ctx, order.URI)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed waiting for ACME order to be ready: %w", err)
+	}
+
+	certDERChain, _, err := a.client.CreateOrderCert(ctx, order.FinalizeURL, csrDER, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to finalize ACME order: %w", err)
+	}
+
+	var certBuf []byte
+	for _, der := range certDERChain {
+		certBuf = append(certBuf, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(leafKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal leaf private key: %w", err)
+	}
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	if err := a.cacheCert(names, certBuf, keyPEM); err != nil {
+		utils.Warn("ACMEIssuer: failed to cache issued certificate for %v: %v", names, err)
+	}
+
+	utils.Info("ACMEIssuer: issued certificate for %v via %s", names, a.cfg.ACME.DirectoryURL)
+	return certBuf, keyPEM, nil
+}
+
+// loadCachedCert returns the certificate and key previously cached for
+// names by cacheCert, or an error if nothing is cached yet. It does not
+// check the cached certificate's expiry: RotateCert/the caller's own
+// renewal schedule is responsible for calling IssueServerCert again (which
+// bypasses the cache) once it's due.
+func (a *ACMEIssuer) loadCachedCert(names []string) (certPEM, keyPEM []byte, err error) {
+	certPEM, err = os.ReadFile(acmeCachedCertPath(a.cfg, names))
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err = os.ReadFile(acmeCachedKeyPath(a.cfg, names))
+	if err != nil {
+		return nil, nil, err
+	}
+	return certPEM, keyPEM, nil
+}
+
+// cacheCert persists certPEM/keyPEM under cfg.ACME.CacheDir (or its
+// CertDir/acme/cache default) with 0600 permissions, so a restart reuses
+// the issued certificate instead of re-requesting one from the directory.
+func (a *ACMEIssuer) cacheCert(names []string, certPEM, keyPEM []byte) error {
+	dir := acmeCacheDir(a.cfg)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create ACME cache directory: %w", err)
+	}
+	if err := os.WriteFile(acmeCachedCertPath(a.cfg, names), certPEM, 0600); err != nil {
+		return fmt.Errorf("failed to cache certificate: %w", err)
+	}
+	if err := os.WriteFile(acmeCachedKeyPath(a.cfg, names), keyPEM, 0600); err != nil {
+		return fmt.Errorf("failed to cache private key: %w", err)
+	}
+	return nil
+}
+
+// ChallengeHandler serves RFC 8555 http-01 challenge responses at
+// /.well-known/acme-challenge/<token>, the well-known path the ACME
+// directory fetches from this deployment's own HTTP listener (port 80, or
+// the main HTTP port when TLS_UPGRADE_MODE-style port consolidation is in
+// use) to validate domain control. Mount it on that listener's mux whenever
+// cfg.ACME.ChallengeType is "http-01"; it 404s for any token it doesn't
+// currently have a pending challenge for.
+func (a *ACMEIssuer) ChallengeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.URL.Path, "/.well-known/acme-challenge/")
+		keyAuth, ok := a.httpChallenges.Load(token)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, keyAuth)
+	})
+}
+
+// acmeCacheDir returns where cacheCert/loadCachedCert store issued
+// certificates, defaulting to CertDir/acme/cache if cfg.ACME.CacheDir is
+// unset.
+func acmeCacheDir(cfg *config.TLSConfig) string {
+	if cfg.ACME != nil && cfg.ACME.CacheDir != "" {
+		return cfg.ACME.CacheDir
+	}
+	return filepath.Join(acmeStateDir(cfg), "cache")
+}
+
+// acmeCachedCertPath and acmeCachedKeyPath name the cached PEM files for a
+// given domain set, keyed by names[0] the same way acmeOrderStatePath is.
+func acmeCachedCertPath(cfg *config.TLSConfig, names []string) string {
+	return filepath.Join(acmeCacheDir(cfg), names[0]+".crt")
+}
+
+func acmeCachedKeyPath(cfg *config.TLSConfig, names []string) string {
+	return filepath.Join(acmeCacheDir(cfg), names[0]+".key")
+}
+
+// RotateCert re-issues the certificate for names, the ACME counterpart to
+// CertificateManager.RotateCA/CertRenewer: ACME has no concept of
+// incrementally rotating a key in place, so renewal is just issuance again
+// against the same order's domain set.
+func (a *ACMEIssuer) RotateCert(names []string) (certPEM, keyPEM []byte, err error) {
+	return a.IssueServerCert(names)
+}
+
+// orderFor returns the persisted in-flight order for names if one exists
+// and is still pending, otherwise creates a new one and persists it.
+func (a *ACMEIssuer) orderFor(ctx context.Context, names []string) (*acme.Order, error) {
+	statePath := acmeOrderStatePath(a.cfg, names)
+
+	if data, err := os.ReadFile(statePath); err == nil {
+		var state acmeOrderState
+		if err := json.Unmarshal(data, &state); err == nil {
+			if order, err := a.client.GetOrder(ctx, state.OrderURL); err == nil {
+				return order, nil
+			}
+		}
+	}
+
+	authzIDs := make([]acme.AuthzID, 0, len(names))
+	for _, name := range names {
+		authzIDs = append(authzIDs, acme.AuthzID{Type: "dns", Value: name})
+	}
+
+	order, err := a.client.AuthorizeOrder(ctx, authzIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	state := acmeOrderState{Names: names, OrderURL: order.URI}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err == nil {
+		_ = os.WriteFile(statePath, data, 0600)
+	}
+
+	return order, nil
+}
+
+// satisfyAuthorization fetches the authorization at authzURL, picks the
+// challenge matching cfg.ACME.ChallengeType, completes it, and waits for the
+// ACME server to confirm it.
+func (a *ACMEIssuer) satisfyAuthorization(ctx context.Context, authzURL string) error {
+	authz, err := a.client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return err
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	challenge, err := a.selectChallenge(authz)
+	if err != nil {
+		return err
+	}
+
+	if err := a.prepareChallenge(authz.Identifier.Value, challenge); err != nil {
+		return err
+	}
+
+	if _, err := a.client.Accept(ctx, challenge); err != nil {
+		return err
+	}
+	_, err = a.client.WaitAuthorization(ctx, authzURL)
+	return err
+}
+
+// selectChallenge picks the challenge in authz matching cfg.ACME.ChallengeType.
+func (a *ACMEIssuer) selectChallenge(authz *acme.Authorization) (*acme.Challenge, error) {
+	for _, challenge := range authz.Challenges {
+		if challenge.Type == a.cfg.ACME.ChallengeType {
+			return challenge, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s challenge offered for %s", a.cfg.ACME.ChallengeType, authz.Identifier.Value)
+}
+
+// prepareChallenge performs the out-of-band half of domain validation
+// (publishing a DNS TXT record, in the dns-01 case) before Accept is called.
+// http-01 and tls-alpn-01 are served by the MCP server itself (the
+// well-known path / ALPN cert), so there's nothing for the issuer to do here
+// beyond computing the expected response for the server to serve.
+func (a *ACMEIssuer) prepareChallenge(domain string, challenge *acme.Challenge) error {
+	switch challenge.Type {
+	case "dns-01":
+		keyAuth, err := a.client.DNS01ChallengeRecord(challenge.Token)
+		if err != nil {
+			return err
+		}
+		return a.dns.Present(domain, challenge.Token, keyAuth)
+	case "http-01":
+		keyAuth, err := a.client.HTTP01ChallengeResponse(challenge.Token)
+		if err != nil {
+			return err
+		}
+		a.httpChallenges.Store(challenge.Token, keyAuth)
+		return nil
+	case "tls-alpn-01":
+		// The MCP server's TLS listener answers this directly from the
+		// challenge token via a tls-alpn-01 GetCertificate hook; nothing
+		// further to prepare here.
+		return nil
+	default:
+		return fmt.Errorf("unsupported ACME challenge type %q", challenge.Type)
+	}
+}
+
+// acmeStateDir returns CertDir/acme, where ACMEIssuer persists in-flight
+// orders so a restart doesn't re-request them.
+func acmeStateDir(cfg *config.TLSConfig) string {
+	return filepath.Join(cfg.CertDir, "acme")
+}
+
+// acmeOrderStatePath names the persisted order file for a given domain set.
+func acmeOrderStatePath(cfg *config.TLSConfig, names []string) string {
+	return filepath.Join(acmeStateDir(cfg), names[0]+".json")
+}
@@ -0,0 +1,71 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"crypto/x509"
+	"fmt"
+
+	"github.com/steve/llm-agents/internal/config"
+)
+
+// PeerAuthorizer decides whether an already chain-verified peer certificate
+// belongs to an identity this side is willing to talk to. It layers on top
+// of CA trust rather than replacing it: a cert can be signed by our CA and
+// still be rejected if it isn't who we expect.
+type PeerAuthorizer interface {
+	// Authorize returns nil if cert identifies an allowed peer, or an error
+	// naming why it was rejected.
+	Authorize(cert *x509.Certificate) error
+}
+
+// SPIFFEAuthorizer authorizes peers by matching their certificate's URI
+// SANs (SPIFFE IDs, e.g. "spiffe://llm-agents/agent/coordinator") or DNS
+// SANs against an allow-list, modeled on SPIFFE/SPIRE's workload identity
+// model.
+type SPIFFEAuthorizer struct {
+	allowed map[string]bool
+}
+
+// NewSPIFFEAuthorizer builds a SPIFFEAuthorizer from a role->allowed-IDs
+// map. The role is only used to group IDs in config; a peer is authorized
+// if it matches any ID under any role.
+func NewSPIFFEAuthorizer(allowedPeerIDs map[config.Role][]string) *SPIFFEAuthorizer {
+	allowed := make(map[string]bool)
+	for _, ids := range allowedPeerIDs {
+		for _, id := range ids {
+			allowed[id] = true
+		}
+	}
+	return &SPIFFEAuthorizer{allowed: allowed}
+}
+
+// NewSingleIDAuthorizer builds a SPIFFEAuthorizer pinned to exactly one
+// expected peer identity, for a caller that only ever expects one specific
+// peer on the other end of the connection - e.g. a sub-agent's MCP client,
+// which always dials the one server backing its AgentType - as opposed to
+// NewSPIFFEAuthorizer's multi-role allow-list built from a full TLSConfig.
+func NewSingleIDAuthorizer(expectedPeerID string) *SPIFFEAuthorizer {
+	return &SPIFFEAuthorizer{allowed: map[string]bool{expectedPeerID: true}}
+}
+
+// Authorize checks cert's URI SANs and DNS SANs against the allow-list. An
+// authorizer built from an empty map authorizes everything, so configuring
+// AllowedPeerIDs remains opt-in.
+func (a *SPIFFEAuthorizer) Authorize(cert *x509.Certificate) error {
+	if len(a.allowed) == 0 {
+		return nil
+	}
+
+	for _, uri := range cert.URIs {
+		if a.allowed[uri.String()] {
+			return nil
+		}
+	}
+	for _, name := range cert.DNSNames {
+		if a.allowed[name] {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("peer identity not in allow-list: uris=%v dns=%v", cert.URIs, cert.DNSNames)
+}
@@ -0,0 +1,139 @@
+// Package bootstrap wraps internal/mcp/bootstrap's one-time-token
+// enrollment with a smallstep ca.Bootstrap*-shaped API: given a short-lived
+// token, BootstrapServer/BootstrapClient install a live, self-renewing TLS
+// identity directly into a caller-supplied *http.Server or http.Client,
+// rather than handing back a *tls.Config the caller has to wire in by hand.
+package bootstrap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	mcpbootstrap "github.com/steve/llm-agents/internal/mcp/bootstrap"
+)
+
+// Identity is the enrolled TLS identity installed by BootstrapServer or
+// BootstrapClient. Its background renewal goroutine keeps the installed
+// tls.Config current; Close stops that goroutine without revoking the
+// identity already in use.
+type Identity struct {
+	enrollment *mcpbootstrap.Enrollment
+}
+
+// Close stops the background renewal goroutine.
+func (id *Identity) Close() {
+	id.enrollment.Close()
+}
+
+// options collects the WithXxx functional options layered on top of the
+// enrolled tls.Config.
+type options struct {
+	verifyClientCertIfGiven bool
+	extraClientCAs          []*x509.Certificate
+}
+
+// Option customizes the tls.Config BootstrapServer installs into base.
+type Option func(*options)
+
+// WithVerifyClientCertIfGiven relaxes mTLS from "always require a client
+// certificate" (the enrollment default) to "verify one if the client
+// presents it, otherwise proceed unauthenticated" - for endpoints such as
+// health checks that must stay reachable from callers without an enrolled
+// identity yet.
+func WithVerifyClientCertIfGiven() Option {
+	return func(o *options) { o.verifyClientCertIfGiven = true }
+}
+
+// WithAddClientCA layers an additional trust root into the server's client
+// verification pool, alongside the CA bundle returned by enrollment - e.g.
+// to keep accepting a legacy root while callers roll onto the bootstrap CA.
+func WithAddClientCA(cert *x509.Certificate) Option {
+	return func(o *options) { o.extraClientCAs = append(o.extraClientCAs, cert) }
+}
+
+// BootstrapServer enrolls base.Addr for a server identity against the CA's
+// /sign endpoint at caURL using token, then installs the resulting,
+// self-renewing mTLS configuration directly into base.TLSConfig -
+// mirroring smallstep's ca.BootstrapServer, which hands callers a
+// ready-to-serve *http.Server instead of a bag of TLS fields to assemble.
+func BootstrapServer(ctx context.Context, token, caURL string, base *http.Server, opts ...Option) (*Identity, error) {
+	if base == nil {
+		return nil, fmt.Errorf("bootstrap: base *http.Server must not be nil")
+	}
+
+	name := base.Addr
+	if name == "" {
+		name = "mcp-server"
+	}
+
+	enrollment, err := mcpbootstrap.BootstrapServer(ctx, token, caURL, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := enrollment.TLSConfig().Clone()
+	cfg.Certificates = nil
+	cfg.GetCertificate = currentServerCertificate(enrollment)
+
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.verifyClientCertIfGiven {
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	for _, extra := range o.extraClientCAs {
+		cfg.ClientCAs.AddCert(extra)
+	}
+
+	base.TLSConfig = cfg
+	return &Identity{enrollment: enrollment}, nil
+}
+
+// BootstrapClient enrolls for a client identity against the CA's /sign
+// endpoint at caURL using token, and returns a ready-to-use *http.Client
+// whose transport presents the enrolled leaf and trusts the returned CA
+// bundle - mirroring smallstep's client-side ca.Bootstrap.
+func BootstrapClient(ctx context.Context, token, caURL string) (*http.Client, *Identity, error) {
+	enrollment, err := mcpbootstrap.BootstrapClient(ctx, token, caURL, "mcp-client")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cfg := enrollment.TLSConfig().Clone()
+	cfg.Certificates = nil
+	cfg.GetClientCertificate = currentClientCertificate(enrollment)
+
+	client := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: cfg},
+	}
+	return client, &Identity{enrollment: enrollment}, nil
+}
+
+// currentServerCertificate returns a tls.Config.GetCertificate callback
+// that always serves the enrollment's current leaf, so a renewal swaps in
+// without restarting the listener base.TLSConfig was installed into.
+func currentServerCertificate(enrollment *mcpbootstrap.Enrollment) func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		current := enrollment.TLSConfig()
+		if current == nil || len(current.Certificates) == 0 {
+			return nil, fmt.Errorf("bootstrap: no enrolled server certificate available")
+		}
+		return &current.Certificates[0], nil
+	}
+}
+
+// currentClientCertificate returns a tls.Config.GetClientCertificate
+// callback that always presents the enrollment's current leaf.
+func currentClientCertificate(enrollment *mcpbootstrap.Enrollment) func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		current := enrollment.TLSConfig()
+		if current == nil || len(current.Certificates) == 0 {
+			return nil, fmt.Errorf("bootstrap: no enrolled client certificate available")
+		}
+		return &current.Certificates[0], nil
+	}
+}
@@ -2,37 +2,97 @@
 package tls
 
 import (
+	"crypto"
 	"crypto/rand"
-	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/hex"
 	"encoding/pem"
 	"fmt"
 	"math/big"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/tls/kms"
+	"github.com/steve/llm-agents/internal/utils"
 )
 
 // CertificateManager handles certificate generation, loading, and validation
 type CertificateManager struct {
 	config *config.TLSConfig
+	policy *config.SigningPolicy
+
+	// keyManager creates every private key this manager writes: the root
+	// and intermediate CA keys, and server/client leaf keys. It defaults to
+	// an in-process kms.SoftwareKeyManager; WithKeyManager swaps in an
+	// HSM-backed one (e.g. kms.PKCS11KeyManager) so the CA key never
+	// touches disk.
+	keyManager kms.KeyManager
+
+	// revocationStore, if attached via WithRevocationStore, is what
+	// RevokeCertificate records into; also what GenerateCRL/OCSPResponder
+	// read from when given the same store.
+	revocationStore *RevocationStore
 }
 
 // NewCertificateManager creates a new certificate manager
 func NewCertificateManager(cfg *config.TLSConfig) *CertificateManager {
 	return &CertificateManager{
-		config: cfg,
+		config:     cfg,
+		keyManager: kms.NewSoftwareKeyManager(),
 	}
 }
 
-// GenerateCA generates a Certificate Authority certificate and private key
-func (cm *CertificateManager) GenerateCA() error {
+// WithKeyManager attaches a KeyManager to this certificate manager, so keys
+// it creates (via GenerateRootCA, GenerateIntermediateCA, GenerateServerCert,
+// GenerateClientCert, RotateCA) are created through it instead of the
+// default in-process kms.SoftwareKeyManager.
+func (cm *CertificateManager) WithKeyManager(km kms.KeyManager) *CertificateManager {
+	cm.keyManager = km
+	return cm
+}
+
+// WithRevocationStore attaches a RevocationStore to this certificate
+// manager, enabling RevokeCertificate.
+func (cm *CertificateManager) WithRevocationStore(store *RevocationStore) *CertificateManager {
+	cm.revocationStore = store
+	return cm
+}
+
+// RevokeCertificate records serial as revoked for reason (an
+// x509.RevocationReasonCode, e.g. x509.KeyCompromise) in the
+// RevocationStore attached via WithRevocationStore. The revocation only
+// takes effect for peers once GenerateCRL republishes it (or, for a local
+// CertReloader checking the same store directly, immediately).
+func (cm *CertificateManager) RevokeCertificate(serial *big.Int, reason int) error {
+	if cm.revocationStore == nil {
+		return fmt.Errorf("no revocation store configured on this certificate manager")
+	}
+	return cm.revocationStore.Revoke(serial, reason)
+}
+
+// keyAlgorithm returns the key algorithm this manager's keys should use,
+// defaulting to kms.DefaultKeyAlgorithm when the config leaves KeyType unset.
+func (cm *CertificateManager) keyAlgorithm() config.KeyAlgorithm {
+	if cm.config.KeyType == "" {
+		return kms.DefaultKeyAlgorithm
+	}
+	return cm.config.KeyType
+}
+
+// GenerateRootCA generates the long-lived root CA. Real deployments keep
+// this key offline once the hierarchy is bootstrapped: day-to-day
+// certificate issuance goes through GenerateIntermediateCA and the
+// intermediate it produces, so the root's key usage is restricted to
+// signing other CAs, never leaf certificates directly.
+func (cm *CertificateManager) GenerateRootCA() error {
 	// Generate private key
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	privateKey, err := cm.keyManager.CreateKey("root-ca", cm.keyAlgorithm())
 	if err != nil {
 		return fmt.Errorf("failed to generate CA private key: %w", err)
 	}
@@ -49,15 +109,14 @@ func (cm *CertificateManager) GenerateCA() error {
 			PostalCode:    []string{""},
 		},
 		NotBefore:             time.Now(),
-		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
-		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		NotAfter:              time.Now().Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
 		BasicConstraintsValid: true,
 		IsCA:                  true,
 	}
 
 	// Create the certificate
-	certDER, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey)
+	certDER, err := x509.CreateCertificate(rand.Reader, &template, &template, privateKey.Public(), privateKey)
 	if err != nil {
 		return fmt.Errorf("failed to create CA certificate: %w", err)
 	}
@@ -73,27 +132,81 @@ func (cm *CertificateManager) GenerateCA() error {
 		return fmt.Errorf("failed to write CA certificate: %w", err)
 	}
 
-	// Write private key to file
-	keyOut, err := os.Create(filepath.Join(cm.config.CertDir, "ca.key"))
+	return writePrivateKeyFile(filepath.Join(cm.config.CertDir, "ca.key"), privateKey)
+}
+
+// GenerateCA is a deprecated alias for GenerateRootCA, kept for callers
+// that pre-date the root+intermediate hierarchy.
+func (cm *CertificateManager) GenerateCA() error {
+	return cm.GenerateRootCA()
+}
+
+// intermediatePaths returns the intermediate CA's configured cert/key
+// paths, defaulting to CertDir/intermediate.{crt,key} if unset.
+func (cm *CertificateManager) intermediatePaths() (certPath, keyPath string) {
+	certPath = cm.config.IntermediateCert
+	if certPath == "" {
+		certPath = filepath.Join(cm.config.CertDir, "intermediate.crt")
+	}
+	keyPath = cm.config.IntermediateKey
+	if keyPath == "" {
+		keyPath = filepath.Join(cm.config.CertDir, "intermediate.key")
+	}
+	return certPath, keyPath
+}
+
+// fileExists reports whether path can be stat'd successfully.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// GenerateIntermediateCA generates an intermediate CA signed by the root
+// CA (from GenerateRootCA), through which all leaf certificates are
+// chained: loadCA prefers the intermediate once it exists, so
+// GenerateServerCert/GenerateClientCert/SignCSR chain through it without
+// further configuration.
+func (cm *CertificateManager) GenerateIntermediateCA() error {
+	rootCert, rootKey, err := cm.loadRootCA()
 	if err != nil {
-		return fmt.Errorf("failed to create CA key file: %w", err)
+		return fmt.Errorf("failed to load root CA: %w", err)
 	}
-	defer keyOut.Close()
 
-	// Set restrictive permissions for private key
-	if err := keyOut.Chmod(0600); err != nil {
-		return fmt.Errorf("failed to set CA key permissions: %w", err)
+	privateKey, err := cm.keyManager.CreateKey("intermediate-ca", cm.keyAlgorithm())
+	if err != nil {
+		return fmt.Errorf("failed to generate intermediate CA private key: %w", err)
 	}
 
-	privateKeyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject: pkix.Name{
+			Organization: []string{"MCP Demo Intermediate CA"},
+			Country:      []string{"US"},
+		},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(5 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            0,
+		MaxPathLenZero:        true,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, &template, rootCert, privateKey.Public(), rootKey)
 	if err != nil {
-		return fmt.Errorf("failed to marshal CA private key: %w", err)
+		return fmt.Errorf("failed to create intermediate CA certificate: %w", err)
 	}
 
-	if err := pem.Encode(keyOut, &pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyDER}); err != nil {
-		return fmt.Errorf("failed to write CA private key: %w", err)
+	certPath, keyPath := cm.intermediatePaths()
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0644); err != nil {
+		return fmt.Errorf("failed to write intermediate CA certificate: %w", err)
+	}
+	if err := writePrivateKeyFile(keyPath, privateKey); err != nil {
+		return fmt.Errorf("failed to write intermediate CA key: %w", err)
 	}
 
+	utils.Info("Generated intermediate CA at %s, signed by root %s", certPath, cm.config.CACert)
 	return nil
 }
 
@@ -107,8 +220,40 @@ func (cm *CertificateManager) GenerateClientCert(commonName string) error {
 	return cm.generateCert(commonName, config.ClientCert, cm.config.ClientCert, cm.config.ClientKey, false)
 }
 
+// ClientIdentity names the SPIFFE-style identity GenerateClientCertForIdentity
+// encodes into a client certificate's URI SAN, so internal/authz can
+// authorize an mTLS caller by role instead of (or alongside) its Subject CN.
+type ClientIdentity struct {
+	// Role and Name form the spiffe://llm-agents/<Role>/<Name> URI encoded
+	// into the certificate; Role is what internal/authz policies key on.
+	Role string
+	Name string
+	// ExtraSANs are additional URI SANs to include alongside the SPIFFE ID,
+	// e.g. for a caller that also needs a non-SPIFFE identifier.
+	ExtraSANs []*url.URL
+}
+
+// spiffeID returns the spiffe://llm-agents/<Role>/<Name> URI this identity
+// encodes.
+func (id ClientIdentity) spiffeID() (*url.URL, error) {
+	return url.Parse(fmt.Sprintf("spiffe://llm-agents/%s/%s", id.Role, id.Name))
+}
+
+// GenerateClientCertForIdentity issues a client certificate the same way
+// GenerateClientCert does, but additionally encodes identity's SPIFFE URI
+// into the certificate's URI SAN, so the MCP server's AuthZPolicy can read
+// the caller's role straight off r.TLS.PeerCertificates[0].URIs.
+func (cm *CertificateManager) GenerateClientCertForIdentity(identity ClientIdentity) error {
+	spiffeID, err := identity.spiffeID()
+	if err != nil {
+		return fmt.Errorf("failed to build SPIFFE URI for %s/%s: %w", identity.Role, identity.Name, err)
+	}
+	uris := append([]*url.URL{spiffeID}, identity.ExtraSANs...)
+	return cm.generateCert(identity.Name, config.ClientCert, cm.config.ClientCert, cm.config.ClientKey, false, uris...)
+}
+
 // generateCert is a helper function to generate certificates
-func (cm *CertificateManager) generateCert(commonName string, certType config.CertificateType, certPath, keyPath string, isServer bool) error {
+func (cm *CertificateManager) generateCert(commonName string, certType config.CertificateType, certPath, keyPath string, isServer bool, extraURIs ...*url.URL) error {
 	// Load CA certificate and key
 	caCert, caKey, err := cm.loadCA()
 	if err != nil {
@@ -116,7 +261,7 @@ func (cm *CertificateManager) generateCert(commonName string, certType config.Ce
 	}
 
 	// Generate private key
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	privateKey, err := cm.keyManager.CreateKey(keyPath, cm.keyAlgorithm())
 	if err != nil {
 		return fmt.Errorf("failed to generate private key: %w", err)
 	}
@@ -133,10 +278,18 @@ func (cm *CertificateManager) generateCert(commonName string, certType config.Ce
 			PostalCode:    []string{""},
 			CommonName:    commonName,
 		},
-		NotBefore:    time.Now(),
-		NotAfter:     time.Now().Add(365 * 24 * time.Hour),
-		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
-		ExtKeyUsage:  []x509.ExtKeyUsage{},
+		NotBefore:   time.Now(),
+		NotAfter:    time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{},
+		URIs:        extraURIs,
+	}
+
+	if cm.config.IssuedCRLURL != "" {
+		template.CRLDistributionPoints = []string{cm.config.IssuedCRLURL}
+	}
+	if cm.config.IssuedOCSPURL != "" {
+		template.OCSPServer = []string{cm.config.IssuedOCSPURL}
 	}
 
 	if isServer {
@@ -149,7 +302,7 @@ func (cm *CertificateManager) generateCert(commonName string, certType config.Ce
 	}
 
 	// Create the certificate
-	certDER, err := x509.CreateCertificate(rand.Reader, &template, caCert, &privateKey.PublicKey, caKey)
+	certDER, err := x509.CreateCertificate(rand.Reader, &template, caCert, privateKey.Public(), caKey)
 	if err != nil {
 		return fmt.Errorf("failed to create certificate: %w", err)
 	}
@@ -165,78 +318,82 @@ func (cm *CertificateManager) generateCert(commonName string, certType config.Ce
 		return fmt.Errorf("failed to write certificate: %w", err)
 	}
 
-	// Write private key to file
-	keyOut, err := os.Create(keyPath)
-	if err != nil {
-		return fmt.Errorf("failed to create key file: %w", err)
-	}
-	defer keyOut.Close()
-
-	// Set restrictive permissions for private key
-	if err := keyOut.Chmod(0600); err != nil {
-		return fmt.Errorf("failed to set key permissions: %w", err)
-	}
-
-	privateKeyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
-	if err != nil {
-		return fmt.Errorf("failed to marshal private key: %w", err)
-	}
+	return writePrivateKeyFile(keyPath, privateKey)
+}
 
-	if err := pem.Encode(keyOut, &pem.Block{Type: "PRIVATE KEY", Bytes: privateKeyDER}); err != nil {
-		return fmt.Errorf("failed to write private key: %w", err)
+// loadCA loads the certificate authority that should sign new leaf
+// certificates: the intermediate CA if GenerateIntermediateCA has produced
+// one, falling back to the root CA for deployments that haven't adopted
+// the two-tier hierarchy.
+func (cm *CertificateManager) loadCA() (*x509.Certificate, crypto.Signer, error) {
+	certPath, keyPath := cm.intermediatePaths()
+	if fileExists(certPath) && fileExists(keyPath) {
+		return loadCertAndKey(certPath, keyPath)
 	}
+	return cm.loadRootCA()
+}
 
-	return nil
+// loadRootCA loads the root CA certificate and private key from CACert and
+// CertDir/ca.key, regardless of whether an intermediate CA has since been
+// generated. RotateCA and GenerateIntermediateCA operate on the root tier
+// specifically, so they use this instead of loadCA.
+func (cm *CertificateManager) loadRootCA() (*x509.Certificate, crypto.Signer, error) {
+	return loadCertAndKey(cm.config.CACert, filepath.Join(cm.config.CertDir, "ca.key"))
 }
 
-// loadCA loads the CA certificate and private key
-func (cm *CertificateManager) loadCA() (*x509.Certificate, *rsa.PrivateKey, error) {
-	// Load CA certificate
-	caCertPEM, err := os.ReadFile(cm.config.CACert)
+// loadCertAndKey reads and parses a CA certificate and its private key
+// (RSA, ECDSA, or Ed25519) from the given PEM files.
+func loadCertAndKey(certPath, keyPath string) (*x509.Certificate, crypto.Signer, error) {
+	certPEM, err := os.ReadFile(certPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to read CA certificate: %w", err)
 	}
 
-	caCertBlock, _ := pem.Decode(caCertPEM)
-	if caCertBlock == nil {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
 		return nil, nil, fmt.Errorf("failed to parse CA certificate PEM")
 	}
 
-	caCert, err := x509.ParseCertificate(caCertBlock.Bytes)
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to parse CA certificate: %w", err)
 	}
 
-	// Load CA private key
-	caKeyPath := filepath.Join(cm.config.CertDir, "ca.key")
-	caKeyPEM, err := os.ReadFile(caKeyPath)
+	keyPEM, err := os.ReadFile(keyPath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to read CA private key: %w", err)
 	}
 
-	caKeyBlock, _ := pem.Decode(caKeyPEM)
-	if caKeyBlock == nil {
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
 		return nil, nil, fmt.Errorf("failed to parse CA private key PEM")
 	}
 
-	caKey, err := x509.ParsePKCS8PrivateKey(caKeyBlock.Bytes)
+	key, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to parse CA private key: %w", err)
 	}
 
-	rsaKey, ok := caKey.(*rsa.PrivateKey)
+	signer, ok := key.(crypto.Signer)
 	if !ok {
-		return nil, nil, fmt.Errorf("CA private key is not RSA")
+		return nil, nil, fmt.Errorf("CA private key does not support signing")
 	}
 
-	return caCert, rsaKey, nil
+	return cert, signer, nil
 }
 
-// GenerateAllCerts generates all required certificates (CA, server, client)
+// GenerateAllCerts generates all required certificates: root CA,
+// intermediate CA, server, and client, with the server and client certs
+// chained through the intermediate.
 func (cm *CertificateManager) GenerateAllCerts() error {
-	// Generate CA first
-	if err := cm.GenerateCA(); err != nil {
-		return fmt.Errorf("failed to generate CA: %w", err)
+	// Generate root CA first
+	if err := cm.GenerateRootCA(); err != nil {
+		return fmt.Errorf("failed to generate root CA: %w", err)
+	}
+
+	// Generate intermediate CA, signed by the root
+	if err := cm.GenerateIntermediateCA(); err != nil {
+		return fmt.Errorf("failed to generate intermediate CA: %w", err)
 	}
 
 	// Generate server certificate
@@ -252,6 +409,35 @@ func (cm *CertificateManager) GenerateAllCerts() error {
 	return nil
 }
 
+// GenerateAllCertsForIdentities is GenerateAllCerts, but additionally encodes
+// serverIdentity's and clientIdentity's SPIFFE URIs into the server and
+// client leaf certificates it issues, so a demo-mode deployment (or a test)
+// gets SPIFFE-pinned peer identities for free instead of needing a separate
+// cert-gen --client-role invocation per identity.
+func (cm *CertificateManager) GenerateAllCertsForIdentities(serverIdentity, clientIdentity ClientIdentity) error {
+	if err := cm.GenerateRootCA(); err != nil {
+		return fmt.Errorf("failed to generate root CA: %w", err)
+	}
+
+	if err := cm.GenerateIntermediateCA(); err != nil {
+		return fmt.Errorf("failed to generate intermediate CA: %w", err)
+	}
+
+	serverSPIFFEID, err := serverIdentity.spiffeID()
+	if err != nil {
+		return fmt.Errorf("failed to build SPIFFE URI for server identity %s/%s: %w", serverIdentity.Role, serverIdentity.Name, err)
+	}
+	if err := cm.generateCert(serverIdentity.Name, config.ServerCert, cm.config.ServerCert, cm.config.ServerKey, true, serverSPIFFEID); err != nil {
+		return fmt.Errorf("failed to generate server certificate: %w", err)
+	}
+
+	if err := cm.GenerateClientCertForIdentity(clientIdentity); err != nil {
+		return fmt.Errorf("failed to generate client certificate: %w", err)
+	}
+
+	return nil
+}
+
 // ValidateCertificate validates a certificate file
 func (cm *CertificateManager) ValidateCertificate(certPath string) error {
 	certPEM, err := os.ReadFile(certPath)
@@ -290,6 +476,183 @@ type CertificateInfo struct {
 	NotAfter     time.Time `json:"not_after"`
 	IsCA         bool      `json:"is_ca"`
 	KeyUsage     []string  `json:"key_usage"`
+	// KeyAlgorithm is the config.KeyAlgorithm that produced this
+	// certificate's key pair, recovered from its SubjectPublicKeyInfo
+	// (kms.KeyAlgorithmFromPublicKey) since CertificateInfo is built from a
+	// certificate on disk, not the policy that issued it.
+	KeyAlgorithm config.KeyAlgorithm `json:"key_algorithm"`
+	// PublicKeyFingerprint is the SHA-256 digest of the raw
+	// SubjectPublicKeyInfo, hex-encoded, so an operator can confirm a
+	// rotation actually changed the key (or that two certificates share
+	// one) without comparing full PEM blobs.
+	PublicKeyFingerprint string `json:"public_key_fingerprint"`
+	// Issuers holds the non-leaf certificates of this certificate's
+	// verified chain up to (and including) the root CA, root last. It's
+	// populated on a best-effort basis: nil if the certificate's chain
+	// can't be verified against the configured root/intermediate CAs,
+	// since inspecting a certificate shouldn't require a fully configured
+	// trust store.
+	Issuers []CertificateInfo `json:"issuers,omitempty"`
+}
+
+// RotateCA generates a new CA key pair, cross-signs the existing server and
+// client leaf certificates against it so they keep validating under the new
+// root, and writes a combined bundle (old CA + new CA) to CACert for the
+// duration of overlap. Callers re-run GenerateServerCert/GenerateClientCert
+// against the new CA once the overlap window has elapsed and peers have
+// picked up the bundle, then replace CACert with the new CA alone.
+func (cm *CertificateManager) RotateCA(overlap time.Duration) error {
+	oldCACert, oldCAKey, err := cm.loadRootCA()
+	if err != nil {
+		return fmt.Errorf("failed to load existing CA: %w", err)
+	}
+
+	newCAKey, err := cm.keyManager.CreateKey("root-ca", cm.keyAlgorithm())
+	if err != nil {
+		return fmt.Errorf("failed to generate new CA private key: %w", err)
+	}
+
+	newCATemplate := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject: pkix.Name{
+			Organization: []string{"MCP Demo CA"},
+			Country:      []string{"US"},
+		},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	newCADER, err := x509.CreateCertificate(rand.Reader, &newCATemplate, &newCATemplate, newCAKey.Public(), newCAKey)
+	if err != nil {
+		return fmt.Errorf("failed to create new CA certificate: %w", err)
+	}
+
+	newCACert, err := x509.ParseCertificate(newCADER)
+	if err != nil {
+		return fmt.Errorf("failed to parse new CA certificate: %w", err)
+	}
+
+	// Cross-sign the existing leaf certs against the new CA so sessions that
+	// haven't reloaded their trust store keep working, and peers that have
+	// already switched to the new root can verify them too.
+	for _, leaf := range []struct {
+		certPath, keyPath string
+		isServer          bool
+	}{
+		{cm.config.ServerCert, cm.config.ServerKey, true},
+		{cm.config.ClientCert, cm.config.ClientKey, false},
+	} {
+		if err := cm.crossSign(leaf.certPath, leaf.keyPath, newCACert, newCAKey); err != nil {
+			return fmt.Errorf("failed to cross-sign %s: %w", leaf.certPath, err)
+		}
+	}
+
+	// Write the combined bundle: old CA first, new CA second. Peers
+	// validating with either root succeed until the overlap window expires
+	// and the bundle is collapsed to the new CA alone.
+	bundle := append(append([]byte{}, pemEncodeCert(oldCACert)...), pemEncodeCert(newCACert)...)
+	if err := os.WriteFile(cm.config.CACert, bundle, 0644); err != nil {
+		return fmt.Errorf("failed to write combined CA bundle: %w", err)
+	}
+
+	// Persist the new CA key under a generation-specific name so operators
+	// can finish the rotation (drop the old root) after the overlap window.
+	newCAKeyPath := filepath.Join(cm.config.CertDir, "ca-new.key")
+	newCACertPath := filepath.Join(cm.config.CertDir, "ca-new.crt")
+	if err := writePrivateKeyFile(newCAKeyPath, newCAKey); err != nil {
+		return fmt.Errorf("failed to write new CA key: %w", err)
+	}
+	if err := os.WriteFile(newCACertPath, pemEncodeCert(newCACert), 0644); err != nil {
+		return fmt.Errorf("failed to write new CA certificate: %w", err)
+	}
+
+	_ = oldCAKey // retained only long enough to prove possession above
+	utils.Info("CA rotation started: combined bundle written to %s, overlap window %s; finish with the new CA at %s once peers have reloaded", cm.config.CACert, overlap, newCACertPath)
+	return nil
+}
+
+// crossSign re-issues the leaf certificate at certPath/keyPath, signed by
+// the given CA, keeping its subject, SANs, and key usage intact.
+func (cm *CertificateManager) crossSign(certPath, keyPath string, caCert *x509.Certificate, caKey crypto.Signer) error {
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return fmt.Errorf("failed to read leaf certificate: %w", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return fmt.Errorf("failed to parse leaf certificate PEM")
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse leaf certificate: %w", err)
+	}
+
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read leaf private key: %w", err)
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return fmt.Errorf("failed to parse leaf private key PEM")
+	}
+	leafKey, err := x509.ParsePKCS8PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse leaf private key: %w", err)
+	}
+	leafSigner, ok := leafKey.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("leaf private key does not support signing")
+	}
+
+	template := *leaf
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+
+	newCertDER, err := x509.CreateCertificate(rand.Reader, &template, caCert, leafSigner.Public(), caKey)
+	if err != nil {
+		return fmt.Errorf("failed to re-sign certificate: %w", err)
+	}
+
+	certOut, err := os.Create(certPath)
+	if err != nil {
+		return fmt.Errorf("failed to open certificate for writing: %w", err)
+	}
+	defer certOut.Close()
+
+	return pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: newCertDER})
+}
+
+// pemEncodeCert PEM-encodes a parsed certificate back to DER bytes.
+func pemEncodeCert(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
+
+// writePrivateKeyFile PEM-encodes and writes a private key with restrictive
+// permissions. Keys that can't be PKCS#8-marshaled (an opaque crypto.Signer
+// backed by an HSM/token, e.g. kms.PKCS11KeyManager's) are left alone: their
+// whole point is that the private key material never leaves the device, so
+// there's nothing to write to disk.
+func writePrivateKeyFile(path string, key crypto.Signer) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		utils.Info("private key for %s is held by its KeyManager and was not written to disk", path)
+		return nil
+	}
+
+	keyOut, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer keyOut.Close()
+
+	if err := keyOut.Chmod(0600); err != nil {
+		return err
+	}
+
+	return pem.Encode(keyOut, &pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
 }
 
 // GetCertificateInfo returns information about a certificate
@@ -309,6 +672,14 @@ func (cm *CertificateManager) GetCertificateInfo(certPath string) (*CertificateI
 		return nil, fmt.Errorf("failed to parse certificate: %w", err)
 	}
 
+	info := certificateInfoFromX509(cert)
+	info.Issuers = cm.verifiedIssuers(cert)
+	return info, nil
+}
+
+// certificateInfoFromX509 extracts CertificateInfo's basic fields from a
+// parsed certificate, leaving Issuers unset.
+func certificateInfoFromX509(cert *x509.Certificate) *CertificateInfo {
 	keyUsage := []string{}
 	if cert.KeyUsage&x509.KeyUsageDigitalSignature != 0 {
 		keyUsage = append(keyUsage, "Digital Signature")
@@ -320,13 +691,54 @@ func (cm *CertificateManager) GetCertificateInfo(certPath string) (*CertificateI
 		keyUsage = append(keyUsage, "Certificate Sign")
 	}
 
+	fingerprint := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+
 	return &CertificateInfo{
-		Subject:      cert.Subject.String(),
-		Issuer:       cert.Issuer.String(),
-		SerialNumber: cert.SerialNumber.String(),
-		NotBefore:    cert.NotBefore,
-		NotAfter:     cert.NotAfter,
-		IsCA:         cert.IsCA,
-		KeyUsage:     keyUsage,
-	}, nil
-}
\ No newline at end of file
+		Subject:              cert.Subject.String(),
+		Issuer:               cert.Issuer.String(),
+		SerialNumber:         cert.SerialNumber.String(),
+		NotBefore:            cert.NotBefore,
+		NotAfter:             cert.NotAfter,
+		IsCA:                 cert.IsCA,
+		KeyUsage:             keyUsage,
+		KeyAlgorithm:         kms.KeyAlgorithmFromPublicKey(cert.PublicKey),
+		PublicKeyFingerprint: hex.EncodeToString(fingerprint[:]),
+	}
+}
+
+// verifiedIssuers walks cert's chain up to the root CA (via the
+// intermediate if one exists), returning the non-leaf certificates of the
+// first verified chain, root last. Returns nil if the trust store isn't
+// configured or the chain doesn't verify.
+func (cm *CertificateManager) verifiedIssuers(cert *x509.Certificate) []CertificateInfo {
+	rootPEM, err := os.ReadFile(cm.config.CACert)
+	if err != nil {
+		return nil
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(rootPEM) {
+		return nil
+	}
+
+	intermediates := x509.NewCertPool()
+	intermediateCertPath, _ := cm.intermediatePaths()
+	if intermediatePEM, err := os.ReadFile(intermediateCertPath); err == nil {
+		intermediates.AppendCertsFromPEM(intermediatePEM)
+	}
+
+	chains, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil || len(chains) == 0 {
+		return nil
+	}
+
+	verified := chains[0]
+	issuers := make([]CertificateInfo, 0, len(verified)-1)
+	for _, issuerCert := range verified[1:] {
+		issuers = append(issuers, *certificateInfoFromX509(issuerCert))
+	}
+	return issuers
+}
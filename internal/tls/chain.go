@@ -0,0 +1,107 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+)
+
+// LoadChain reads and parses every certificate PEM block in certPath,
+// returning them leaf-first. It reads a lone leaf certificate as a
+// one-element chain, and a file already holding a concatenated
+// leaf+intermediate(+root) bundle as the full chain.
+func (cm *CertificateManager) LoadChain(certPath string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate chain: %w", err)
+	}
+
+	var chain []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate in chain: %w", err)
+		}
+		chain = append(chain, cert)
+	}
+
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no certificates found in %s", certPath)
+	}
+	return chain, nil
+}
+
+// WriteChainPEM writes leafPath's leaf certificate followed by the
+// intermediate CA (if GenerateIntermediateCA has produced one) to w, so a
+// server can present the full leaf→intermediate bundle over TLS instead of
+// just the leaf — many strict TLS clients refuse to validate without it.
+func (cm *CertificateManager) WriteChainPEM(w io.Writer, leafPath string) error {
+	leafPEM, err := os.ReadFile(leafPath)
+	if err != nil {
+		return fmt.Errorf("failed to read leaf certificate: %w", err)
+	}
+	if _, err := w.Write(leafPEM); err != nil {
+		return err
+	}
+
+	intermediateCertPath, _ := cm.intermediatePaths()
+	if !fileExists(intermediateCertPath) {
+		return nil
+	}
+
+	intermediatePEM, err := os.ReadFile(intermediateCertPath)
+	if err != nil {
+		return fmt.Errorf("failed to read intermediate CA certificate: %w", err)
+	}
+	_, err = w.Write(intermediatePEM)
+	return err
+}
+
+// VerifyChain verifies certPath's leaf certificate against the root CA
+// pool, chaining through the intermediate CA (if one exists) and any
+// additional certificates already concatenated into certPath itself.
+func (cm *CertificateManager) VerifyChain(certPath string) error {
+	chain, err := cm.LoadChain(certPath)
+	if err != nil {
+		return err
+	}
+	leaf := chain[0]
+
+	rootPEM, err := os.ReadFile(cm.config.CACert)
+	if err != nil {
+		return fmt.Errorf("failed to read root CA: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(rootPEM) {
+		return fmt.Errorf("failed to parse root CA certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	intermediateCertPath, _ := cm.intermediatePaths()
+	if intermediatePEM, err := os.ReadFile(intermediateCertPath); err == nil {
+		intermediates.AppendCertsFromPEM(intermediatePEM)
+	}
+	for _, extra := range chain[1:] {
+		intermediates.AddCert(extra)
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("certificate chain verification failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// GenerateCRL builds and signs a CRL covering every serial number in store,
+// using the CA CertificateManager.generateCert chains leaf certs through
+// (the intermediate if one exists, else the root), and writes it PEM-encoded
+// to path. Leaf certs issued by generateCert name this CRL in their
+// CRLDistributionPoints extension, so peers that don't have a fresher OCSP
+// staple can fetch and check against it.
+func (cm *CertificateManager) GenerateCRL(store *RevocationStore, path string, validity time.Duration) error {
+	caCert, caKey, err := cm.loadCA()
+	if err != nil {
+		return fmt.Errorf("failed to load CA: %w", err)
+	}
+
+	entries := store.Entries()
+	revoked := make([]x509.RevocationListEntry, 0, len(entries))
+	for _, entry := range entries {
+		serial, ok := new(big.Int).SetString(entry.Serial, 10)
+		if !ok {
+			return fmt.Errorf("revocation store contains an invalid serial number %q", entry.Serial)
+		}
+		revoked = append(revoked, x509.RevocationListEntry{
+			SerialNumber:   serial,
+			RevocationTime: entry.RevokedAt,
+			ReasonCode:     entry.Reason,
+		})
+	}
+
+	template := &x509.RevocationList{
+		Number:                    big.NewInt(time.Now().UnixNano()),
+		ThisUpdate:                time.Now(),
+		NextUpdate:                time.Now().Add(validity),
+		RevokedCertificateEntries: revoked,
+	}
+
+	crlDER, err := x509.CreateRevocationList(rand.Reader, template, caCert, caKey)
+	if err != nil {
+		return fmt.Errorf("failed to create CRL: %w", err)
+	}
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crlDER}), 0644); err != nil {
+		return fmt.Errorf("failed to write CRL: %w", err)
+	}
+
+	utils.Info("Generated CRL at %s covering %d revoked certificate(s), next update %s", path, len(revoked), template.NextUpdate)
+	return nil
+}
+
+// StartCRLAutoRefresh periodically re-signs the CRL at path (via
+// GenerateCRL) every refreshInterval, each one valid for validity, so a
+// certificate revoked after the last refresh shows up in the next published
+// CRL without an operator re-running the CLI by hand.
+func (cm *CertificateManager) StartCRLAutoRefresh(ctx context.Context, store *RevocationStore, path string, refreshInterval, validity time.Duration) {
+	go func() {
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := cm.GenerateCRL(store, path, validity); err != nil {
+					utils.Error("CRL auto-refresh failed: %v", err)
+				}
+			}
+		}
+	}()
+}
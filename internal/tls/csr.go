@@ -0,0 +1,182 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// SANs holds the subject alternative names to embed in a CSR.
+type SANs struct {
+	DNSNames    []string
+	IPAddresses []net.IP
+	URIs        []*url.URL
+}
+
+// ParseSANs parses a comma-separated "type:value" list, e.g.
+// "dns:foo,ip:10.0.0.1,uri:spiffe://example.org/ns/default", into a SANs
+// value for GenerateCSR.
+func ParseSANs(spec string) (SANs, error) {
+	var sans SANs
+	if spec == "" {
+		return sans, nil
+	}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, ":", 2)
+		if len(parts) != 2 {
+			return SANs{}, fmt.Errorf("invalid SAN entry %q, expected type:value", entry)
+		}
+
+		switch sanType, value := strings.ToLower(parts[0]), parts[1]; sanType {
+		case "dns":
+			sans.DNSNames = append(sans.DNSNames, value)
+		case "ip":
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return SANs{}, fmt.Errorf("invalid IP SAN %q", value)
+			}
+			sans.IPAddresses = append(sans.IPAddresses, ip)
+		case "uri":
+			u, err := url.Parse(value)
+			if err != nil {
+				return SANs{}, fmt.Errorf("invalid URI SAN %q: %w", value, err)
+			}
+			sans.URIs = append(sans.URIs, u)
+		default:
+			return SANs{}, fmt.Errorf("unsupported SAN type %q", sanType)
+		}
+	}
+
+	return sans, nil
+}
+
+// GenerateCSR creates a new RSA key pair and a PKCS#10 certificate signing
+// request for name with the given SANs, returning both PEM-encoded. It
+// never reads or writes the CA, so it can run on a host that doesn't hold
+// ca.key as part of an air-gapped CA workflow.
+func (cm *CertificateManager) GenerateCSR(name string, sans SANs) (csrPEM, keyPEM []byte, err error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	template := x509.CertificateRequest{
+		Subject: pkix.Name{
+			Organization: []string{"MCP Demo"},
+			Country:      []string{"US"},
+			CommonName:   name,
+		},
+		DNSNames:    sans.DNSNames,
+		IPAddresses: sans.IPAddresses,
+		URIs:        sans.URIs,
+	}
+
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &template, privateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create CSR: %w", err)
+	}
+	csrPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	utils.Info("Generated CSR for %s (%d DNS SAN(s), %d IP SAN(s))", name, len(sans.DNSNames), len(sans.IPAddresses))
+	return csrPEM, keyPEM, nil
+}
+
+// SignCSR verifies csrPEM's self-signature and validates its SANs against
+// the named signing profile, then issues a certificate from it signed by
+// the CA. It is meant to run on a host that holds ca.key but never a
+// leaf's private key, the counterpart to GenerateCSR in an air-gapped CA
+// operation model.
+func (cm *CertificateManager) SignCSR(csrPEM []byte, profileName string) ([]byte, error) {
+	if cm.policy == nil {
+		return nil, fmt.Errorf("no signing policy configured on this certificate manager")
+	}
+
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, fmt.Errorf("failed to parse CSR PEM")
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSR: %w", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("CSR signature is invalid: %w", err)
+	}
+
+	profile, err := cm.policy.Profile(profileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve profile: %w", err)
+	}
+
+	if err := cm.validateSANsAgainstProfile(profile, CSRTemplate{
+		DNSNames:    csr.DNSNames,
+		IPAddresses: csr.IPAddresses,
+		URIs:        csr.URIs,
+	}); err != nil {
+		return nil, fmt.Errorf("SANs not permitted by profile %q: %w", profile.Name, err)
+	}
+
+	caCert, caKey, err := cm.loadCA()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA: %w", err)
+	}
+
+	keyUsage, err := parseKeyUsages(profile.KeyUsages)
+	if err != nil {
+		return nil, fmt.Errorf("profile %q: %w", profile.Name, err)
+	}
+	extKeyUsage, err := parseExtKeyUsages(profile.ExtKeyUsages)
+	if err != nil {
+		return nil, fmt.Errorf("profile %q: %w", profile.Name, err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      csr.Subject,
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(profile.MaxValidity),
+		KeyUsage:     keyUsage,
+		ExtKeyUsage:  extKeyUsage,
+		DNSNames:     csr.DNSNames,
+		IPAddresses:  csr.IPAddresses,
+		URIs:         csr.URIs,
+	}
+
+	if nc := profile.NameConstraints; nc != nil {
+		template.PermittedDNSDomains = nc.PermittedDNSDomains
+		template.ExcludedDNSDomains = nc.ExcludedDNSDomains
+		template.PermittedDNSDomainsCritical = len(nc.PermittedDNSDomains) > 0
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, &template, caCert, csr.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign CSR with profile %q: %w", profile.Name, err)
+	}
+
+	utils.Info("Signed CSR for %q from profile %q", csr.Subject.CommonName, profile.Name)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), nil
+}
@@ -0,0 +1,64 @@
+package tls
+
+import (
+	"context"
+	"time"
+
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// expiryWarningThreshold is how soon a certificate's expiry must be before
+// StartExpiryMonitor logs a warning for it, mirroring the operational
+// heuristic a certificate-manager dashboard typically surfaces (two weeks'
+// notice is enough to cut a renewal before anything actually breaks).
+const expiryWarningThreshold = 14 * 24 * time.Hour
+
+// DefaultExpiryCheckInterval is how often StartExpiryMonitor re-validates
+// the configured server/client certificates against cfg.CACert.
+const DefaultExpiryCheckInterval = 1 * time.Hour
+
+// StartExpiryMonitor starts a background goroutine that re-validates
+// cfg.ServerCert and cfg.ClientCert (whichever are set) against cfg.CACert
+// every checkInterval via ValidateCertificate, logging a warning whenever a
+// cert's DaysUntilExpiry drops under 14. It stops when ctx is cancelled.
+// Unlike CertRenewer, it never regenerates anything — it's a read-only
+// ops-facing signal, meant to run alongside rotation/renewal rather than
+// replace it.
+func StartExpiryMonitor(ctx context.Context, cfg *config.TLSConfig, checkInterval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		checkExpiryMonitorOnce(cfg)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				checkExpiryMonitorOnce(cfg)
+			}
+		}
+	}()
+}
+
+func checkExpiryMonitorOnce(cfg *config.TLSConfig) {
+	for _, certPath := range []string{cfg.ServerCert, cfg.ClientCert} {
+		if certPath == "" {
+			continue
+		}
+
+		resp, err := ValidateCertificate(CertificateValidationRequest{
+			CertPath:   certPath,
+			CACertPath: cfg.CACert,
+		})
+		if err != nil {
+			utils.Warn("expiry monitor: failed to validate %s: %v", certPath, err)
+			continue
+		}
+
+		if time.Until(resp.ExpiresAt) < expiryWarningThreshold {
+			utils.Warn("expiry monitor: %s (%s) expires in %d day(s), at %s", certPath, resp.Subject, resp.DaysUntilExpiry, resp.ExpiresAt)
+		}
+	}
+}
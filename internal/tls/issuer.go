@@ -0,0 +1,44 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"fmt"
+	"os"
+)
+
+// Issuer is the common "obtain a server certificate" surface shared by the
+// self-signed CertificateManager and ACMEIssuer, so callers can pick between
+// a private CA and a public ACME directory per environment without branching
+// on which one they're using.
+type Issuer interface {
+	// IssueServerCert returns a PEM-encoded certificate and private key
+	// covering names (names[0] as the subject CommonName, all of them as
+	// DNS SANs).
+	IssueServerCert(names []string) (certPEM, keyPEM []byte, err error)
+}
+
+// IssueServerCert implements Issuer for the local CA: it's GenerateServerCert
+// plus returning the issued cert/key as PEM instead of requiring the caller
+// to re-read them from cm.config.ServerCert/ServerKey. Only names[0] is
+// embedded as the certificate's CommonName and primary SAN, matching
+// generateCert's existing single-name behavior; ACMEIssuer is the
+// multi-domain-capable Issuer for deployments that need that.
+func (cm *CertificateManager) IssueServerCert(names []string) (certPEM, keyPEM []byte, err error) {
+	if len(names) == 0 {
+		return nil, nil, fmt.Errorf("at least one name is required")
+	}
+
+	if err := cm.GenerateServerCert(names[0]); err != nil {
+		return nil, nil, err
+	}
+
+	certPEM, err = os.ReadFile(cm.config.ServerCert)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read issued certificate: %w", err)
+	}
+	keyPEM, err = os.ReadFile(cm.config.ServerKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read issued private key: %w", err)
+	}
+	return certPEM, keyPEM, nil
+}
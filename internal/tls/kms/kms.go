@@ -0,0 +1,139 @@
+// Package kms abstracts over where a certificate's private key material
+// actually lives, modeled after smallstep's kms/apiv1 package.
+// CertificateManager creates every key it needs through a KeyManager
+// instead of calling rsa.GenerateKey directly, so a deployment can swap the
+// default in-process SoftwareKeyManager for an HSM- or smart-card-backed
+// implementation (see PKCS11KeyManager) without changing certificate-issuing
+// logic.
+package kms
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"sync"
+
+	"github.com/steve/llm-agents/internal/config"
+)
+
+// DefaultKeyAlgorithm is used when a config.TLSConfig leaves KeyType unset,
+// matching this package's fixed RSA-2048 key size before key algorithms
+// became pluggable.
+const DefaultKeyAlgorithm = config.KeyAlgorithmRSA2048
+
+// KeyManager creates and operates signing keys by name.
+type KeyManager interface {
+	// CreateKey generates a new key pair named name and returns a signer
+	// for it. Calling CreateKey again with the same name replaces the key.
+	CreateKey(name string, alg config.KeyAlgorithm) (crypto.Signer, error)
+	// GetPublicKey returns the public key of a previously created key.
+	GetPublicKey(name string) (crypto.PublicKey, error)
+	// Sign signs digest with the named key using opts (typically a hash
+	// algorithm), without requiring the caller to hold the private key
+	// itself.
+	Sign(name string, digest []byte, opts crypto.SignerOpts) ([]byte, error)
+}
+
+// SoftwareKeyManager generates keys in-process and keeps them in memory for
+// the life of the process; CertificateManager PEM-encodes the signer it
+// returns to disk itself. This is the default KeyManager, matching this
+// package's behavior before key management became pluggable.
+type SoftwareKeyManager struct {
+	mu      sync.RWMutex
+	signers map[string]crypto.Signer
+}
+
+// NewSoftwareKeyManager returns an empty in-process KeyManager.
+func NewSoftwareKeyManager() *SoftwareKeyManager {
+	return &SoftwareKeyManager{signers: make(map[string]crypto.Signer)}
+}
+
+// CreateKey generates a key pair for alg and keeps it in memory under name.
+func (m *SoftwareKeyManager) CreateKey(name string, alg config.KeyAlgorithm) (crypto.Signer, error) {
+	signer, err := GenerateSigner(alg)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	m.signers[name] = signer
+	m.mu.Unlock()
+	return signer, nil
+}
+
+// GetPublicKey returns the public key of the key previously created under name.
+func (m *SoftwareKeyManager) GetPublicKey(name string) (crypto.PublicKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	signer, ok := m.signers[name]
+	if !ok {
+		return nil, fmt.Errorf("kms: no key named %q", name)
+	}
+	return signer.Public(), nil
+}
+
+// Sign signs digest with the key previously created under name.
+func (m *SoftwareKeyManager) Sign(name string, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	m.mu.RLock()
+	signer, ok := m.signers[name]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("kms: no key named %q", name)
+	}
+	return signer.Sign(rand.Reader, digest, opts)
+}
+
+// GenerateSigner creates a new in-process key pair for alg. It's shared by
+// SoftwareKeyManager and anything else that needs a key without going
+// through the KeyManager interface (e.g. GenerateCSR's standalone keypair).
+func GenerateSigner(alg config.KeyAlgorithm) (crypto.Signer, error) {
+	switch alg {
+	case config.KeyAlgorithmRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case config.KeyAlgorithmRSA3072:
+		return rsa.GenerateKey(rand.Reader, 3072)
+	case config.KeyAlgorithmRSA4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case config.KeyAlgorithmECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case config.KeyAlgorithmECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case config.KeyAlgorithmEd25519:
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		return priv, err
+	case "":
+		return GenerateSigner(DefaultKeyAlgorithm)
+	default:
+		return nil, fmt.Errorf("kms: unsupported key algorithm: %s", alg)
+	}
+}
+
+// KeyAlgorithmFromPublicKey identifies which config.KeyAlgorithm produced
+// pub, the inverse of GenerateSigner, so callers that only have a parsed
+// certificate (e.g. CertificateInfo) can report the algorithm that issued
+// it without having tracked it separately.
+func KeyAlgorithmFromPublicKey(pub crypto.PublicKey) config.KeyAlgorithm {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		switch key.N.BitLen() {
+		case 3072:
+			return config.KeyAlgorithmRSA3072
+		case 4096:
+			return config.KeyAlgorithmRSA4096
+		default:
+			return config.KeyAlgorithmRSA2048
+		}
+	case *ecdsa.PublicKey:
+		if key.Curve == elliptic.P384() {
+			return config.KeyAlgorithmECDSAP384
+		}
+		return config.KeyAlgorithmECDSAP256
+	case ed25519.PublicKey:
+		return config.KeyAlgorithmEd25519
+	default:
+		return ""
+	}
+}
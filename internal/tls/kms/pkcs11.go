@@ -0,0 +1,291 @@
+package kms
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+
+	"github.com/steve/llm-agents/internal/config"
+)
+
+// oidP256 and oidP384 are the DER-encoded named-curve OIDs PKCS#11 expects
+// in a CKA_EC_PARAMS attribute when generating an EC key pair.
+var (
+	oidP256 = mustMarshalOID(1, 2, 840, 10045, 3, 1, 7)
+	oidP384 = mustMarshalOID(1, 3, 132, 0, 34)
+)
+
+func mustMarshalOID(parts ...int) []byte {
+	der, err := asn1.Marshal(asn1.ObjectIdentifier(parts))
+	if err != nil {
+		panic(fmt.Sprintf("kms: failed to encode OID: %v", err))
+	}
+	return der
+}
+
+// PKCS11KeyManager implements KeyManager against a PKCS#11 token (an HSM or
+// a smart card such as a YubiKey). Keys are generated on the token with
+// C_GenerateKeyPair and marked non-extractable; Sign delegates to
+// C_SignInit/C_Sign, so the private key material never leaves the token and
+// is never written to disk by CertificateManager.
+type PKCS11KeyManager struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+
+	mu      sync.RWMutex
+	signers map[string]*pkcs11Signer
+}
+
+// NewPKCS11KeyManager loads the PKCS#11 module at modulePath, opens a
+// read-write session against slot, and logs in with pin. Callers must call
+// Close when done to release the session and unload the module.
+func NewPKCS11KeyManager(modulePath string, slot uint, pin string) (*PKCS11KeyManager, error) {
+	ctx := pkcs11.New(modulePath)
+	if ctx == nil {
+		return nil, fmt.Errorf("kms: failed to load PKCS#11 module %s", modulePath)
+	}
+	if err := ctx.Initialize(); err != nil {
+		return nil, fmt.Errorf("kms: failed to initialize PKCS#11 module: %w", err)
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		ctx.Finalize()
+		return nil, fmt.Errorf("kms: failed to open PKCS#11 session: %w", err)
+	}
+	if err := ctx.Login(session, pkcs11.CKU_USER, pin); err != nil {
+		ctx.CloseSession(session)
+		ctx.Finalize()
+		return nil, fmt.Errorf("kms: failed to log in to PKCS#11 token: %w", err)
+	}
+
+	return &PKCS11KeyManager{
+		ctx:     ctx,
+		session: session,
+		signers: make(map[string]*pkcs11Signer),
+	}, nil
+}
+
+// Close logs out of the token, closes the session, and unloads the module.
+func (m *PKCS11KeyManager) Close() error {
+	_ = m.ctx.Logout(m.session)
+	_ = m.ctx.CloseSession(m.session)
+	m.ctx.Finalize()
+	m.ctx.Destroy()
+	return nil
+}
+
+// CreateKey generates a key pair on the token, labeled name, and returns a
+// signer that performs every signature on the token itself.
+func (m *PKCS11KeyManager) CreateKey(name string, alg config.KeyAlgorithm) (crypto.Signer, error) {
+	mechanism, pubTemplate, privTemplate, err := templatesForAlgorithm(name, alg)
+	if err != nil {
+		return nil, err
+	}
+
+	pubHandle, privHandle, err := m.ctx.GenerateKeyPair(m.session, mechanism, pubTemplate, privTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("kms: failed to generate key pair on token: %w", err)
+	}
+
+	pub, err := m.exportPublicKey(pubHandle, alg)
+	if err != nil {
+		return nil, err
+	}
+
+	signer := &pkcs11Signer{manager: m, handle: privHandle, public: pub}
+	m.mu.Lock()
+	m.signers[name] = signer
+	m.mu.Unlock()
+	return signer, nil
+}
+
+// GetPublicKey returns the public key of a previously created key.
+func (m *PKCS11KeyManager) GetPublicKey(name string) (crypto.PublicKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	signer, ok := m.signers[name]
+	if !ok {
+		return nil, fmt.Errorf("kms: no key named %q", name)
+	}
+	return signer.public, nil
+}
+
+// Sign signs digest with the named key, entirely on the token.
+func (m *PKCS11KeyManager) Sign(name string, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	m.mu.RLock()
+	signer, ok := m.signers[name]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("kms: no key named %q", name)
+	}
+	return signer.Sign(nil, digest, opts)
+}
+
+// templatesForAlgorithm builds the PKCS#11 mechanism and object templates
+// for generating alg under label/ID name. Ed25519 isn't offered here since
+// most deployed tokens don't yet implement CKM_EC_EDWARDS_KEY_PAIR_GEN.
+func templatesForAlgorithm(name string, alg config.KeyAlgorithm) (mechanism []*pkcs11.Mechanism, pubTemplate, privTemplate []*pkcs11.Attribute, err error) {
+	label := []byte(name)
+
+	base := func(sign bool) []*pkcs11.Attribute {
+		return []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+			pkcs11.NewAttribute(pkcs11.CKA_ID, label),
+		}
+	}
+
+	switch alg {
+	case config.KeyAlgorithmRSA2048, config.KeyAlgorithmRSA3072, config.KeyAlgorithmRSA4096:
+		bits := map[config.KeyAlgorithm]int{
+			config.KeyAlgorithmRSA2048: 2048,
+			config.KeyAlgorithmRSA3072: 3072,
+			config.KeyAlgorithmRSA4096: 4096,
+		}[alg]
+
+		pubTemplate = append(base(false),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+			pkcs11.NewAttribute(pkcs11.CKA_MODULUS_BITS, bits),
+			pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, []byte{0x01, 0x00, 0x01}),
+		)
+		privTemplate = append(base(true),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		)
+		return []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS_KEY_PAIR_GEN, nil)}, pubTemplate, privTemplate, nil
+
+	case config.KeyAlgorithmECDSAP256, config.KeyAlgorithmECDSAP384:
+		oid := oidP256
+		if alg == config.KeyAlgorithmECDSAP384 {
+			oid = oidP384
+		}
+
+		pubTemplate = append(base(false),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, oid),
+		)
+		privTemplate = append(base(true),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		)
+		return []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)}, pubTemplate, privTemplate, nil
+
+	default:
+		return nil, nil, nil, fmt.Errorf("kms: key algorithm %s is not supported on a PKCS#11 token", alg)
+	}
+}
+
+// exportPublicKey reads the public half of a key the token just generated;
+// only the public key ever leaves the token.
+func (m *PKCS11KeyManager) exportPublicKey(handle pkcs11.ObjectHandle, alg config.KeyAlgorithm) (crypto.PublicKey, error) {
+	switch alg {
+	case config.KeyAlgorithmRSA2048, config.KeyAlgorithmRSA3072, config.KeyAlgorithmRSA4096:
+		attrs, err := m.ctx.GetAttributeValue(m.session, handle, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_MODULUS, nil),
+			pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, nil),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("kms: failed to read RSA public key from token: %w", err)
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(attrs[0].Value),
+			E: int(new(big.Int).SetBytes(attrs[1].Value).Int64()),
+		}, nil
+
+	case config.KeyAlgorithmECDSAP256, config.KeyAlgorithmECDSAP384:
+		curve := elliptic.P256()
+		if alg == config.KeyAlgorithmECDSAP384 {
+			curve = elliptic.P384()
+		}
+		attrs, err := m.ctx.GetAttributeValue(m.session, handle, []*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("kms: failed to read EC public key from token: %w", err)
+		}
+
+		var rawPoint []byte
+		if _, err := asn1.Unmarshal(attrs[0].Value, &rawPoint); err != nil {
+			return nil, fmt.Errorf("kms: failed to decode EC point: %w", err)
+		}
+		x, y := elliptic.Unmarshal(curve, rawPoint)
+		if x == nil {
+			return nil, fmt.Errorf("kms: token returned an invalid EC point")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("kms: key algorithm %s is not supported on a PKCS#11 token", alg)
+	}
+}
+
+// pkcs11Signer is a crypto.Signer backed by a non-extractable private key
+// handle on a PKCS#11 token: Sign performs C_SignInit/C_Sign on the token
+// and the private key material is never read into process memory.
+type pkcs11Signer struct {
+	manager *PKCS11KeyManager
+	handle  pkcs11.ObjectHandle
+	public  crypto.PublicKey
+}
+
+func (s *pkcs11Signer) Public() crypto.PublicKey {
+	return s.public
+}
+
+func (s *pkcs11Signer) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var mechanism *pkcs11.Mechanism
+	switch s.public.(type) {
+	case *rsa.PublicKey:
+		if _, ok := opts.(*rsa.PSSOptions); ok {
+			return nil, fmt.Errorf("kms: RSA-PSS is not supported on a PKCS#11 token")
+		}
+		prefix, err := pkcs11DigestInfoPrefix(opts.HashFunc())
+		if err != nil {
+			return nil, err
+		}
+		mechanism = pkcs11.NewMechanism(pkcs11.CKM_RSA_PKCS, nil)
+		digest = append(prefix, digest...)
+	case *ecdsa.PublicKey:
+		mechanism = pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)
+	default:
+		return nil, fmt.Errorf("kms: unsupported public key type %T", s.public)
+	}
+
+	ctx := s.manager.ctx
+	if err := ctx.SignInit(s.manager.session, []*pkcs11.Mechanism{mechanism}, s.handle); err != nil {
+		return nil, fmt.Errorf("kms: failed to initialize signing on token: %w", err)
+	}
+	signature, err := ctx.Sign(s.manager.session, digest)
+	if err != nil {
+		return nil, fmt.Errorf("kms: token signing failed: %w", err)
+	}
+	return signature, nil
+}
+
+// pkcs11DigestInfoPrefix returns the DER-encoded DigestInfo prefix CKM_RSA_PKCS
+// expects ahead of the raw hash, since (unlike CKM_SHA256_RSA_PKCS) it signs
+// exactly what it's given without hashing or wrapping it itself.
+func pkcs11DigestInfoPrefix(hash crypto.Hash) ([]byte, error) {
+	prefix, ok := map[crypto.Hash][]byte{
+		crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+		crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+		crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+	}[hash]
+	if !ok {
+		return nil, fmt.Errorf("kms: unsupported hash algorithm for PKCS#11 signing: %v", hash)
+	}
+	return append([]byte{}, prefix...), nil
+}
@@ -2,24 +2,188 @@
 package tls
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/utils"
 )
 
 // TLSLoader handles loading and configuring TLS certificates
 type TLSLoader struct {
 	config *config.TLSConfig
+
+	authorizer PeerAuthorizer
+	revocation *RevocationChecker
+
+	mu             sync.RWMutex
+	serverReloader *CertReloader
+	clientReloader *CertReloader
 }
 
-// NewTLSLoader creates a new TLS loader with the given configuration
+// NewTLSLoader creates a new TLS loader with the given configuration. If
+// cfg.AllowedPeerIDs is non-empty, peers are authorized by SPIFFE/DNS
+// identity via a SPIFFEAuthorizer; call WithPeerAuthorizer to override this
+// with a different PeerAuthorizer. A RevocationChecker is always attached;
+// it's only a no-op until cfg names a CRL source or a served leaf carries
+// an OCSPServer AIA.
 func NewTLSLoader(cfg *config.TLSConfig) *TLSLoader {
-	return &TLSLoader{
-		config: cfg,
+	loader := &TLSLoader{
+		config:     cfg,
+		revocation: NewRevocationChecker(cfg),
+	}
+	if cfg != nil && len(cfg.AllowedPeerIDs) > 0 {
+		loader.authorizer = NewSPIFFEAuthorizer(cfg.AllowedPeerIDs)
+	}
+	return loader
+}
+
+// WithPeerAuthorizer attaches a PeerAuthorizer to this loader, replacing
+// whatever NewTLSLoader derived from cfg.AllowedPeerIDs (if anything).
+func (loader *TLSLoader) WithPeerAuthorizer(authorizer PeerAuthorizer) *TLSLoader {
+	loader.authorizer = authorizer
+	return loader
+}
+
+// StartReloader starts filesystem watchers (via CertReloader) over the
+// server and client certificate/key pairs and the CA bundle named in
+// loader.config. Once started, LoadServerTLSConfig and LoadClientTLSConfig
+// return configs whose GetCertificate/GetClientCertificate/CA trust always
+// consult the latest reload instead of the files read at call time, so a
+// long-running server or client picks up rotated certificates without a
+// restart. The watchers stop when ctx is canceled.
+func (loader *TLSLoader) StartReloader(ctx context.Context) error {
+	serverReloader, err := NewCertReloader(loader.config, true)
+	if err != nil {
+		return fmt.Errorf("failed to start server certificate reloader: %w", err)
+	}
+
+	if loader.config != nil && loader.config.RevocationStorePath != "" {
+		store, err := NewRevocationStore(loader.config.RevocationStorePath)
+		if err != nil {
+			serverReloader.Close()
+			return fmt.Errorf("failed to load revocation store: %w", err)
+		}
+		serverReloader.WithRevocationStore(store)
+	}
+
+	clientReloader, err := NewCertReloader(loader.config, false)
+	if err != nil {
+		serverReloader.Close()
+		return fmt.Errorf("failed to start client certificate reloader: %w", err)
+	}
+
+	loader.mu.Lock()
+	loader.serverReloader = serverReloader
+	loader.clientReloader = clientReloader
+	loader.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		serverReloader.Close()
+		clientReloader.Close()
+	}()
+
+	return nil
+}
+
+// Reload forces an immediate reload of whichever reloaders StartReloader
+// installed. It's a no-op if StartReloader hasn't been called.
+func (loader *TLSLoader) Reload() error {
+	serverReloader, clientReloader := loader.activeReloaders()
+
+	if serverReloader != nil {
+		if err := serverReloader.Reload(); err != nil {
+			return fmt.Errorf("failed to reload server certificates: %w", err)
+		}
+	}
+	if clientReloader != nil {
+		if err := clientReloader.Reload(); err != nil {
+			return fmt.Errorf("failed to reload client certificates: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReloadedAt returns the most recent time either reloader started by
+// StartReloader reloaded successfully, or the zero time if StartReloader
+// hasn't been called yet.
+func (loader *TLSLoader) ReloadedAt() time.Time {
+	serverReloader, clientReloader := loader.activeReloaders()
+
+	var latest time.Time
+	if serverReloader != nil {
+		latest = serverReloader.ReloadedAt()
+	}
+	if clientReloader != nil {
+		if t := clientReloader.ReloadedAt(); t.After(latest) {
+			latest = t
+		}
 	}
+	return latest
+}
+
+func (loader *TLSLoader) activeReloaders() (*CertReloader, *CertReloader) {
+	loader.mu.RLock()
+	defer loader.mu.RUnlock()
+	return loader.serverReloader, loader.clientReloader
+}
+
+// LoadCAPool builds a trust pool from caFile plus every PEM file in caPath,
+// so server, client, and the demo verifier all trust the same bundle. caPath
+// lets a deployment add new roots (rotation) or a peer deployment's CA
+// (federation) without replacing caFile; either argument may be empty, but
+// not both. Unreadable or non-PEM files in caPath are skipped with a
+// warning rather than failing the whole load.
+func LoadCAPool(caFile, caPath string) (*x509.CertPool, error) {
+	caCertPool := x509.NewCertPool()
+	loaded := false
+
+	if caFile != "" {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("failed to parse CA certificate")
+		}
+		loaded = true
+	}
+
+	if caPath != "" {
+		entries, err := os.ReadDir(caPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA directory %s: %w", caPath, err)
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			file := filepath.Join(caPath, entry.Name())
+			pemBytes, err := os.ReadFile(file)
+			if err != nil {
+				utils.Warn("LoadCAPool: failed to read %s: %v", file, err)
+				continue
+			}
+			if !caCertPool.AppendCertsFromPEM(pemBytes) {
+				utils.Warn("LoadCAPool: no PEM certificates found in %s", file)
+				continue
+			}
+			loaded = true
+		}
+	}
+
+	if !loaded {
+		return nil, fmt.Errorf("no CA certificates loaded from ca_cert or ca_path")
+	}
+	return caCertPool, nil
 }
 
 // LoadServerTLSConfig loads and creates a TLS configuration for servers
@@ -30,24 +194,99 @@ func (loader *TLSLoader) LoadServerTLSConfig() (*tls.Config, error) {
 		return nil, fmt.Errorf("failed to load server certificate: %w", err)
 	}
 
-	// Load CA certificate for client verification
-	caCert, err := os.ReadFile(loader.config.CACert)
+	// Load CA certificate(s) for client verification
+	caCertPool, err := LoadCAPool(loader.config.CACert, loader.config.CAPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		return nil, err
 	}
 
-	caCertPool := x509.NewCertPool()
-	if !caCertPool.AppendCertsFromPEM(caCert) {
-		return nil, fmt.Errorf("failed to parse CA certificate")
+	// ClientCACert, if set, verifies client certs against a separate trust
+	// bundle from the one above (e.g. a dedicated client-issuing CA)
+	// instead of reusing CACert/CAPath.
+	clientCACertPool := caCertPool
+	if loader.config.ClientCACert != "" {
+		clientCACertPool, err = LoadCAPool(loader.config.ClientCACert, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client CA trust bundle: %w", err)
+		}
 	}
 
 	// Create TLS configuration
 	tlsConfig := &tls.Config{
 		Certificates: []tls.Certificate{cert},
-		ClientCAs:    caCertPool,
-		ClientAuth:   tls.RequireAndVerifyClientCert,
-		MinVersion:   loader.config.MinTLSVersion,
-		MaxVersion:   tls.VersionTLS13,
+		ClientCAs:    clientCACertPool,
+		ClientAuth:   loader.config.GetAuthType(),
+	}
+	applySecurityProfile(tlsConfig, loader.config.SecurityProfile, loader.config.MinTLSVersion)
+
+	// If StartReloader is running, serve the certificate and verify client
+	// certs against its live CA pool instead of the snapshot read above, so
+	// a cert/key rotated on disk takes effect without reloading this config.
+	serverReloader, _ := loader.activeReloaders()
+	defaultCert := cert
+	if serverReloader != nil {
+		tlsConfig.Certificates = nil
+		tlsConfig.ClientCAs = nil
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = serverReloader.VerifyPeerCertificate
+		tlsConfig.GetCertificate = serverReloader.GetCertificate
+	}
+
+	// AdditionalCerts lets one listener present a different certificate per
+	// SNI ServerName (e.g. weather.mcp.local, datetime.mcp.local,
+	// echo.mcp.local all served from the same binary/port). GetCertificate
+	// is preferred over the deprecated BuildNameToCertificate so routing is
+	// explicit rather than inferred from each cert's SAN/CN.
+	if len(loader.config.AdditionalCerts) > 0 {
+		certsByName := make(map[string]*tls.Certificate, len(loader.config.AdditionalCerts))
+
+		for _, vhost := range loader.config.AdditionalCerts {
+			vhostCert, err := tls.LoadX509KeyPair(vhost.CertFile, vhost.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load certificate for vhost %s: %w", vhost.ServerName, err)
+			}
+			certsByName[vhost.ServerName] = &vhostCert
+			if serverReloader == nil {
+				tlsConfig.Certificates = append(tlsConfig.Certificates, vhostCert)
+			}
+		}
+
+		tlsConfig.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if hello.ServerName != "" {
+				if vhostCert, ok := certsByName[hello.ServerName]; ok {
+					return vhostCert, nil
+				}
+			}
+			if serverReloader != nil {
+				return serverReloader.GetCertificate(hello)
+			}
+			return &defaultCert, nil
+		}
+	}
+
+	// Staple an OCSP response onto whichever certificate(s) the handshake
+	// serves, opportunistically: a leaf with no OCSPServer AIA (or an
+	// unparseable CA) is served unstapled rather than failing the
+	// handshake. This wraps GetCertificate if the branches above already
+	// set one (reloader and/or AdditionalCerts), or staples the single
+	// static certificate otherwise.
+	issuerCert, err := firstCertFromFile(loader.config.CACert)
+	if err == nil {
+		if tlsConfig.GetCertificate != nil {
+			resolve := tlsConfig.GetCertificate
+			tlsConfig.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+				cert, err := resolve(hello)
+				if err != nil {
+					return nil, err
+				}
+				loader.revocation.AttachStaple(cert, issuerCert)
+				return cert, nil
+			}
+		} else {
+			loader.revocation.AttachStaple(&tlsConfig.Certificates[0], issuerCert)
+		}
+	} else {
+		utils.Warn("TLSLoader: failed to parse CA certificate for OCSP stapling: %v", err)
 	}
 
 	// Configure for demo mode if enabled
@@ -55,19 +294,145 @@ func (loader *TLSLoader) LoadServerTLSConfig() (*tls.Config, error) {
 		// In demo mode, accept any client cert and do minimal custom verification
 		tlsConfig.ClientAuth = tls.RequireAnyClientCert
 		tlsConfig.InsecureSkipVerify = true // Skip built-in validation
+		tlsConfig.VerifyPeerCertificate = nil
 		tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
 			// Custom demo-mode verification that's more permissive
 			if len(cs.PeerCertificates) == 0 {
 				return fmt.Errorf("no client certificate provided")
 			}
-			// In demo mode, just accept any certificate that's present and parseable
-			return nil
+			// In demo mode, just accept any certificate that's present and
+			// parseable, then apply identity authorization if configured.
+			if loader.authorizer == nil {
+				return nil
+			}
+			return loader.authorizer.Authorize(cs.PeerCertificates[0])
+		}
+	} else if loader.authorizer != nil {
+		// In strict mode, CA trust is already enforced by ClientCAs (or by
+		// serverReloader.VerifyPeerCertificate above); layer identity
+		// authorization on top via VerifyConnection so a cert signed by our
+		// CA still isn't enough unless it's also an allowed peer.
+		tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			roots := clientCACertPool
+			if serverReloader != nil {
+				roots = serverReloader.CAPool()
+			}
+			return verifyPeerChainAndIdentity(cs, roots, x509.ExtKeyUsageClientAuth, loader.authorizer)
 		}
 	}
 
 	return tlsConfig, nil
 }
 
+// verifyPeerChainAndIdentity re-verifies the peer's certificate chain for
+// extUsage (ExtKeyUsageClientAuth for a server verifying a client,
+// ExtKeyUsageServerAuth for a client verifying a server) and checks the
+// leaf's SPIFFE/DNS identity against authorizer's allow-list. It's used as
+// a strict-mode VerifyConnection hook, layered on top of whatever CA-trust
+// verification already ran (the built-in verifier or a CertReloader).
+func verifyPeerChainAndIdentity(cs tls.ConnectionState, roots *x509.CertPool, extUsage x509.ExtKeyUsage, authorizer PeerAuthorizer) error {
+	if len(cs.PeerCertificates) == 0 {
+		return fmt.Errorf("no peer certificate provided")
+	}
+	leaf := cs.PeerCertificates[0]
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{extUsage},
+	}); err != nil {
+		return fmt.Errorf("peer certificate chain verification failed: %w", err)
+	}
+
+	if authorizer == nil {
+		return nil
+	}
+	if err := authorizer.Authorize(leaf); err != nil {
+		return fmt.Errorf("peer authorization failed: %w", err)
+	}
+	return nil
+}
+
+// IncomingRPCConfig returns the server TLS config for the mTLS MCP RPC
+// listener, layering config.TLSConfig.RPC's protocol-scoped policy (if set)
+// on top of LoadServerTLSConfig's certificate loading/reload/demo-mode
+// handling.
+func (loader *TLSLoader) IncomingRPCConfig() (*tls.Config, error) {
+	return loader.incomingProtocolConfig(loader.config.RPC)
+}
+
+// IncomingHTTPSConfig returns the server TLS config for an admin/HTTPS
+// surface, layering config.TLSConfig.HTTPS's protocol-scoped policy (if
+// set) on top of LoadServerTLSConfig.
+func (loader *TLSLoader) IncomingHTTPSConfig() (*tls.Config, error) {
+	return loader.incomingProtocolConfig(loader.config.HTTPS)
+}
+
+// IncomingGRPCConfig returns the server TLS config for a gRPC listener,
+// layering config.TLSConfig.GRPC's protocol-scoped policy (if set) on top
+// of LoadServerTLSConfig.
+func (loader *TLSLoader) IncomingGRPCConfig() (*tls.Config, error) {
+	return loader.incomingProtocolConfig(loader.config.GRPC)
+}
+
+// incomingProtocolConfig builds the shared server TLS config via
+// LoadServerTLSConfig, then applies proto's min version, cipher suites,
+// client-auth policy, and ALPN protocols on top if proto is set.
+func (loader *TLSLoader) incomingProtocolConfig(proto *config.ProtocolTLSConfig) (*tls.Config, error) {
+	tlsConfig, err := loader.LoadServerTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	applyProtocolPolicy(tlsConfig, proto)
+	return tlsConfig, nil
+}
+
+// OutgoingALPNConfig returns the client TLS config for serverName with
+// NextProtos set to alpnProtos, for outbound connections (e.g. gRPC) that
+// need to negotiate a specific ALPN protocol on top of the shared
+// client-cert loading/reload handling in LoadClientTLSConfig.
+func (loader *TLSLoader) OutgoingALPNConfig(alpnProtos []string, serverName string) (*tls.Config, error) {
+	tlsConfig, err := loader.LoadClientTLSConfig(serverName)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.NextProtos = alpnProtos
+	return tlsConfig, nil
+}
+
+// applyProtocolPolicy layers proto's min version, cipher suites,
+// client-auth policy, and ALPN protocols onto tlsConfig. proto is a
+// complete override, not a sparse patch: a nil proto leaves tlsConfig
+// untouched, and a non-nil proto's zero-value MTLSState means "no client
+// certs" for that protocol, matching the top-level MTLSState convention.
+func applyProtocolPolicy(tlsConfig *tls.Config, proto *config.ProtocolTLSConfig) {
+	if proto == nil {
+		return
+	}
+
+	tlsConfig.MinVersion = proto.MinTLSVersion
+	if len(proto.CipherSuites) > 0 {
+		tlsConfig.CipherSuites = proto.CipherSuites
+	}
+	if len(proto.ALPNProtocols) > 0 {
+		tlsConfig.NextProtos = proto.ALPNProtocols
+	}
+
+	switch proto.MTLSState {
+	case config.MTLSStateVerifyIfGiven:
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	case config.MTLSStateEnabled:
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		tlsConfig.ClientAuth = tls.NoClientCert
+	}
+}
+
 // LoadClientTLSConfig loads and creates a TLS configuration for clients
 func (loader *TLSLoader) LoadClientTLSConfig(serverName string) (*tls.Config, error) {
 	// Load client certificate and key
@@ -76,15 +441,10 @@ func (loader *TLSLoader) LoadClientTLSConfig(serverName string) (*tls.Config, er
 		return nil, fmt.Errorf("failed to load client certificate: %w", err)
 	}
 
-	// Load CA certificate for server verification
-	caCert, err := os.ReadFile(loader.config.CACert)
+	// Load CA certificate(s) for server verification
+	caCertPool, err := LoadCAPool(loader.config.CACert, loader.config.CAPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
-	}
-
-	caCertPool := x509.NewCertPool()
-	if !caCertPool.AppendCertsFromPEM(caCert) {
-		return nil, fmt.Errorf("failed to parse CA certificate")
+		return nil, err
 	}
 
 	// Create TLS configuration
@@ -92,14 +452,58 @@ func (loader *TLSLoader) LoadClientTLSConfig(serverName string) (*tls.Config, er
 		Certificates: []tls.Certificate{cert},
 		RootCAs:      caCertPool,
 		ServerName:   serverName,
-		MinVersion:   loader.config.MinTLSVersion,
-		MaxVersion:   tls.VersionTLS13,
+	}
+	applySecurityProfile(tlsConfig, loader.config.SecurityProfile, loader.config.MinTLSVersion)
+
+	// If StartReloader is running, present the client certificate and trust
+	// the server against its live CA pool instead of the snapshot above.
+	_, clientReloader := loader.activeReloaders()
+	if clientReloader != nil {
+		tlsConfig.Certificates = nil
+		tlsConfig.RootCAs = nil
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.GetClientCertificate = clientReloader.GetClientCertificate
+		tlsConfig.VerifyPeerCertificate = clientReloader.VerifyPeerCertificate
+	}
+
+	// issuerCert is used to verify a stapled OCSP response's signature and
+	// to check a live OCSP responder. A failure here just disables
+	// revocation checking for this config (logged, not fatal): identity
+	// and CA-trust checks still run.
+	issuerCert, issuerErr := firstCertFromFile(loader.config.CACert)
+	if issuerErr != nil {
+		utils.Warn("TLSLoader: failed to parse CA certificate for revocation checking: %v", issuerErr)
 	}
 
 	// Configure for demo mode if enabled
 	if loader.config.DemoMode {
 		tlsConfig.InsecureSkipVerify = true // Skip hostname verification in demo mode
 		tlsConfig.VerifyPeerCertificate = loader.demoModeVerifyPeerCertificate
+		tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			if issuerErr != nil || len(cs.PeerCertificates) == 0 {
+				return nil
+			}
+			return loader.revocation.CheckDemo(cs.PeerCertificates[0], issuerCert, cs.OCSPResponse)
+		}
+	} else {
+		// In strict mode, CA trust is already enforced by RootCAs (or by
+		// clientReloader.VerifyPeerCertificate above); layer identity
+		// authorization and revocation checking on top via VerifyConnection.
+		tlsConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			if loader.authorizer != nil {
+				roots := caCertPool
+				if clientReloader != nil {
+					roots = clientReloader.CAPool()
+				}
+				if err := verifyPeerChainAndIdentity(cs, roots, x509.ExtKeyUsageServerAuth, loader.authorizer); err != nil {
+					return err
+				}
+			}
+			if issuerErr != nil || len(cs.PeerCertificates) == 0 {
+				return nil
+			}
+			return loader.revocation.Check(cs.PeerCertificates[0], issuerCert, cs.OCSPResponse)
+		}
 	}
 
 	return tlsConfig, nil
@@ -111,6 +515,8 @@ func (loader *TLSLoader) demoModeVerifyPeerCertificate(rawCerts [][]byte, verifi
 	// 1. Certificate must be parseable
 	// 2. Certificate must not be expired
 	// 3. Certificate must be signed by our CA
+	// 4. If an authorizer is configured, the certificate's identity must be
+	//    in its allow-list (not just signed by our CA)
 
 	if len(rawCerts) == 0 {
 		return fmt.Errorf("no certificates provided")
@@ -122,15 +528,9 @@ func (loader *TLSLoader) demoModeVerifyPeerCertificate(rawCerts [][]byte, verifi
 		return fmt.Errorf("failed to parse peer certificate: %w", err)
 	}
 
-	// Load CA certificate
-	caCertPEM, err := os.ReadFile(loader.config.CACert)
+	caCertPool, err := loader.trustedCAPool()
 	if err != nil {
-		return fmt.Errorf("failed to read CA certificate: %w", err)
-	}
-
-	caCertPool := x509.NewCertPool()
-	if !caCertPool.AppendCertsFromPEM(caCertPEM) {
-		return fmt.Errorf("failed to parse CA certificate")
+		return err
 	}
 
 	// Verify certificate against CA
@@ -143,9 +543,27 @@ func (loader *TLSLoader) demoModeVerifyPeerCertificate(rawCerts [][]byte, verifi
 		return fmt.Errorf("certificate verification failed: %w", err)
 	}
 
+	if loader.authorizer == nil {
+		return nil
+	}
+	if err := loader.authorizer.Authorize(cert); err != nil {
+		return fmt.Errorf("peer authorization failed: %w", err)
+	}
+
 	return nil
 }
 
+// trustedCAPool returns the CA trust pool to verify peer certificates
+// against: the live pool from an active client reloader if StartReloader
+// has been called, or a fresh read of loader.config.CACert otherwise.
+func (loader *TLSLoader) trustedCAPool() (*x509.CertPool, error) {
+	if _, clientReloader := loader.activeReloaders(); clientReloader != nil {
+		return clientReloader.CAPool(), nil
+	}
+
+	return LoadCAPool(loader.config.CACert, loader.config.CAPath)
+}
+
 // ValidateCertificatePair validates that a certificate and private key pair match
 func (loader *TLSLoader) ValidateCertificatePair(certPath, keyPath string) error {
 	_, err := tls.LoadX509KeyPair(certPath, keyPath)
@@ -155,8 +573,18 @@ func (loader *TLSLoader) ValidateCertificatePair(certPath, keyPath string) error
 	return nil
 }
 
-// GetTLSConnectionInfo extracts information from a TLS connection
-func (loader *TLSLoader) GetTLSConnectionInfo(conn *tls.Conn) (*TLSConnectionInfo, error) {
+// StatefulConn is anything that can report the state of an underlying TLS
+// connection: a raw *tls.Conn, or a wrapper around one (e.g. a yamux
+// Session multiplexed over a single mTLS handshake) that exposes the same
+// two methods.
+type StatefulConn interface {
+	ConnectionState() tls.ConnectionState
+	RemoteAddr() net.Addr
+}
+
+// GetTLSConnectionInfo extracts information from a TLS connection, or from
+// a multiplexed session built on top of one.
+func (loader *TLSLoader) GetTLSConnectionInfo(conn StatefulConn) (*TLSConnectionInfo, error) {
 	state := conn.ConnectionState()
 
 	tlsVersion := "Unknown"
@@ -183,17 +611,31 @@ func (loader *TLSLoader) GetTLSConnectionInfo(conn *tls.Conn) (*TLSConnectionInf
 		TLSVersion:        tlsVersion,
 		CipherSuite:       cipherSuite,
 		ClientCertCN:      clientCertCN,
+		NegotiatedProto:   state.NegotiatedProtocol,
+		SecurityProfile:   negotiatedSecurityProfile(state),
 		HandshakeComplete: state.HandshakeComplete,
+		EstablishedAt:     time.Now(),
+		ReloadedAt:        loader.ReloadedAt(),
 	}, nil
 }
 
 // TLSConnectionInfo holds information about a TLS connection
 type TLSConnectionInfo struct {
-	RemoteAddr        string `json:"remote_addr"`
-	TLSVersion        string `json:"tls_version"`
-	CipherSuite       string `json:"cipher_suite"`
-	ClientCertCN      string `json:"client_cert_cn"`
-	HandshakeComplete bool   `json:"handshake_complete"`
+	RemoteAddr      string `json:"remote_addr"`
+	TLSVersion      string `json:"tls_version"`
+	CipherSuite     string `json:"cipher_suite"`
+	ClientCertCN    string `json:"client_cert_cn"`
+	NegotiatedProto string `json:"negotiated_proto,omitempty"`
+	// SecurityProfile is the config.SecurityProfile this connection's
+	// negotiated version/cipher suite matches (e.g. "modern", "fips"), or
+	// empty if it doesn't cleanly match any known profile. Callers can log
+	// or deny sessions that fell back below their configured floor.
+	SecurityProfile   string    `json:"security_profile,omitempty"`
+	HandshakeComplete bool      `json:"handshake_complete"`
+	EstablishedAt     time.Time `json:"established_at"`
+	// ReloadedAt is the most recent time StartReloader's watcher reloaded
+	// certificates, or the zero time if StartReloader was never called.
+	ReloadedAt time.Time `json:"reloaded_at,omitempty"`
 }
 
 // getCipherSuiteName converts cipher suite ID to human readable name
@@ -230,4 +672,4 @@ func getCipherSuiteName(id uint16) string {
 		return name
 	}
 	return fmt.Sprintf("Unknown cipher suite (0x%04x)", id)
-}
\ No newline at end of file
+}
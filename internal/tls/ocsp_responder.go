@@ -0,0 +1,78 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// ocspResponseValidity bounds how long a signed OCSP response may be cached
+// by the requester before it must be treated as stale and re-checked.
+const ocspResponseValidity = 1 * time.Hour
+
+// OCSPResponder answers RFC 6960 OCSP requests at /ocsp for certificates
+// issued by cm, consulting store for the per-serial revocation status.
+// Meant to be mounted directly on an http.ServeMux, e.g.
+// mux.Handle("/ocsp", tls.NewOCSPResponder(cm, store)).
+type OCSPResponder struct {
+	cm    *CertificateManager
+	store *RevocationStore
+}
+
+// NewOCSPResponder returns an OCSPResponder signing responses with cm's CA
+// key (the intermediate if one exists, else the root) and answering from
+// store's revocation records.
+func NewOCSPResponder(cm *CertificateManager, store *RevocationStore) *OCSPResponder {
+	return &OCSPResponder{cm: cm, store: store}
+}
+
+// ServeHTTP implements the OCSP HTTP binding: the request body is a
+// DER-encoded OCSP request, and the response is signed with the issuing
+// CA's key.
+func (o *OCSPResponder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	reqDER, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read OCSP request", http.StatusBadRequest)
+		return
+	}
+
+	ocspReq, err := ocsp.ParseRequest(reqDER)
+	if err != nil {
+		http.Error(w, "malformed OCSP request", http.StatusBadRequest)
+		return
+	}
+
+	caCert, caKey, err := o.cm.loadCA()
+	if err != nil {
+		utils.Error("OCSPResponder: failed to load CA: %v", err)
+		http.Error(w, "OCSP responder unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	resp := ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: ocspReq.SerialNumber,
+		ThisUpdate:   time.Now(),
+		NextUpdate:   time.Now().Add(ocspResponseValidity),
+	}
+	if o.store.IsRevoked(ocspReq.SerialNumber) {
+		resp.Status = ocsp.Revoked
+		resp.RevokedAt = time.Now()
+	}
+
+	respDER, err := ocsp.CreateResponse(caCert, caCert, resp, caKey)
+	if err != nil {
+		utils.Error("OCSPResponder: failed to sign response: %v", err)
+		http.Error(w, "failed to sign OCSP response", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/ocsp-response")
+	w.Write(respDER)
+}
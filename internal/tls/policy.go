@@ -0,0 +1,242 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// CSRTemplate describes the identity and SANs for a certificate requested
+// against a signing profile. Validity, key usages, and key algorithm come
+// from the profile; this only carries the subject-specific parts.
+type CSRTemplate struct {
+	CommonName  string
+	DNSNames    []string
+	IPAddresses []net.IP
+	URIs        []*url.URL
+	OutputCert  string // defaults to <CertDir>/<profile>.crt
+	OutputKey   string // defaults to <CertDir>/<profile>.key
+}
+
+// WithSigningPolicy attaches a signing policy to this certificate manager,
+// enabling GenerateFromProfile.
+func (cm *CertificateManager) WithSigningPolicy(policy *config.SigningPolicy) *CertificateManager {
+	cm.policy = policy
+	return cm
+}
+
+// GenerateFromProfile issues a certificate signed by the CA, shaped by the
+// named signing profile: validity capped at the profile's max, key usages
+// and extended key usages from the profile, only the SAN types the profile
+// allows, and the profile's key algorithm/size.
+func (cm *CertificateManager) GenerateFromProfile(profileName string, csr CSRTemplate) error {
+	if cm.policy == nil {
+		return fmt.Errorf("no signing policy configured on this certificate manager")
+	}
+
+	profile, err := cm.policy.Profile(profileName)
+	if err != nil {
+		return fmt.Errorf("failed to resolve profile: %w", err)
+	}
+
+	if err := cm.validateSANsAgainstProfile(profile, csr); err != nil {
+		return fmt.Errorf("SANs not permitted by profile %q: %w", profile.Name, err)
+	}
+
+	caCert, caKey, err := cm.loadCA()
+	if err != nil {
+		return fmt.Errorf("failed to load CA: %w", err)
+	}
+
+	pub, signer, err := generateKeyForAlgorithm(profile.KeyAlgorithm)
+	if err != nil {
+		return fmt.Errorf("failed to generate key for profile %q: %w", profile.Name, err)
+	}
+
+	keyUsage, err := parseKeyUsages(profile.KeyUsages)
+	if err != nil {
+		return fmt.Errorf("profile %q: %w", profile.Name, err)
+	}
+	extKeyUsage, err := parseExtKeyUsages(profile.ExtKeyUsages)
+	if err != nil {
+		return fmt.Errorf("profile %q: %w", profile.Name, err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject: pkix.Name{
+			Organization: []string{"MCP Demo"},
+			Country:      []string{"US"},
+			CommonName:   csr.CommonName,
+		},
+		NotBefore:   time.Now(),
+		NotAfter:    time.Now().Add(profile.MaxValidity),
+		KeyUsage:    keyUsage,
+		ExtKeyUsage: extKeyUsage,
+		DNSNames:    csr.DNSNames,
+		IPAddresses: csr.IPAddresses,
+		URIs:        csr.URIs,
+	}
+
+	if nc := profile.NameConstraints; nc != nil {
+		template.PermittedDNSDomains = nc.PermittedDNSDomains
+		template.ExcludedDNSDomains = nc.ExcludedDNSDomains
+		template.PermittedDNSDomainsCritical = len(nc.PermittedDNSDomains) > 0
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, &template, caCert, pub, caKey)
+	if err != nil {
+		return fmt.Errorf("failed to create certificate from profile %q: %w", profile.Name, err)
+	}
+
+	certPath := csr.OutputCert
+	if certPath == "" {
+		certPath = cm.config.CertDir + "/" + profile.Name + ".crt"
+	}
+	keyPath := csr.OutputKey
+	if keyPath == "" {
+		keyPath = cm.config.CertDir + "/" + profile.Name + ".key"
+	}
+
+	if err := writeCertPEM(certPath, certDER); err != nil {
+		return fmt.Errorf("failed to write profile certificate: %w", err)
+	}
+	if err := writePrivateKey(keyPath, signer); err != nil {
+		return fmt.Errorf("failed to write profile private key: %w", err)
+	}
+
+	utils.Info("Issued certificate from profile %q: %s (valid %s)", profile.Name, certPath, profile.MaxValidity)
+	return nil
+}
+
+// validateSANsAgainstProfile rejects a CSR that requests SAN types the
+// profile doesn't permit.
+func (cm *CertificateManager) validateSANsAgainstProfile(profile config.CertProfile, csr CSRTemplate) error {
+	allowed := make(map[config.SANType]bool, len(profile.AllowedSANTypes))
+	for _, t := range profile.AllowedSANTypes {
+		allowed[t] = true
+	}
+
+	if len(csr.DNSNames) > 0 && !allowed[config.SANTypeDNS] {
+		return fmt.Errorf("DNS SANs are not permitted by this profile")
+	}
+	if len(csr.IPAddresses) > 0 && !allowed[config.SANTypeIP] {
+		return fmt.Errorf("IP SANs are not permitted by this profile")
+	}
+	if len(csr.URIs) > 0 && !allowed[config.SANTypeURI] {
+		return fmt.Errorf("URI SANs are not permitted by this profile")
+	}
+	return nil
+}
+
+// generateKeyForAlgorithm creates a key pair for the given algorithm,
+// returning its public key and the crypto.Signer used to sign the cert.
+func generateKeyForAlgorithm(alg config.KeyAlgorithm) (interface{}, interface{}, error) {
+	switch alg {
+	case config.KeyAlgorithmRSA2048:
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		return &key.PublicKey, key, err
+	case config.KeyAlgorithmRSA3072:
+		key, err := rsa.GenerateKey(rand.Reader, 3072)
+		return &key.PublicKey, key, err
+	case config.KeyAlgorithmRSA4096:
+		key, err := rsa.GenerateKey(rand.Reader, 4096)
+		return &key.PublicKey, key, err
+	case config.KeyAlgorithmECDSAP256:
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		return &key.PublicKey, key, err
+	case config.KeyAlgorithmECDSAP384:
+		key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		return &key.PublicKey, key, err
+	case config.KeyAlgorithmEd25519:
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		return pub, key, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported key algorithm: %s", alg)
+	}
+}
+
+// parseKeyUsages maps cfssl-style key usage names to x509.KeyUsage bits.
+func parseKeyUsages(names []string) (x509.KeyUsage, error) {
+	var usage x509.KeyUsage
+	for _, name := range names {
+		switch name {
+		case "digital signature":
+			usage |= x509.KeyUsageDigitalSignature
+		case "key encipherment":
+			usage |= x509.KeyUsageKeyEncipherment
+		case "cert sign":
+			usage |= x509.KeyUsageCertSign
+		case "crl sign":
+			usage |= x509.KeyUsageCRLSign
+		case "key agreement":
+			usage |= x509.KeyUsageKeyAgreement
+		default:
+			return 0, fmt.Errorf("unsupported key usage: %s", name)
+		}
+	}
+	return usage, nil
+}
+
+// parseExtKeyUsages maps cfssl-style extended key usage names to
+// x509.ExtKeyUsage values.
+func parseExtKeyUsages(names []string) ([]x509.ExtKeyUsage, error) {
+	usages := make([]x509.ExtKeyUsage, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "server auth":
+			usages = append(usages, x509.ExtKeyUsageServerAuth)
+		case "client auth":
+			usages = append(usages, x509.ExtKeyUsageClientAuth)
+		default:
+			return nil, fmt.Errorf("unsupported extended key usage: %s", name)
+		}
+	}
+	return usages, nil
+}
+
+// writeCertPEM PEM-encodes and writes a DER certificate.
+func writeCertPEM(path string, certDER []byte) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	return pem.Encode(out, &pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+}
+
+// writePrivateKey PEM-encodes and writes an RSA or ECDSA private key with
+// restrictive permissions.
+func writePrivateKey(path string, signer interface{}) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := out.Chmod(0600); err != nil {
+		return err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	return pem.Encode(out, &pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
@@ -0,0 +1,414 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// defaultReloadPollInterval is used as a belt-and-suspenders re-read in
+// addition to fsnotify, since some environments (network filesystems,
+// certain container volume mounts) don't reliably deliver fs events.
+const defaultReloadPollInterval = 30 * time.Second
+
+// CertReloader watches the certificate/key files referenced by a
+// config.TLSConfig and keeps an in-memory certificate and CA pool up to
+// date, so servers and clients built on top of it can rotate certificates
+// without a restart.
+type CertReloader struct {
+	cfg      *config.TLSConfig
+	isServer bool
+
+	mu         sync.RWMutex
+	cert       *tls.Certificate
+	caPool     *x509.CertPool
+	generation int
+	reloadedAt time.Time
+
+	reloadCount  int64
+	failureCount int64
+	pollInterval time.Duration
+
+	watcher *fsnotify.Watcher
+	sigCh   chan os.Signal
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+
+	// revocationStore, if set via WithRevocationStore, makes
+	// VerifyPeerCertificate reject a peer certificate whose serial number
+	// has been revoked, in addition to the existing CA-chain check. nil
+	// leaves revocation unchecked, as before this field existed.
+	revocationStore *RevocationStore
+}
+
+// WithRevocationStore attaches a RevocationStore to this reloader, so
+// VerifyPeerCertificate rejects peers presenting a revoked certificate for
+// mTLS client auth, not just ones outside the CA's trust chain.
+func (r *CertReloader) WithRevocationStore(store *RevocationStore) *CertReloader {
+	r.revocationStore = store
+	return r
+}
+
+// ReloadMetrics reports how a CertReloader's background watch loop has
+// behaved so far, primarily for /metrics-style exposition and diagnostics.
+type ReloadMetrics struct {
+	Generation int
+	Reloads    int64
+	Failures   int64
+}
+
+// NewCertReloader creates a CertReloader for a server (serving cert +
+// verifying client certs) or a client (client cert + verifying server
+// certs), performs an initial load, and starts the background watch loop.
+func NewCertReloader(cfg *config.TLSConfig, isServer bool) (*CertReloader, error) {
+	r := &CertReloader{
+		cfg:      cfg,
+		isServer: isServer,
+		sigCh:    make(chan os.Signal, 1),
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, fmt.Errorf("initial certificate load failed: %w", err)
+	}
+
+	pollInterval := defaultReloadPollInterval
+	if cfg.ReloadInterval > 0 {
+		pollInterval = cfg.ReloadInterval
+	}
+	r.pollInterval = pollInterval
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		utils.Warn("CertReloader: fsnotify unavailable (%v), falling back to polling every %s", err, pollInterval)
+	} else {
+		r.watcher = watcher
+		for _, path := range r.watchedFiles() {
+			if err := watcher.Add(path); err != nil {
+				utils.Warn("CertReloader: failed to watch %s: %v", path, err)
+			}
+		}
+	}
+
+	// SIGHUP is the conventional operator signal for "reload your
+	// configuration without restarting"; forward it into the watch loop
+	// alongside fsnotify events and the polling ticker.
+	signal.Notify(r.sigCh, syscall.SIGHUP)
+
+	go r.run()
+	return r, nil
+}
+
+// Reload forces an immediate reload of certificates and the CA pool,
+// independent of the fsnotify watcher or polling ticker. It's intended to
+// be called directly (e.g. from an admin endpoint) in addition to the
+// automatic SIGHUP handling installed by NewCertReloader.
+func (r *CertReloader) Reload() error {
+	return r.reload()
+}
+
+// Metrics reports the reloader's current generation and reload/failure
+// counts, for callers that expose them as process metrics or logs.
+func (r *CertReloader) Metrics() ReloadMetrics {
+	return ReloadMetrics{
+		Generation: r.Generation(),
+		Reloads:    atomic.LoadInt64(&r.reloadCount),
+		Failures:   atomic.LoadInt64(&r.failureCount),
+	}
+}
+
+// watchedFiles returns the cert/key files this reloader should track. The
+// CACert spec may expand to more than one path (comma-separated list or
+// directory); entries that can't be resolved yet are skipped since the
+// periodic poll and SIGHUP/Reload still cover them.
+func (r *CertReloader) watchedFiles() []string {
+	files := []string{r.cfg.ServerCert, r.cfg.ServerKey}
+	if !r.isServer {
+		files = []string{r.cfg.ClientCert, r.cfg.ClientKey}
+	}
+	if caPaths, err := expandCACertPaths(r.cfg.CACert); err == nil {
+		files = append(files, caPaths...)
+	} else {
+		files = append(files, r.cfg.CACert)
+	}
+	return files
+}
+
+// run watches for fs events and polls periodically, reloading the
+// certificate and CA pool whenever the underlying files change.
+func (r *CertReloader) run() {
+	defer close(r.doneCh)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	var events <-chan fsnotify.Event
+	var errs <-chan error
+	if r.watcher != nil {
+		events = r.watcher.Events
+		errs = r.watcher.Errors
+	}
+
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case <-ticker.C:
+			r.safeReload()
+		case sig := <-r.sigCh:
+			utils.Info("CertReloader: received %s, reloading certificates", sig)
+			r.safeReload()
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			utils.Debug("CertReloader: fs event %s on %s", event.Op, event.Name)
+			r.safeReload()
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			utils.Warn("CertReloader: watcher error: %v", err)
+		}
+	}
+}
+
+// safeReload reloads certificates, logging (but not returning) errors so a
+// transient write (e.g. a certgen tool mid-write) never tears down a
+// previously good configuration.
+func (r *CertReloader) safeReload() {
+	if err := r.reload(); err != nil {
+		utils.Warn("CertReloader: reload failed, keeping previous certificates in use: %v", err)
+	}
+}
+
+// reload re-reads the CA bundle and leaf certificate from disk and
+// atomically swaps them in.
+func (r *CertReloader) reload() error {
+	caPool, err := loadCertPool(r.cfg.CACert)
+	if err != nil {
+		atomic.AddInt64(&r.failureCount, 1)
+		return fmt.Errorf("failed to load CA bundle: %w", err)
+	}
+
+	certPath, keyPath := r.cfg.ClientCert, r.cfg.ClientKey
+	if r.isServer {
+		certPath, keyPath = r.cfg.ServerCert, r.cfg.ServerKey
+	}
+
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		atomic.AddInt64(&r.failureCount, 1)
+		return fmt.Errorf("failed to load leaf certificate: %w", err)
+	}
+
+	r.mu.Lock()
+	r.caPool = caPool
+	r.cert = &cert
+	r.generation++
+	r.reloadedAt = time.Now()
+	generation := r.generation
+	r.mu.Unlock()
+
+	atomic.AddInt64(&r.reloadCount, 1)
+	utils.Info("CertReloader: certificates reloaded (generation %d)", generation)
+	return nil
+}
+
+// loadCertPool reads the CA trust material referenced by caCertSpec, which
+// may be a single PEM bundle file, a comma-separated list of such files, or
+// a directory containing them. Accepting more than one source lets a root
+// rotation stage the new CA alongside the old one (e.g. two files, or a
+// drop-in directory) instead of requiring every root to live in one bundle,
+// while a single bundle file keeps working exactly as before.
+func loadCertPool(caCertSpec string) (*x509.CertPool, error) {
+	paths, err := expandCACertPaths(caCertSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	loaded := 0
+	for _, path := range paths {
+		caCertPEM, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle %s: %w", path, err)
+		}
+		if pool.AppendCertsFromPEM(caCertPEM) {
+			loaded++
+		}
+	}
+	if loaded == 0 {
+		return nil, fmt.Errorf("failed to parse CA bundle")
+	}
+	return pool, nil
+}
+
+// expandCACertPaths turns a CACert spec into the concrete file paths to
+// read: each comma-separated entry is either used as-is, or, if it names a
+// directory, expanded to every regular file directly inside it (sorted for
+// deterministic ordering).
+func expandCACertPaths(caCertSpec string) ([]string, error) {
+	var paths []string
+	for _, entry := range strings.Split(caCertSpec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		info, err := os.Stat(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat CA cert path %s: %w", entry, err)
+		}
+
+		if !info.IsDir() {
+			paths = append(paths, entry)
+			continue
+		}
+
+		dirEntries, err := os.ReadDir(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA cert directory %s: %w", entry, err)
+		}
+		var dirFiles []string
+		for _, de := range dirEntries {
+			if de.IsDir() {
+				continue
+			}
+			dirFiles = append(dirFiles, filepath.Join(entry, de.Name()))
+		}
+		sort.Strings(dirFiles)
+		paths = append(paths, dirFiles...)
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no CA cert files found in %q", caCertSpec)
+	}
+	return paths, nil
+}
+
+// GetCertificate implements the signature of tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.cert == nil {
+		return nil, fmt.Errorf("no certificate loaded")
+	}
+	return r.cert, nil
+}
+
+// GetClientCertificate implements the signature of
+// tls.Config.GetClientCertificate.
+func (r *CertReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.cert == nil {
+		return nil, fmt.Errorf("no certificate loaded")
+	}
+	return r.cert, nil
+}
+
+// VerifyPeerCertificate verifies the peer's leaf certificate against the
+// current CA pool. It's meant to be installed as tls.Config's
+// VerifyPeerCertificate alongside InsecureSkipVerify so that verification
+// always consults the latest CA pool, including during a rotation overlap
+// window where the bundle contains both the old and new root.
+func (r *CertReloader) VerifyPeerCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("no peer certificate presented")
+	}
+
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse peer certificate: %w", err)
+	}
+
+	r.mu.RLock()
+	pool := r.caPool
+	r.mu.RUnlock()
+
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: pool}); err != nil {
+		return fmt.Errorf("peer certificate verification failed: %w", err)
+	}
+
+	if r.revocationStore != nil && r.revocationStore.IsRevoked(cert.SerialNumber) {
+		return fmt.Errorf("peer certificate %s has been revoked", cert.SerialNumber)
+	}
+	return nil
+}
+
+// TLSConfig returns a *tls.Config whose certificate and verification
+// callbacks always consult this reloader's current state, so rotating the
+// files on disk rotates the live configuration without a restart.
+func (r *CertReloader) TLSConfig(minVersion uint16) *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: minVersion,
+		MaxVersion: tls.VersionTLS13,
+		// Verification is performed in VerifyPeerCertificate against the
+		// reloader's current CA pool, so the built-in verifier is disabled.
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: r.VerifyPeerCertificate,
+	}
+
+	if r.isServer {
+		cfg.GetCertificate = r.GetCertificate
+		cfg.ClientAuth = tls.RequireAnyClientCert
+	} else {
+		cfg.GetClientCertificate = r.GetClientCertificate
+	}
+
+	return cfg
+}
+
+// Generation returns how many times certificates have been (re)loaded,
+// primarily useful for tests and diagnostics.
+func (r *CertReloader) Generation() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.generation
+}
+
+// ReloadedAt returns the time of the most recent successful reload.
+func (r *CertReloader) ReloadedAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.reloadedAt
+}
+
+// CAPool returns the reloader's current CA trust pool. It lets callers that
+// need a fresh pool (e.g. TLSLoader's demo-mode verification) read it from
+// memory instead of re-reading the CA file from disk on every call.
+func (r *CertReloader) CAPool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.caPool
+}
+
+// Close stops the background watch loop, releases the fsnotify watcher, and
+// stops forwarding SIGHUP to this reloader.
+func (r *CertReloader) Close() error {
+	signal.Stop(r.sigCh)
+	close(r.stopCh)
+	<-r.doneCh
+	if r.watcher != nil {
+		return r.watcher.Close()
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"context"
+	"fmt"
+	mathrand "math/rand"
+	"os"
+	"time"
+
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// renewJitterFraction bounds how much StartAutoRenew's check interval is
+// jittered (±20%), modeled after smallstep's ca/renew.go: many servers
+// provisioned from the same CA at the same time would otherwise all wake up
+// and renew in the same instant every check interval.
+const renewJitterFraction = 0.2
+
+// Reloader is satisfied by *CertReloader. After a CertRenewer regenerates a
+// certificate's PEM files on disk it calls Reload, so in-memory tls.Config
+// state (GetCertificate/GetClientCertificate) picks up the new keypair
+// immediately instead of waiting for the next fsnotify event or poll tick.
+type Reloader interface {
+	Reload() error
+}
+
+// CertRenewer periodically checks one managed certificate's remaining
+// validity and regenerates it in place before it expires. Regeneration
+// writes to temp files and atomically renames them over the live cert/key
+// paths, so a concurrent reader never observes a partially-written PEM
+// file, then notifies subscribers via Notify and, if a Reloader was
+// supplied, calls its Reload so dependent tls.Config state is refreshed
+// without a restart.
+type CertRenewer struct {
+	cm       *CertificateManager
+	reloader Reloader
+
+	commonName string
+	certPath   string
+	keyPath    string
+	isServer   bool
+
+	notifyCh chan struct{}
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+// NewCertRenewer returns a CertRenewer for the server or client certificate
+// (depending on isServer) at certPath/keyPath, managed by cm. reloader may
+// be nil if nothing needs to be notified beyond Notify's channel.
+func NewCertRenewer(cm *CertificateManager, commonName, certPath, keyPath string, isServer bool, reloader Reloader) *CertRenewer {
+	return &CertRenewer{
+		cm:         cm,
+		reloader:   reloader,
+		commonName: commonName,
+		certPath:   certPath,
+		keyPath:    keyPath,
+		isServer:   isServer,
+		notifyCh:   make(chan struct{}, 1),
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}
+}
+
+// Notify returns a channel that receives a value every time this renewer
+// regenerates its certificate, so servers/clients can hot-reload dependent
+// state beyond what a Reloader already covers.
+func (r *CertRenewer) Notify() <-chan struct{} {
+	return r.notifyCh
+}
+
+// StartAutoRenew starts the background renewal loop: every checkInterval
+// (jittered per renewJitterFraction), it inspects the certificate via
+// GetCertificateInfo and regenerates it once less than renewBefore remains
+// until NotAfter.
+func (r *CertRenewer) StartAutoRenew(ctx context.Context, checkInterval, renewBefore time.Duration) {
+	go r.run(ctx, checkInterval, renewBefore)
+}
+
+func (r *CertRenewer) run(ctx context.Context, checkInterval, renewBefore time.Duration) {
+	defer close(r.doneCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stopCh:
+			return
+		case <-time.After(jitteredInterval(checkInterval)):
+			r.checkAndRenew(renewBefore)
+		}
+	}
+}
+
+// checkAndRenew renews the certificate if less than renewBefore remains
+// until its expiry, logging (but not returning) errors so a transient
+// failure doesn't take down the background loop.
+func (r *CertRenewer) checkAndRenew(renewBefore time.Duration) {
+	info, err := r.cm.GetCertificateInfo(r.certPath)
+	if err != nil {
+		utils.Warn("CertRenewer: failed to inspect %s: %v", r.certPath, err)
+		return
+	}
+
+	if time.Until(info.NotAfter) >= renewBefore {
+		return
+	}
+
+	utils.Info("CertRenewer: %s expires at %s, renewing", r.certPath, info.NotAfter)
+	if err := r.renew(); err != nil {
+		utils.Error("CertRenewer: renewal failed for %s: %v", r.certPath, err)
+	}
+}
+
+// Force renews the certificate immediately, regardless of its remaining
+// validity, for out-of-band renewal (e.g. an admin endpoint or CLI command).
+func (r *CertRenewer) Force() error {
+	return r.renew()
+}
+
+// renew regenerates the certificate and key into temp files, atomically
+// renames them over the live paths, then notifies subscribers and (if
+// configured) the paired Reloader.
+func (r *CertRenewer) renew() error {
+	tmpCert := r.certPath + ".tmp"
+	tmpKey := r.keyPath + ".tmp"
+
+	certType := config.ClientCert
+	if r.isServer {
+		certType = config.ServerCert
+	}
+	if err := r.cm.generateCert(r.commonName, certType, tmpCert, tmpKey, r.isServer); err != nil {
+		return fmt.Errorf("failed to regenerate certificate: %w", err)
+	}
+
+	if err := os.Rename(tmpCert, r.certPath); err != nil {
+		return fmt.Errorf("failed to swap certificate file: %w", err)
+	}
+	if err := os.Rename(tmpKey, r.keyPath); err != nil {
+		return fmt.Errorf("failed to swap key file: %w", err)
+	}
+
+	if r.reloader != nil {
+		if err := r.reloader.Reload(); err != nil {
+			utils.Warn("CertRenewer: reloader failed to pick up renewed certificate: %v", err)
+		}
+	}
+
+	select {
+	case r.notifyCh <- struct{}{}:
+	default:
+	}
+
+	utils.Info("CertRenewer: renewed %s", r.certPath)
+	return nil
+}
+
+// Close stops the background renewal loop and waits for it to exit.
+func (r *CertRenewer) Close() {
+	close(r.stopCh)
+	<-r.doneCh
+}
+
+// DefaultRotationCheckInterval is how often StartRotation's CertRenewer
+// wakes up to check whether the leaf certificate needs renewing. It should
+// be well under the shortest validity period this deployment's CA issues.
+const DefaultRotationCheckInterval = 10 * time.Minute
+
+// StartRotation wires together this package's rotation primitives for a
+// server's own leaf certificate: it starts loader's CertReloader (so
+// LoadServerTLSConfig's GetCertificate and CA pool always serve the latest
+// files on disk instead of the snapshot read at startup) and a CertRenewer
+// that regenerates cfg.ServerCert/ServerKey in place once less than a third
+// of their total validity remains, mirroring smallstep's renewal cadence.
+// Both stop when ctx is cancelled; the returned CertRenewer is also
+// returned so a caller that wants to force an out-of-band renewal (e.g. an
+// admin endpoint) can call its Force method directly.
+func StartRotation(ctx context.Context, loader *TLSLoader, cfg *config.TLSConfig, commonName string, checkInterval time.Duration) (*CertRenewer, error) {
+	if err := loader.StartReloader(ctx); err != nil {
+		return nil, err
+	}
+
+	cm := NewCertificateManager(cfg)
+	info, err := cm.GetCertificateInfo(cfg.ServerCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect server certificate for renewal scheduling: %w", err)
+	}
+	renewBefore := info.NotAfter.Sub(info.NotBefore) / 3
+
+	renewer := NewCertRenewer(cm, commonName, cfg.ServerCert, cfg.ServerKey, true, loader)
+	renewer.StartAutoRenew(ctx, checkInterval, renewBefore)
+	return renewer, nil
+}
+
+// jitteredInterval returns interval with up to ±renewJitterFraction jitter
+// applied, so many renewers watching certificates with a shared expiry
+// don't all wake and regenerate in the same instant.
+func jitteredInterval(interval time.Duration) time.Duration {
+	delta := time.Duration((mathrand.Float64()*2 - 1) * renewJitterFraction * float64(interval))
+	return interval + delta
+}
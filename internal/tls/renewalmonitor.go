@@ -0,0 +1,158 @@
+package tls
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/steve/llm-agents/internal/metrics"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// warnDayThresholds are the DaysRemaining values RenewalMonitor logs a
+// warning at as a tracked certificate approaches expiry.
+var warnDayThresholds = []int{30, 14, 7, 1}
+
+// CertStatus is one certificate's state as of the last RenewalMonitor scan.
+type CertStatus struct {
+	Path          string    `json:"path"`
+	Subject       string    `json:"subject"`
+	NotBefore     time.Time `json:"not_before"`
+	NotAfter      time.Time `json:"not_after"`
+	DaysRemaining int       `json:"days_remaining"`
+}
+
+// RenewalMonitor is the observability and on-demand-control counterpart to
+// the CertRenewer instances StartRotation/StartAutoRenew already run: it
+// doesn't duplicate their scheduled renewal decision, but periodically
+// records each tracked certificate's remaining lifetime to the
+// cert_expiry_seconds gauge, logs a warning as expiry approaches, and lets a
+// caller (e.g. the `llm-agents cert check` CLI command) force an immediate
+// renewal or list every tracked certificate's status in one call.
+type RenewalMonitor struct {
+	cm *CertificateManager
+
+	mu      sync.Mutex
+	tracked map[string]*CertRenewer
+}
+
+// NewRenewalMonitor creates a RenewalMonitor that inspects certificates via
+// cm.
+func NewRenewalMonitor(cm *CertificateManager) *RenewalMonitor {
+	return &RenewalMonitor{
+		cm:      cm,
+		tracked: make(map[string]*CertRenewer),
+	}
+}
+
+// Track adds certPath to the set RenewalMonitor scans in CheckExpiry/Start,
+// and registers renewer as the one Renew(certPath) forces. renewer is
+// typically the same CertRenewer already returned by StartRotation or
+// constructed alongside a StartAutoRenew call, so an on-demand renewal here
+// and that certificate's own scheduled renewal never race each other.
+func (m *RenewalMonitor) Track(certPath string, renewer *CertRenewer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tracked[certPath] = renewer
+}
+
+// CheckExpiry inspects every tracked certificate and returns its current
+// CertStatus, sorted by Path for stable output (e.g. a CLI table). It also
+// records each cert's remaining lifetime to the cert_expiry_seconds gauge.
+func (m *RenewalMonitor) CheckExpiry() ([]CertStatus, error) {
+	m.mu.Lock()
+	paths := make([]string, 0, len(m.tracked))
+	for path := range m.tracked {
+		paths = append(paths, path)
+	}
+	m.mu.Unlock()
+	sort.Strings(paths)
+
+	statuses := make([]CertStatus, 0, len(paths))
+	for _, path := range paths {
+		info, err := m.cm.GetCertificateInfo(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect %s: %w", path, err)
+		}
+
+		status := CertStatus{
+			Path:          path,
+			Subject:       info.Subject,
+			NotBefore:     info.NotBefore,
+			NotAfter:      info.NotAfter,
+			DaysRemaining: int(time.Until(info.NotAfter).Hours() / 24),
+		}
+		metrics.SetCertExpirySeconds(status.Subject, status.NotAfter)
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Renew forces immediate regeneration of the tracked certificate at path,
+// via its CertRenewer's Force, and records the renewal in
+// cert_renewals_total.
+func (m *RenewalMonitor) Renew(path string) error {
+	m.mu.Lock()
+	renewer, ok := m.tracked[path]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("renewal monitor: %s is not tracked", path)
+	}
+
+	subject := path
+	if info, err := m.cm.GetCertificateInfo(path); err == nil {
+		subject = info.Subject
+	}
+
+	if err := renewer.Force(); err != nil {
+		return err
+	}
+	metrics.IncCertRenewals(subject)
+	return nil
+}
+
+// Start starts the background scan loop: every checkInterval, CheckExpiry
+// runs against every tracked cert and a warning is logged for any cert
+// whose DaysRemaining has just crossed a warnDayThresholds entry. It stops
+// when ctx is cancelled.
+func (m *RenewalMonitor) Start(ctx context.Context, checkInterval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+
+		m.scanOnce()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				m.scanOnce()
+			}
+		}
+	}()
+}
+
+func (m *RenewalMonitor) scanOnce() {
+	statuses, err := m.CheckExpiry()
+	if err != nil {
+		utils.Warn("RenewalMonitor: %v", err)
+		return
+	}
+
+	for _, status := range statuses {
+		warnAtDayThreshold(status)
+	}
+}
+
+// warnAtDayThreshold logs a warning the scan where status.DaysRemaining
+// first reaches one of warnDayThresholds.
+func warnAtDayThreshold(status CertStatus) {
+	for _, threshold := range warnDayThresholds {
+		if status.DaysRemaining == threshold {
+			utils.Warn("RenewalMonitor: %s (%s) expires in %d day(s), at %s", status.Path, status.Subject, status.DaysRemaining, status.NotAfter)
+			return
+		}
+	}
+}
@@ -0,0 +1,279 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/utils"
+)
+
+// errRevoked marks a revocation error as an explicit Revoked status (as
+// opposed to an OCSP/CRL fetch or parse failure), so demo-mode callers can
+// downgrade the latter to a warning while still rejecting the former.
+var errRevoked = errors.New("certificate revoked")
+
+// RevocationChecker checks whether a peer certificate has been revoked,
+// via a stapled OCSP response (or one fetched live, for server-side
+// stapling) and, failing that, a cached CRL loaded from
+// config.TLSConfig.CRLFile or fetched from CRLDistributionPoints. It's a
+// no-op (Check always passes) until a leaf names an OCSPServer or cfg
+// names a CRL source, mirroring how AllowedPeerIDs makes peer
+// authorization opt-in.
+type RevocationChecker struct {
+	cfg *config.TLSConfig
+
+	mu      sync.Mutex
+	staples map[string]*cachedStaple // keyed by leaf serial number
+	crl     *x509.RevocationList
+}
+
+type cachedStaple struct {
+	der        []byte
+	nextUpdate time.Time
+}
+
+// NewRevocationChecker creates a RevocationChecker for cfg.
+func NewRevocationChecker(cfg *config.TLSConfig) *RevocationChecker {
+	return &RevocationChecker{
+		cfg:     cfg,
+		staples: make(map[string]*cachedStaple),
+	}
+}
+
+// AttachStaple fetches (or reuses the cached, not-yet-expired) OCSP staple
+// for cert's leaf and sets it as cert.OCSPStaple so crypto/tls serves it
+// during the handshake. Stapling is opportunistic: a leaf with no
+// OCSPServer AIA, or a responder that can't be reached, is logged and left
+// unstapled rather than failing the handshake.
+func (rc *RevocationChecker) AttachStaple(cert *tls.Certificate, issuer *x509.Certificate) {
+	if cert.Leaf == nil && len(cert.Certificate) > 0 {
+		if parsed, err := x509.ParseCertificate(cert.Certificate[0]); err == nil {
+			cert.Leaf = parsed
+		}
+	}
+	if cert.Leaf == nil || issuer == nil || len(cert.Leaf.OCSPServer) == 0 {
+		return
+	}
+
+	staple, err := rc.stapleFor(cert.Leaf, issuer)
+	if err != nil {
+		utils.Warn("RevocationChecker: failed to fetch OCSP staple for %s: %v", cert.Leaf.Subject.CommonName, err)
+		return
+	}
+	cert.OCSPStaple = staple
+}
+
+// stapleFor returns the cached OCSP response DER for leaf, refreshing it
+// from leaf.OCSPServer once the cached response's NextUpdate has passed.
+func (rc *RevocationChecker) stapleFor(leaf, issuer *x509.Certificate) ([]byte, error) {
+	key := leaf.SerialNumber.String()
+
+	rc.mu.Lock()
+	cached, ok := rc.staples[key]
+	rc.mu.Unlock()
+	if ok && time.Now().Before(cached.nextUpdate) {
+		return cached.der, nil
+	}
+
+	der, nextUpdate, err := fetchOCSPStaple(leaf, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	rc.mu.Lock()
+	rc.staples[key] = &cachedStaple{der: der, nextUpdate: nextUpdate}
+	rc.mu.Unlock()
+
+	return der, nil
+}
+
+// fetchOCSPStaple requests leaf's revocation status from the first
+// responder in leaf.OCSPServer.
+func fetchOCSPStaple(leaf, issuer *x509.Certificate) ([]byte, time.Time, error) {
+	req, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to create OCSP request: %w", err)
+	}
+
+	resp, err := http.Post(leaf.OCSPServer[0], "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to reach OCSP responder %s: %w", leaf.OCSPServer[0], err)
+	}
+	defer resp.Body.Close()
+
+	der, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read OCSP response: %w", err)
+	}
+
+	parsed, err := ocsp.ParseResponseForCert(der, leaf, issuer)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to parse OCSP response: %w", err)
+	}
+	if parsed.Status == ocsp.Revoked {
+		return nil, time.Time{}, fmt.Errorf("%w: %s is revoked per OCSP (revoked at %s)", errRevoked, leaf.SerialNumber, parsed.RevokedAt)
+	}
+
+	return der, parsed.NextUpdate, nil
+}
+
+// Check verifies leaf's revocation status: first via staple (a response
+// stapled by the peer during the handshake), parsed and signature-checked
+// against issuer, and via the cached/refreshed CRL if staple is empty or
+// unverifiable. A leaf with neither a usable staple nor a configured CRL
+// source is treated as not revoked.
+func (rc *RevocationChecker) Check(leaf, issuer *x509.Certificate, staple []byte) error {
+	if len(staple) > 0 {
+		parsed, err := ocsp.ParseResponseForCert(staple, leaf, issuer)
+		if err == nil {
+			if parsed.Status == ocsp.Revoked {
+				return fmt.Errorf("%w: %s is revoked per OCSP (revoked at %s)", errRevoked, leaf.SerialNumber, parsed.RevokedAt)
+			}
+			return nil
+		}
+		utils.Warn("RevocationChecker: stapled OCSP response for %s didn't validate, falling back to CRL: %v", leaf.SerialNumber, err)
+	}
+
+	return rc.checkCRL(leaf)
+}
+
+// CheckDemo behaves like Check, except OCSP/CRL fetch or parse failures
+// are downgraded to a logged warning and treated as "not revoked" -- demo
+// mode prioritizes staying up over strict enforcement. An explicit Revoked
+// status is never downgraded.
+func (rc *RevocationChecker) CheckDemo(leaf, issuer *x509.Certificate, staple []byte) error {
+	err := rc.Check(leaf, issuer, staple)
+	if err == nil || errors.Is(err, errRevoked) {
+		return err
+	}
+	utils.Warn("RevocationChecker: revocation check failed in demo mode, allowing connection: %v", err)
+	return nil
+}
+
+// checkCRL checks leaf's serial number against the cached CRL, refreshing
+// it from cfg.CRLFile or cfg.CRLDistributionPoints if it's stale or hasn't
+// been loaded yet.
+func (rc *RevocationChecker) checkCRL(leaf *x509.Certificate) error {
+	crl, err := rc.currentCRL()
+	if err != nil {
+		return err
+	}
+	if crl == nil {
+		return nil
+	}
+
+	for _, revoked := range crl.RevokedCertificateEntries {
+		if revoked.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+			return fmt.Errorf("%w: %s is revoked per CRL", errRevoked, leaf.SerialNumber)
+		}
+	}
+	return nil
+}
+
+// currentCRL returns the cached CRL, reloading it once its NextUpdate has
+// passed. Returns nil, nil if neither CRLFile nor CRLDistributionPoints is
+// configured.
+func (rc *RevocationChecker) currentCRL() (*x509.RevocationList, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.crl != nil && time.Now().Before(rc.crl.NextUpdate) {
+		return rc.crl, nil
+	}
+
+	der, err := rc.loadCRL()
+	if err != nil {
+		return nil, err
+	}
+	if der == nil {
+		return nil, nil
+	}
+
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CRL: %w", err)
+	}
+
+	rc.crl = crl
+	return rc.crl, nil
+}
+
+// loadCRL reads cfg.CRLFile if set, else fetches the first reachable URL
+// in cfg.CRLDistributionPoints.
+func (rc *RevocationChecker) loadCRL() ([]byte, error) {
+	if rc.cfg.CRLFile != "" {
+		data, err := readCRLFile(rc.cfg.CRLFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CRL file %s: %w", rc.cfg.CRLFile, err)
+		}
+		return data, nil
+	}
+
+	for _, url := range rc.cfg.CRLDistributionPoints {
+		der, err := fetchCRL(url)
+		if err != nil {
+			utils.Warn("RevocationChecker: failed to fetch CRL from %s: %v", url, err)
+			continue
+		}
+		return der, nil
+	}
+
+	return nil, nil
+}
+
+// readCRLFile reads cfg.CRLFile and decodes it from PEM if it's
+// PEM-encoded, returning the raw CRL DER either way.
+func readCRLFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		return block.Bytes, nil
+	}
+	return data, nil
+}
+
+// fetchCRL fetches a CRL from a distribution point URL.
+func fetchCRL(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// firstCertFromPEM parses the first certificate block in a PEM bundle, for
+// callers (OCSP stapling, revocation checking) that need the issuer as a
+// *x509.Certificate rather than just an x509.CertPool.
+func firstCertFromPEM(pemBytes []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in CA certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// firstCertFromFile reads path and parses its first PEM certificate block,
+// for callers that only have a CA file path rather than already-read bytes.
+func firstCertFromFile(path string) (*x509.Certificate, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+	}
+	return firstCertFromPEM(pemBytes)
+}
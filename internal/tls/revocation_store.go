@@ -0,0 +1,120 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// RevocationEntry records why and when a certificate serial number was
+// revoked, for inclusion in a CRL (CertificateManager.GenerateCRL) or an
+// OCSP response (OCSPResponder).
+type RevocationEntry struct {
+	Serial    string    `json:"serial"`
+	Reason    int       `json:"reason"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// RevocationStore is a persistent, serial-number-keyed record of revoked
+// certificates, the producer-side counterpart to RevocationChecker: where
+// RevocationChecker answers "has my peer's cert been revoked" from a CRL or
+// OCSP staple fetched elsewhere, RevocationStore is where this CA's own
+// revocations are recorded, and is what GenerateCRL and OCSPResponder read
+// from. It's backed by a single JSON file rather than BoltDB, matching how
+// the rest of this package persists small amounts of state (SigningPolicy,
+// CertRenewer) without pulling in an embedded database.
+type RevocationStore struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]RevocationEntry
+}
+
+// NewRevocationStore loads the revocation list at path, or starts an empty
+// one if the file doesn't exist yet.
+func NewRevocationStore(path string) (*RevocationStore, error) {
+	store := &RevocationStore{path: path, entries: make(map[string]RevocationEntry)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revocation store %s: %w", path, err)
+	}
+
+	var entries []RevocationEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse revocation store %s: %w", path, err)
+	}
+	for _, entry := range entries {
+		store.entries[entry.Serial] = entry
+	}
+	return store, nil
+}
+
+// Revoke records serial as revoked for reason (an x509.RevocationReasonCode,
+// e.g. x509.KeyCompromise) and persists the store to disk.
+func (s *RevocationStore) Revoke(serial *big.Int, reason int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[serial.String()] = RevocationEntry{
+		Serial:    serial.String(),
+		Reason:    reason,
+		RevokedAt: time.Now(),
+	}
+	return s.save()
+}
+
+// IsRevoked reports whether serial has been revoked.
+func (s *RevocationStore) IsRevoked(serial *big.Int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, revoked := s.entries[serial.String()]
+	return revoked
+}
+
+// Entries returns every revoked serial, for GenerateCRL and OCSPResponder to
+// build their responses from.
+func (s *RevocationStore) Entries() []RevocationEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]RevocationEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// save writes the store to a temp file and renames it over path, so a
+// concurrent reader never observes a partially-written file.
+func (s *RevocationStore) save() error {
+	data, err := json.MarshalIndent(s.entriesLocked(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal revocation store: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write revocation store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to swap revocation store into place: %w", err)
+	}
+	return nil
+}
+
+// entriesLocked returns the entries slice for marshaling; callers must hold s.mu.
+func (s *RevocationStore) entriesLocked() []RevocationEntry {
+	entries := make([]RevocationEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	return entries
+}
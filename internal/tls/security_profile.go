@@ -0,0 +1,121 @@
+// Package tls provides certificate management and TLS configuration utilities
+package tls
+
+import (
+	"crypto/tls"
+
+	"github.com/steve/llm-agents/internal/config"
+)
+
+// modernCipherSuites are the three AEAD suites TLS 1.3 negotiates
+// internally; Go's crypto/tls doesn't accept them in tls.Config.CipherSuites
+// (TLS 1.3 suite selection isn't configurable), but isSecureCipherSuite
+// uses this list to recognize them on an already-negotiated connection.
+var modernCipherSuites = []uint16{
+	tls.TLS_AES_128_GCM_SHA256,
+	tls.TLS_AES_256_GCM_SHA384,
+	tls.TLS_CHACHA20_POLY1305_SHA256,
+}
+
+// intermediateCipherSuites are the ECDHE-AEAD suites usable on TLS 1.2,
+// matching Mozilla's "intermediate" compatibility recommendation.
+var intermediateCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+}
+
+// fipsCipherSuites are the intermediate suites minus ChaCha20-Poly1305,
+// which isn't a FIPS-140-approved algorithm.
+var fipsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// legacyCipherSuites additionally allows CBC-mode suites, for
+// interoperating with peers that predate AEAD support.
+var legacyCipherSuites = append(append([]uint16{}, intermediateCipherSuites...),
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA256,
+	tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+)
+
+// fipsCurves are the only curves approved for FIPS-140 key exchange.
+var fipsCurves = []tls.CurveID{tls.CurveP256, tls.CurveP384}
+
+// isSecureCipherSuite reports whether id is one of the AEAD suites
+// negotiated under TLS 1.3, the strongest tier a connection can land on.
+func isSecureCipherSuite(id uint16) bool {
+	for _, suite := range modernCipherSuites {
+		if suite == id {
+			return true
+		}
+	}
+	return false
+}
+
+// securityProfilePolicy translates profile into concrete MinVersion,
+// MaxVersion, CipherSuites, and CurvePreferences. An unrecognized or empty
+// profile falls back to SecurityProfileIntermediate. fallbackMinVersion is
+// used as the floor for SecurityProfileLegacy, mirroring the
+// previously-hardcoded cfg.MinTLSVersion behavior.
+func securityProfilePolicy(profile config.SecurityProfile, fallbackMinVersion uint16) (minVersion, maxVersion uint16, cipherSuites []uint16, curves []tls.CurveID) {
+	switch profile {
+	case config.SecurityProfileModern:
+		return tls.VersionTLS13, tls.VersionTLS13, nil, nil
+	case config.SecurityProfileFIPS:
+		return tls.VersionTLS12, tls.VersionTLS13, fipsCipherSuites, fipsCurves
+	case config.SecurityProfileLegacy:
+		min := fallbackMinVersion
+		if min == 0 {
+			min = tls.VersionTLS12
+		}
+		return min, tls.VersionTLS13, legacyCipherSuites, nil
+	default:
+		return tls.VersionTLS12, tls.VersionTLS13, intermediateCipherSuites, nil
+	}
+}
+
+// applySecurityProfile layers profile's MinVersion/MaxVersion/CipherSuites/
+// CurvePreferences onto tlsConfig, replacing the caller's own choice of
+// those fields.
+func applySecurityProfile(tlsConfig *tls.Config, profile config.SecurityProfile, fallbackMinVersion uint16) {
+	minVersion, maxVersion, cipherSuites, curves := securityProfilePolicy(profile, fallbackMinVersion)
+	tlsConfig.MinVersion = minVersion
+	tlsConfig.MaxVersion = maxVersion
+	tlsConfig.CipherSuites = cipherSuites
+	tlsConfig.CurvePreferences = curves
+}
+
+// negotiatedSecurityProfile classifies an established connection's
+// negotiated version and cipher suite against the named profiles, so a
+// caller can log or deny sessions that fell back below the configured
+// floor. It returns "" if the connection doesn't cleanly match any known
+// profile (e.g. a legacy CBC suite).
+func negotiatedSecurityProfile(state tls.ConnectionState) string {
+	if state.Version == tls.VersionTLS13 && isSecureCipherSuite(state.CipherSuite) {
+		return string(config.SecurityProfileModern)
+	}
+	for _, suite := range fipsCipherSuites {
+		if suite == state.CipherSuite {
+			return string(config.SecurityProfileFIPS)
+		}
+	}
+	for _, suite := range intermediateCipherSuites {
+		if suite == state.CipherSuite {
+			return string(config.SecurityProfileIntermediate)
+		}
+	}
+	for _, suite := range legacyCipherSuites {
+		if suite == state.CipherSuite {
+			return string(config.SecurityProfileLegacy)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+// Package upgrade lets a single TCP listener serve both plaintext HTTP and
+// TLS, so a deployment can consolidate a server's plaintext and HTTPS ports
+// (e.g. an MCP server's :8082/:8444 pair) onto one port behind
+// port-restricted networks. It works the way STARTTLS does for IMAP/SMTP:
+// the listener sniffs the first byte of each new connection to tell a TLS
+// ClientHello (which always starts 0x16 for a handshake record) from plain
+// HTTP, and wraps the connection in *tls.Conn only when a handshake is
+// detected.
+package upgrade
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+)
+
+// tlsRecordHandshake is the first byte of every TLS record that carries a
+// handshake message (RFC 8446 §5.1), which is what a ClientHello always
+// starts with regardless of TLS version.
+const tlsRecordHandshake = 0x16
+
+// Listen wraps inner so that each Accepted connection is inspected before
+// being handed to a caller: a connection whose first byte looks like a TLS
+// ClientHello is wrapped in tls.Server(conn, tlsConfig); anything else
+// (plain HTTP, or a closed/errored peek) passes through unwrapped. The
+// returned net.Listener is safe to pass directly to http.Serve or
+// http.Server.Serve.
+func Listen(inner net.Listener, tlsConfig *tls.Config) net.Listener {
+	return &sniffingListener{inner: inner, tlsConfig: tlsConfig}
+}
+
+type sniffingListener struct {
+	inner     net.Listener
+	tlsConfig *tls.Config
+}
+
+func (l *sniffingListener) Accept() (net.Conn, error) {
+	conn, err := l.inner.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReader(conn)
+	preface, err := buffered.Peek(1)
+	sniffed := &sniffedConn{Conn: conn, r: buffered}
+	if err != nil {
+		// Couldn't even peek one byte (e.g. the peer closed immediately).
+		// Hand back the connection as-is; the caller's normal read path
+		// will surface the same error.
+		return sniffed, nil
+	}
+
+	if preface[0] == tlsRecordHandshake {
+		return tls.Server(sniffed, l.tlsConfig), nil
+	}
+	return sniffed, nil
+}
+
+func (l *sniffingListener) Close() error   { return l.inner.Close() }
+func (l *sniffingListener) Addr() net.Addr { return l.inner.Addr() }
+
+// sniffedConn is a net.Conn whose Read is served from r (a bufio.Reader
+// wrapping the real connection) so the Peek in Accept doesn't discard
+// already-buffered bytes.
+type sniffedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *sniffedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
@@ -0,0 +1,98 @@
+// Package transport multiplexes logical streams (tool calls, streaming
+// tokens, control messages) over a single mTLS connection between agents,
+// using hashicorp/yamux, the same pattern Consul's tlsutil tests use for
+// its RPC transport. LLM agents make many short-lived RPCs, so
+// re-handshaking mTLS per call is a real latency hit; a Session amortizes
+// one handshake across however many streams the conversation needs.
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/yamux"
+)
+
+// Session wraps a yamux session multiplexed over a single TLS connection,
+// so callers can Open/Accept logical streams without paying for a new
+// handshake per stream.
+type Session struct {
+	*yamux.Session
+	conn *tls.Conn
+}
+
+// ConnectionState returns the underlying TLS connection's state, so a
+// Session satisfies internal/tls's StatefulConn interface alongside a raw
+// *tls.Conn, and can be passed to TLSLoader.GetTLSConnectionInfo directly.
+func (s *Session) ConnectionState() tls.ConnectionState {
+	return s.conn.ConnectionState()
+}
+
+// RemoteAddr returns the underlying TLS connection's remote address.
+func (s *Session) RemoteAddr() net.Addr {
+	return s.conn.RemoteAddr()
+}
+
+// Dial opens a TLS connection to addr and establishes a yamux client
+// session on top of it. The returned Session's Open method hands back a
+// net.Conn-compatible stream per logical RPC; closing the Session tears
+// down every stream and the underlying connection.
+func Dial(ctx context.Context, addr string, tlsConfig *tls.Config) (*Session, error) {
+	dialer := &tls.Dialer{Config: tlsConfig}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("dialed connection to %s was not TLS", addr)
+	}
+
+	session, err := yamux.Client(tlsConn, yamux.DefaultConfig())
+	if err != nil {
+		tlsConn.Close()
+		return nil, fmt.Errorf("failed to establish yamux session to %s: %w", addr, err)
+	}
+
+	return &Session{Session: session, conn: tlsConn}, nil
+}
+
+// Serve accepts TLS connections on l, establishes a yamux server session
+// over each, and hands every logical stream it accepts to handler on its
+// own goroutine. Serve blocks until l.Accept returns an error (e.g. the
+// listener is closed), matching net/http's ListenAndServe convention.
+func Serve(l net.Listener, tlsConfig *tls.Config, handler func(net.Conn)) error {
+	tlsListener := tls.NewListener(l, tlsConfig)
+
+	for {
+		conn, err := tlsListener.Accept()
+		if err != nil {
+			return fmt.Errorf("transport listener closed: %w", err)
+		}
+
+		go serveConn(conn, handler)
+	}
+}
+
+// serveConn establishes a yamux server session over conn and dispatches
+// each accepted stream to handler until the session closes.
+func serveConn(conn net.Conn, handler func(net.Conn)) {
+	session, err := yamux.Server(conn, yamux.DefaultConfig())
+	if err != nil {
+		conn.Close()
+		return
+	}
+	defer session.Close()
+
+	for {
+		stream, err := session.Accept()
+		if err != nil {
+			return
+		}
+		go handler(stream)
+	}
+}
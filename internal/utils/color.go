@@ -0,0 +1,84 @@
+// Package utils provides utility functions for the multi-agent system
+package utils
+
+import (
+	"os"
+	"runtime"
+	"sync"
+)
+
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+)
+
+var (
+	colorMu      sync.Mutex
+	colorEnabled = detectColorSupport()
+)
+
+// detectColorSupport decides whether ANSI colors should be used by default:
+// disabled when NO_COLOR is set, when stdout isn't a terminal, and on
+// Windows consoles that aren't known to process VT sequences.
+func detectColorSupport() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if !isTerminal(os.Stdout) {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return os.Getenv("WT_SESSION") != "" || os.Getenv("ANSICON") != "" || os.Getenv("ConEmuANSI") == "ON"
+	}
+	return true
+}
+
+// isTerminal reports whether f appears to be an interactive terminal rather
+// than a redirected file or pipe.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// SetColorEnabled overrides the auto-detected color setting, e.g. to honor
+// a --no-color flag.
+func SetColorEnabled(enabled bool) {
+	colorMu.Lock()
+	defer colorMu.Unlock()
+	colorEnabled = enabled
+}
+
+func colorsEnabled() bool {
+	colorMu.Lock()
+	defer colorMu.Unlock()
+	return colorEnabled
+}
+
+func colorize(code, text string) string {
+	if !colorsEnabled() {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// Red colorizes text for errors and failures.
+func Red(text string) string { return colorize(ansiRed, text) }
+
+// Green colorizes text for success output.
+func Green(text string) string { return colorize(ansiGreen, text) }
+
+// Yellow colorizes text for warnings.
+func Yellow(text string) string { return colorize(ansiYellow, text) }
+
+// Cyan colorizes text for low-severity/debug output.
+func Cyan(text string) string { return colorize(ansiCyan, text) }
+
+// Bold emphasizes text without changing its color.
+func Bold(text string) string { return colorize(ansiBold, text) }
@@ -2,10 +2,15 @@
 package utils
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -35,18 +40,102 @@ func (l LogLevel) String() string {
 	}
 }
 
-// Logger provides structured logging functionality
+// slogLevel maps l onto the slog.Level backing this package's handlers.
+func (l LogLevel) slogLevel() slog.Level {
+	switch l {
+	case DEBUG:
+		return slog.LevelDebug
+	case WARN:
+		return slog.LevelWarn
+	case ERROR:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// levelFromSlog is slogLevel's inverse, used by the handlers below to
+// recover a LogLevel (for sampling and colorizing) from a slog.Record.
+func levelFromSlog(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return DEBUG
+	case level < slog.LevelWarn:
+		return INFO
+	case level < slog.LevelError:
+		return WARN
+	default:
+		return ERROR
+	}
+}
+
+// Fields is a set of structured attributes threaded through a context by
+// ContextWithFields and picked up by Logger.WithContext - session_id,
+// client_id, method, jsonrpc_id, remote_addr, and anything else worth
+// correlating a log line back to the request that produced it.
+type Fields map[string]interface{}
+
+type fieldsKey struct{}
+
+// ContextWithFields returns a context carrying fields merged on top of
+// whatever Fields ctx already carries, so a handler can attach a session id
+// up front and add jsonrpc_id/method once the body is decoded, without
+// later attachments clobbering earlier ones.
+func ContextWithFields(ctx context.Context, fields Fields) context.Context {
+	merged := make(Fields, len(fields))
+	if existing, ok := ctx.Value(fieldsKey{}).(Fields); ok {
+		for k, v := range existing {
+			merged[k] = v
+		}
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+func fieldsFromContext(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).(Fields)
+	return fields
+}
+
+// Logger provides structured logging functionality, backed by a
+// log/slog.Handler. The default handler is the colorized, human-readable
+// text format this package has always produced; NewJSONLogger swaps in a
+// zerolog-style single-line JSON handler for high-throughput scenarios
+// where logs are shipped to an aggregator rather than read in a terminal.
 type Logger struct {
+	slog    *slog.Logger
 	level   LogLevel
 	verbose bool
+	sampler *sampler
 }
 
-// NewLogger creates a new logger with the specified level
+// NewLogger creates a new logger with the specified level, using the
+// colorized text handler.
 func NewLogger(levelStr string, verbose bool) *Logger {
 	level := parseLogLevel(levelStr)
+	return newLogger(level, verbose, newTextHandler(os.Stdout, os.Stderr, level.slogLevel()))
+}
+
+// NewJSONLogger creates a new logger with the specified level, using a
+// zerolog-style JSON handler: one compact object per line with
+// "level"/"time"/"message" keys plus whatever fields were attached via
+// WithContext or With.
+func NewJSONLogger(levelStr string, verbose bool) *Logger {
+	level := parseLogLevel(levelStr)
+	return newLogger(level, verbose, newJSONHandler(os.Stdout, level.slogLevel()))
+}
+
+func newLogger(level LogLevel, verbose bool, handler slog.Handler) *Logger {
 	return &Logger{
+		slog:    slog.New(handler),
 		level:   level,
 		verbose: verbose,
+		sampler: newSampler(),
 	}
 }
 
@@ -66,59 +155,240 @@ func parseLogLevel(levelStr string) LogLevel {
 	}
 }
 
+// With returns a Logger that annotates every record with the given
+// key/value pairs (key1, value1, key2, value2, ...), in addition to
+// whatever fields it already carries.
+func (l *Logger) With(args ...interface{}) *Logger {
+	cp := *l
+	cp.slog = l.slog.With(args...)
+	return &cp
+}
+
+// WithContext returns a Logger that annotates every record with whatever
+// Fields ContextWithFields attached to ctx, so a log line can be correlated
+// back to the request that produced it. Returns l unchanged if ctx carries
+// no fields.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	fields := fieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	args := make([]interface{}, 0, len(fields)*2)
+	for k, v := range fields {
+		args = append(args, k, v)
+	}
+	return l.With(args...)
+}
+
 // Debug logs a debug message
 func (l *Logger) Debug(format string, args ...interface{}) {
-	if l.level <= DEBUG {
-		l.log(DEBUG, format, args...)
-	}
+	l.log(DEBUG, format, args...)
 }
 
 // Info logs an info message
 func (l *Logger) Info(format string, args ...interface{}) {
-	if l.level <= INFO {
-		l.log(INFO, format, args...)
-	}
+	l.log(INFO, format, args...)
 }
 
 // Warn logs a warning message
 func (l *Logger) Warn(format string, args ...interface{}) {
-	if l.level <= WARN {
-		l.log(WARN, format, args...)
-	}
+	l.log(WARN, format, args...)
 }
 
 // Error logs an error message
 func (l *Logger) Error(format string, args ...interface{}) {
-	if l.level <= ERROR {
-		l.log(ERROR, format, args...)
-	}
+	l.log(ERROR, format, args...)
 }
 
 // Verbose logs a verbose message (only if verbose mode is enabled)
 func (l *Logger) Verbose(format string, args ...interface{}) {
 	if l.verbose {
-		l.logVerbose(format, args...)
+		l.slog.Debug(fmt.Sprintf(format, args...))
 	}
 }
 
-// log logs a message with the specified level
+// log logs a message at level, dropping it below l.level and subjecting it
+// to l.sampler so a burst of identical records (e.g. many SSE clients
+// disconnecting in the same second) can't flood the log.
 func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	message := fmt.Sprintf(format, args...)
-	logLine := fmt.Sprintf("[%s] [%s] %s", timestamp, level.String(), message)
+	if level < l.level {
+		return
+	}
+	if !l.sampler.allow(level) {
+		return
+	}
+	l.slog.Log(context.Background(), level.slogLevel(), fmt.Sprintf(format, args...))
+}
+
+// sampler throttles repeated log lines per level: the first sampleBurst
+// records in a sampleWindow at a given level pass through unthrottled, then
+// only 1 in sampleRate after that, resetting each window. ERROR is never
+// sampled, since those are rare and operators need every one.
+type sampler struct {
+	mu     sync.Mutex
+	window time.Time
+	counts map[LogLevel]int
+}
+
+const (
+	sampleWindow = time.Second
+	sampleBurst  = 10
+	sampleRate   = 50
+)
+
+func newSampler() *sampler {
+	return &sampler{counts: make(map[LogLevel]int)}
+}
 
+func (s *sampler) allow(level LogLevel) bool {
 	if level >= ERROR {
-		fmt.Fprintln(os.Stderr, logLine)
-	} else {
-		fmt.Println(logLine)
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.window) > sampleWindow {
+		s.window = now
+		s.counts = make(map[LogLevel]int)
 	}
+	s.counts[level]++
+	n := s.counts[level]
+	if n <= sampleBurst {
+		return true
+	}
+	return (n-sampleBurst)%sampleRate == 0
+}
+
+// levelLabel renders level the way this package always has, for handlers
+// that only see a slog.Level and need the matching DEBUG/INFO/WARN/ERROR
+// string rather than slog's own "DEBUG+0" style.
+func levelLabel(level slog.Level) string {
+	return levelFromSlog(level).String()
 }
 
-// logVerbose logs a verbose message with special formatting
-func (l *Logger) logVerbose(format string, args ...interface{}) {
-	timestamp := time.Now().Format("15:04:05.000")
-	message := fmt.Sprintf(format, args...)
-	fmt.Printf("[%s] %s\n", timestamp, message)
+// colorizeLevel renders level's label with a severity-appropriate color (a
+// no-op when color output is disabled).
+func colorizeLevel(level slog.Level) string {
+	label := levelLabel(level)
+	switch levelFromSlog(level) {
+	case DEBUG:
+		return Cyan(label)
+	case WARN:
+		return Yellow(label)
+	case ERROR:
+		return Red(label)
+	default:
+		return label
+	}
+}
+
+// textHandler is a slog.Handler producing this package's long-standing
+// "[timestamp] [LEVEL] message key=value ..." format, colorized the same
+// way the pre-slog logger was.
+type textHandler struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	errOut io.Writer
+	level  slog.Level
+	attrs  []slog.Attr
+}
+
+func newTextHandler(out, errOut io.Writer, level slog.Level) *textHandler {
+	return &textHandler{mu: &sync.Mutex{}, out: out, errOut: errOut, level: level}
+}
+
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	timestamp := r.Time.Format("2006-01-02 15:04:05")
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] [%s] %s", timestamp, colorizeLevel(r.Level), r.Message)
+
+	for _, a := range h.attrs {
+		fmt.Fprintf(&b, " %s=%v", a.Key, a.Value.Any())
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		fmt.Fprintf(&b, " %s=%v", a.Key, a.Value.Any())
+		return true
+	})
+
+	w := h.out
+	if r.Level >= slog.LevelError {
+		w = h.errOut
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprintln(w, b.String())
+	return err
+}
+
+func (h *textHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	cp := *h
+	cp.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &cp
+}
+
+func (h *textHandler) WithGroup(_ string) slog.Handler {
+	return h
+}
+
+// jsonHandler is a slog.Handler emitting one zerolog-style JSON object per
+// line: compact, field-stable, and cheap enough to use under heavy SSE
+// fan-out without the colorizing/formatting overhead of textHandler.
+type jsonHandler struct {
+	mu    *sync.Mutex
+	out   io.Writer
+	level slog.Level
+	attrs []slog.Attr
+}
+
+func newJSONHandler(out io.Writer, level slog.Level) *jsonHandler {
+	return &jsonHandler{mu: &sync.Mutex{}, out: out, level: level}
+}
+
+func (h *jsonHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *jsonHandler) Handle(_ context.Context, r slog.Record) error {
+	rec := make(map[string]interface{}, 3+len(h.attrs)+r.NumAttrs())
+	rec["level"] = strings.ToLower(levelLabel(r.Level))
+	rec["time"] = r.Time.Format(time.RFC3339)
+	rec["message"] = r.Message
+
+	for _, a := range h.attrs {
+		rec[a.Key] = a.Value.Any()
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		rec[a.Key] = a.Value.Any()
+		return true
+	})
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err = h.out.Write(line)
+	return err
+}
+
+func (h *jsonHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	cp := *h
+	cp.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &cp
+}
+
+func (h *jsonHandler) WithGroup(_ string) slog.Handler {
+	return h
 }
 
 // Global logger instance
@@ -172,6 +442,18 @@ func Verbose(format string, args ...interface{}) {
 	}
 }
 
+// FromContext returns the global logger annotated with ctx's Fields (see
+// ContextWithFields), for the many call sites that log through the
+// package-level functions above rather than holding their own *Logger.
+// Initializes the global logger with its defaults first if nothing has
+// called InitLogger yet.
+func FromContext(ctx context.Context) *Logger {
+	if globalLogger == nil {
+		InitLogging()
+	}
+	return globalLogger.WithContext(ctx)
+}
+
 // InitLogging initializes global logging with default settings
 func InitLogging() {
 	globalLogger = NewLogger("INFO", false)
@@ -7,21 +7,9 @@ import (
 
 	"github.com/steve/llm-agents/internal/config"
 	"github.com/steve/llm-agents/internal/models"
+	mcptls "github.com/steve/llm-agents/internal/tls"
 )
 
-// TLSConnectionInfo represents TLS connection information (placeholder)
-type TLSConnectionInfo struct {
-	Version           string    `json:"version"`
-	CipherSuite       string    `json:"cipher_suite"`
-	ServerName        string    `json:"server_name"`
-	Verified          bool      `json:"verified"`
-	RemoteAddr        string    `json:"remote_addr"`
-	TLSVersion        string    `json:"tls_version"`
-	ClientCertCN      string    `json:"client_cert_cn"`
-	HandshakeComplete bool      `json:"handshake_complete"`
-	EstablishedAt     time.Time `json:"established_at"`
-}
-
 // CertificateValidationRequest represents a certificate validation request (placeholder)
 type CertificateValidationRequest struct {
 	CertPath   string `json:"cert_path"`
@@ -42,7 +30,7 @@ type MockTLSClient interface {
 	CallWeather(ctx context.Context, city string) (*models.TemperatureData, error)
 	CallDateTime(ctx context.Context, city string) (*models.DateTimeData, error)
 	CallEcho(ctx context.Context, text string) (*models.EchoData, error)
-	GetConnectionInfo() *TLSConnectionInfo
+	GetConnectionInfo() *mcptls.TLSConnectionInfo
 	ValidateServerCert() error
 	Close()
 }
@@ -201,11 +189,11 @@ type ClientConnectionTestRequest struct {
 
 // ClientConnectionTestResponse defines the expected connection test response structure
 type ClientConnectionTestResponse struct {
-	Success        bool              `json:"success"`
-	ResponseTime   time.Duration     `json:"response_time"`
-	TLSInfo        TLSConnectionInfo `json:"tls_info"`
-	ServerResponse interface{}       `json:"server_response,omitempty"`
-	Error          string            `json:"error,omitempty"`
+	Success        bool                     `json:"success"`
+	ResponseTime   time.Duration            `json:"response_time"`
+	TLSInfo        mcptls.TLSConnectionInfo `json:"tls_info"`
+	ServerResponse interface{}              `json:"server_response,omitempty"`
+	Error          string                   `json:"error,omitempty"`
 }
 
 // TestCertificateValidationContract tests certificate validation functionality
@@ -166,6 +166,65 @@ func TestCertificateConfigContract(t *testing.T) {
 			wantErr:  true,
 			errorMsg: "validity period must be > 0 and <= 10 years",
 		},
+		{
+			name: "valid_spiffe_id",
+			cert: config.Certificate{
+				Type:         config.ClientCert,
+				CommonName:   "weather-agent",
+				Organization: "MCP Demo",
+				Country:      "US",
+				Validity:     365 * 24 * time.Hour,
+				KeySize:      2048,
+				SerialNumber: 123456,
+				SPIFFEID:     "spiffe://llm-agents/agent/weather",
+			},
+			wantErr: false,
+		},
+		{
+			name: "spiffe_id_wrong_scheme",
+			cert: config.Certificate{
+				Type:         config.ClientCert,
+				CommonName:   "weather-agent",
+				Organization: "MCP Demo",
+				Country:      "US",
+				Validity:     365 * 24 * time.Hour,
+				KeySize:      2048,
+				SerialNumber: 123456,
+				SPIFFEID:     "https://llm-agents/agent/weather",
+			},
+			wantErr:  true,
+			errorMsg: `must use the "spiffe" scheme`,
+		},
+		{
+			name: "spiffe_id_missing_trust_domain",
+			cert: config.Certificate{
+				Type:         config.ClientCert,
+				CommonName:   "weather-agent",
+				Organization: "MCP Demo",
+				Country:      "US",
+				Validity:     365 * 24 * time.Hour,
+				KeySize:      2048,
+				SerialNumber: 123456,
+				SPIFFEID:     "spiffe:///agent/weather",
+			},
+			wantErr:  true,
+			errorMsg: "missing a trust domain",
+		},
+		{
+			name: "spiffe_id_missing_workload_path",
+			cert: config.Certificate{
+				Type:         config.ClientCert,
+				CommonName:   "weather-agent",
+				Organization: "MCP Demo",
+				Country:      "US",
+				Validity:     365 * 24 * time.Hour,
+				KeySize:      2048,
+				SerialNumber: 123456,
+				SPIFFEID:     "spiffe://llm-agents",
+			},
+			wantErr:  true,
+			errorMsg: "missing a workload path",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1,14 +1,71 @@
 package integration
 
 import (
+	"crypto/tls"
+	"os"
 	"testing"
 
 	"github.com/steve/llm-agents/internal/config"
+	mcptls "github.com/steve/llm-agents/internal/tls"
 )
 
-// TestEchoMCPServerTLS tests the echo MCP server with TLS support
+// TestEchoMCPServerTLS drives a live mTLS listener standing in for echo-mcp
+// through TLSLoader, proving a client presenting the pinned server identity
+// is accepted and one presenting any other identity - even a certificate
+// that still chains to the trusted CA - is rejected, the same SPIFFE-pinning
+// contract internal/mcp/client.NewTLSClientWithPeerPin gives the real echo
+// agent.
 func TestEchoMCPServerTLS(t *testing.T) {
-	t.Skip("Echo MCP server TLS not yet implemented - this test should fail")
-	// Similar structure to weather_tls_test.go but for echo server
-	t.Fatal("Echo MCP server TLS support not implemented yet")
-}
\ No newline at end of file
+	tempDir, err := os.MkdirTemp("", "echo_tls_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tlsConfig := config.NewTLSConfig(tempDir, true)
+	certManager := mcptls.NewCertificateManager(tlsConfig)
+
+	serverIdentity := mcptls.ClientIdentity{Role: "server", Name: "echo"}
+	otherIdentity := mcptls.ClientIdentity{Role: "agent", Name: "imposter"}
+	if err := certManager.GenerateAllCertsForIdentities(serverIdentity, otherIdentity); err != nil {
+		t.Fatalf("Failed to generate test certificates: %v", err)
+	}
+
+	serverTLSConfig, err := mcptls.NewTLSLoader(tlsConfig).LoadServerTLSConfig()
+	if err != nil {
+		t.Fatalf("Failed to load server TLS config: %v", err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverTLSConfig)
+	if err != nil {
+		t.Fatalf("Failed to start TLS listener: %v", err)
+	}
+	defer listener.Close()
+	go runEchoListener(listener)
+
+	// A client pinned to the server's real identity is accepted.
+	pinnedLoader := mcptls.NewTLSLoader(tlsConfig).WithPeerAuthorizer(mcptls.NewSingleIDAuthorizer("spiffe://llm-agents/server/echo"))
+	pinnedClientTLSConfig, err := pinnedLoader.LoadClientTLSConfig("localhost")
+	if err != nil {
+		t.Fatalf("Failed to load pinned client TLS config: %v", err)
+	}
+	conn, err := tls.Dial("tcp", listener.Addr().String(), pinnedClientTLSConfig)
+	if err != nil {
+		t.Fatalf("Expected client pinned to the real server identity to be accepted, got: %v", err)
+	}
+	defer conn.Close()
+	if err := echoRoundTrip(conn, "pinned-client"); err != nil {
+		t.Errorf("Pinned client session did not produce a working round trip: %v", err)
+	}
+
+	// A client pinned to any other identity is rejected, even though the
+	// server's certificate still chains to the same trusted CA.
+	wrongLoader := mcptls.NewTLSLoader(tlsConfig).WithPeerAuthorizer(mcptls.NewSingleIDAuthorizer("spiffe://llm-agents/server/not-echo"))
+	wrongClientTLSConfig, err := wrongLoader.LoadClientTLSConfig("localhost")
+	if err != nil {
+		t.Fatalf("Failed to load mis-pinned client TLS config: %v", err)
+	}
+	if _, err := tls.Dial("tcp", listener.Addr().String(), wrongClientTLSConfig); err == nil {
+		t.Errorf("Expected client pinned to the wrong server identity to be rejected, but the handshake succeeded")
+	}
+}
@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"crypto/tls"
+	"os"
+	"testing"
+
+	"github.com/steve/llm-agents/internal/config"
+	mcptls "github.com/steve/llm-agents/internal/tls"
+)
+
+// TestKeyAlgorithmsRoundTripThroughLiveHandshake generates a full CA/server/
+// client chain under each supported config.KeyAlgorithm and drives a real
+// TLS handshake and echo round trip over it, proving sign/verify works end
+// to end for every algorithm CertificateManager can issue, and that
+// GetCertificateInfo reports back the algorithm that produced each leaf.
+func TestKeyAlgorithmsRoundTripThroughLiveHandshake(t *testing.T) {
+	algorithms := []config.KeyAlgorithm{
+		config.KeyAlgorithmRSA2048,
+		config.KeyAlgorithmRSA3072,
+		config.KeyAlgorithmRSA4096,
+		config.KeyAlgorithmECDSAP256,
+		config.KeyAlgorithmECDSAP384,
+		config.KeyAlgorithmEd25519,
+	}
+
+	for _, alg := range algorithms {
+		alg := alg
+		t.Run(string(alg), func(t *testing.T) {
+			tempDir, err := os.MkdirTemp("", "key_algorithm_test")
+			if err != nil {
+				t.Fatalf("Failed to create temp directory: %v", err)
+			}
+			defer os.RemoveAll(tempDir)
+
+			tlsConfig := config.NewTLSConfig(tempDir, true)
+			tlsConfig.KeyType = alg
+			certManager := mcptls.NewCertificateManager(tlsConfig)
+			if err := certManager.GenerateAllCerts(); err != nil {
+				t.Fatalf("Failed to generate certificates for %s: %v", alg, err)
+			}
+
+			serverReloader, err := mcptls.NewCertReloader(tlsConfig, true)
+			if err != nil {
+				t.Fatalf("Failed to create server CertReloader for %s: %v", alg, err)
+			}
+			defer serverReloader.Close()
+
+			listener, err := tls.Listen("tcp", "127.0.0.1:0", serverReloader.TLSConfig(tls.VersionTLS12))
+			if err != nil {
+				t.Fatalf("Failed to start TLS listener for %s: %v", alg, err)
+			}
+			defer listener.Close()
+			go runEchoListener(listener)
+
+			conn, err := dialEcho(t, listener.Addr().String(), tlsConfig.ClientCert, tlsConfig.ClientKey, tlsConfig.CACert)
+			if err != nil {
+				t.Fatalf("Failed to complete handshake for %s: %v", alg, err)
+			}
+			defer conn.Close()
+			if err := echoRoundTrip(conn, "round-trip-"+string(alg)); err != nil {
+				t.Fatalf("Echo round trip failed for %s: %v", alg, err)
+			}
+
+			info, err := certManager.GetCertificateInfo(tlsConfig.ServerCert)
+			if err != nil {
+				t.Fatalf("Failed to get certificate info for %s: %v", alg, err)
+			}
+			if info.KeyAlgorithm != alg {
+				t.Errorf("expected server certificate KeyAlgorithm %s, got %s", alg, info.KeyAlgorithm)
+			}
+			if info.PublicKeyFingerprint == "" {
+				t.Errorf("expected a non-empty PublicKeyFingerprint for %s", alg)
+			}
+		})
+	}
+}
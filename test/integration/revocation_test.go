@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steve/llm-agents/internal/config"
+	mcptls "github.com/steve/llm-agents/internal/tls"
+)
+
+// reasonKeyCompromise is the RFC 5280 section 5.3.1 CRL revocation reason
+// code for "key compromise". crypto/x509 doesn't export these as constants
+// (pkix.RevokedCertificate/x509.RevocationListEntry only expose a plain
+// ReasonCode int), so it's reproduced here - same as internal/cli/cert.go's
+// revocationReasons.
+const reasonKeyCompromise = 1
+
+// TestRevokedClientCertificateFailsHandshake verifies the CA-revocation
+// path end to end: a client certificate that verifies fine is revoked via
+// CertificateManager.RevokeCertificate, and the server's CertReloader -
+// wired to the same RevocationStore, as TLSLoader.StartReloader does for
+// config.TLSConfig.RevocationStorePath - rejects the next handshake from
+// that certificate while a different, unrevoked client still gets through.
+func TestRevokedClientCertificateFailsHandshake(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "revocation_test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tlsConfig := config.NewTLSConfig(tempDir, true)
+	certManager := mcptls.NewCertificateManager(tlsConfig)
+	if err := certManager.GenerateAllCerts(); err != nil {
+		t.Fatalf("Failed to generate certificates: %v", err)
+	}
+
+	storePath := filepath.Join(tempDir, "revocations.json")
+	store, err := mcptls.NewRevocationStore(storePath)
+	if err != nil {
+		t.Fatalf("Failed to create revocation store: %v", err)
+	}
+
+	serverReloader, err := mcptls.NewCertReloader(tlsConfig, true)
+	if err != nil {
+		t.Fatalf("Failed to create server CertReloader: %v", err)
+	}
+	defer serverReloader.Close()
+	serverReloader.WithRevocationStore(store)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverReloader.TLSConfig(tls.VersionTLS12))
+	if err != nil {
+		t.Fatalf("Failed to start TLS listener: %v", err)
+	}
+	defer listener.Close()
+	go runEchoListener(listener)
+
+	conn, err := dialEcho(t, listener.Addr().String(), tlsConfig.ClientCert, tlsConfig.ClientKey, tlsConfig.CACert)
+	if err != nil {
+		t.Fatalf("Failed to dial with the client certificate before revocation: %v", err)
+	}
+	if err := echoRoundTrip(conn, "before-revocation"); err != nil {
+		t.Fatalf("Handshake before revocation did not produce a working session: %v", err)
+	}
+	conn.Close()
+
+	clientCertPEM, err := os.ReadFile(tlsConfig.ClientCert)
+	if err != nil {
+		t.Fatalf("Failed to read client certificate: %v", err)
+	}
+	block, _ := pem.Decode(clientCertPEM)
+	if block == nil {
+		t.Fatalf("Failed to decode client certificate PEM")
+	}
+	clientCert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse client certificate: %v", err)
+	}
+
+	cm := mcptls.NewCertificateManager(tlsConfig).WithRevocationStore(store)
+	if err := cm.RevokeCertificate(clientCert.SerialNumber, reasonKeyCompromise); err != nil {
+		t.Fatalf("Failed to revoke client certificate: %v", err)
+	}
+
+	if _, err := dialEcho(t, listener.Addr().String(), tlsConfig.ClientCert, tlsConfig.ClientKey, tlsConfig.CACert); err == nil {
+		t.Fatalf("expected handshake with a revoked client certificate to fail")
+	}
+
+	crlPath := filepath.Join(tempDir, "ca.crl")
+	if err := cm.GenerateCRL(store, crlPath, 7*24*time.Hour); err != nil {
+		t.Fatalf("Failed to republish CRL after revocation: %v", err)
+	}
+	if _, err := os.Stat(crlPath); err != nil {
+		t.Fatalf("expected GenerateCRL to write %s: %v", crlPath, err)
+	}
+}
@@ -1,13 +1,10 @@
 package integration
 
 import (
-	"context"
 	"os"
 	"testing"
-	"time"
 
 	"github.com/steve/llm-agents/internal/config"
-	"github.com/steve/llm-agents/internal/models"
 	mcptls "github.com/steve/llm-agents/internal/tls"
 )
 
@@ -137,10 +134,14 @@ func TestWeatherMCPServerTLS(t *testing.T) {
 	})
 
 	t.Run("weather_client_certificate_requirement", func(t *testing.T) {
-		// Test that weather server requires client certificates
-		// Will fail until client certificate requirement is implemented
+		// Test that the weather server's client certificate requirement
+		// tracks its configured MTLSState. Will fail until client
+		// certificate requirement is implemented.
 
-		// server := startWeatherServerTLS(t, tlsConfig)
+		// requireConfig := *tlsConfig
+		// requireConfig.MTLSState = config.MTLSStateEnabled
+		//
+		// server := startWeatherServerTLS(t, &requireConfig)
 		// defer server.Stop()
 		//
 		// // Try to connect without client certificate
@@ -159,46 +160,62 @@ func TestWeatherMCPServerTLS(t *testing.T) {
 		// client, err := weather.NewTLSClient(clientConfigNoCert)
 		// if err == nil {
 		//     client.Close()
-		//     t.Error("Connection should fail without client certificate")
+		//     t.Error("MTLSStateEnabled should refuse a connection without a client certificate")
 		// }
+		//
+		// // MTLSStateVerifyIfGiven should allow the same connection through
+		// verifyIfGivenConfig := *tlsConfig
+		// verifyIfGivenConfig.MTLSState = config.MTLSStateVerifyIfGiven
+		//
+		// server2 := startWeatherServerTLS(t, &verifyIfGivenConfig)
+		// defer server2.Stop()
+		//
+		// client2, err := weather.NewTLSClient(clientConfigNoCert)
+		// if err != nil {
+		//     t.Fatalf("MTLSStateVerifyIfGiven should allow connection without a client certificate: %v", err)
+		// }
+		// defer client2.Close()
 
 		t.Fatal("Client certificate requirement not implemented yet")
 	})
 
 	t.Run("weather_demo_mode_validation", func(t *testing.T) {
-		// Test weather server in demo mode (relaxed validation)
-		// Will fail until demo mode is implemented
+		// Test weather server in demo mode (relaxed validation) across all
+		// three MTLSState modes. Will fail until demo mode is implemented.
 
-		// demoConfig := *tlsConfig
-		// demoConfig.DemoMode = true
+		// for _, state := range []config.MTLSState{config.MTLSStateDisabled, config.MTLSStateVerifyIfGiven, config.MTLSStateEnabled} {
+		//     demoConfig := *tlsConfig
+		//     demoConfig.DemoMode = true
+		//     demoConfig.MTLSState = state
 		//
-		// server := startWeatherServerTLS(t, &demoConfig)
-		// defer server.Stop()
+		//     server := startWeatherServerTLS(t, &demoConfig)
+		//     defer server.Stop()
 		//
-		// clientConfig := config.MCPClientConfig{
-		//     ServerURL: "https://localhost:8443",
-		//     UseTLS:    true,
-		//     TLSConfig: demoConfig,
-		//     Timeout:   30 * time.Second,
-		// }
+		//     clientConfig := config.MCPClientConfig{
+		//         ServerURL: "https://localhost:8443",
+		//         UseTLS:    true,
+		//         TLSConfig: demoConfig,
+		//         Timeout:   30 * time.Second,
+		//     }
 		//
-		// client, err := weather.NewTLSClient(clientConfig)
-		// if err != nil {
-		//     t.Fatalf("Demo mode should allow connection: %v", err)
-		// }
-		// defer client.Close()
+		//     client, err := weather.NewTLSClient(clientConfig)
+		//     if err != nil {
+		//         t.Fatalf("Demo mode should allow connection under %s: %v", state, err)
+		//     }
+		//     defer client.Close()
 		//
-		// // Should be able to make API calls in demo mode
-		// ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		// defer cancel()
+		//     // Should be able to make API calls in demo mode regardless of MTLSState
+		//     ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		//     defer cancel()
 		//
-		// weatherData, err := client.CallWeather(ctx, "Boston")
-		// if err != nil {
-		//     t.Fatalf("Demo mode API call failed: %v", err)
-		// }
+		//     weatherData, err := client.CallWeather(ctx, "Boston")
+		//     if err != nil {
+		//         t.Fatalf("Demo mode API call failed under %s: %v", state, err)
+		//     }
 		//
-		// if weatherData.City != "Boston" {
-		//     t.Errorf("Expected city 'Boston', got '%s'", weatherData.City)
+		//     if weatherData.City != "Boston" {
+		//         t.Errorf("Expected city 'Boston', got '%s'", weatherData.City)
+		//     }
 		// }
 
 		t.Fatal("Demo mode validation not implemented yet")
@@ -214,7 +231,7 @@ func TestWeatherServerTLSConfiguration(t *testing.T) {
 		// Test that weather server validates TLS configuration on startup
 		// Will fail until configuration validation is implemented
 
-		invalidConfig := config.MCPServerConfig{
+		_ = config.MCPServerConfig{ // placeholder for invalidConfig
 			Name:       "weather-mcp-test",
 			HTTPPort:   8081,
 			TLSPort:    8081, // Same as HTTP port - invalid
@@ -308,7 +325,7 @@ func startWeatherServerTLS(t *testing.T, tlsConfig *config.TLSConfig) interface{
 	panic("startWeatherServerTLS not implemented yet")
 }
 
-func getServerCertificateInfo(address string) (*CertificateInfoResponse, error) {
+func getServerCertificateInfo(address string) (interface{}, error) {
 	// This function should retrieve server certificate information
 	// Will be implemented in the core implementation phase
 	panic("getServerCertificateInfo not implemented yet")
@@ -351,4 +368,4 @@ func TestWeatherServerTLSPerformance(t *testing.T) {
 
 		t.Fatal("TLS memory monitoring not implemented yet")
 	})
-}
\ No newline at end of file
+}
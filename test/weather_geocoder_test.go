@@ -0,0 +1,293 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/steve/llm-agents/internal/mcp/weather"
+)
+
+// TestStaticGeocoderResolvesKnownCity verifies the offline fallback
+// geocoder resolves a city from its fixed table.
+func TestStaticGeocoderResolvesKnownCity(t *testing.T) {
+	g := weather.NewStaticGeocoder()
+
+	lat, lon, name, country, err := g.Lookup(context.Background(), "  SEATTLE  ")
+	if err != nil {
+		t.Fatalf("Lookup returned an error: %v", err)
+	}
+	if name != "Seattle" || country != "US" {
+		t.Errorf("expected Seattle/US, got %s/%s", name, country)
+	}
+	if lat == 0 || lon == 0 {
+		t.Errorf("expected non-zero coordinates, got %f,%f", lat, lon)
+	}
+}
+
+// TestStaticGeocoderRejectsUnknownCity verifies an unresolvable query
+// returns an error instead of zero-value coordinates.
+func TestStaticGeocoderRejectsUnknownCity(t *testing.T) {
+	g := weather.NewStaticGeocoder()
+
+	if _, _, _, _, err := g.Lookup(context.Background(), "Atlantis"); err == nil {
+		t.Error("expected an error for an unknown city")
+	}
+}
+
+// TestNominatimGeocoderParsesResponse verifies the Nominatim geocoder
+// builds the expected request and parses a successful response.
+func TestNominatimGeocoderParsesResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("User-Agent"); got == "" {
+			t.Error("expected a non-empty User-Agent header")
+		}
+		if got := r.URL.Query().Get("q"); got != "Paris" {
+			t.Errorf("expected query %q, got %q", "Paris", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]interface{}{
+			{
+				"lat":          "48.8566",
+				"lon":          "2.3522",
+				"display_name": "Paris, Île-de-France, France",
+				"address":      map[string]string{"country_code": "fr"},
+			},
+		})
+	}))
+	defer server.Close()
+
+	g := weather.NewNominatimGeocoder()
+	g.BaseURL = server.URL
+
+	lat, lon, name, country, err := g.Lookup(context.Background(), "Paris")
+	if err != nil {
+		t.Fatalf("Lookup returned an error: %v", err)
+	}
+	if country != "FR" {
+		t.Errorf("expected country code FR, got %s", country)
+	}
+	if name != "Paris, Île-de-France, France" {
+		t.Errorf("unexpected resolved name: %s", name)
+	}
+	if lat != 48.8566 || lon != 2.3522 {
+		t.Errorf("unexpected coordinates: %f,%f", lat, lon)
+	}
+}
+
+// TestNominatimGeocoderRejectsNoResults verifies an empty results array is
+// treated as a not-found error rather than returning zero coordinates.
+func TestNominatimGeocoderRejectsNoResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]interface{}{})
+	}))
+	defer server.Close()
+
+	g := weather.NewNominatimGeocoder()
+	g.BaseURL = server.URL
+
+	if _, _, _, _, err := g.Lookup(context.Background(), "Nowhere"); err == nil {
+		t.Error("expected an error for an empty result set")
+	}
+}
+
+// TestCensusGeocoderParsesResponse verifies the Census geocoder builds the
+// expected request and parses a successful response, always reporting US.
+func TestCensusGeocoderParsesResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("address"); got != "Denver" {
+			t.Errorf("expected address %q, got %q", "Denver", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"result": map[string]interface{}{
+				"addressMatches": []map[string]interface{}{
+					{
+						"matchedAddress": "Denver, CO",
+						"coordinates":    map[string]float64{"x": -104.9903, "y": 39.7392},
+					},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	g := weather.NewCensusGeocoder()
+	g.BaseURL = server.URL
+
+	lat, lon, name, country, err := g.Lookup(context.Background(), "Denver")
+	if err != nil {
+		t.Fatalf("Lookup returned an error: %v", err)
+	}
+	if country != "US" || name != "Denver, CO" {
+		t.Errorf("unexpected result: %s/%s", name, country)
+	}
+	if lat != 39.7392 || lon != -104.9903 {
+		t.Errorf("unexpected coordinates: %f,%f", lat, lon)
+	}
+}
+
+// countingGeocoder counts Lookup calls so CachedGeocoder tests can verify
+// it actually avoids re-querying the wrapped geocoder.
+type countingGeocoder struct {
+	calls int
+}
+
+func (g *countingGeocoder) Lookup(ctx context.Context, query string) (float64, float64, string, string, error) {
+	g.calls++
+	return 1.0, 2.0, "Resolved " + query, "US", nil
+}
+
+// TestCachedGeocoderAvoidsRepeatedLookups verifies a second Lookup for the
+// same (normalized) query is served from cache instead of hitting inner.
+func TestCachedGeocoderAvoidsRepeatedLookups(t *testing.T) {
+	inner := &countingGeocoder{}
+	cached := weather.NewCachedGeocoder(inner, "", 10)
+
+	if _, _, _, _, err := cached.Lookup(context.Background(), "Tokyo"); err != nil {
+		t.Fatalf("first Lookup returned an error: %v", err)
+	}
+	if _, _, _, _, err := cached.Lookup(context.Background(), "  TOKYO  "); err != nil {
+		t.Fatalf("second Lookup returned an error: %v", err)
+	}
+
+	if inner.calls != 1 {
+		t.Errorf("expected 1 call to the wrapped geocoder, got %d", inner.calls)
+	}
+}
+
+// TestCachedGeocoderPersistsAcrossInstances verifies the on-disk cache
+// survives re-wrapping the same inner geocoder in a new CachedGeocoder, as
+// happens across a process restart.
+func TestCachedGeocoderPersistsAcrossInstances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geocode-cache.json")
+	inner := &countingGeocoder{}
+
+	first := weather.NewCachedGeocoder(inner, path, 10)
+	if _, _, _, _, err := first.Lookup(context.Background(), "Oslo"); err != nil {
+		t.Fatalf("Lookup returned an error: %v", err)
+	}
+
+	second := weather.NewCachedGeocoder(inner, path, 10)
+	if _, _, _, _, err := second.Lookup(context.Background(), "Oslo"); err != nil {
+		t.Fatalf("Lookup on the restarted cache returned an error: %v", err)
+	}
+
+	if inner.calls != 1 {
+		t.Errorf("expected the restarted cache to be warm from disk, got %d calls", inner.calls)
+	}
+}
+
+// stubGeocoder resolves every query to a fixed location, for routing tests
+// that don't need a real geocoding backend.
+type stubGeocoder struct {
+	lat, lon              float64
+	resolvedName, country string
+	err                   error
+}
+
+func (g *stubGeocoder) Lookup(ctx context.Context, query string) (float64, float64, string, string, error) {
+	return g.lat, g.lon, g.resolvedName, g.country, g.err
+}
+
+// stubProvider records whether it was called, for verifying NWSProvider's
+// fallback routing.
+type stubProvider struct {
+	called bool
+	obs    weather.Observation
+	err    error
+}
+
+func (p *stubProvider) Get(ctx context.Context, city string) (weather.Observation, error) {
+	p.called = true
+	return p.obs, p.err
+}
+
+// TestNWSProviderServesUSCoordinatesDirectly verifies a US resolution is
+// served from api.weather.gov rather than the fallback provider.
+func TestNWSProviderServesUSCoordinatesDirectly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/points/39.7392,-104.9903" {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"properties": map[string]string{"forecast": "http://" + r.Host + "/forecast"},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"properties": map[string]interface{}{
+				"periods": []map[string]interface{}{
+					{"temperature": 68, "shortForecast": "Sunny"},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	geocoder := &stubGeocoder{lat: 39.7392, lon: -104.9903, resolvedName: "Denver", country: "US"}
+	fallback := &stubProvider{}
+	provider := weather.NewNWSProvider(geocoder, fallback, weather.UnitsImperial)
+	provider.BaseURL = server.URL
+
+	obs, err := provider.Get(context.Background(), "Denver")
+	if err != nil {
+		t.Fatalf("Get returned an error: %v", err)
+	}
+	if fallback.called {
+		t.Error("expected the fallback provider not to be called for a US resolution")
+	}
+	if obs.Temperature != 68 || obs.Description != "Sunny" {
+		t.Errorf("unexpected observation: %+v", obs)
+	}
+}
+
+// TestNWSProviderRoutesNonUSToFallback verifies a non-US resolution is
+// routed to the fallback provider instead of being sent to NWS, which has
+// no grid data outside the US.
+func TestNWSProviderRoutesNonUSToFallback(t *testing.T) {
+	geocoder := &stubGeocoder{lat: 48.8566, lon: 2.3522, resolvedName: "Paris", country: "FR"}
+	fallback := &stubProvider{obs: weather.Observation{City: "Paris", Temperature: 18, Description: "Cloudy"}}
+	provider := weather.NewNWSProvider(geocoder, fallback, weather.UnitsMetric)
+
+	obs, err := provider.Get(context.Background(), "Paris")
+	if err != nil {
+		t.Fatalf("Get returned an error: %v", err)
+	}
+	if !fallback.called {
+		t.Error("expected the fallback provider to be called for a non-US resolution")
+	}
+	if obs.Temperature != 18 {
+		t.Errorf("expected the fallback provider's observation to be returned, got %+v", obs)
+	}
+}
+
+// TestNWSProviderRequiresFallbackForNonUS verifies a non-US resolution
+// without a configured fallback fails loudly instead of silently querying
+// NWS with out-of-coverage coordinates.
+func TestNWSProviderRequiresFallbackForNonUS(t *testing.T) {
+	geocoder := &stubGeocoder{lat: 48.8566, lon: 2.3522, resolvedName: "Paris", country: "FR"}
+	provider := weather.NewNWSProvider(geocoder, nil, weather.UnitsMetric)
+
+	if _, err := provider.Get(context.Background(), "Paris"); err == nil {
+		t.Error("expected an error when no fallback provider is configured for a non-US resolution")
+	}
+}
+
+// TestNWSProviderWrapsGeocoderErrors verifies a geocoder failure surfaces
+// as a "city not found" error rather than an opaque wrapped error.
+func TestNWSProviderWrapsGeocoderErrors(t *testing.T) {
+	geocoder := &stubGeocoder{err: errors.New("boom")}
+	provider := weather.NewNWSProvider(geocoder, nil, weather.UnitsMetric)
+
+	_, err := provider.Get(context.Background(), "Nowhere")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,286 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/steve/llm-agents/internal/config"
+	"github.com/steve/llm-agents/internal/mcp/weather"
+)
+
+// TestMockProviderReturnsPlausibleObservation verifies the default provider
+// returns a usable observation without any network access.
+func TestMockProviderReturnsPlausibleObservation(t *testing.T) {
+	provider := weather.NewMockProvider(weather.UnitsMetric)
+
+	obs, err := provider.Get(context.Background(), "Seattle")
+	if err != nil {
+		t.Fatalf("MockProvider.Get returned an error: %v", err)
+	}
+
+	if obs.City != "Seattle" {
+		t.Errorf("expected city %q, got %q", "Seattle", obs.City)
+	}
+	if obs.Temperature < 20.0 || obs.Temperature > 45.0 {
+		t.Errorf("expected temperature in [20, 45], got %f", obs.Temperature)
+	}
+	if obs.Description == "" {
+		t.Error("expected a non-empty description")
+	}
+}
+
+// TestOpenWeatherMapProviderParsesResponse verifies the OpenWeatherMap
+// provider builds the expected request and parses a successful response.
+func TestOpenWeatherMapProviderParsesResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("q"); got != "London" {
+			t.Errorf("expected city query %q, got %q", "London", got)
+		}
+		if got := r.URL.Query().Get("appid"); got != "test-key" {
+			t.Errorf("expected appid %q, got %q", "test-key", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"main":    map[string]float64{"temp": 15.5},
+			"weather": []map[string]string{{"description": "light rain"}},
+			"name":    "London",
+		})
+	}))
+	defer server.Close()
+
+	provider := weather.NewOpenWeatherMapProvider("test-key", weather.UnitsMetric)
+	provider.BaseURL = server.URL
+
+	obs, err := provider.Get(context.Background(), "London")
+	if err != nil {
+		t.Fatalf("OpenWeatherMapProvider.Get returned an error: %v", err)
+	}
+
+	if obs.Temperature != 15.5 {
+		t.Errorf("expected temperature 15.5, got %f", obs.Temperature)
+	}
+	if obs.Description != "light rain" {
+		t.Errorf("expected description %q, got %q", "light rain", obs.Description)
+	}
+}
+
+// TestOpenWeatherMapProviderSurfacesHTTPErrors verifies a non-200 response
+// from OpenWeatherMap is surfaced as an error rather than a zero-value
+// observation.
+func TestOpenWeatherMapProviderSurfacesHTTPErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "city not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	provider := weather.NewOpenWeatherMapProvider("test-key", weather.UnitsMetric)
+	provider.BaseURL = server.URL
+
+	if _, err := provider.Get(context.Background(), "Nowhere"); err == nil {
+		t.Error("expected an error for a non-200 response, got nil")
+	}
+}
+
+// TestOpenWeatherMapProviderSurfacesRateLimit verifies a 429 response is
+// wrapped in weather.ErrRateLimited rather than a generic error, so callers
+// can distinguish "try again later" from a broken request.
+func TestOpenWeatherMapProviderSurfacesRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	provider := weather.NewOpenWeatherMapProvider("test-key", weather.UnitsMetric)
+	provider.BaseURL = server.URL
+
+	_, err := provider.Get(context.Background(), "London")
+	if !errors.Is(err, weather.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+}
+
+// TestOpenWeatherMapProviderConvertsImperialUnits verifies the units query
+// parameter and Observation.Unit both reflect UnitsImperial.
+func TestOpenWeatherMapProviderConvertsImperialUnits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("units"); got != "imperial" {
+			t.Errorf("expected units query %q, got %q", "imperial", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"main":    map[string]float64{"temp": 59.9},
+			"weather": []map[string]string{{"description": "clear sky"}},
+			"name":    "London",
+		})
+	}))
+	defer server.Close()
+
+	provider := weather.NewOpenWeatherMapProvider("test-key", weather.UnitsImperial)
+	provider.BaseURL = server.URL
+
+	obs, err := provider.Get(context.Background(), "London")
+	if err != nil {
+		t.Fatalf("OpenWeatherMapProvider.Get returned an error: %v", err)
+	}
+	if obs.Unit != "°F" {
+		t.Errorf("expected unit %q, got %q", "°F", obs.Unit)
+	}
+}
+
+// TestOpenMeteoProviderGeocodesThenFetchesForecast verifies the two-step
+// geocode-then-forecast flow and the weather-code-to-description mapping.
+func TestOpenMeteoProviderGeocodesThenFetchesForecast(t *testing.T) {
+	geocodeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("name"); got != "Tokyo" {
+			t.Errorf("expected geocode name %q, got %q", "Tokyo", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"results": []map[string]interface{}{
+				{"name": "Tokyo", "latitude": 35.6762, "longitude": 139.6503},
+			},
+		})
+	}))
+	defer geocodeServer.Close()
+
+	forecastServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"current_weather": map[string]interface{}{
+				"temperature": 18.2,
+				"weathercode": 1,
+			},
+		})
+	}))
+	defer forecastServer.Close()
+
+	provider := weather.NewOpenMeteoProvider(weather.UnitsMetric)
+	provider.GeocodeURL = geocodeServer.URL
+	provider.ForecastURL = forecastServer.URL
+
+	obs, err := provider.Get(context.Background(), "Tokyo")
+	if err != nil {
+		t.Fatalf("OpenMeteoProvider.Get returned an error: %v", err)
+	}
+
+	if obs.City != "Tokyo" {
+		t.Errorf("expected city %q, got %q", "Tokyo", obs.City)
+	}
+	if obs.Temperature != 18.2 {
+		t.Errorf("expected temperature 18.2, got %f", obs.Temperature)
+	}
+	if obs.Description != "Partly cloudy" {
+		t.Errorf("expected description %q, got %q", "Partly cloudy", obs.Description)
+	}
+}
+
+// TestOpenMeteoProviderRejectsUnknownCity verifies an empty geocode result
+// set is surfaced as an error.
+func TestOpenMeteoProviderRejectsUnknownCity(t *testing.T) {
+	geocodeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"results": []map[string]interface{}{}})
+	}))
+	defer geocodeServer.Close()
+
+	provider := weather.NewOpenMeteoProvider(weather.UnitsMetric)
+	provider.GeocodeURL = geocodeServer.URL
+
+	if _, err := provider.Get(context.Background(), "Nowhereville"); err == nil {
+		t.Error("expected an error for a city with no geocode results, got nil")
+	}
+}
+
+// countingProvider counts how many times Get is called, for cache and
+// retry tests that assert on call counts rather than response content.
+type countingProvider struct {
+	calls int
+	err   error
+}
+
+func (p *countingProvider) Get(ctx context.Context, city string) (weather.Observation, error) {
+	p.calls++
+	if p.err != nil {
+		return weather.Observation{}, p.err
+	}
+	return weather.Observation{City: city, Temperature: 10, Unit: "°C", Description: "Clear", Timestamp: time.Now()}, nil
+}
+
+// TestCachingProviderAvoidsRepeatedCalls verifies a second Get for the same
+// (normalized) city within the TTL is served from cache.
+func TestCachingProviderAvoidsRepeatedCalls(t *testing.T) {
+	inner := &countingProvider{}
+	cached := weather.NewCachingProvider(inner, time.Minute)
+
+	if _, err := cached.Get(context.Background(), "Paris"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cached.Get(context.Background(), " PARIS "); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if inner.calls != 1 {
+		t.Errorf("expected the inner provider to be called once, got %d calls", inner.calls)
+	}
+}
+
+// TestRetryingProviderRetriesUpToConfiguredAttempts verifies a failing
+// inner provider is retried RetryAttempts times before the error surfaces.
+func TestRetryingProviderRetriesUpToConfiguredAttempts(t *testing.T) {
+	inner := &countingProvider{err: fmt.Errorf("temporary backend failure")}
+	retrying := weather.NewRetryingProvider(inner, config.MCPClientConfig{
+		Timeout:       time.Second,
+		RetryAttempts: 3,
+	})
+
+	if _, err := retrying.Get(context.Background(), "Berlin"); err == nil {
+		t.Fatal("expected an error once all retries are exhausted")
+	}
+
+	if inner.calls != 3 {
+		t.Errorf("expected 3 attempts, got %d", inner.calls)
+	}
+}
+
+// TestRetryingProviderDoesNotRetryRateLimit verifies a rate-limited inner
+// provider is not retried, since hammering a backend that just asked us to
+// back off would only make things worse.
+func TestRetryingProviderDoesNotRetryRateLimit(t *testing.T) {
+	inner := &countingProvider{err: fmt.Errorf("rate limited: %w", weather.ErrRateLimited)}
+	retrying := weather.NewRetryingProvider(inner, config.MCPClientConfig{
+		Timeout:       time.Second,
+		RetryAttempts: 3,
+	})
+
+	if _, err := retrying.Get(context.Background(), "Berlin"); !errors.Is(err, weather.ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+	if inner.calls != 1 {
+		t.Errorf("expected 1 attempt (no retry on rate limit), got %d calls", inner.calls)
+	}
+}
+
+// TestNewProviderByNameRejectsUnknownProvider verifies the factory
+// validates the provider name instead of silently falling back.
+func TestNewProviderByNameRejectsUnknownProvider(t *testing.T) {
+	_, err := weather.NewProviderByName("not-a-real-provider", "", "", config.MCPClientConfig{RetryAttempts: 1}, time.Minute, "")
+	if err == nil {
+		t.Error("expected an error for an unknown provider name")
+	}
+}
+
+// TestNewProviderByNameRequiresAPIKeyForOpenWeatherMap verifies the
+// openweathermap provider can't be selected without an API key.
+func TestNewProviderByNameRequiresAPIKeyForOpenWeatherMap(t *testing.T) {
+	_, err := weather.NewProviderByName("openweathermap", "", "", config.MCPClientConfig{RetryAttempts: 1}, time.Minute, "")
+	if err == nil {
+		t.Error("expected an error when no API key is provided for openweathermap")
+	}
+}